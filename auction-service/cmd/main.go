@@ -1,65 +1,264 @@
 package main
 
 import (
+	"auction-service/internal/blob"
 	"auction-service/internal/config"
+	"auction-service/internal/consumer"
+	"auction-service/internal/db"
 	"auction-service/internal/handler"
+	"auction-service/internal/live"
+	"auction-service/internal/messaging"
 	"auction-service/internal/model"
 	"auction-service/internal/repository"
+	"auction-service/internal/service"
 	"auction-service/rabbitmq"
+	"context"
 	"log"
 	"net/http"
+	"os"
+	"strconv"
+	"time"
 
 	_ "github.com/lib/pq"
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
 )
 
-type RabbitMQConfig struct {
-	RabbitMQURL string `json:"rabbitmq_url"`
-	QueueName   string `json:"queue_name"`
-}
-
 func main() {
 	cfg := config.LoadConfig() // Get DatabaseURL from config
 
 	// Database connection details (use value from config)
 	dsn := cfg.DatabaseURL // Use DatabaseURL returned by LoadConfig
 
-	// Connect to the database
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{}) // Use postgres.Open with dsn
+	// Connect to the database, Postgres or SQLite depending on the URL scheme
+	conn, err := db.Open(dsn)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
-	// Migrar el esquema de User
-	err = db.AutoMigrate(&model.User{}, &model.Auction{})
+	// Create or update the tables of every stored model.
+	err = conn.AutoMigrate(&model.User{}, &model.Auction{}, &model.Bid{}, &model.ProxyBid{}, &model.BidAuditEntry{}, &model.Order{}, &model.SecondChanceOffer{}, &model.WatchlistEntry{}, &model.OutboxMessage{}, &model.ExchangeRate{}, &model.WebhookSubscription{}, &model.WebhookDelivery{}, &model.Category{}, &model.Attachment{}, &model.AuctionTemplate{})
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
+	if err := repository.MigrateSearch(conn); err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+
 	log.Println("Migration successful")
-	repo := repository.NewAuctionRepository(db)
+	repo := repository.NewAuctionRepository(conn)
 
-	consumer, err := rabbitmq.NewConsumer(config.LoadConfig().RabbitMQURL, config.LoadConfig().QUEUE_USER_CREATED, repo)
+	bus, err := openBus(cfg.RabbitMQURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to the message bus: %v", err)
+	}
+	txManager := repository.NewTxManager(conn)
+	var opts []service.Option
+	if cfg.BidIncrements != "" {
+		increments, err := service.ParseIncrementTable(cfg.BidIncrements)
+		if err != nil {
+			log.Fatalf("Invalid BID_INCREMENTS: %v", err)
+		}
+		opts = append(opts, service.WithIncrements(increments))
+	}
+	retraction := service.DefaultRetractionRules
+	if cfg.BidRetractionWindow != "" {
+		if retraction.Window, err = time.ParseDuration(cfg.BidRetractionWindow); err != nil {
+			log.Fatalf("Invalid BID_RETRACTION_WINDOW: %v", err)
+		}
+	}
+	if cfg.BidRetractionCutoff != "" {
+		if retraction.FinalPeriod, err = time.ParseDuration(cfg.BidRetractionCutoff); err != nil {
+			log.Fatalf("Invalid BID_RETRACTION_CUTOFF: %v", err)
+		}
+	}
+	opts = append(opts, service.WithRetractionRules(retraction))
+	if cfg.PaymentPeriod != "" {
+		period, err := time.ParseDuration(cfg.PaymentPeriod)
+		if err != nil {
+			log.Fatalf("Invalid PAYMENT_PERIOD: %v", err)
+		}
+		opts = append(opts, service.WithPaymentPeriod(period))
+	}
+	if cfg.SecondChancePeriod != "" {
+		period, err := time.ParseDuration(cfg.SecondChancePeriod)
+		if err != nil {
+			log.Fatalf("Invalid SECOND_CHANCE_PERIOD: %v", err)
+		}
+		opts = append(opts, service.WithOfferPeriod(period))
+	}
+	if cfg.EndingSoonWindow != "" {
+		window, err := time.ParseDuration(cfg.EndingSoonWindow)
+		if err != nil {
+			log.Fatalf("Invalid ENDING_SOON_WINDOW: %v", err)
+		}
+		opts = append(opts, service.WithEndingSoonWindow(window))
+	}
+	heartbeat := handler.DefaultHeartbeat
+	if cfg.StreamHeartbeat != "" {
+		if heartbeat, err = time.ParseDuration(cfg.StreamHeartbeat); err != nil || heartbeat <= 0 {
+			log.Fatalf("Invalid STREAM_HEARTBEAT: %q", cfg.StreamHeartbeat)
+		}
+	}
+	blobs, err := blob.Open(context.Background(), cfg.BlobStoreURL)
 	if err != nil {
-		log.Fatalf("Failed to create RabbitMQ consumer: %v", err)
+		log.Fatalf("Failed to open the blob store: %v", err)
+	}
+	opts = append(opts, service.WithBlobStore(blobs))
+	if cfg.MediaBaseURL != "" {
+		opts = append(opts, service.WithMediaURL(cfg.MediaBaseURL))
+	}
+	if cfg.MaxUploadBytes != "" {
+		limits := service.DefaultUploadLimits
+		if limits.MaxSize, err = strconv.ParseInt(cfg.MaxUploadBytes, 10, 64); err != nil || limits.MaxSize <= 0 {
+			log.Fatalf("Invalid MAX_UPLOAD_BYTES: %q", cfg.MaxUploadBytes)
+		}
+		opts = append(opts, service.WithUploadLimits(limits))
+	}
+	// Changes made on this replica reach its live streams right after the
+	// commit; the relay broadcast brings those of every replica.
+	broadcaster := live.NewBroadcaster(live.DefaultHistorySize)
+	opts = append(opts, service.WithLiveNotifier(broadcaster))
+	go func() {
+		for range time.Tick(time.Minute) {
+			broadcaster.Expire(time.Now().Add(-live.DefaultMaxIdle))
+		}
+	}()
+	auctionService := service.NewAuctionService(repo, txManager, opts...)
+
+	rateRepo := repository.NewExchangeRateRepository(conn)
+	exchangeRateService := service.NewExchangeRateService(rateRepo, service.NewRateTable(rateRepo))
+	if cfg.ExchangeRatesFile != "" {
+		if err := importRatesFile(exchangeRateService, cfg.ExchangeRatesFile); err != nil {
+			log.Fatalf("Failed to import exchange rates: %v", err)
+		}
 	}
-	log.Println("Connected to RabbitMQ")
-	consumer.StartConsuming()
 
-	// Initialize auction repository with database connection
-	handler.InitAuctionRepository(db)
+	relay := service.NewOutboxRelay(txManager, bus, cfg.QUEUE_AUCTION_EVENTS, time.Second)
+	relay.AlsoPublishTo(cfg.QUEUE_AUCTION_WEBHOOKS)
+	relay.BroadcastTo(bus, cfg.EXCHANGE_AUCTION_LIVE)
+	relay.Start()
+
+	closer := service.NewAuctionCloser(repo, txManager, 5*time.Second, opts...)
+	closer.Start()
+
+	settlementService := service.NewSettlementService(txManager, opts...)
+	watchlistService := service.NewWatchlistService(txManager, opts...)
+	categoryService := service.NewCategoryService(txManager)
+	attachmentService := service.NewAttachmentService(txManager, blobs, opts...)
+	templateService := service.NewTemplateService(txManager, auctionService, opts...)
+	deadlines := service.NewPaymentDeadlines(repository.NewOrderRepository(conn), repository.NewSecondChanceOfferRepository(conn), txManager, time.Minute, opts...)
+	deadlines.Start()
+
+	webhookRepo := repository.NewWebhookRepository(conn)
+	webhookService := service.NewWebhookService(webhookRepo)
+	dispatcher := service.NewWebhookDispatcher(webhookRepo, time.Second, opts...)
+	dispatcher.Start()
+
+	if err := bus.Subscribe(cfg.QUEUE_USER_CREATED, consumer.NewUserCreated(auctionService).Handle); err != nil {
+		log.Fatalf("Failed to subscribe to %s: %v", cfg.QUEUE_USER_CREATED, err)
+	}
+	if err := bus.Subscribe(cfg.QUEUE_AUCTION_WEBHOOKS, consumer.NewWebhookEvents(webhookService).Handle); err != nil {
+		log.Fatalf("Failed to subscribe to %s: %v", cfg.QUEUE_AUCTION_WEBHOOKS, err)
+	}
+	if err := bus.SubscribeBroadcast(cfg.EXCHANGE_AUCTION_LIVE, broadcaster.Handle); err != nil {
+		log.Fatalf("Failed to subscribe to %s: %v", cfg.EXCHANGE_AUCTION_LIVE, err)
+	}
+	log.Println("Connected to the message bus")
 
 	// Create an AuctionHandler instance
-	var auctionHandler handler.AuctionHandler
+	auctionHandler := handler.NewAuctionHandler(auctionService, handler.WithExchangeRates(exchangeRateService))
+	exchangeRateHandler := handler.NewExchangeRateHandler(exchangeRateService)
+	orderHandler := handler.NewOrderHandler(settlementService)
+	watchlistHandler := handler.NewWatchlistHandler(watchlistService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	categoryHandler := handler.NewCategoryHandler(categoryService)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentService, blobs)
+	templateHandler := handler.NewTemplateHandler(templateService)
+	streamHandler := handler.NewStreamHandler(auctionService, broadcaster, heartbeat)
+	biddingSocket := handler.NewBiddingSocket(auctionService, broadcaster, handler.DefaultSocketLimits)
 
 	// Register HTTP endpoints with handler methods
 	http.HandleFunc("/auctions", auctionHandler.GetAllAuctions)
 	http.HandleFunc("/auctions/{id}", auctionHandler.GetAuctionByID)
+	http.HandleFunc("/auctions/search", auctionHandler.SearchAuctions)
 	http.HandleFunc("/auctions/create", auctionHandler.CreateAuction)
 	http.HandleFunc("/auctions/update/{id}", auctionHandler.UpdateAuction)
 	http.HandleFunc("/auctions/delete/{id}", auctionHandler.DeleteAuction)
+	http.HandleFunc("/auctions/close/{id}", auctionHandler.CloseAuction)
+	http.HandleFunc("/auctions/bid/{id}", auctionHandler.PlaceBid)
+	http.HandleFunc("/auctions/proxy-bid/{id}", auctionHandler.PlaceProxyBid)
+	http.HandleFunc("/auctions/bids/{id}", auctionHandler.GetBids)
+	http.HandleFunc("/auctions/buy-now/{id}", auctionHandler.BuyNow)
+	http.HandleFunc("/auctions/bid-audit/{id}", auctionHandler.GetBidAudit)
+	http.HandleFunc("/auctions/upload/{id}", attachmentHandler.UploadAttachments)
+	http.HandleFunc("/auctions/attachments/{id}", attachmentHandler.GetAttachments)
+	http.HandleFunc("/attachments/delete/{id}", attachmentHandler.DeleteAttachment)
+	http.HandleFunc("/media/{key...}", attachmentHandler.ServeMedia)
+	http.HandleFunc("/auctions/ws", biddingSocket.ServeBidding)
+	http.HandleFunc("/bids/retract/{id}", auctionHandler.RetractBid)
+	http.HandleFunc("/bids/cancel/{id}", auctionHandler.CancelBid)
+	http.HandleFunc("/auctions/orders/{id}", orderHandler.GetAuctionOrders)
+	http.HandleFunc("/orders/{id}", orderHandler.GetOrder)
+	http.HandleFunc("/orders/pay/{id}", orderHandler.PayOrder)
+	http.HandleFunc("/orders/ship/{id}", orderHandler.ShipOrder)
+	http.HandleFunc("/orders/complete/{id}", orderHandler.CompleteOrder)
+	http.HandleFunc("/auctions/second-chance/{id}", orderHandler.OfferSecondChance)
+	http.HandleFunc("/auctions/second-chance-offers/{id}", orderHandler.GetSecondChanceOffers)
+	http.HandleFunc("/second-chance/accept/{id}", orderHandler.AcceptSecondChance)
+	http.HandleFunc("/second-chance/decline/{id}", orderHandler.DeclineSecondChance)
+	http.HandleFunc("/auctions/watch/{id}", watchlistHandler.WatchAuction)
+	http.HandleFunc("/auctions/unwatch/{id}", watchlistHandler.UnwatchAuction)
+	http.HandleFunc("/watchlist", watchlistHandler.GetWatchlist)
+	http.HandleFunc("/templates", templateHandler.GetTemplates)
+	http.HandleFunc("/templates/{id}", templateHandler.GetTemplate)
+	http.HandleFunc("/templates/create", templateHandler.CreateTemplate)
+	http.HandleFunc("/templates/update/{id}", templateHandler.UpdateTemplate)
+	http.HandleFunc("/templates/delete/{id}", templateHandler.DeleteTemplate)
+	http.HandleFunc("/templates/use/{id}", templateHandler.ListFromTemplate)
+	http.HandleFunc("/categories", categoryHandler.GetCategories)
+	http.HandleFunc("/categories/{id}", categoryHandler.GetCategory)
+	http.HandleFunc("/exchange-rates", exchangeRateHandler.GetRates)
+	http.HandleFunc("/admin/exchange-rates", handler.RequireAdmin(cfg.AdminToken, exchangeRateHandler.ImportRates))
+	http.HandleFunc("/admin/categories/create", handler.RequireAdmin(cfg.AdminToken, categoryHandler.CreateCategory))
+	http.HandleFunc("/admin/categories/update/{id}", handler.RequireAdmin(cfg.AdminToken, categoryHandler.UpdateCategory))
+	http.HandleFunc("/admin/categories/delete/{id}", handler.RequireAdmin(cfg.AdminToken, categoryHandler.DeleteCategory))
+	http.HandleFunc("/admin/webhooks", handler.RequireAdmin(cfg.AdminToken, webhookHandler.GetSubscriptions))
+	http.HandleFunc("/admin/webhooks/{id}", handler.RequireAdmin(cfg.AdminToken, webhookHandler.GetSubscription))
+	http.HandleFunc("/admin/webhooks/create", handler.RequireAdmin(cfg.AdminToken, webhookHandler.CreateSubscription))
+	http.HandleFunc("/admin/webhooks/update/{id}", handler.RequireAdmin(cfg.AdminToken, webhookHandler.UpdateSubscription))
+	http.HandleFunc("/admin/webhooks/delete/{id}", handler.RequireAdmin(cfg.AdminToken, webhookHandler.DeleteSubscription))
+	http.HandleFunc("/admin/webhooks/enable/{id}", handler.RequireAdmin(cfg.AdminToken, webhookHandler.EnableSubscription))
+	http.HandleFunc("/admin/webhooks/deliveries/{id}", handler.RequireAdmin(cfg.AdminToken, webhookHandler.GetDeliveries))
+
+	subresources := http.NewServeMux()
+	subresources.HandleFunc("GET /auctions/{id}/stream", streamHandler.StreamAuction)
+	subresources.HandleFunc("POST /auctions/{id}/relist", auctionHandler.RelistAuction)
+	subresources.HandleFunc("GET /auctions/{id}/lineage", auctionHandler.GetRelistLineage)
 
 	log.Printf("Auction Service running on port %s", cfg.ServerPort)
-	log.Fatal(http.ListenAndServe(":"+cfg.ServerPort, nil))
+	log.Fatal(http.ListenAndServe(":"+cfg.ServerPort, handler.WithSubresources(subresources, http.DefaultServeMux)))
+}
+
+// importRatesFile loads the exchange rates of a CSV file.
+func importRatesFile(rates service.ExchangeRateService, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	parsed, err := service.ParseRatesCSV(file)
+	if err != nil {
+		return err
+	}
+	return rates.ImportRates(parsed)
+}
+
+// openBus connects to RabbitMQ, or starts an in-process bus when url is
+// memory:// so the service can run without a broker.
+func openBus(url string) (messaging.Bus, error) {
+	if url == "memory://" {
+		return messaging.NewMemoryBus(), nil
+	}
+	return rabbitmq.NewBus(url)
 }
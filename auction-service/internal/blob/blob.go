@@ -0,0 +1,92 @@
+// Package blob stores files, such as the images of auctions, in a local
+// directory or an S3-compatible bucket.
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// ErrNotFound is returned when there is no blob under a key.
+var ErrNotFound = errors.New("blob not found")
+
+// Store keeps blobs under slash-separated keys like "auctions/1/a1b2.jpg".
+type Store interface {
+	// Put stores data under key, replacing any blob there.
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	// Get opens the blob under key. The caller closes it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob under key. Deleting a missing blob is not
+	// an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// Open returns the store described by storeURL:
+//
+//	file://blobs                 (directory, relative to the working directory)
+//	file:///var/data/blobs       (absolute path)
+//	s3://key:secret@bucket?endpoint=http://minio:9000&region=us-east-1
+//
+// S3 buckets are addressed path-style, which AWS and S3-compatible servers
+// such as MinIO understand. The endpoint defaults to AWS in the region, and
+// create_bucket=true creates the bucket when it does not exist.
+func Open(ctx context.Context, storeURL string) (Store, error) {
+	scheme, rest, found := strings.Cut(storeURL, "://")
+	if !found {
+		return nil, fmt.Errorf("blob store url %q has no scheme", storeURL)
+	}
+
+	switch scheme {
+	case "file":
+		return NewFileSystem(rest)
+	case "s3":
+		u, err := url.Parse(storeURL)
+		if err != nil {
+			return nil, err
+		}
+		config := S3Config{
+			Bucket:   u.Host,
+			Endpoint: u.Query().Get("endpoint"),
+			Region:   u.Query().Get("region"),
+		}
+		if u.User != nil {
+			config.AccessKey = u.User.Username()
+			config.SecretKey, _ = u.User.Password()
+		}
+		store, err := NewS3(config)
+		if err != nil {
+			return nil, err
+		}
+		if u.Query().Get("create_bucket") == "true" {
+			if err := store.CreateBucket(ctx); err != nil {
+				return nil, err
+			}
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unsupported blob store scheme %q", scheme)
+	}
+}
+
+// ValidKey reports whether key is a clean relative path without "..", so it
+// cannot escape the directory or bucket of a store.
+func ValidKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == ".." || segment == "." {
+			return false
+		}
+	}
+	return !strings.ContainsAny(key, "\\\x00")
+}
+
+func invalidKey(key string) error {
+	return fmt.Errorf("invalid blob key %q", key)
+}
@@ -0,0 +1,58 @@
+package blob_test
+
+import (
+	"auction-service/internal/blob"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testStore checks the behaviour every Store must have.
+func testStore(t *testing.T, store blob.Store) {
+	t.Helper()
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "auctions/1/photo.jpg", "image/jpeg", []byte("first")))
+	require.NoError(t, store.Put(ctx, "auctions/1/photo.jpg", "image/jpeg", []byte("second")))
+	reader, err := store.Get(ctx, "auctions/1/photo.jpg")
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	reader.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(data))
+
+	require.NoError(t, store.Delete(ctx, "auctions/1/photo.jpg"))
+	_, err = store.Get(ctx, "auctions/1/photo.jpg")
+	assert.ErrorIs(t, err, blob.ErrNotFound)
+	assert.NoError(t, store.Delete(ctx, "auctions/1/photo.jpg"))
+
+	for _, key := range []string{"", "/etc/passwd", "../secret", "a/../../b", "a//b", `a\b`} {
+		assert.Error(t, store.Put(ctx, key, "text/plain", []byte("x")), key)
+		_, err := store.Get(ctx, key)
+		assert.Error(t, err, key)
+	}
+}
+
+func TestFileSystemStore(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "blobs")
+	store, err := blob.Open(context.Background(), "file://"+root)
+	require.NoError(t, err)
+	testStore(t, store)
+
+	require.NoError(t, store.Put(context.Background(), "auctions/2/a.png", "image/png", []byte("png")))
+	data, err := os.ReadFile(filepath.Join(root, "auctions", "2", "a.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "png", string(data))
+}
+
+func TestOpenUnsupported(t *testing.T) {
+	_, err := blob.Open(context.Background(), "ftp://example.com")
+	assert.Error(t, err)
+	_, err = blob.Open(context.Background(), "blobs")
+	assert.Error(t, err)
+}
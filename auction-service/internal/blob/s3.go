@@ -0,0 +1,234 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// S3Config describes an S3-compatible bucket.
+type S3Config struct {
+	// Endpoint is the base URL of the server, e.g. http://minio:9000.
+	// Empty means AWS in Region.
+	Endpoint string
+	// Region defaults to us-east-1.
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// Client sends the requests, http.DefaultClient when nil.
+	Client *http.Client
+}
+
+// S3 stores blobs as objects of an S3-compatible bucket, signing requests
+// with AWS Signature Version 4.
+type S3 struct {
+	config   S3Config
+	endpoint *url.URL
+	now      func() time.Time
+}
+
+// Ensure S3 implements Store
+var _ Store = (*S3)(nil)
+
+// NewS3 creates an S3 store.
+func NewS3(config S3Config) (*S3, error) {
+	if config.Bucket == "" {
+		return nil, errors.New("s3 bucket is required")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://s3." + config.Region + ".amazonaws.com"
+	}
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("s3 endpoint: %w", err)
+	}
+	return &S3{config: config, endpoint: endpoint, now: time.Now}, nil
+}
+
+// CreateBucket creates the bucket unless it exists already.
+func (s *S3) CreateBucket(ctx context.Context) error {
+	response, err := s.do(ctx, http.MethodPut, "", "", nil)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusConflict {
+		// BucketAlreadyOwnedByYou, or taken by somebody else, which the
+		// first Put will report.
+		return nil
+	}
+	return s3Error(response)
+}
+
+func (s *S3) Put(ctx context.Context, key, contentType string, data []byte) error {
+	if !ValidKey(key) {
+		return invalidKey(key)
+	}
+	response, err := s.do(ctx, http.MethodPut, key, contentType, data)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	return s3Error(response)
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if !ValidKey(key) {
+		return nil, invalidKey(key)
+	}
+	response, err := s.do(ctx, http.MethodGet, key, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode == http.StatusNotFound {
+		response.Body.Close()
+		return nil, ErrNotFound
+	}
+	if err := s3Error(response); err != nil {
+		response.Body.Close()
+		return nil, err
+	}
+	return response.Body, nil
+}
+
+// Delete removes an object. S3 answers 204 whether or not it existed.
+func (s *S3) Delete(ctx context.Context, key string) error {
+	if !ValidKey(key) {
+		return invalidKey(key)
+	}
+	response, err := s.do(ctx, http.MethodDelete, key, "", nil)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return s3Error(response)
+}
+
+func (s *S3) do(ctx context.Context, method, key, contentType string, body []byte) (*http.Response, error) {
+	target := *s.endpoint
+	target.Path = strings.TrimSuffix(target.Path, "/") + "/" + s.config.Bucket
+	if key != "" {
+		target.Path += "/" + key
+	}
+	request, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	SignV4(request, body, s.config.Region, s.config.AccessKey, s.config.SecretKey, s.now())
+	return s.config.Client.Do(request)
+}
+
+// s3Error returns nil for 2xx responses and an error with the status and
+// the start of the body, which holds the S3 error code, otherwise.
+func s3Error(response *http.Response) error {
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+	return fmt.Errorf("s3 %s %s: %s: %s", response.Request.Method, response.Request.URL.Path, response.Status, bytes.TrimSpace(body))
+}
+
+// SignV4 adds the x-amz-date, x-amz-content-sha256 and Authorization
+// headers of AWS Signature Version 4 for S3 to request, signing the host and
+// every header already set. See
+// https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html.
+func SignV4(request *http.Request, body []byte, region, accessKey, secretKey string, now time.Time) {
+	payloadHash := sha256.Sum256(body)
+	amzDate := now.UTC().Format("20060102T150405Z")
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+
+	headers := map[string]string{"host": request.URL.Host}
+	for name, values := range request.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		uriEncode(request.URL.Path, false),
+		canonicalQuery(request.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	date := amzDate[:8]
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	request.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// uriEncode percent-encodes s the way AWS expects: everything except
+// unreserved characters, and slashes too unless s is a path.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func canonicalQuery(query url.Values) string {
+	var pairs []string
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, "&")
+}
@@ -0,0 +1,46 @@
+// Package consumer holds the handlers for the events auction-service
+// subscribes to.
+package consumer
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+
+	"auction-service/internal/messaging"
+	"auction-service/internal/model"
+	"auction-service/internal/service"
+)
+
+// UserCreated creates a welcome auction for every new user.
+type UserCreated struct {
+	service service.AuctionService
+}
+
+func NewUserCreated(auctionService service.AuctionService) *UserCreated {
+	return &UserCreated{service: auctionService}
+}
+
+// Handle processes a user.created message. Messages that can never succeed,
+// because they are malformed or describe an invalid auction, are
+// acknowledged and logged; other errors are returned so they are redelivered.
+func (c *UserCreated) Handle(d messaging.Delivery) error {
+	var user model.User
+	if err := json.Unmarshal(d.Body, &user); err != nil {
+		log.Printf("Error decoding message: %v", err)
+		return nil
+	}
+
+	auction := model.Auction{
+		Item:   "Welcome Item for " + user.Name,
+		UserID: user.ID,
+	}
+	if _, err := c.service.CreateAuction(auction); err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			log.Printf("Error creating auction: %v", err)
+			return nil
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package consumer_test
+
+import (
+	"auction-service/internal/consumer"
+	"auction-service/internal/messaging"
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"auction-service/internal/service"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserCreatedOpensWelcomeAuction(t *testing.T) {
+	bus := messaging.NewMemoryBus()
+	defer bus.Close()
+	store := repository.NewMemoryStore()
+	auctionService := service.NewAuctionService(store.Auctions(), store)
+	relay := service.NewOutboxRelay(store, bus, "auction_events", 0)
+
+	var events []model.Event
+	bus.Subscribe("auction_events", func(d messaging.Delivery) error {
+		var event model.Event
+		require.NoError(t, json.Unmarshal(d.Body, &event))
+		events = append(events, event)
+		return nil
+	})
+	require.NoError(t, bus.Subscribe("user_created", consumer.NewUserCreated(auctionService).Handle))
+
+	bus.Publish("user_created", []byte(`{"id": 7, "name": "Ada"}`))
+	bus.Wait()
+	_, err := relay.Flush()
+	require.NoError(t, err)
+	bus.Wait()
+
+	auctions, _ := auctionService.GetAllAuctions()
+	if assert.Len(t, auctions, 1) {
+		assert.Equal(t, "Welcome Item for Ada", auctions[0].Item)
+		assert.Equal(t, 7, auctions[0].UserID)
+	}
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, model.EventAuctionCreated, events[0].Type)
+		assert.Equal(t, auctions[0].ID, events[0].AuctionID)
+	}
+}
+
+func TestUserCreatedAcknowledgesBadMessages(t *testing.T) {
+	store := repository.NewMemoryStore()
+	handler := consumer.NewUserCreated(service.NewAuctionService(store.Auctions(), store))
+
+	assert.NoError(t, handler.Handle(messaging.Delivery{Body: []byte("not json")}))
+	assert.NoError(t, handler.Handle(messaging.Delivery{Body: []byte(`{"name": "No ID"}`)}))
+
+	auctions, _ := store.Auctions().GetAllAuctions()
+	assert.Empty(t, auctions)
+}
@@ -0,0 +1,32 @@
+package consumer
+
+import (
+	"errors"
+	"log"
+
+	"auction-service/internal/messaging"
+	"auction-service/internal/service"
+)
+
+// WebhookEvents queues the auction events for the partners subscribed to
+// them.
+type WebhookEvents struct {
+	service service.WebhookService
+}
+
+func NewWebhookEvents(webhookService service.WebhookService) *WebhookEvents {
+	return &WebhookEvents{service: webhookService}
+}
+
+// Handle processes an auction event. Malformed messages are acknowledged and
+// logged; other errors are returned so they are redelivered.
+func (c *WebhookEvents) Handle(d messaging.Delivery) error {
+	if err := c.service.QueueEvent(d.Body); err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			log.Printf("Error decoding message: %v", err)
+			return nil
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package consumer_test
+
+import (
+	"auction-service/internal/consumer"
+	"auction-service/internal/messaging"
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"auction-service/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookEventsQueuesDeliveries(t *testing.T) {
+	bus := messaging.NewMemoryBus()
+	defer bus.Close()
+	store := repository.NewMemoryStore()
+	webhookService := service.NewWebhookService(store.Webhooks())
+	subscription, err := webhookService.CreateSubscription(model.WebhookSubscription{URL: "https://partner.example/hook", EventTypes: []string{model.EventAuctionClosed}})
+	require.NoError(t, err)
+	require.NoError(t, bus.Subscribe("auction_webhooks", consumer.NewWebhookEvents(webhookService).Handle))
+
+	bus.Publish("auction_webhooks", []byte(`not json`))
+	bus.Publish("auction_webhooks", []byte(`{"type": "bid.placed", "auction_id": 1}`))
+	bus.Publish("auction_webhooks", []byte(`{"type": "auction.closed", "auction_id": 1}`))
+	bus.Wait()
+
+	deliveries, err := webhookService.GetDeliveries(subscription.ID)
+	require.NoError(t, err)
+	if assert.Len(t, deliveries, 1) {
+		assert.Equal(t, model.EventAuctionClosed, deliveries[0].EventType)
+	}
+}
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// AdminTokenHeader carries the token that authorizes admin endpoints.
+const AdminTokenHeader = "X-Admin-Token"
+
+// RequireAdmin only lets requests carrying token in the AdminTokenHeader
+// through to next. An empty token disables the endpoint.
+func RequireAdmin(token string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		given := r.Header.Get(AdminTokenHeader)
+		if token == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			http.Error(w, "Admin token required", http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
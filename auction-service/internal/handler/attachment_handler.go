@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"auction-service/internal/blob"
+	"auction-service/internal/model"
+	"auction-service/internal/service"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// AttachmentHandler serves the photos and documents of auctions: uploads,
+// listings and the files themselves.
+type AttachmentHandler struct {
+	service service.AttachmentService
+	blobs   blob.Store
+}
+
+// NewAttachmentHandler creates an AttachmentHandler serving files out of
+// blobs.
+func NewAttachmentHandler(attachmentService service.AttachmentService, blobs blob.Store) *AttachmentHandler {
+	return &AttachmentHandler{service: attachmentService, blobs: blobs}
+}
+
+// UploadAttachments stores every "file" field of a multipart/form-data
+// request as an attachment of the auction. Files are stored one by one; when
+// one fails, those before it are kept.
+func (h *AttachmentHandler) UploadAttachments(w http.ResponseWriter, r *http.Request) {
+	auctionID, ok := auctionIDFromPath(w, r, `^/auctions/upload/(\d+)$`)
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+	reader, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "Expected a multipart/form-data request body", http.StatusBadRequest)
+		return
+	}
+
+	created := []model.Attachment{}
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			http.Error(w, "Invalid multipart request body", http.StatusBadRequest)
+			return
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+		attachment, err := h.service.AddAttachment(userID, auctionID, part.FileName(), part)
+		part.Close()
+		if err != nil {
+			writeServiceError(w, "uploading attachment", err)
+			return
+		}
+		created = append(created, attachment)
+	}
+	if len(created) == 0 {
+		http.Error(w, "No file field in the request body", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// GetAttachments lists the attachments of an auction.
+func (h *AttachmentHandler) GetAttachments(w http.ResponseWriter, r *http.Request) {
+	auctionID, ok := auctionIDFromPath(w, r, `^/auctions/attachments/(\d+)$`)
+	if !ok {
+		return
+	}
+
+	attachments, err := h.service.GetAttachments(auctionID)
+	if err != nil {
+		writeServiceError(w, "fetching attachments", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(attachments)
+}
+
+// DeleteAttachment removes an attachment of an auction of the caller.
+func (h *AttachmentHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	attachmentID, ok := idFromPath(w, r, `^/attachments/delete/(\d+)$`, "attachment")
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteAttachment(userID, attachmentID); err != nil {
+		writeServiceError(w, "deleting attachment", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ServeMedia serves the blob under the rest of a /media/ path. Blob keys
+// are random and never reused, so clients may cache them for good.
+func (h *AttachmentHandler) ServeMedia(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/media/")
+	if !blob.ValidKey(key) {
+		http.NotFound(w, r)
+		return
+	}
+	reader, err := h.blobs.Get(r.Context(), key)
+	if errors.Is(err, blob.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Printf("Error reading blob %s: %v", key, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	defer reader.Close()
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	io.Copy(w, reader)
+}
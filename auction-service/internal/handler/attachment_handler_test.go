@@ -0,0 +1,165 @@
+package handler_test
+
+import (
+	"auction-service/internal/blob"
+	"auction-service/internal/handler"
+	"auction-service/internal/model"
+	"auction-service/internal/service"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAttachmentService is a mock implementation of the AttachmentService interface
+type MockAttachmentService struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentService) AddAttachment(userID, auctionID int, filename string, file io.Reader) (model.Attachment, error) {
+	data, _ := io.ReadAll(file)
+	args := m.Called(userID, auctionID, filename, string(data))
+	return args.Get(0).(model.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentService) GetAttachments(auctionID int) ([]model.Attachment, error) {
+	args := m.Called(auctionID)
+	return args.Get(0).([]model.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentService) DeleteAttachment(userID, attachmentID int) error {
+	args := m.Called(userID, attachmentID)
+	return args.Error(0)
+}
+
+// multipartBody builds a multipart/form-data body with a "file" field per
+// file, keyed by file name, and a "note" field.
+func multipartBody(t *testing.T, files map[string]string) (*bytes.Buffer, string) {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	require.NoError(t, writer.WriteField("note", "ignored"))
+	for name, content := range files {
+		part, err := writer.CreateFormFile("file", name)
+		require.NoError(t, err)
+		part.Write([]byte(content))
+	}
+	require.NoError(t, writer.Close())
+	return &body, writer.FormDataContentType()
+}
+
+func TestUploadAttachments(t *testing.T) {
+	mockService := new(MockAttachmentService)
+	attachmentHandler := handler.NewAttachmentHandler(mockService, nil)
+	created := model.Attachment{ID: 3, AuctionID: 7, Filename: "manual.pdf", ContentType: "application/pdf", URL: "/media/auctions/7/a.pdf"}
+	mockService.On("AddAttachment", 2, 7, "manual.pdf", "%PDF-1.4").Return(created, nil)
+
+	body, contentType := multipartBody(t, map[string]string{"manual.pdf": "%PDF-1.4"})
+	req, _ := http.NewRequest("POST", "/auctions/upload/7", body)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-User-ID", "2")
+	rr := httptest.NewRecorder()
+	attachmentHandler.UploadAttachments(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var returned []model.Attachment
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returned))
+	assert.Equal(t, []model.Attachment{created}, returned)
+	mockService.AssertExpectations(t)
+}
+
+func TestUploadAttachmentsRejected(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"too large", service.ErrFileTooLarge, http.StatusRequestEntityTooLarge},
+		{"unsupported type", service.ErrUnsupportedFileType, http.StatusUnsupportedMediaType},
+		{"not owner", service.ErrForbidden, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAttachmentService)
+			attachmentHandler := handler.NewAttachmentHandler(mockService, nil)
+			mockService.On("AddAttachment", 2, 7, "photo.jpg", "data").Return(model.Attachment{}, tt.err)
+
+			body, contentType := multipartBody(t, map[string]string{"photo.jpg": "data"})
+			req, _ := http.NewRequest("POST", "/auctions/upload/7", body)
+			req.Header.Set("Content-Type", contentType)
+			req.Header.Set("X-User-ID", "2")
+			rr := httptest.NewRecorder()
+			attachmentHandler.UploadAttachments(rr, req)
+
+			assert.Equal(t, tt.code, rr.Code)
+		})
+	}
+
+	mockService := new(MockAttachmentService)
+	attachmentHandler := handler.NewAttachmentHandler(mockService, nil)
+	req, _ := http.NewRequest("POST", "/auctions/upload/7", bytes.NewBufferString(`{"file": "data"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", "2")
+	rr := httptest.NewRecorder()
+	attachmentHandler.UploadAttachments(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	body, contentType := multipartBody(t, nil)
+	req, _ = http.NewRequest("POST", "/auctions/upload/7", body)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-User-ID", "2")
+	rr = httptest.NewRecorder()
+	attachmentHandler.UploadAttachments(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "AddAttachment")
+}
+
+func TestDeleteAttachmentHandler(t *testing.T) {
+	mockService := new(MockAttachmentService)
+	attachmentHandler := handler.NewAttachmentHandler(mockService, nil)
+	mockService.On("DeleteAttachment", 2, 3).Return(nil)
+	mockService.On("DeleteAttachment", 2, 4).Return(service.ErrAttachmentNotFound)
+
+	req, _ := http.NewRequest("DELETE", "/attachments/delete/3", nil)
+	req.Header.Set("X-User-ID", "2")
+	rr := httptest.NewRecorder()
+	attachmentHandler.DeleteAttachment(rr, req)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	req, _ = http.NewRequest("DELETE", "/attachments/delete/4", nil)
+	req.Header.Set("X-User-ID", "2")
+	rr = httptest.NewRecorder()
+	attachmentHandler.DeleteAttachment(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestServeMedia(t *testing.T) {
+	blobs, err := blob.NewFileSystem(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, blobs.Put(context.Background(), "auctions/7/a_thumb.jpg", "image/jpeg", []byte("jpeg data")))
+	attachmentHandler := handler.NewAttachmentHandler(new(MockAttachmentService), blobs)
+
+	req, _ := http.NewRequest("GET", "/media/auctions/7/a_thumb.jpg", nil)
+	rr := httptest.NewRecorder()
+	attachmentHandler.ServeMedia(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "image/jpeg", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "nosniff", rr.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "jpeg data", rr.Body.String())
+
+	for _, path := range []string{"/media/auctions/7/missing.jpg", "/media/../secret", "/media/"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		rr := httptest.NewRecorder()
+		attachmentHandler.ServeMedia(rr, req)
+		assert.Equal(t, http.StatusNotFound, rr.Code, path)
+	}
+}
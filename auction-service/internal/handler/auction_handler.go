@@ -2,86 +2,187 @@ package handler
 
 import (
 	"auction-service/internal/model"
-	"auction-service/internal/repository"
+	"auction-service/internal/service"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"regexp"
 	"strconv"
-
-	"gorm.io/gorm"
+	"strings"
+	"time"
 )
 
-type AuctionRepository interface {
-	GetAllAuctions() ([]model.Auction, error)
-	GetAuctionByID(id int) (model.Auction, error)
-	CreateAuction(auction model.Auction) (model.Auction, error)
-	UpdateAuction(auction model.Auction) error
-	DeleteAuction(id int) error
-}
+// UserIDHeader carries the ID of the user performing the request.
+const UserIDHeader = "X-User-ID"
 
 type AuctionHandler struct {
-	repo AuctionRepository
+	service service.AuctionService
+	rates   service.ExchangeRateService
+}
+
+// HandlerOption enables optional features of an AuctionHandler.
+type HandlerOption func(*AuctionHandler)
+
+// WithExchangeRates lets clients ask for auction prices in another currency
+// with the display_currency query parameter.
+func WithExchangeRates(rates service.ExchangeRateService) HandlerOption {
+	return func(h *AuctionHandler) { h.rates = rates }
+}
+
+func NewAuctionHandler(auctionService service.AuctionService, opts ...HandlerOption) *AuctionHandler {
+	h := &AuctionHandler{service: auctionService}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// bidRequest carries a decimal amount, as a JSON string or number, in the
+// currency of the auction.
+type bidRequest struct {
+	Amount   json.Number `json:"amount"`
+	Currency string      `json:"currency"`
 }
 
-func NewAuctionHandler(repo AuctionRepository) *AuctionHandler {
-	return &AuctionHandler{repo: repo}
+type proxyBidRequest struct {
+	MaxAmount json.Number `json:"max_amount"`
+	Currency  string      `json:"currency"`
 }
 
-func InitAuctionRepository(db *gorm.DB) repository.AuctionRepository {
-	auctionRepository := repository.NewAuctionRepository(db)
-	return auctionRepository
+type withdrawBidRequest struct {
+	Reason string `json:"reason"`
 }
 
+// scheduleRequest sets when a new auction opens. Without a start time it
+// opens right away.
+type scheduleRequest struct {
+	StartsAt *time.Time `json:"starts_at"`
+}
+
+// GetAllAuctions lists the auctions. The category query parameter, an ID or
+// slug, keeps the auctions in that category and its subcategories, and
+// attr.<name> parameters those with the given attribute values.
 func (h *AuctionHandler) GetAllAuctions(w http.ResponseWriter, r *http.Request) {
-	auctions, err := h.repo.GetAllAuctions()
+	filter := auctionFilterFromQuery(r)
+	var auctions []model.Auction
+	var err error
+	if filter.Category != "" || filter.Attributes != nil {
+		auctions, err = h.service.FindAuctions(filter)
+	} else {
+		auctions, err = h.service.GetAllAuctions()
+	}
+	if errors.Is(err, service.ErrCategoryNotFound) || errors.Is(err, service.ErrInvalidInput) {
+		writeServiceError(w, "fetching auctions", err)
+		return
+	}
 	if err != nil {
 		log.Printf("Error fetching auctions: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
+	for i := range auctions {
+		converted, err := h.withDisplayPrices(r, auctions[i])
+		if err != nil {
+			writeServiceError(w, "converting prices", err)
+			return
+		}
+		auctions[i] = converted
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(auctions)
 }
 
-func (h *AuctionHandler) GetAuctionByID(w http.ResponseWriter, r *http.Request) {
-	re := regexp.MustCompile(`^/auctions/(\d+)$`)
-	matches := re.FindStringSubmatch(r.URL.Path)
-	if len(matches) != 2 {
-		http.Error(w, "Invalid auction ID", http.StatusBadRequest)
-		return
+// SearchAuctions finds auctions by the words in the q query parameter, best
+// match first, with the item and a snippet of the description highlighted.
+// It takes the filters of GetAllAuctions and a limit.
+func (h *AuctionHandler) SearchAuctions(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
 	}
 
-	auctionID, err := strconv.Atoi(matches[1])
+	results, err := h.service.SearchAuctions(r.URL.Query().Get("q"), auctionFilterFromQuery(r), limit)
 	if err != nil {
-		http.Error(w, "Invalid auction ID", http.StatusBadRequest)
+		writeServiceError(w, "searching auctions", err)
 		return
 	}
+	for i := range results {
+		converted, err := h.withDisplayPrices(r, results[i].Auction)
+		if err != nil {
+			writeServiceError(w, "converting prices", err)
+			return
+		}
+		results[i].Auction = converted
+	}
 
-	auction, err := h.repo.GetAuctionByID(auctionID)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+// auctionFilterFromQuery reads the category and attr.<name> query
+// parameters.
+func auctionFilterFromQuery(r *http.Request) service.AuctionFilter {
+	var filter service.AuctionFilter
+	query := r.URL.Query()
+	for name, values := range query {
+		if attribute, ok := strings.CutPrefix(name, "attr."); ok {
+			if filter.Attributes == nil {
+				filter.Attributes = map[string]string{}
+			}
+			filter.Attributes[attribute] = values[0]
+		}
+	}
+	filter.Category = query.Get("category")
+	return filter
+}
+
+func (h *AuctionHandler) GetAuctionByID(w http.ResponseWriter, r *http.Request) {
+	auctionID, ok := auctionIDFromPath(w, r, `^/auctions/(\d+)$`)
+	if !ok {
+		return
+	}
+
+	auction, err := h.service.GetAuctionByID(auctionID)
 	if err != nil {
 		log.Printf("Error fetching auction by ID: %v", err)
 		http.Error(w, "Auction not found", http.StatusNotFound)
 		return
 	}
+	if auction, err = h.withDisplayPrices(r, auction); err != nil {
+		writeServiceError(w, "converting prices", err)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(auction)
 }
 
+// CreateAuction lists an auction for the requesting user, who becomes its
+// seller whatever UserID the body holds.
 func (h *AuctionHandler) CreateAuction(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
 	var newAuction model.Auction
 	if err := json.NewDecoder(r.Body).Decode(&newAuction); err != nil {
 		http.Error(w, "Invalid JSON request body", http.StatusBadRequest)
 		return
 	}
+	newAuction.UserID = userID
 
-	createdAuction, err := h.repo.CreateAuction(newAuction)
+	createdAuction, err := h.service.CreateAuction(newAuction)
 	if err != nil {
-		log.Printf("Error creating auction: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		writeServiceError(w, "creating auction", err)
 		return
 	}
 
@@ -91,56 +192,392 @@ func (h *AuctionHandler) CreateAuction(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *AuctionHandler) UpdateAuction(w http.ResponseWriter, r *http.Request) {
-	re := regexp.MustCompile(`^/auctions/update/(\d+)$`)
-	matches := re.FindStringSubmatch(r.URL.Path)
-	if len(matches) != 2 {
-		http.Error(w, "Invalid auction ID", http.StatusBadRequest)
+	auctionID, ok := auctionIDFromPath(w, r, `^/auctions/update/(\d+)$`)
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var update model.AuctionUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, "Invalid JSON request body", http.StatusBadRequest)
+		return
+	}
+
+	update.ID = auctionID
+	auction, err := h.service.UpdateAuction(userID, update)
+	if err != nil {
+		writeServiceError(w, "updating auction", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(auction)
+}
+
+func (h *AuctionHandler) DeleteAuction(w http.ResponseWriter, r *http.Request) {
+	auctionID, ok := auctionIDFromPath(w, r, `^/auctions/delete/(\d+)$`)
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteAuction(userID, auctionID); err != nil {
+		writeServiceError(w, "deleting auction", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CloseAuction handles the request of a seller to close their auction.
+func (h *AuctionHandler) CloseAuction(w http.ResponseWriter, r *http.Request) {
+	auctionID, ok := auctionIDFromPath(w, r, `^/auctions/close/(\d+)$`)
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
 		return
 	}
 
-	auctionID, err := strconv.Atoi(matches[1])
+	auction, err := h.service.CloseAuction(userID, auctionID)
 	if err != nil {
-		http.Error(w, "Invalid auction ID", http.StatusBadRequest)
+		writeServiceError(w, "closing auction", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(auction)
+}
+
+// RelistAuction handles the request of a seller to list their unsold closed
+// auction again, scheduled at the start time of the body.
+func (h *AuctionHandler) RelistAuction(w http.ResponseWriter, r *http.Request) {
+	auctionID, ok := idFromPattern(w, r, "auction")
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
 		return
 	}
 
-	var updatedAuction model.Auction
-	if err := json.NewDecoder(r.Body).Decode(&updatedAuction); err != nil {
+	var request scheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
 		http.Error(w, "Invalid JSON request body", http.StatusBadRequest)
 		return
 	}
 
-	updatedAuction.ID = int(auctionID)
-	if err := h.repo.UpdateAuction(updatedAuction); err != nil {
-		log.Printf("Error updating auction: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	auction, err := h.service.RelistAuction(userID, auctionID, request.StartsAt)
+	if err != nil {
+		writeServiceError(w, "relisting auction", err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(updatedAuction)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(auction)
 }
 
-func (h *AuctionHandler) DeleteAuction(w http.ResponseWriter, r *http.Request) {
-	re := regexp.MustCompile(`^/auctions/delete/(\d+)$`)
-	matches := re.FindStringSubmatch(r.URL.Path)
-	if len(matches) != 2 {
-		http.Error(w, "Invalid auction ID", http.StatusBadRequest)
+// GetRelistLineage handles the request for the original listing of an
+// auction and all its relists.
+func (h *AuctionHandler) GetRelistLineage(w http.ResponseWriter, r *http.Request) {
+	auctionID, ok := idFromPattern(w, r, "auction")
+	if !ok {
 		return
 	}
 
-	auctionID, err := strconv.Atoi(matches[1])
+	auctions, err := h.service.GetRelistLineage(auctionID)
 	if err != nil {
-		http.Error(w, "Invalid auction ID", http.StatusBadRequest)
+		writeServiceError(w, "fetching relist lineage", err)
 		return
 	}
 
-	if err := h.repo.DeleteAuction(auctionID); err != nil {
-		log.Printf("Error deleting auction: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(auctions)
+}
+
+// PlaceBid handles a bid from the requesting user on an auction.
+func (h *AuctionHandler) PlaceBid(w http.ResponseWriter, r *http.Request) {
+	auctionID, ok := auctionIDFromPath(w, r, `^/auctions/bid/(\d+)$`)
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
-	fmt.Fprintf(w, "Auction deleted successfully")
+	var bid bidRequest
+	if err := json.NewDecoder(r.Body).Decode(&bid); err != nil {
+		http.Error(w, "Invalid JSON request body", http.StatusBadRequest)
+		return
+	}
+	amount, err := model.ParseMoney(bid.Amount.String(), bid.Currency)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	auction, err := h.service.PlaceBid(auctionID, userID, amount)
+	if err != nil {
+		writeServiceError(w, "placing bid", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(auction)
+}
+
+// PlaceProxyBid sets the hidden maximum the requesting user is willing to pay
+// for an auction.
+func (h *AuctionHandler) PlaceProxyBid(w http.ResponseWriter, r *http.Request) {
+	auctionID, ok := auctionIDFromPath(w, r, `^/auctions/proxy-bid/(\d+)$`)
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var proxyBid proxyBidRequest
+	if err := json.NewDecoder(r.Body).Decode(&proxyBid); err != nil {
+		http.Error(w, "Invalid JSON request body", http.StatusBadRequest)
+		return
+	}
+	maxAmount, err := model.ParseMoney(proxyBid.MaxAmount.String(), proxyBid.Currency)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	auction, err := h.service.PlaceProxyBid(auctionID, userID, maxAmount)
+	if err != nil {
+		writeServiceError(w, "placing proxy bid", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(auction)
+}
+
+// BuyNow handles the request of a user to buy an auction at its buy-now price.
+func (h *AuctionHandler) BuyNow(w http.ResponseWriter, r *http.Request) {
+	auctionID, ok := auctionIDFromPath(w, r, `^/auctions/buy-now/(\d+)$`)
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	auction, err := h.service.BuyNow(auctionID, userID)
+	if err != nil {
+		writeServiceError(w, "buying auction", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(auction)
+}
+
+// GetBids handles the request for the bid history of an auction.
+func (h *AuctionHandler) GetBids(w http.ResponseWriter, r *http.Request) {
+	auctionID, ok := auctionIDFromPath(w, r, `^/auctions/bids/(\d+)$`)
+	if !ok {
+		return
+	}
+
+	bids, err := h.service.GetBids(auctionID)
+	if err != nil {
+		writeServiceError(w, "fetching bids", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(bids)
+}
+
+// RetractBid handles the request of a bidder to retract one of their bids.
+// The body, with the reason, is optional.
+func (h *AuctionHandler) RetractBid(w http.ResponseWriter, r *http.Request) {
+	bidID, ok := idFromPath(w, r, `^/bids/retract/(\d+)$`, "bid")
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var request withdrawBidRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid JSON request body", http.StatusBadRequest)
+		return
+	}
+
+	auction, err := h.service.RetractBid(userID, bidID, request.Reason)
+	if err != nil {
+		writeServiceError(w, "retracting bid", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(auction)
+}
+
+// CancelBid handles the request of a seller to cancel a bid on their auction.
+func (h *AuctionHandler) CancelBid(w http.ResponseWriter, r *http.Request) {
+	bidID, ok := idFromPath(w, r, `^/bids/cancel/(\d+)$`, "bid")
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var request withdrawBidRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON request body", http.StatusBadRequest)
+		return
+	}
+
+	auction, err := h.service.CancelBid(userID, bidID, request.Reason)
+	if err != nil {
+		writeServiceError(w, "cancelling bid", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(auction)
+}
+
+// GetBidAudit handles the request for the log of withdrawn bids of an
+// auction.
+func (h *AuctionHandler) GetBidAudit(w http.ResponseWriter, r *http.Request) {
+	auctionID, ok := auctionIDFromPath(w, r, `^/auctions/bid-audit/(\d+)$`)
+	if !ok {
+		return
+	}
+
+	entries, err := h.service.GetBidAudit(auctionID)
+	if err != nil {
+		writeServiceError(w, "fetching bid audit", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+// withDisplayPrices converts the prices of auction to the currency asked for
+// with the display_currency query parameter, if any. Every read fails alike
+// when a rate is missing, lists included, rather than leaving some auctions
+// unconverted.
+func (h *AuctionHandler) withDisplayPrices(r *http.Request, auction model.Auction) (model.Auction, error) {
+	currency := r.URL.Query().Get("display_currency")
+	if currency == "" {
+		return auction, nil
+	}
+	if h.rates == nil {
+		return auction, fmt.Errorf("%w: display currencies are not available", service.ErrInvalidInput)
+	}
+	return h.rates.ConvertForDisplay(auction, currency)
+}
+
+// auctionIDFromPath extracts the auction ID with pattern, writing a 400
+// response when it is missing.
+func auctionIDFromPath(w http.ResponseWriter, r *http.Request, pattern string) (int, bool) {
+	return idFromPath(w, r, pattern, "auction")
+}
+
+// idFromPath extracts the ID of a resource with pattern, writing a 400
+// response when it is missing.
+func idFromPath(w http.ResponseWriter, r *http.Request, pattern, resource string) (int, bool) {
+	re := regexp.MustCompile(pattern)
+	matches := re.FindStringSubmatch(r.URL.Path)
+	if len(matches) != 2 {
+		http.Error(w, "Invalid "+resource+" ID", http.StatusBadRequest)
+		return 0, false
+	}
+
+	id, err := strconv.Atoi(matches[1])
+	if err != nil {
+		http.Error(w, "Invalid "+resource+" ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// idFromPattern reads the ID of a resource from the {id} wildcard of the
+// route pattern, writing a 400 response when it is not a number.
+func idFromPattern(w http.ResponseWriter, r *http.Request, resource string) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid "+resource+" ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// userIDFromRequest reads the acting user from the UserIDHeader, writing a
+// 401 response when it is missing or malformed.
+func userIDFromRequest(w http.ResponseWriter, r *http.Request) (int, bool) {
+	userID, err := strconv.Atoi(r.Header.Get(UserIDHeader))
+	if err != nil || userID <= 0 {
+		http.Error(w, "Missing or invalid "+UserIDHeader+" header", http.StatusUnauthorized)
+		return 0, false
+	}
+	return userID, true
+}
+
+// writeServiceError maps service errors to HTTP status codes.
+func writeServiceError(w http.ResponseWriter, action string, err error) {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		http.Error(w, "Auction not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrBidNotFound):
+		http.Error(w, "Bid not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrOrderNotFound):
+		http.Error(w, "Order not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrOfferNotFound):
+		http.Error(w, "Offer not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrSubscriptionNotFound):
+		http.Error(w, "Webhook subscription not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrCategoryNotFound):
+		http.Error(w, "Category not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrAttachmentNotFound):
+		http.Error(w, "Attachment not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrTemplateNotFound):
+		http.Error(w, "Auction template not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, service.ErrInvalidInput):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrAuctionClosed), errors.Is(err, service.ErrBuyNowUnavailable),
+		errors.Is(err, service.ErrRetractionNotAllowed), errors.Is(err, service.ErrOrderState),
+		errors.Is(err, service.ErrOfferClosed), errors.Is(err, service.ErrNoRunnerUp),
+		errors.Is(err, service.ErrCategoryInUse), errors.Is(err, service.ErrAuctionNotStarted),
+		errors.Is(err, service.ErrNotRelistable):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, service.ErrRateUnavailable):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	case errors.Is(err, service.ErrFileTooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, service.ErrUnsupportedFileType):
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+	default:
+		log.Printf("Error %s: %v", action, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
 }
@@ -3,76 +3,179 @@ package handler_test
 import (
 	"auction-service/internal/handler"
 	"auction-service/internal/model"
+	"auction-service/internal/service"
 	"bytes"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
-// MockAuctionRepository is a mock implementation of the AuctionRepository interface
-type MockAuctionRepository struct {
+// MockAuctionService is a mock implementation of the AuctionService interface
+type MockAuctionService struct {
 	mock.Mock
 }
 
-func (m *MockAuctionRepository) GetAllAuctions() ([]model.Auction, error) {
+func (m *MockAuctionService) GetAllAuctions() ([]model.Auction, error) {
 	args := m.Called()
 	return args.Get(0).([]model.Auction), args.Error(1)
 }
 
-func (m *MockAuctionRepository) GetAuctionByID(id int) (model.Auction, error) {
+func (m *MockAuctionService) FindAuctions(filter service.AuctionFilter) ([]model.Auction, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]model.Auction), args.Error(1)
+}
+
+func (m *MockAuctionService) SearchAuctions(query string, filter service.AuctionFilter, limit int) ([]model.SearchResult, error) {
+	args := m.Called(query, filter, limit)
+	return args.Get(0).([]model.SearchResult), args.Error(1)
+}
+
+func (m *MockAuctionService) GetAuctionByID(id int) (model.Auction, error) {
 	args := m.Called(id)
 	return args.Get(0).(model.Auction), args.Error(1)
 }
 
-func (m *MockAuctionRepository) CreateAuction(auction model.Auction) (model.Auction, error) {
+func (m *MockAuctionService) CreateAuction(auction model.Auction) (model.Auction, error) {
 	args := m.Called(auction)
 	return args.Get(0).(model.Auction), args.Error(1)
 }
 
-func (m *MockAuctionRepository) UpdateAuction(auction model.Auction) error {
-	args := m.Called(auction)
-	return args.Error(0)
+func (m *MockAuctionService) RelistAuction(userID, id int, startsAt *time.Time) (model.Auction, error) {
+	args := m.Called(userID, id, startsAt)
+	return args.Get(0).(model.Auction), args.Error(1)
 }
 
-func (m *MockAuctionRepository) DeleteAuction(id int) error {
+func (m *MockAuctionService) GetRelistLineage(id int) ([]model.Auction, error) {
 	args := m.Called(id)
+	return args.Get(0).([]model.Auction), args.Error(1)
+}
+
+func (m *MockAuctionService) UpdateAuction(userID int, update model.AuctionUpdate) (model.Auction, error) {
+	args := m.Called(userID, update)
+	return args.Get(0).(model.Auction), args.Error(1)
+}
+
+func (m *MockAuctionService) DeleteAuction(userID, id int) error {
+	args := m.Called(userID, id)
 	return args.Error(0)
 }
 
+func (m *MockAuctionService) CloseAuction(userID, id int) (model.Auction, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(model.Auction), args.Error(1)
+}
+
+func (m *MockAuctionService) PlaceBid(auctionID, bidderID int, bidAmount model.Money) (model.Auction, error) {
+	args := m.Called(auctionID, bidderID, bidAmount)
+	return args.Get(0).(model.Auction), args.Error(1)
+}
+
+func (m *MockAuctionService) PlaceProxyBid(auctionID, bidderID int, maxAmount model.Money) (model.Auction, error) {
+	args := m.Called(auctionID, bidderID, maxAmount)
+	return args.Get(0).(model.Auction), args.Error(1)
+}
+
+func (m *MockAuctionService) BuyNow(auctionID, buyerID int) (model.Auction, error) {
+	args := m.Called(auctionID, buyerID)
+	return args.Get(0).(model.Auction), args.Error(1)
+}
+
+func (m *MockAuctionService) GetBids(auctionID int) ([]model.Bid, error) {
+	args := m.Called(auctionID)
+	return args.Get(0).([]model.Bid), args.Error(1)
+}
+
+func (m *MockAuctionService) RetractBid(bidderID, bidID int, reason string) (model.Auction, error) {
+	args := m.Called(bidderID, bidID, reason)
+	return args.Get(0).(model.Auction), args.Error(1)
+}
+
+func (m *MockAuctionService) CancelBid(sellerID, bidID int, reason string) (model.Auction, error) {
+	args := m.Called(sellerID, bidID, reason)
+	return args.Get(0).(model.Auction), args.Error(1)
+}
+
+func (m *MockAuctionService) GetBidAudit(auctionID int) ([]model.BidAuditEntry, error) {
+	args := m.Called(auctionID)
+	return args.Get(0).([]model.BidAuditEntry), args.Error(1)
+}
+
 func TestCreateAuction(t *testing.T) {
-	mockRepo := new(MockAuctionRepository)
+	mockService := new(MockAuctionService)
 	auction := model.Auction{Item: "Test Item", UserID: 1}
-	mockRepo.On("CreateAuction", auction).Return(auction, nil)
+	mockService.On("CreateAuction", auction).Return(auction, nil)
 
-	auctionHandler := handler.NewAuctionHandler(mockRepo)
+	auctionHandler := handler.NewAuctionHandler(mockService)
 
-	reqBody, _ := json.Marshal(auction)
+	// The seller comes from the header, not the body.
+	reqBody, _ := json.Marshal(model.Auction{Item: "Test Item", UserID: 9})
 	req, err := http.NewRequest("POST", "/auctions", bytes.NewBuffer(reqBody))
 	if err != nil {
 		t.Fatal(err)
 	}
+	req.Header.Set(handler.UserIDHeader, "1")
 
 	rr := httptest.NewRecorder()
 	handler := http.HandlerFunc(auctionHandler.CreateAuction)
 	handler.ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusCreated, rr.Code)
-	mockRepo.AssertExpectations(t)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateAuctionWithoutUser(t *testing.T) {
+	mockService := new(MockAuctionService)
+	auctionHandler := handler.NewAuctionHandler(mockService)
+
+	req, err := http.NewRequest("POST", "/auctions/create", bytes.NewBufferString(`{"Item": "Test Item", "UserID": 1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(auctionHandler.CreateAuction)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	mockService.AssertNotCalled(t, "CreateAuction", mock.Anything)
+}
+
+func TestCreateAuctionInvalid(t *testing.T) {
+	mockService := new(MockAuctionService)
+	auction := model.Auction{UserID: 1}
+	mockService.On("CreateAuction", auction).Return(model.Auction{}, service.ErrInvalidInput)
+
+	auctionHandler := handler.NewAuctionHandler(mockService)
+
+	reqBody, _ := json.Marshal(auction)
+	req, err := http.NewRequest("POST", "/auctions/create", bytes.NewBuffer(reqBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(handler.UserIDHeader, "1")
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(auctionHandler.CreateAuction)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
 }
 
 func TestGetAuctionByID(t *testing.T) {
-	mockRepo := new(MockAuctionRepository)
+	mockService := new(MockAuctionService)
 
 	auction := model.Auction{Item: "Test Item", UserID: 1}
 
-	mockRepo.On("GetAuctionByID", 1).Return(auction, nil)
+	mockService.On("GetAuctionByID", 1).Return(auction, nil)
 
-	auctionHandler := handler.NewAuctionHandler(mockRepo)
+	auctionHandler := handler.NewAuctionHandler(mockService)
 
 	req, err := http.NewRequest("GET", "/auctions/1", nil)
 	if err != nil {
@@ -97,25 +200,45 @@ func TestGetAuctionByID(t *testing.T) {
 	assert.Equal(t, auction.Item, returnedAuction.Item)
 	assert.Equal(t, auction.UserID, returnedAuction.UserID)
 
-	mockRepo.AssertExpectations(t)
+	mockService.AssertExpectations(t)
 }
+
 func TestUpdateAuction(t *testing.T) {
-	mockRepo := new(MockAuctionRepository)
-	initialAuction := model.Auction{ID: 1, Item: "Initial Item", UserID: 1}
+	mockService := new(MockAuctionService)
 	updatedAuction := model.Auction{ID: 1, Item: "Updated Item", UserID: 1}
 
-	mockRepo.On("CreateAuction", initialAuction).Return(initialAuction, nil)
+	// Prices left out of the body reach the service as nil, not as zero.
+	mockService.On("UpdateAuction", 1, model.AuctionUpdate{ID: 1, Item: "Updated Item"}).Return(updatedAuction, nil)
 
-	mockRepo.On("UpdateAuction", updatedAuction).Return(nil)
+	auctionHandler := handler.NewAuctionHandler(mockService)
 
-	auctionHandler := handler.NewAuctionHandler(mockRepo)
+	req, err := http.NewRequest("PUT", "/auctions/update/1", bytes.NewBufferString(`{"Item": "Updated Item"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(handler.UserIDHeader, "1")
 
-	createdAuction, err := mockRepo.CreateAuction(initialAuction)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(auctionHandler.UpdateAuction)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var returnedAuction model.Auction
+	err = json.Unmarshal(rr.Body.Bytes(), &returnedAuction)
 	if err != nil {
 		t.Fatal(err)
 	}
+	assert.Equal(t, updatedAuction.Item, returnedAuction.Item)
 
-	reqBody, _ := json.Marshal(updatedAuction)
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateAuctionRequiresUser(t *testing.T) {
+	mockService := new(MockAuctionService)
+	auctionHandler := handler.NewAuctionHandler(mockService)
+
+	reqBody, _ := json.Marshal(model.Auction{Item: "Updated Item"})
 	req, err := http.NewRequest("PUT", "/auctions/update/1", bytes.NewBuffer(reqBody))
 	if err != nil {
 		t.Fatal(err)
@@ -125,23 +248,42 @@ func TestUpdateAuction(t *testing.T) {
 	handler := http.HandlerFunc(auctionHandler.UpdateAuction)
 	handler.ServeHTTP(rr, req)
 
-	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	mockService.AssertNotCalled(t, "UpdateAuction", mock.Anything, mock.Anything)
+}
 
-	assert.NotEqual(t, updatedAuction.Item, createdAuction.Item)
-	assert.Equal(t, updatedAuction.UserID, createdAuction.UserID)
+func TestUpdateAuctionNotOwner(t *testing.T) {
+	mockService := new(MockAuctionService)
+	updatedAuction := model.AuctionUpdate{ID: 1, Item: "Updated Item"}
 
-	mockRepo.AssertExpectations(t)
+	mockService.On("UpdateAuction", 2, updatedAuction).Return(model.Auction{}, service.ErrForbidden)
+
+	auctionHandler := handler.NewAuctionHandler(mockService)
+
+	reqBody, _ := json.Marshal(updatedAuction)
+	req, err := http.NewRequest("PUT", "/auctions/update/1", bytes.NewBuffer(reqBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(handler.UserIDHeader, "2")
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(auctionHandler.UpdateAuction)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	mockService.AssertExpectations(t)
 }
 
 func TestGetAllAuctions(t *testing.T) {
-	mockRepo := new(MockAuctionRepository)
+	mockService := new(MockAuctionService)
 	auctions := []model.Auction{
 		{Item: "Test Item 1", UserID: 1},
 		{Item: "Test Item 2", UserID: 2},
 	}
-	mockRepo.On("GetAllAuctions").Return(auctions, nil)
+	mockService.On("GetAllAuctions").Return(auctions, nil)
 
-	auctionHandler := handler.NewAuctionHandler(mockRepo)
+	auctionHandler := handler.NewAuctionHandler(mockService)
 
 	req, err := http.NewRequest("GET", "/auctions", nil)
 	if err != nil {
@@ -161,17 +303,74 @@ func TestGetAllAuctions(t *testing.T) {
 	}
 
 	assert.Equal(t, auctions, returnedAuctions)
-	mockRepo.AssertExpectations(t)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetAllAuctionsFiltered(t *testing.T) {
+	mockService := new(MockAuctionService)
+	category := 3
+	auctions := []model.Auction{{ID: 1, Item: "Runners", UserID: 1, CategoryID: &category, Attributes: map[string]string{"size": "42"}}}
+	mockService.On("FindAuctions", service.AuctionFilter{Category: "sneakers", Attributes: map[string]string{"size": "42"}}).Return(auctions, nil)
+	mockService.On("FindAuctions", service.AuctionFilter{Category: "garden"}).Return([]model.Auction(nil), service.ErrCategoryNotFound)
+	auctionHandler := handler.NewAuctionHandler(mockService)
+
+	req, _ := http.NewRequest("GET", "/auctions?category=sneakers&attr.size=42", nil)
+	rr := httptest.NewRecorder()
+	auctionHandler.GetAllAuctions(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var returnedAuctions []model.Auction
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returnedAuctions))
+	assert.Equal(t, auctions, returnedAuctions)
+
+	req, _ = http.NewRequest("GET", "/auctions?category=garden", nil)
+	rr = httptest.NewRecorder()
+	auctionHandler.GetAllAuctions(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestSearchAuctions(t *testing.T) {
+	mockService := new(MockAuctionService)
+	results := []model.SearchResult{{
+		Auction:         model.Auction{ID: 1, Item: "Red boots", UserID: 1},
+		Rank:            0.6,
+		HighlightedItem: "<mark>Red</mark> boots",
+	}}
+	mockService.On("SearchAuctions", "red boo", service.AuctionFilter{Category: "shoes"}, 5).Return(results, nil)
+	mockService.On("SearchAuctions", "", service.AuctionFilter{}, 0).Return([]model.SearchResult(nil), service.ErrInvalidInput)
+	auctionHandler := handler.NewAuctionHandler(mockService)
+
+	req, _ := http.NewRequest("GET", "/auctions/search?q=red+boo&category=shoes&limit=5", nil)
+	rr := httptest.NewRecorder()
+	auctionHandler.SearchAuctions(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var returned []model.SearchResult
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returned))
+	assert.Equal(t, results, returned)
+
+	req, _ = http.NewRequest("GET", "/auctions/search", nil)
+	rr = httptest.NewRecorder()
+	auctionHandler.SearchAuctions(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	req, _ = http.NewRequest("GET", "/auctions/search?q=red&limit=many", nil)
+	rr = httptest.NewRecorder()
+	auctionHandler.SearchAuctions(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
 }
 
 func TestDeleteAuction(t *testing.T) {
-	mockRepo := new(MockAuctionRepository)
+	mockService := new(MockAuctionService)
 	auction := model.Auction{ID: 1, Item: "Test Item", UserID: 1}
 
 	// Mock the CreateAuction method
-	mockRepo.On("CreateAuction", auction).Return(auction, nil)
+	mockService.On("CreateAuction", auction).Return(auction, nil)
 
-	auctionHandler := handler.NewAuctionHandler(mockRepo)
+	auctionHandler := handler.NewAuctionHandler(mockService)
 
 	// Step 1: Create an auction
 	auctionJSON, _ := json.Marshal(auction)
@@ -180,6 +379,7 @@ func TestDeleteAuction(t *testing.T) {
 		t.Fatal(err)
 	}
 	reqCreate.Header.Set("Content-Type", "application/json")
+	reqCreate.Header.Set(handler.UserIDHeader, "1")
 
 	rrCreate := httptest.NewRecorder()
 	createHandler := http.HandlerFunc(auctionHandler.CreateAuction)
@@ -194,13 +394,14 @@ func TestDeleteAuction(t *testing.T) {
 	}
 
 	// Mock the DeleteAuction method
-	mockRepo.On("DeleteAuction", createdAuction.ID).Return(nil)
+	mockService.On("DeleteAuction", createdAuction.UserID, createdAuction.ID).Return(nil)
 
 	// Step 2: Delete the created auction
 	reqDelete, err := http.NewRequest("DELETE", "/auctions/delete/"+strconv.Itoa(int(createdAuction.ID)), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
+	reqDelete.Header.Set(handler.UserIDHeader, strconv.Itoa(createdAuction.UserID))
 
 	rrDelete := httptest.NewRecorder()
 	deleteHandler := http.HandlerFunc(auctionHandler.DeleteAuction)
@@ -208,5 +409,293 @@ func TestDeleteAuction(t *testing.T) {
 
 	assert.Equal(t, http.StatusNoContent, rrDelete.Code)
 
-	mockRepo.AssertExpectations(t)
+	mockService.AssertExpectations(t)
+}
+
+func TestCloseAuction(t *testing.T) {
+	mockService := new(MockAuctionService)
+	closed := model.Auction{ID: 1, Item: "Test Item", UserID: 1, Status: model.AuctionStatusClosed}
+	mockService.On("CloseAuction", 1, 1).Return(closed, nil)
+
+	auctionHandler := handler.NewAuctionHandler(mockService)
+
+	req, err := http.NewRequest("POST", "/auctions/close/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(handler.UserIDHeader, "1")
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(auctionHandler.CloseAuction)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestRelistAuction(t *testing.T) {
+	mockService := new(MockAuctionService)
+	startsAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	relist := model.Auction{ID: 2, Item: "Test Item", UserID: 1, Status: model.AuctionStatusScheduled, StartsAt: &startsAt, RelistedFromID: new(int)}
+	mockService.On("RelistAuction", 1, 1, &startsAt).Return(relist, nil)
+	mockService.On("RelistAuction", 1, 2, (*time.Time)(nil)).Return(model.Auction{}, service.ErrInvalidInput)
+	mockService.On("RelistAuction", 1, 3, (*time.Time)(nil)).Return(model.Auction{}, service.ErrNotRelistable)
+
+	auctionHandler := handler.NewAuctionHandler(mockService)
+
+	for _, tt := range []struct {
+		path string
+		body string
+		want int
+	}{
+		{"/auctions/1/relist", `{"starts_at": "2024-05-01T12:00:00Z"}`, http.StatusCreated},
+		{"/auctions/2/relist", "", http.StatusBadRequest},
+		{"/auctions/3/relist", "", http.StatusConflict},
+		{"/auctions/1/relist", `{"starts_at": "tomorrow"}`, http.StatusBadRequest},
+		{"/auctions/x/relist", "", http.StatusBadRequest},
+	} {
+		req, err := http.NewRequest("POST", tt.path, bytes.NewBufferString(tt.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set(handler.UserIDHeader, "1")
+
+		rr := httptest.NewRecorder()
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /auctions/{id}/relist", auctionHandler.RelistAuction)
+		mux.ServeHTTP(rr, req)
+
+		assert.Equal(t, tt.want, rr.Code, "%s %s", tt.path, tt.body)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRelistLineage(t *testing.T) {
+	mockService := new(MockAuctionService)
+	lineage := []model.Auction{{ID: 1, Item: "Test Item", UserID: 1, Status: model.AuctionStatusClosed}, {ID: 2, Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen}}
+	mockService.On("GetRelistLineage", 2).Return(lineage, nil)
+
+	auctionHandler := handler.NewAuctionHandler(mockService)
+
+	req, err := http.NewRequest("GET", "/auctions/2/lineage", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.SetPathValue("id", "2")
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(auctionHandler.GetRelistLineage)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var auctions []model.Auction
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &auctions))
+	assert.Len(t, auctions, 2)
+	mockService.AssertExpectations(t)
+}
+
+func TestPlaceBidOnClosedAuction(t *testing.T) {
+	mockService := new(MockAuctionService)
+	mockService.On("PlaceBid", 1, 2, model.NewMoney(1550, "USD")).Return(model.Auction{}, service.ErrAuctionClosed)
+
+	auctionHandler := handler.NewAuctionHandler(mockService)
+
+	req, err := http.NewRequest("POST", "/auctions/bid/1", bytes.NewBufferString(`{"amount": 15.5, "currency": "USD"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(handler.UserIDHeader, "2")
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(auctionHandler.PlaceBid)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPlaceBidRejectsInvalidAmount(t *testing.T) {
+	for _, body := range []string{`{"amount": 15.5}`, `{"amount": "15.555", "currency": "USD"}`, `{"amount": "15", "currency": "usd"}`} {
+		mockService := new(MockAuctionService)
+		auctionHandler := handler.NewAuctionHandler(mockService)
+
+		req, err := http.NewRequest("POST", "/auctions/bid/1", bytes.NewBufferString(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set(handler.UserIDHeader, "2")
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(auctionHandler.PlaceBid)
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+		mockService.AssertNotCalled(t, "PlaceBid")
+	}
+}
+
+func TestPlaceProxyBid(t *testing.T) {
+	mockService := new(MockAuctionService)
+	updated := model.Auction{ID: 1, Item: "Test Item", UserID: 1, Currency: "EUR", CurrentPrice: model.NewMoney(1100, "EUR")}
+	mockService.On("PlaceProxyBid", 1, 2, model.NewMoney(5000, "EUR")).Return(updated, nil)
+
+	auctionHandler := handler.NewAuctionHandler(mockService)
+
+	req, err := http.NewRequest("POST", "/auctions/proxy-bid/1", bytes.NewBufferString(`{"max_amount": "50.00", "currency": "EUR"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(handler.UserIDHeader, "2")
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(auctionHandler.PlaceProxyBid)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var returned model.Auction
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returned))
+	assert.Equal(t, model.NewMoney(1100, "EUR"), returned.CurrentPrice)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetBids(t *testing.T) {
+	mockService := new(MockAuctionService)
+	bids := []model.Bid{
+		{ID: 1, AuctionID: 1, UserID: 2, Amount: model.NewMoney(1000, "USD")},
+		{ID: 2, AuctionID: 1, UserID: 3, Amount: model.NewMoney(1100, "USD"), Automatic: true},
+	}
+	mockService.On("GetBids", 1).Return(bids, nil)
+
+	auctionHandler := handler.NewAuctionHandler(mockService)
+
+	req, err := http.NewRequest("GET", "/auctions/bids/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(auctionHandler.GetBids)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var returned []model.Bid
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returned))
+	assert.Equal(t, bids, returned)
+	mockService.AssertExpectations(t)
+}
+
+func TestBuyNowUnavailable(t *testing.T) {
+	mockService := new(MockAuctionService)
+	mockService.On("BuyNow", 1, 2).Return(model.Auction{}, service.ErrBuyNowUnavailable)
+
+	auctionHandler := handler.NewAuctionHandler(mockService)
+
+	req, err := http.NewRequest("POST", "/auctions/buy-now/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(handler.UserIDHeader, "2")
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(auctionHandler.BuyNow)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestRetractBid(t *testing.T) {
+	mockService := new(MockAuctionService)
+	updated := model.Auction{ID: 1, Item: "Test Item", UserID: 1, CurrentPrice: model.NewMoney(10000, "USD")}
+	mockService.On("RetractBid", 2, 7, "").Return(updated, nil)
+	mockService.On("RetractBid", 2, 8, "").Return(model.Auction{}, service.ErrRetractionNotAllowed)
+
+	auctionHandler := handler.NewAuctionHandler(mockService)
+
+	for bidID, want := range map[int]int{7: http.StatusOK, 8: http.StatusConflict} {
+		req, err := http.NewRequest("POST", "/bids/retract/"+strconv.Itoa(bidID), http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set(handler.UserIDHeader, "2")
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(auctionHandler.RetractBid)
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, want, rr.Code)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestCancelBid(t *testing.T) {
+	mockService := new(MockAuctionService)
+	mockService.On("CancelBid", 1, 7, "bidder asked").Return(model.Auction{ID: 1, Item: "Test Item", UserID: 1}, nil)
+	mockService.On("CancelBid", 1, 9, "bidder asked").Return(model.Auction{}, service.ErrBidNotFound)
+
+	auctionHandler := handler.NewAuctionHandler(mockService)
+
+	for bidID, want := range map[int]int{7: http.StatusOK, 9: http.StatusNotFound} {
+		req, err := http.NewRequest("POST", "/bids/cancel/"+strconv.Itoa(bidID), bytes.NewBufferString(`{"reason": "bidder asked"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set(handler.UserIDHeader, "1")
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(auctionHandler.CancelBid)
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, want, rr.Code)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestGetBidAudit(t *testing.T) {
+	mockService := new(MockAuctionService)
+	entries := []model.BidAuditEntry{
+		{ID: 1, AuctionID: 1, BidID: 7, BidderID: 2, ActorID: 1, Action: model.BidAuditCancelled, Reason: "bidder asked", Amount: model.NewMoney(100000, "USD"), Withdrawn: []int{7}},
+	}
+	mockService.On("GetBidAudit", 1).Return(entries, nil)
+
+	auctionHandler := handler.NewAuctionHandler(mockService)
+
+	req, err := http.NewRequest("GET", "/auctions/bid-audit/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(auctionHandler.GetBidAudit)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var returned []model.BidAuditEntry
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returned))
+	assert.Equal(t, entries, returned)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetAuctionHidesReservePrice(t *testing.T) {
+	mockService := new(MockAuctionService)
+	auction := model.Auction{ID: 1, Item: "Test Item", UserID: 1, Currency: "USD", CurrentPrice: model.NewMoney(2000, "USD"), ReservePrice: model.NewMoney(5000, "USD")}
+	mockService.On("GetAuctionByID", 1).Return(auction, nil)
+
+	auctionHandler := handler.NewAuctionHandler(mockService)
+
+	req, err := http.NewRequest("GET", "/auctions/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(auctionHandler.GetAuctionByID)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.NotContains(t, body, "ReservePrice")
+	assert.Equal(t, true, body["HasReserve"])
+	assert.Equal(t, false, body["ReserveMet"])
 }
@@ -0,0 +1,372 @@
+package handler
+
+import (
+	"auction-service/internal/live"
+	"auction-service/internal/model"
+	"auction-service/internal/service"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Rejection codes sent to WebSocket clients when a request fails.
+const (
+	RejectBadRequest      = "bad_request"
+	RejectRateLimited     = "rate_limited"
+	RejectTooManyAuctions = "too_many_subscriptions"
+	RejectAuctionNotFound = "auction_not_found"
+	RejectAuctionClosed   = "auction_closed"
+	RejectNotStarted      = "auction_not_started"
+	RejectForbidden       = "forbidden"
+	RejectInvalidBid      = "invalid_bid"
+	RejectInternalError   = "internal_error"
+)
+
+// SocketLimits bounds what a single WebSocket connection may do.
+type SocketLimits struct {
+	// Rate is how many messages per second a client may send on average,
+	// and Burst how many at once.
+	Rate  float64
+	Burst int
+	// SendBuffer is how many messages may wait for a slow client before it
+	// is disconnected.
+	SendBuffer int
+	// MaxSubscriptions is how many auctions a connection may follow.
+	MaxSubscriptions int
+	// MaxMessageSize is the largest message accepted from the client.
+	MaxMessageSize int64
+	// PingPeriod is how often the server pings the client. A client that
+	// sends nothing, not even a pong, for PongWait is disconnected.
+	PingPeriod time.Duration
+	PongWait   time.Duration
+}
+
+// DefaultSocketLimits are the limits used in production.
+var DefaultSocketLimits = SocketLimits{
+	Rate:             5,
+	Burst:            10,
+	SendBuffer:       64,
+	MaxSubscriptions: 50,
+	MaxMessageSize:   4096,
+	PingPeriod:       30 * time.Second,
+	PongWait:         60 * time.Second,
+}
+
+// writeWait is how long a single write to the client may take.
+const writeWait = 10 * time.Second
+
+type BiddingSocket struct {
+	service  service.AuctionService
+	live     *live.Broadcaster
+	limits   SocketLimits
+	upgrader websocket.Upgrader
+}
+
+func NewBiddingSocket(auctionService service.AuctionService, broadcaster *live.Broadcaster, limits SocketLimits) *BiddingSocket {
+	return &BiddingSocket{service: auctionService, live: broadcaster, limits: limits}
+}
+
+// socketRequest is a message from the client. Type is subscribe,
+// unsubscribe or bid; the reply to a request carries its RequestID.
+type socketRequest struct {
+	Type      string `json:"type"`
+	RequestID string `json:"request_id"`
+	AuctionID int    `json:"auction_id"`
+	// LastEventID resumes a subscription after the given event.
+	LastEventID int         `json:"last_event_id"`
+	Amount      json.Number `json:"amount"`
+	Currency    string      `json:"currency"`
+}
+
+// socketMessage is a message to the client. Type is subscribed,
+// unsubscribed, ack or rejected in reply to a request, and event for the
+// events of the subscribed auctions.
+type socketMessage struct {
+	Type      string          `json:"type"`
+	RequestID string          `json:"request_id,omitempty"`
+	AuctionID int             `json:"auction_id,omitempty"`
+	EventID   int             `json:"event_id,omitempty"`
+	Event     json.RawMessage `json:"event,omitempty"`
+	Price     *model.Money    `json:"price,omitempty"`
+	Code      string          `json:"code,omitempty"`
+	Message   string          `json:"message,omitempty"`
+}
+
+// ServeBidding upgrades the request to a WebSocket on which the user
+// follows auctions and bids on them. Like every request, the upgrade is
+// trusted for the UserIDHeader. Browsers cannot set headers on it, so the
+// gateway in front of the service must authenticate them its own way, with
+// a cookie or a token in the URL, and add the header.
+func (h *BiddingSocket) ServeBidding(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+	ws, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already replied.
+		return
+	}
+
+	c := &socketConn{
+		BiddingSocket: h,
+		ws:            ws,
+		userID:        userID,
+		send:          make(chan socketMessage, h.limits.SendBuffer),
+		done:          make(chan struct{}),
+		subscriptions: map[int]*live.Subscription{},
+		limiter:       newTokenBucket(h.limits.Rate, h.limits.Burst, time.Now),
+	}
+	c.run()
+}
+
+// socketConn is one client connection. Requests are handled one at a time
+// by run; everything sent to the client goes through send and is written
+// by writeLoop.
+type socketConn struct {
+	*BiddingSocket
+	ws      *websocket.Conn
+	userID  int
+	send    chan socketMessage
+	done    chan struct{}
+	limiter *tokenBucket
+	slow    sync.Once
+
+	mu            sync.Mutex
+	subscriptions map[int]*live.Subscription
+	forwarders    sync.WaitGroup
+}
+
+func (c *socketConn) run() {
+	writer := make(chan struct{})
+	go func() {
+		defer close(writer)
+		c.writeLoop()
+	}()
+	defer func() {
+		close(c.done)
+		c.unsubscribeAll()
+		c.forwarders.Wait()
+		<-writer
+		c.ws.Close()
+	}()
+
+	c.ws.SetReadLimit(c.limits.MaxMessageSize)
+	c.ws.SetReadDeadline(time.Now().Add(c.limits.PongWait))
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(time.Now().Add(c.limits.PongWait))
+	})
+	for {
+		_, data, err := c.ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		c.ws.SetReadDeadline(time.Now().Add(c.limits.PongWait))
+
+		var req socketRequest
+		if !c.limiter.allow() {
+			json.Unmarshal(data, &req)
+			c.reject(req, RejectRateLimited, "Too many messages, slow down")
+			continue
+		}
+		if err := json.Unmarshal(data, &req); err != nil {
+			c.reject(req, RejectBadRequest, "Invalid JSON message")
+			continue
+		}
+		switch req.Type {
+		case "subscribe":
+			c.subscribe(req)
+		case "unsubscribe":
+			c.unsubscribe(req)
+		case "bid":
+			c.bid(req)
+		default:
+			c.reject(req, RejectBadRequest, "Unknown message type")
+		}
+	}
+}
+
+// writeLoop writes the queued messages and the pings until the connection
+// ends.
+func (c *socketConn) writeLoop() {
+	ping := time.NewTicker(c.limits.PingPeriod)
+	defer ping.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case msg := <-c.send:
+			c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.ws.WriteJSON(msg); err != nil {
+				c.ws.Close()
+				return
+			}
+		case <-ping.C:
+			if err := c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				c.ws.Close()
+				return
+			}
+		}
+	}
+}
+
+// enqueue hands msg to the writer. A client that lets SendBuffer messages
+// pile up is disconnected rather than slowing down the server; it can
+// reconnect and resume its subscriptions from the last event it got.
+func (c *socketConn) enqueue(msg socketMessage) {
+	select {
+	case c.send <- msg:
+	case <-c.done:
+	default:
+		c.disconnectSlow()
+	}
+}
+
+func (c *socketConn) disconnectSlow() {
+	c.slow.Do(func() {
+		log.Printf("Disconnecting slow WebSocket client of user %d", c.userID)
+		closing := websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "client too slow")
+		c.ws.WriteControl(websocket.CloseMessage, closing, time.Now().Add(writeWait))
+		c.ws.Close()
+	})
+}
+
+func (c *socketConn) reject(req socketRequest, code, message string) {
+	c.enqueue(socketMessage{Type: "rejected", RequestID: req.RequestID, AuctionID: req.AuctionID, Code: code, Message: message})
+}
+
+func (c *socketConn) subscribe(req socketRequest) {
+	if _, err := c.service.GetAuctionByID(req.AuctionID); err != nil {
+		c.rejectServiceError(req, "subscribing to auction", err)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.subscriptions[req.AuctionID]; ok {
+		c.enqueue(socketMessage{Type: "subscribed", RequestID: req.RequestID, AuctionID: req.AuctionID})
+		return
+	}
+	if len(c.subscriptions) >= c.limits.MaxSubscriptions {
+		c.reject(req, RejectTooManyAuctions, "Too many auctions followed on this connection")
+		return
+	}
+
+	missed, subscription := c.live.Subscribe(req.AuctionID, req.LastEventID)
+	c.subscriptions[req.AuctionID] = subscription
+	c.enqueue(socketMessage{Type: "subscribed", RequestID: req.RequestID, AuctionID: req.AuctionID})
+	for _, event := range missed {
+		c.enqueue(eventMessage(event))
+	}
+	c.forwarders.Add(1)
+	go c.forward(req.AuctionID, subscription)
+}
+
+// forward passes the events of a subscription on to the client. When the
+// broadcaster drops the subscription because the client fell behind, the
+// client is disconnected.
+func (c *socketConn) forward(auctionID int, subscription *live.Subscription) {
+	defer c.forwarders.Done()
+	for event := range subscription.Events() {
+		c.enqueue(eventMessage(event))
+	}
+
+	c.mu.Lock()
+	dropped := c.subscriptions[auctionID] == subscription
+	c.mu.Unlock()
+	if dropped {
+		c.disconnectSlow()
+	}
+}
+
+func (c *socketConn) unsubscribe(req socketRequest) {
+	c.mu.Lock()
+	subscription, ok := c.subscriptions[req.AuctionID]
+	delete(c.subscriptions, req.AuctionID)
+	c.mu.Unlock()
+	if ok {
+		subscription.Close()
+	}
+	c.enqueue(socketMessage{Type: "unsubscribed", RequestID: req.RequestID, AuctionID: req.AuctionID})
+}
+
+func (c *socketConn) unsubscribeAll() {
+	c.mu.Lock()
+	subscriptions := c.subscriptions
+	c.subscriptions = map[int]*live.Subscription{}
+	c.mu.Unlock()
+	for _, subscription := range subscriptions {
+		subscription.Close()
+	}
+}
+
+// bid places a bid and acknowledges it with the resulting price of the
+// auction.
+func (c *socketConn) bid(req socketRequest) {
+	amount, err := model.ParseMoney(req.Amount.String(), req.Currency)
+	if err != nil {
+		c.reject(req, RejectBadRequest, err.Error())
+		return
+	}
+
+	auction, err := c.service.PlaceBid(req.AuctionID, c.userID, amount)
+	if err != nil {
+		c.rejectServiceError(req, "placing bid", err)
+		return
+	}
+	c.enqueue(socketMessage{Type: "ack", RequestID: req.RequestID, AuctionID: auction.ID, Price: &auction.CurrentPrice})
+}
+
+// rejectServiceError maps service errors to rejection codes, as
+// writeServiceError does to HTTP status codes.
+func (c *socketConn) rejectServiceError(req socketRequest, action string, err error) {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		c.reject(req, RejectAuctionNotFound, "Auction not found")
+	case errors.Is(err, service.ErrForbidden):
+		c.reject(req, RejectForbidden, err.Error())
+	case errors.Is(err, service.ErrInvalidInput):
+		c.reject(req, RejectInvalidBid, err.Error())
+	case errors.Is(err, service.ErrAuctionClosed):
+		c.reject(req, RejectAuctionClosed, err.Error())
+	case errors.Is(err, service.ErrAuctionNotStarted):
+		c.reject(req, RejectNotStarted, err.Error())
+	default:
+		log.Printf("Error %s: %v", action, err)
+		c.reject(req, RejectInternalError, "Internal server error")
+	}
+}
+
+func eventMessage(event live.Event) socketMessage {
+	return socketMessage{Type: "event", AuctionID: event.AuctionID, EventID: event.ID, Event: event.Data}
+}
+
+// tokenBucket allows rate events per second on average and up to burst at
+// once. It is not safe for concurrent use.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now func() time.Time) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: now(), now: now}
+}
+
+func (b *tokenBucket) allow() bool {
+	now := b.now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,175 @@
+package handler_test
+
+import (
+	"auction-service/internal/handler"
+	"auction-service/internal/live"
+	"auction-service/internal/model"
+	"auction-service/internal/service"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// socketReply is a message received from the bidding socket.
+type socketReply struct {
+	Type      string       `json:"type"`
+	RequestID string       `json:"request_id"`
+	AuctionID int          `json:"auction_id"`
+	EventID   int          `json:"event_id"`
+	Event     model.Event  `json:"event"`
+	Price     *model.Money `json:"price"`
+	Code      string       `json:"code"`
+}
+
+// dialBidding serves socket until the end of the test and connects to it
+// as userID.
+func dialBidding(t *testing.T, socket *handler.BiddingSocket, userID string) *websocket.Conn {
+	server := newStreamServer(t, http.HandlerFunc(socket.ServeBidding))
+	header := http.Header{}
+	header.Set(handler.UserIDHeader, userID)
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), header)
+	require.NoError(t, err)
+	t.Cleanup(func() { ws.Close() })
+	return ws
+}
+
+func send(t *testing.T, ws *websocket.Conn, request string) {
+	require.NoError(t, ws.WriteMessage(websocket.TextMessage, []byte(request)))
+}
+
+func receive(t *testing.T, ws *websocket.Conn) socketReply {
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var reply socketReply
+	require.NoError(t, ws.ReadJSON(&reply))
+	return reply
+}
+
+func TestBiddingSocket(t *testing.T) {
+	mockService := new(MockAuctionService)
+	mockService.On("GetAuctionByID", 1).Return(model.Auction{ID: 1}, nil)
+	mockService.On("PlaceBid", 1, 2, model.NewMoney(2500, "USD")).Return(model.Auction{ID: 1, CurrentPrice: model.NewMoney(2500, "USD")}, nil)
+	broadcaster := live.NewBroadcaster(live.DefaultHistorySize)
+	broadcaster.Notify([]model.OutboxMessage{priceChangedMessage(t, 1, 100)})
+
+	ws := dialBidding(t, handler.NewBiddingSocket(mockService, broadcaster, handler.DefaultSocketLimits), "2")
+
+	send(t, ws, `{"type": "subscribe", "request_id": "s1", "auction_id": 1}`)
+	reply := receive(t, ws)
+	assert.Equal(t, "subscribed", reply.Type)
+	assert.Equal(t, "s1", reply.RequestID)
+	// The events kept for the auction come first.
+	reply = receive(t, ws)
+	assert.Equal(t, "event", reply.Type)
+	assert.Equal(t, 1, reply.EventID)
+	assert.Equal(t, model.EventPriceChanged, reply.Event.Type)
+
+	send(t, ws, `{"type": "bid", "request_id": "b1", "auction_id": 1, "amount": "25.00", "currency": "USD"}`)
+	reply = receive(t, ws)
+	assert.Equal(t, "ack", reply.Type)
+	assert.Equal(t, "b1", reply.RequestID)
+	if assert.NotNil(t, reply.Price) {
+		assert.Equal(t, model.NewMoney(2500, "USD"), *reply.Price)
+	}
+
+	broadcaster.Notify([]model.OutboxMessage{priceChangedMessage(t, 2, 2500)})
+	reply = receive(t, ws)
+	assert.Equal(t, "event", reply.Type)
+	assert.Equal(t, 2, reply.EventID)
+
+	send(t, ws, `{"type": "unsubscribe", "request_id": "u1", "auction_id": 1}`)
+	assert.Equal(t, "unsubscribed", receive(t, ws).Type)
+	broadcaster.Notify([]model.OutboxMessage{priceChangedMessage(t, 3, 3000)})
+	send(t, ws, `{"type": "subscribe", "request_id": "s2", "auction_id": 1, "last_event_id": 3}`)
+	reply = receive(t, ws)
+	assert.Equal(t, "subscribed", reply.Type)
+	assert.Equal(t, "s2", reply.RequestID)
+}
+
+func TestBiddingSocketRejections(t *testing.T) {
+	mockService := new(MockAuctionService)
+	mockService.On("GetAuctionByID", 2).Return(model.Auction{}, service.ErrNotFound)
+	mockService.On("PlaceBid", 1, 2, model.NewMoney(100, "USD")).Return(model.Auction{}, service.ErrInvalidInput)
+	mockService.On("PlaceBid", 3, 2, model.NewMoney(100, "USD")).Return(model.Auction{}, service.ErrAuctionClosed)
+	mockService.On("PlaceBid", 4, 2, model.NewMoney(100, "USD")).Return(model.Auction{}, service.ErrForbidden)
+	mockService.On("PlaceBid", 5, 2, model.NewMoney(100, "USD")).Return(model.Auction{}, service.ErrAuctionNotStarted)
+
+	ws := dialBidding(t, handler.NewBiddingSocket(mockService, live.NewBroadcaster(live.DefaultHistorySize), handler.DefaultSocketLimits), "2")
+
+	tests := []struct {
+		request string
+		code    string
+	}{
+		{request: `{"type": "subscribe", "auction_id": 2}`, code: handler.RejectAuctionNotFound},
+		{request: `{"type": "bid", "auction_id": 1, "amount": "abc", "currency": "USD"}`, code: handler.RejectBadRequest},
+		{request: `{"type": "bid", "auction_id": 1, "amount": "1", "currency": "USD"}`, code: handler.RejectInvalidBid},
+		{request: `{"type": "bid", "auction_id": 3, "amount": "1", "currency": "USD"}`, code: handler.RejectAuctionClosed},
+		{request: `{"type": "bid", "auction_id": 4, "amount": "1", "currency": "USD"}`, code: handler.RejectForbidden},
+		{request: `{"type": "bid", "auction_id": 5, "amount": "1", "currency": "USD"}`, code: handler.RejectNotStarted},
+		{request: `{"type": "sell"}`, code: handler.RejectBadRequest},
+		{request: `not json`, code: handler.RejectBadRequest},
+	}
+	for _, tt := range tests {
+		send(t, ws, tt.request)
+		reply := receive(t, ws)
+		assert.Equal(t, "rejected", reply.Type, tt.request)
+		assert.Equal(t, tt.code, reply.Code, tt.request)
+	}
+}
+
+func TestBiddingSocketRateLimit(t *testing.T) {
+	mockService := new(MockAuctionService)
+	mockService.On("GetAuctionByID", 1).Return(model.Auction{ID: 1}, nil)
+	limits := handler.DefaultSocketLimits
+	limits.Rate, limits.Burst = 0.001, 2
+
+	ws := dialBidding(t, handler.NewBiddingSocket(mockService, live.NewBroadcaster(live.DefaultHistorySize), limits), "2")
+
+	for _, want := range []string{"subscribed", "subscribed", "rejected"} {
+		send(t, ws, `{"type": "subscribe", "request_id": "r", "auction_id": 1}`)
+		reply := receive(t, ws)
+		assert.Equal(t, want, reply.Type)
+		assert.Equal(t, "r", reply.RequestID)
+	}
+}
+
+func TestBiddingSocketKeepalive(t *testing.T) {
+	limits := handler.DefaultSocketLimits
+	limits.PingPeriod, limits.PongWait = 10*time.Millisecond, 100*time.Millisecond
+	socket := handler.NewBiddingSocket(new(MockAuctionService), live.NewBroadcaster(live.DefaultHistorySize), limits)
+
+	// A client that keeps reading answers the pings and stays connected
+	// past PongWait, so its read only ends at its own deadline.
+	ws := dialBidding(t, socket, "2")
+	ws.SetReadDeadline(time.Now().Add(300 * time.Millisecond))
+	_, _, err := ws.ReadMessage()
+	var netErr net.Error
+	if assert.True(t, errors.As(err, &netErr), "got %v", err) {
+		assert.True(t, netErr.Timeout())
+	}
+
+	// A client that does not answer is disconnected by the server.
+	idle := dialBidding(t, socket, "3")
+	idle.SetPingHandler(func(string) error { return nil })
+	idle.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _, err = idle.ReadMessage()
+	require.Error(t, err)
+	assert.False(t, errors.As(err, &netErr) && netErr.Timeout(), "got %v", err)
+}
+
+func TestBiddingSocketRequiresUser(t *testing.T) {
+	socket := handler.NewBiddingSocket(new(MockAuctionService), live.NewBroadcaster(live.DefaultHistorySize), handler.DefaultSocketLimits)
+	server := newStreamServer(t, http.HandlerFunc(socket.ServeBidding))
+
+	_, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	assert.Error(t, err)
+	if assert.NotNil(t, resp) {
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	}
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/service"
+	"encoding/json"
+	"net/http"
+)
+
+// CategoryHandler serves the category tree. The routes that change it are
+// expected behind RequireAdmin.
+type CategoryHandler struct {
+	service service.CategoryService
+}
+
+func NewCategoryHandler(categoryService service.CategoryService) *CategoryHandler {
+	return &CategoryHandler{service: categoryService}
+}
+
+// categoryRequest is the body of CreateCategory and UpdateCategory.
+type categoryRequest struct {
+	ParentID      *int                    `json:"parent_id"`
+	Name          string                  `json:"name"`
+	Slug          string                  `json:"slug"`
+	Attributes    []model.AttributeSchema `json:"attributes"`
+	BidIncrements model.IncrementTable    `json:"bid_increments"`
+}
+
+func (request categoryRequest) category(id int) model.Category {
+	return model.Category{
+		ID:            id,
+		ParentID:      request.ParentID,
+		Name:          request.Name,
+		Slug:          request.Slug,
+		Attributes:    request.Attributes,
+		BidIncrements: request.BidIncrements,
+	}
+}
+
+// GetCategories returns the category tree.
+func (h *CategoryHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.service.GetCategoryTree()
+	if err != nil {
+		writeServiceError(w, "fetching categories", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(categories)
+}
+
+// GetCategory returns a category with every attribute auctions in it have.
+func (h *CategoryHandler) GetCategory(w http.ResponseWriter, r *http.Request) {
+	id, ok := idFromPath(w, r, `^/categories/(\d+)$`, "category")
+	if !ok {
+		return
+	}
+
+	category, err := h.service.GetCategory(id)
+	if err != nil {
+		writeServiceError(w, "fetching category", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(category)
+}
+
+// CreateCategory adds a category to the tree.
+func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	var request categoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON request body", http.StatusBadRequest)
+		return
+	}
+
+	category, err := h.service.CreateCategory(request.category(0))
+	if err != nil {
+		writeServiceError(w, "creating category", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(category)
+}
+
+// UpdateCategory changes a category, or moves it in the tree.
+func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	id, ok := idFromPath(w, r, `^/admin/categories/update/(\d+)$`, "category")
+	if !ok {
+		return
+	}
+
+	var request categoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON request body", http.StatusBadRequest)
+		return
+	}
+
+	category, err := h.service.UpdateCategory(request.category(id))
+	if err != nil {
+		writeServiceError(w, "updating category", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(category)
+}
+
+// DeleteCategory removes a category without subcategories or auctions.
+func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	id, ok := idFromPath(w, r, `^/admin/categories/delete/(\d+)$`, "category")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteCategory(id); err != nil {
+		writeServiceError(w, "deleting category", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
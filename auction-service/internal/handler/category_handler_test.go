@@ -0,0 +1,128 @@
+package handler_test
+
+import (
+	"auction-service/internal/handler"
+	"auction-service/internal/model"
+	"auction-service/internal/service"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCategoryService is a mock implementation of the CategoryService interface
+type MockCategoryService struct {
+	mock.Mock
+}
+
+func (m *MockCategoryService) GetCategoryTree() ([]model.Category, error) {
+	args := m.Called()
+	return args.Get(0).([]model.Category), args.Error(1)
+}
+
+func (m *MockCategoryService) GetCategory(id int) (model.Category, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Category), args.Error(1)
+}
+
+func (m *MockCategoryService) CreateCategory(category model.Category) (model.Category, error) {
+	args := m.Called(category)
+	return args.Get(0).(model.Category), args.Error(1)
+}
+
+func (m *MockCategoryService) UpdateCategory(category model.Category) (model.Category, error) {
+	args := m.Called(category)
+	return args.Get(0).(model.Category), args.Error(1)
+}
+
+func (m *MockCategoryService) DeleteCategory(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func TestCreateCategory(t *testing.T) {
+	mockService := new(MockCategoryService)
+	categoryHandler := handler.NewCategoryHandler(mockService)
+
+	parent := 1
+	request := model.Category{ParentID: &parent, Name: "Sneakers", Attributes: []model.AttributeSchema{
+		{Name: "condition", Type: model.AttributeTypeEnum, Options: []string{"new", "used"}},
+	}}
+	created := request
+	created.ID = 2
+	created.Slug = "sneakers"
+	mockService.On("CreateCategory", request).Return(created, nil)
+
+	body := `{"parent_id": 1, "name": "Sneakers", "attributes": [{"Name": "condition", "Type": "enum", "Options": ["new", "used"]}]}`
+	req, _ := http.NewRequest("POST", "/admin/categories/create", bytes.NewBufferString(body))
+	rr := httptest.NewRecorder()
+	categoryHandler.CreateCategory(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var returned model.Category
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returned))
+	assert.Equal(t, created, returned)
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateCategoryInvalid(t *testing.T) {
+	mockService := new(MockCategoryService)
+	categoryHandler := handler.NewCategoryHandler(mockService)
+	mockService.On("UpdateCategory", model.Category{ID: 4, Name: ""}).Return(model.Category{}, service.ErrInvalidInput)
+
+	req, _ := http.NewRequest("PUT", "/admin/categories/update/4", bytes.NewBufferString(`{"name": ""}`))
+	rr := httptest.NewRecorder()
+	categoryHandler.UpdateCategory(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestDeleteCategory(t *testing.T) {
+	mockService := new(MockCategoryService)
+	categoryHandler := handler.NewCategoryHandler(mockService)
+	mockService.On("DeleteCategory", 1).Return(service.ErrCategoryInUse)
+	mockService.On("DeleteCategory", 2).Return(nil)
+	mockService.On("DeleteCategory", 3).Return(service.ErrCategoryNotFound)
+
+	for id, code := range map[string]int{"1": http.StatusConflict, "2": http.StatusNoContent, "3": http.StatusNotFound} {
+		req, _ := http.NewRequest("DELETE", "/admin/categories/delete/"+id, nil)
+		rr := httptest.NewRecorder()
+		categoryHandler.DeleteCategory(rr, req)
+		assert.Equal(t, code, rr.Code, id)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestGetCategories(t *testing.T) {
+	mockService := new(MockCategoryService)
+	categoryHandler := handler.NewCategoryHandler(mockService)
+	parent := 1
+	tree := []model.Category{{ID: 1, Name: "Shoes", Slug: "shoes", Children: []model.Category{{ID: 2, ParentID: &parent, Name: "Sneakers", Slug: "sneakers"}}}}
+	category := model.Category{ID: 2, ParentID: &parent, Name: "Sneakers", Slug: "sneakers", Schema: []model.AttributeSchema{{Name: "size", Type: model.AttributeTypeNumber}}}
+	mockService.On("GetCategoryTree").Return(tree, nil)
+	mockService.On("GetCategory", 2).Return(category, nil)
+
+	req, _ := http.NewRequest("GET", "/categories", nil)
+	rr := httptest.NewRecorder()
+	categoryHandler.GetCategories(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var returnedTree []model.Category
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returnedTree))
+	assert.Equal(t, tree, returnedTree)
+
+	req, _ = http.NewRequest("GET", "/categories/2", nil)
+	rr = httptest.NewRecorder()
+	categoryHandler.GetCategory(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var returned model.Category
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returned))
+	assert.Equal(t, category, returned)
+	mockService.AssertExpectations(t)
+}
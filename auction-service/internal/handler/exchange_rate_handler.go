@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/service"
+	"encoding/json"
+	"mime"
+	"net/http"
+)
+
+type ExchangeRateHandler struct {
+	service service.ExchangeRateService
+}
+
+func NewExchangeRateHandler(exchangeRateService service.ExchangeRateService) *ExchangeRateHandler {
+	return &ExchangeRateHandler{service: exchangeRateService}
+}
+
+// GetRates lists the stored exchange rates.
+func (h *ExchangeRateHandler) GetRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.service.GetRates()
+	if err != nil {
+		writeServiceError(w, "fetching exchange rates", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rates)
+}
+
+// ImportRates stores the exchange rates in the request body, either a CSV
+// file of from,to,rate lines (Content-Type text/csv) or a JSON array.
+func (h *ExchangeRateHandler) ImportRates(w http.ResponseWriter, r *http.Request) {
+	var rates []model.ExchangeRate
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/csv" {
+		var err error
+		if rates, err = service.ParseRatesCSV(r.Body); err != nil {
+			writeServiceError(w, "reading exchange rates", err)
+			return
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&rates); err != nil {
+		http.Error(w, "Invalid JSON request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.ImportRates(rates); err != nil {
+		writeServiceError(w, "importing exchange rates", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"imported": len(rates)})
+}
@@ -0,0 +1,161 @@
+package handler_test
+
+import (
+	"auction-service/internal/handler"
+	"auction-service/internal/model"
+	"auction-service/internal/service"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockExchangeRateService is a mock implementation of the ExchangeRateService interface
+type MockExchangeRateService struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateService) ImportRates(rates []model.ExchangeRate) error {
+	args := m.Called(rates)
+	return args.Error(0)
+}
+
+func (m *MockExchangeRateService) GetRates() ([]model.ExchangeRate, error) {
+	args := m.Called()
+	return args.Get(0).([]model.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateService) ConvertForDisplay(auction model.Auction, currency string) (model.Auction, error) {
+	args := m.Called(auction, currency)
+	return args.Get(0).(model.Auction), args.Error(1)
+}
+
+func TestGetAuctionWithDisplayCurrency(t *testing.T) {
+	mockService := new(MockAuctionService)
+	mockRates := new(MockExchangeRateService)
+	auction := model.Auction{ID: 1, Item: "Test Item", UserID: 1, Currency: "USD", CurrentPrice: model.NewMoney(1000, "USD")}
+	converted := auction
+	converted.Display = &model.DisplayPrices{Currency: "EUR", Indicative: true, Rate: "0.92", CurrentPrice: model.NewMoney(920, "EUR")}
+	mockService.On("GetAuctionByID", 1).Return(auction, nil)
+	mockRates.On("ConvertForDisplay", auction, "EUR").Return(converted, nil)
+
+	auctionHandler := handler.NewAuctionHandler(mockService, handler.WithExchangeRates(mockRates))
+
+	req, err := http.NewRequest("GET", "/auctions/1?display_currency=EUR", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(auctionHandler.GetAuctionByID)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var returned model.Auction
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returned))
+	assert.Equal(t, model.NewMoney(1000, "USD"), returned.CurrentPrice)
+	if assert.NotNil(t, returned.Display) {
+		assert.True(t, returned.Display.Indicative)
+		assert.Equal(t, model.NewMoney(920, "EUR"), returned.Display.CurrentPrice)
+	}
+	mockRates.AssertExpectations(t)
+}
+
+func TestListsFailOnMissingRates(t *testing.T) {
+	mockService := new(MockAuctionService)
+	mockRates := new(MockExchangeRateService)
+	auctions := []model.Auction{
+		{ID: 1, Item: "Test Item", UserID: 1, Currency: "USD"},
+		{ID: 2, Item: "Test Item", UserID: 1, Currency: "GBP"},
+	}
+	converted := auctions[0]
+	converted.Display = &model.DisplayPrices{Currency: "EUR", Indicative: true}
+	mockService.On("GetAllAuctions").Return(auctions, nil)
+	mockService.On("SearchAuctions", "item", service.AuctionFilter{}, 0).Return([]model.SearchResult{{Auction: auctions[0]}, {Auction: auctions[1]}}, nil)
+	mockService.On("GetAuctionByID", 2).Return(auctions[1], nil)
+	mockRates.On("ConvertForDisplay", auctions[0], "EUR").Return(converted, nil)
+	mockRates.On("ConvertForDisplay", auctions[1], "EUR").Return(auctions[1], service.ErrRateUnavailable)
+
+	auctionHandler := handler.NewAuctionHandler(mockService, handler.WithExchangeRates(mockRates))
+
+	// The lists answer like the auction the rate is missing for.
+	for _, tt := range []struct {
+		url     string
+		handler http.HandlerFunc
+	}{
+		{url: "/auctions?display_currency=EUR", handler: auctionHandler.GetAllAuctions},
+		{url: "/auctions/search?q=item&display_currency=EUR", handler: auctionHandler.SearchAuctions},
+		{url: "/auctions/2?display_currency=EUR", handler: auctionHandler.GetAuctionByID},
+	} {
+		req, err := http.NewRequest("GET", tt.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		tt.handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code, tt.url)
+	}
+}
+
+func TestDisplayCurrencyWithoutRates(t *testing.T) {
+	mockService := new(MockAuctionService)
+	mockService.On("GetAuctionByID", 1).Return(model.Auction{ID: 1, Currency: "USD"}, nil)
+
+	auctionHandler := handler.NewAuctionHandler(mockService)
+
+	req, err := http.NewRequest("GET", "/auctions/1?display_currency=EUR", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(auctionHandler.GetAuctionByID)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestImportRatesRequiresAdmin(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		given    string
+		wantCode int
+	}{
+		{name: "valid token", token: "secret", given: "secret", wantCode: http.StatusOK},
+		{name: "wrong token", token: "secret", given: "guess", wantCode: http.StatusForbidden},
+		{name: "admin disabled", token: "", given: "", wantCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRates := new(MockExchangeRateService)
+			mockRates.On("ImportRates", []model.ExchangeRate{{From: "USD", To: "EUR", Rate: "0.92"}}).Return(nil)
+			rateHandler := handler.NewExchangeRateHandler(mockRates)
+
+			req, err := http.NewRequest("POST", "/admin/exchange-rates", bytes.NewBufferString("from,to,rate\nUSD,EUR,0.92\n"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "text/csv")
+			req.Header.Set(handler.AdminTokenHeader, tt.given)
+
+			rr := httptest.NewRecorder()
+			handler.RequireAdmin(tt.token, rateHandler.ImportRates).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			if tt.wantCode == http.StatusOK {
+				assert.JSONEq(t, `{"imported": 1}`, rr.Body.String())
+				mockRates.AssertExpectations(t)
+			} else {
+				mockRates.AssertNotCalled(t, "ImportRates", mock.Anything)
+			}
+		})
+	}
+}
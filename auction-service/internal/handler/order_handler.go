@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"auction-service/internal/service"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+type OrderHandler struct {
+	service service.SettlementService
+}
+
+func NewOrderHandler(settlementService service.SettlementService) *OrderHandler {
+	return &OrderHandler{service: settlementService}
+}
+
+type shipOrderRequest struct {
+	TrackingNumber string `json:"tracking_number"`
+}
+
+// GetOrder returns an order to its buyer or seller.
+func (h *OrderHandler) GetOrder(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := idFromPath(w, r, `^/orders/(\d+)$`, "order")
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := h.service.GetOrder(userID, orderID)
+	if err != nil {
+		writeServiceError(w, "fetching order", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
+
+// GetAuctionOrders lists the orders of an auction for its seller.
+func (h *OrderHandler) GetAuctionOrders(w http.ResponseWriter, r *http.Request) {
+	auctionID, ok := auctionIDFromPath(w, r, `^/auctions/orders/(\d+)$`)
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	orders, err := h.service.GetAuctionOrders(userID, auctionID)
+	if err != nil {
+		writeServiceError(w, "fetching orders", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(orders)
+}
+
+// PayOrder handles the payment of an order by its buyer.
+func (h *OrderHandler) PayOrder(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := idFromPath(w, r, `^/orders/pay/(\d+)$`, "order")
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := h.service.PayOrder(userID, orderID)
+	if err != nil {
+		writeServiceError(w, "paying order", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
+
+// ShipOrder handles the seller marking an order as shipped. The body, with
+// the tracking number, is optional.
+func (h *OrderHandler) ShipOrder(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := idFromPath(w, r, `^/orders/ship/(\d+)$`, "order")
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var request shipOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid JSON request body", http.StatusBadRequest)
+		return
+	}
+
+	order, err := h.service.ShipOrder(userID, orderID, request.TrackingNumber)
+	if err != nil {
+		writeServiceError(w, "shipping order", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
+
+// CompleteOrder handles the buyer confirming they received an order.
+func (h *OrderHandler) CompleteOrder(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := idFromPath(w, r, `^/orders/complete/(\d+)$`, "order")
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := h.service.CompleteOrder(userID, orderID)
+	if err != nil {
+		writeServiceError(w, "completing order", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
+
+// OfferSecondChance handles the seller offering the item of an unpaid auction
+// to the runner-up bidder.
+func (h *OrderHandler) OfferSecondChance(w http.ResponseWriter, r *http.Request) {
+	auctionID, ok := auctionIDFromPath(w, r, `^/auctions/second-chance/(\d+)$`)
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	offer, err := h.service.OfferSecondChance(userID, auctionID)
+	if err != nil {
+		writeServiceError(w, "making second-chance offer", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(offer)
+}
+
+// GetSecondChanceOffers lists the second-chance offers of an auction the
+// requesting user can see.
+func (h *OrderHandler) GetSecondChanceOffers(w http.ResponseWriter, r *http.Request) {
+	auctionID, ok := auctionIDFromPath(w, r, `^/auctions/second-chance-offers/(\d+)$`)
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	offers, err := h.service.GetSecondChanceOffers(userID, auctionID)
+	if err != nil {
+		writeServiceError(w, "fetching second-chance offers", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(offers)
+}
+
+// AcceptSecondChance handles the runner-up accepting an offer. It returns the
+// order they now have to pay.
+func (h *OrderHandler) AcceptSecondChance(w http.ResponseWriter, r *http.Request) {
+	offerID, ok := idFromPath(w, r, `^/second-chance/accept/(\d+)$`, "offer")
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := h.service.AcceptSecondChance(userID, offerID)
+	if err != nil {
+		writeServiceError(w, "accepting second-chance offer", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
+
+// DeclineSecondChance handles the runner-up declining an offer.
+func (h *OrderHandler) DeclineSecondChance(w http.ResponseWriter, r *http.Request) {
+	offerID, ok := idFromPath(w, r, `^/second-chance/decline/(\d+)$`, "offer")
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	offer, err := h.service.DeclineSecondChance(userID, offerID)
+	if err != nil {
+		writeServiceError(w, "declining second-chance offer", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(offer)
+}
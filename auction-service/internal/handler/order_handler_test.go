@@ -0,0 +1,179 @@
+package handler_test
+
+import (
+	"auction-service/internal/handler"
+	"auction-service/internal/model"
+	"auction-service/internal/service"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSettlementService is a mock implementation of the SettlementService interface
+type MockSettlementService struct {
+	mock.Mock
+}
+
+func (m *MockSettlementService) GetOrder(userID, id int) (model.Order, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(model.Order), args.Error(1)
+}
+
+func (m *MockSettlementService) GetAuctionOrders(sellerID, auctionID int) ([]model.Order, error) {
+	args := m.Called(sellerID, auctionID)
+	return args.Get(0).([]model.Order), args.Error(1)
+}
+
+func (m *MockSettlementService) PayOrder(buyerID, id int) (model.Order, error) {
+	args := m.Called(buyerID, id)
+	return args.Get(0).(model.Order), args.Error(1)
+}
+
+func (m *MockSettlementService) ShipOrder(sellerID, id int, trackingNumber string) (model.Order, error) {
+	args := m.Called(sellerID, id, trackingNumber)
+	return args.Get(0).(model.Order), args.Error(1)
+}
+
+func (m *MockSettlementService) CompleteOrder(buyerID, id int) (model.Order, error) {
+	args := m.Called(buyerID, id)
+	return args.Get(0).(model.Order), args.Error(1)
+}
+
+func (m *MockSettlementService) OfferSecondChance(sellerID, auctionID int) (model.SecondChanceOffer, error) {
+	args := m.Called(sellerID, auctionID)
+	return args.Get(0).(model.SecondChanceOffer), args.Error(1)
+}
+
+func (m *MockSettlementService) AcceptSecondChance(bidderID, offerID int) (model.Order, error) {
+	args := m.Called(bidderID, offerID)
+	return args.Get(0).(model.Order), args.Error(1)
+}
+
+func (m *MockSettlementService) DeclineSecondChance(bidderID, offerID int) (model.SecondChanceOffer, error) {
+	args := m.Called(bidderID, offerID)
+	return args.Get(0).(model.SecondChanceOffer), args.Error(1)
+}
+
+func (m *MockSettlementService) GetSecondChanceOffers(userID, auctionID int) ([]model.SecondChanceOffer, error) {
+	args := m.Called(userID, auctionID)
+	return args.Get(0).([]model.SecondChanceOffer), args.Error(1)
+}
+
+func TestPayOrder(t *testing.T) {
+	mockService := new(MockSettlementService)
+	mockService.On("PayOrder", 2, 1).Return(model.Order{ID: 1, BuyerID: 2, Status: model.OrderStatusPaid}, nil)
+	mockService.On("PayOrder", 2, 2).Return(model.Order{}, service.ErrOrderState)
+	mockService.On("PayOrder", 2, 3).Return(model.Order{}, service.ErrOrderNotFound)
+
+	orderHandler := handler.NewOrderHandler(mockService)
+
+	for orderID, want := range map[int]int{1: http.StatusOK, 2: http.StatusConflict, 3: http.StatusNotFound} {
+		req, err := http.NewRequest("POST", "/orders/pay/"+strconv.Itoa(orderID), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set(handler.UserIDHeader, "2")
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(orderHandler.PayOrder)
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, want, rr.Code, "order %d", orderID)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestShipOrder(t *testing.T) {
+	mockService := new(MockSettlementService)
+	shipped := model.Order{ID: 1, SellerID: 1, Status: model.OrderStatusShipped, TrackingNumber: "TRACK-1"}
+	mockService.On("ShipOrder", 1, 1, "TRACK-1").Return(shipped, nil)
+
+	orderHandler := handler.NewOrderHandler(mockService)
+
+	req, err := http.NewRequest("POST", "/orders/ship/1", bytes.NewBufferString(`{"tracking_number": "TRACK-1"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(handler.UserIDHeader, "1")
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(orderHandler.ShipOrder)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var returned model.Order
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returned))
+	assert.Equal(t, shipped, returned)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetOrderRequiresUser(t *testing.T) {
+	mockService := new(MockSettlementService)
+	orderHandler := handler.NewOrderHandler(mockService)
+
+	req, err := http.NewRequest("GET", "/orders/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(orderHandler.GetOrder)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	mockService.AssertNotCalled(t, "GetOrder")
+}
+
+func TestOfferSecondChance(t *testing.T) {
+	mockService := new(MockSettlementService)
+	offer := model.SecondChanceOffer{ID: 1, AuctionID: 1, BidderID: 3, Amount: model.NewMoney(1500, "USD"), Status: model.OfferStatusPending}
+	mockService.On("OfferSecondChance", 1, 1).Return(offer, nil)
+	mockService.On("OfferSecondChance", 1, 2).Return(model.SecondChanceOffer{}, service.ErrNoRunnerUp)
+
+	orderHandler := handler.NewOrderHandler(mockService)
+
+	for auctionID, want := range map[int]int{1: http.StatusCreated, 2: http.StatusConflict} {
+		req, err := http.NewRequest("POST", "/auctions/second-chance/"+strconv.Itoa(auctionID), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set(handler.UserIDHeader, "1")
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(orderHandler.OfferSecondChance)
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, want, rr.Code, "auction %d", auctionID)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestAcceptSecondChance(t *testing.T) {
+	mockService := new(MockSettlementService)
+	mockService.On("AcceptSecondChance", 3, 1).Return(model.Order{ID: 2, BuyerID: 3, Status: model.OrderStatusAwaitingPayment}, nil)
+	mockService.On("AcceptSecondChance", 3, 2).Return(model.Order{}, service.ErrOfferClosed)
+	mockService.On("AcceptSecondChance", 3, 3).Return(model.Order{}, service.ErrOfferNotFound)
+
+	orderHandler := handler.NewOrderHandler(mockService)
+
+	for offerID, want := range map[int]int{1: http.StatusOK, 2: http.StatusConflict, 3: http.StatusNotFound} {
+		req, err := http.NewRequest("POST", "/second-chance/accept/"+strconv.Itoa(offerID), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set(handler.UserIDHeader, "3")
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(orderHandler.AcceptSecondChance)
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, want, rr.Code, "offer %d", offerID)
+	}
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,18 @@
+package handler
+
+import "net/http"
+
+// WithSubresources serves the requests matching a pattern of subresources,
+// like GET /auctions/{id}/stream, with it and all others with next. Such
+// patterns cannot share a ServeMux with /auctions/bid/{id} and the other
+// action routes: both match /auctions/bid/stream and neither is more
+// specific, which ServeMux refuses.
+func WithSubresources(subresources *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pattern := subresources.Handler(r); pattern != "" {
+			subresources.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
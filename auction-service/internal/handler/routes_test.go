@@ -0,0 +1,38 @@
+package handler_test
+
+import (
+	"auction-service/internal/handler"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithSubresources(t *testing.T) {
+	subresources := http.NewServeMux()
+	subresources.HandleFunc("GET /auctions/{id}/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("stream " + r.PathValue("id")))
+	})
+	actions := http.NewServeMux()
+	actions.HandleFunc("/auctions/bid/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("bid " + r.PathValue("id")))
+	})
+	routes := handler.WithSubresources(subresources, actions)
+
+	for path, want := range map[string]string{
+		"/auctions/1/stream":   "stream 1",
+		"/auctions/bid/1":      "bid 1",
+		"/auctions/bid/stream": "stream bid",
+	} {
+		rr := httptest.NewRecorder()
+		routes.ServeHTTP(rr, httptest.NewRequest("GET", path, nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code, path)
+		assert.Equal(t, want, rr.Body.String(), path)
+	}
+
+	rr := httptest.NewRecorder()
+	routes.ServeHTTP(rr, httptest.NewRequest("POST", "/auctions/bid/stream", nil))
+	assert.Equal(t, "bid stream", rr.Body.String(), "only GET goes to the stream")
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"auction-service/internal/live"
+	"auction-service/internal/service"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultHeartbeat is how often an idle stream sends a comment, so clients
+// and proxies do not time the connection out.
+const DefaultHeartbeat = 15 * time.Second
+
+type StreamHandler struct {
+	service   service.AuctionService
+	live      *live.Broadcaster
+	heartbeat time.Duration
+}
+
+func NewStreamHandler(auctionService service.AuctionService, broadcaster *live.Broadcaster, heartbeat time.Duration) *StreamHandler {
+	return &StreamHandler{service: auctionService, live: broadcaster, heartbeat: heartbeat}
+}
+
+// StreamAuction pushes the bids, price changes, extensions and closing of an
+// auction as server-sent events. The event IDs grow over time; a client that
+// reconnects with the Last-Event-ID header first gets the events it missed.
+func (h *StreamHandler) StreamAuction(w http.ResponseWriter, r *http.Request) {
+	auctionID, ok := auctionIDFromPath(w, r, `^/auctions/(\d+)/stream$`)
+	if !ok {
+		return
+	}
+	lastEventID := 0
+	if header := r.Header.Get("Last-Event-ID"); header != "" {
+		id, err := strconv.Atoi(header)
+		if err != nil || id < 0 {
+			http.Error(w, "Invalid Last-Event-ID header", http.StatusBadRequest)
+			return
+		}
+		lastEventID = id
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := h.service.GetAuctionByID(auctionID); err != nil {
+		writeServiceError(w, "fetching auction", err)
+		return
+	}
+
+	missed, subscription := h.live.Subscribe(auctionID, lastEventID)
+	defer subscription.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	for _, event := range missed {
+		writeEvent(w, event)
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return
+			}
+			writeEvent(w, event)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		}
+		flusher.Flush()
+	}
+}
+
+// writeEvent writes event in the server-sent events format. The data is
+// single-line JSON, so it fits in one data field.
+func writeEvent(w io.Writer, event live.Event) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+}
@@ -0,0 +1,125 @@
+package handler_test
+
+import (
+	"auction-service/internal/handler"
+	"auction-service/internal/live"
+	"auction-service/internal/model"
+	"auction-service/internal/service"
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func priceChangedMessage(t *testing.T, id int, minor int64) model.OutboxMessage {
+	amount := model.NewMoney(minor, "USD")
+	payload, err := json.Marshal(model.Event{Type: model.EventPriceChanged, AuctionID: 1, Amount: &amount})
+	require.NoError(t, err)
+	return model.OutboxMessage{ID: id, Type: model.EventPriceChanged, Payload: payload}
+}
+
+// newStreamServer serves h until the end of the test. The server is closed
+// after the streams opened later, which it would otherwise wait for.
+func newStreamServer(t *testing.T, h http.Handler) *httptest.Server {
+	server := httptest.NewServer(h)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// openStream starts streaming auction 1 and returns a reader of the lines
+// of the stream.
+func openStream(t *testing.T, server *httptest.Server, lastEventID string) *bufio.Scanner {
+	req, err := http.NewRequest("GET", server.URL+"/auctions/1/stream", nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	return bufio.NewScanner(resp.Body)
+}
+
+// nextBlock reads the lines up to the next blank line.
+func nextBlock(t *testing.T, lines *bufio.Scanner) []string {
+	var block []string
+	for lines.Scan() {
+		if lines.Text() == "" {
+			return block
+		}
+		block = append(block, lines.Text())
+	}
+	t.Fatalf("stream ended: %v", lines.Err())
+	return nil
+}
+
+func TestStreamAuction(t *testing.T) {
+	mockService := new(MockAuctionService)
+	mockService.On("GetAuctionByID", 1).Return(model.Auction{ID: 1}, nil)
+	broadcaster := live.NewBroadcaster(live.DefaultHistorySize)
+	broadcaster.Notify([]model.OutboxMessage{priceChangedMessage(t, 1, 100), priceChangedMessage(t, 2, 200)})
+
+	streamHandler := handler.NewStreamHandler(mockService, broadcaster, time.Hour)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /auctions/{id}/stream", streamHandler.StreamAuction)
+	server := newStreamServer(t, mux)
+
+	// A reconnecting client first gets what it missed.
+	lines := openStream(t, server, "1")
+	block := nextBlock(t, lines)
+	require.Len(t, block, 3)
+	assert.Equal(t, "id: 2", block[0])
+	assert.Equal(t, "event: "+model.EventPriceChanged, block[1])
+	var event model.Event
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(block[2], "data: ")), &event))
+	assert.Equal(t, model.NewMoney(200, "USD"), *event.Amount)
+
+	broadcaster.Notify([]model.OutboxMessage{priceChangedMessage(t, 3, 300)})
+	assert.Equal(t, "id: 3", nextBlock(t, lines)[0])
+}
+
+func TestStreamAuctionHeartbeat(t *testing.T) {
+	mockService := new(MockAuctionService)
+	mockService.On("GetAuctionByID", 1).Return(model.Auction{ID: 1}, nil)
+
+	streamHandler := handler.NewStreamHandler(mockService, live.NewBroadcaster(live.DefaultHistorySize), 10*time.Millisecond)
+	server := newStreamServer(t, http.HandlerFunc(streamHandler.StreamAuction))
+
+	lines := openStream(t, server, "")
+	assert.Equal(t, []string{": heartbeat"}, nextBlock(t, lines))
+}
+
+func TestStreamAuctionErrors(t *testing.T) {
+	mockService := new(MockAuctionService)
+	mockService.On("GetAuctionByID", 1).Return(model.Auction{ID: 1}, nil)
+	mockService.On("GetAuctionByID", 2).Return(model.Auction{}, service.ErrNotFound)
+
+	streamHandler := handler.NewStreamHandler(mockService, live.NewBroadcaster(live.DefaultHistorySize), time.Hour)
+
+	tests := []struct {
+		path        string
+		lastEventID string
+		want        int
+	}{
+		{path: "/auctions/2/stream", want: http.StatusNotFound},
+		{path: "/auctions/1/stream", lastEventID: "abc", want: http.StatusBadRequest},
+		{path: "/auctions/x/stream", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		if tt.lastEventID != "" {
+			req.Header.Set("Last-Event-ID", tt.lastEventID)
+		}
+		rr := httptest.NewRecorder()
+		streamHandler.StreamAuction(rr, req)
+		assert.Equal(t, tt.want, rr.Code, tt.path)
+	}
+}
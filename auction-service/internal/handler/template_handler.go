@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/service"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+type TemplateHandler struct {
+	service service.TemplateService
+}
+
+func NewTemplateHandler(templateService service.TemplateService) *TemplateHandler {
+	return &TemplateHandler{service: templateService}
+}
+
+// GetTemplates returns the auction templates of the user.
+func (h *TemplateHandler) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	templates, err := h.service.GetTemplates(userID)
+	if err != nil {
+		writeServiceError(w, "fetching templates", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(templates)
+}
+
+// GetTemplate returns an auction template of the user.
+func (h *TemplateHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	templateID, ok := idFromPath(w, r, `^/templates/(\d+)$`, "template")
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	template, err := h.service.GetTemplate(userID, templateID)
+	if err != nil {
+		writeServiceError(w, "fetching template", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(template)
+}
+
+// CreateTemplate saves an auction template for the user.
+func (h *TemplateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var template model.AuctionTemplate
+	if err := json.NewDecoder(r.Body).Decode(&template); err != nil {
+		http.Error(w, "Invalid JSON request body", http.StatusBadRequest)
+		return
+	}
+	template.ID = 0
+	template.UserID = userID
+
+	created, err := h.service.CreateTemplate(template)
+	if err != nil {
+		writeServiceError(w, "creating template", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// UpdateTemplate replaces an auction template of the user.
+func (h *TemplateHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	templateID, ok := idFromPath(w, r, `^/templates/update/(\d+)$`, "template")
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var template model.AuctionTemplate
+	if err := json.NewDecoder(r.Body).Decode(&template); err != nil {
+		http.Error(w, "Invalid JSON request body", http.StatusBadRequest)
+		return
+	}
+	template.ID = templateID
+
+	updated, err := h.service.UpdateTemplate(userID, template)
+	if err != nil {
+		writeServiceError(w, "updating template", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DeleteTemplate deletes an auction template of the user.
+func (h *TemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	templateID, ok := idFromPath(w, r, `^/templates/delete/(\d+)$`, "template")
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteTemplate(userID, templateID); err != nil {
+		writeServiceError(w, "deleting template", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListFromTemplate creates an auction from an auction template of the user.
+// The body, with the start time, is optional.
+func (h *TemplateHandler) ListFromTemplate(w http.ResponseWriter, r *http.Request) {
+	templateID, ok := idFromPath(w, r, `^/templates/use/(\d+)$`, "template")
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var request scheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid JSON request body", http.StatusBadRequest)
+		return
+	}
+
+	auction, err := h.service.ListFromTemplate(userID, templateID, request.StartsAt)
+	if err != nil {
+		writeServiceError(w, "listing auction from template", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(auction)
+}
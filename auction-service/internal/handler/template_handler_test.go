@@ -0,0 +1,142 @@
+package handler_test
+
+import (
+	"auction-service/internal/handler"
+	"auction-service/internal/model"
+	"auction-service/internal/service"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTemplateService is a mock implementation of the TemplateService interface
+type MockTemplateService struct {
+	mock.Mock
+}
+
+func (m *MockTemplateService) CreateTemplate(template model.AuctionTemplate) (model.AuctionTemplate, error) {
+	args := m.Called(template)
+	return args.Get(0).(model.AuctionTemplate), args.Error(1)
+}
+
+func (m *MockTemplateService) GetTemplates(userID int) ([]model.AuctionTemplate, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.AuctionTemplate), args.Error(1)
+}
+
+func (m *MockTemplateService) GetTemplate(userID, id int) (model.AuctionTemplate, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(model.AuctionTemplate), args.Error(1)
+}
+
+func (m *MockTemplateService) UpdateTemplate(userID int, template model.AuctionTemplate) (model.AuctionTemplate, error) {
+	args := m.Called(userID, template)
+	return args.Get(0).(model.AuctionTemplate), args.Error(1)
+}
+
+func (m *MockTemplateService) DeleteTemplate(userID, id int) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *MockTemplateService) ListFromTemplate(userID, id int, startsAt *time.Time) (model.Auction, error) {
+	args := m.Called(userID, id, startsAt)
+	return args.Get(0).(model.Auction), args.Error(1)
+}
+
+func TestCreateTemplate(t *testing.T) {
+	mockService := new(MockTemplateService)
+	mockService.On("CreateTemplate", model.AuctionTemplate{Name: "Lamps", UserID: 2, Item: "Brass Lamp", DurationMinutes: 60}).
+		Return(model.AuctionTemplate{ID: 1, Name: "Lamps", UserID: 2, Item: "Brass Lamp", DurationMinutes: 60}, nil)
+
+	templateHandler := handler.NewTemplateHandler(mockService)
+
+	// The owner comes from the header, not the body.
+	req, err := http.NewRequest("POST", "/templates/create", bytes.NewBufferString(`{"ID": 7, "UserID": 9, "Name": "Lamps", "Item": "Brass Lamp", "DurationMinutes": 60}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(handler.UserIDHeader, "2")
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(templateHandler.CreateTemplate)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var created model.AuctionTemplate
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	assert.Equal(t, 1, created.ID)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetTemplate(t *testing.T) {
+	mockService := new(MockTemplateService)
+	mockService.On("GetTemplate", 2, 1).Return(model.AuctionTemplate{ID: 1, Name: "Lamps", UserID: 2}, nil)
+	mockService.On("GetTemplate", 3, 1).Return(model.AuctionTemplate{}, service.ErrTemplateNotFound)
+
+	templateHandler := handler.NewTemplateHandler(mockService)
+
+	for userID, want := range map[string]int{"2": http.StatusOK, "3": http.StatusNotFound, "": http.StatusUnauthorized} {
+		req, err := http.NewRequest("GET", "/templates/1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set(handler.UserIDHeader, userID)
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(templateHandler.GetTemplate)
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, want, rr.Code, "user %q", userID)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestDeleteTemplate(t *testing.T) {
+	mockService := new(MockTemplateService)
+	mockService.On("DeleteTemplate", 2, 1).Return(nil)
+
+	templateHandler := handler.NewTemplateHandler(mockService)
+
+	req, err := http.NewRequest("POST", "/templates/delete/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(handler.UserIDHeader, "2")
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(templateHandler.DeleteTemplate)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestListFromTemplate(t *testing.T) {
+	mockService := new(MockTemplateService)
+	mockService.On("ListFromTemplate", 2, 1, (*time.Time)(nil)).Return(model.Auction{ID: 5, Item: "Brass Lamp", UserID: 2, Status: model.AuctionStatusOpen}, nil)
+
+	templateHandler := handler.NewTemplateHandler(mockService)
+
+	req, err := http.NewRequest("POST", "/templates/use/1", bytes.NewBufferString(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(handler.UserIDHeader, "2")
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(templateHandler.ListFromTemplate)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var auction model.Auction
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &auction))
+	assert.Equal(t, 5, auction.ID)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"auction-service/internal/service"
+	"encoding/json"
+	"net/http"
+)
+
+type WatchlistHandler struct {
+	service service.WatchlistService
+}
+
+func NewWatchlistHandler(watchlistService service.WatchlistService) *WatchlistHandler {
+	return &WatchlistHandler{service: watchlistService}
+}
+
+// WatchAuction adds an auction to the watchlist of the user.
+func (h *WatchlistHandler) WatchAuction(w http.ResponseWriter, r *http.Request) {
+	auctionID, ok := auctionIDFromPath(w, r, `^/auctions/watch/(\d+)$`)
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	entry, err := h.service.WatchAuction(userID, auctionID)
+	if err != nil {
+		writeServiceError(w, "watching auction", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(entry)
+}
+
+// UnwatchAuction removes an auction from the watchlist of the user.
+func (h *WatchlistHandler) UnwatchAuction(w http.ResponseWriter, r *http.Request) {
+	auctionID, ok := auctionIDFromPath(w, r, `^/auctions/unwatch/(\d+)$`)
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.UnwatchAuction(userID, auctionID); err != nil {
+		writeServiceError(w, "unwatching auction", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetWatchlist returns the auctions the user watches.
+func (h *WatchlistHandler) GetWatchlist(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	auctions, err := h.service.GetWatchlist(userID)
+	if err != nil {
+		writeServiceError(w, "fetching watchlist", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(auctions)
+}
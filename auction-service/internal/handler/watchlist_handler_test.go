@@ -0,0 +1,122 @@
+package handler_test
+
+import (
+	"auction-service/internal/handler"
+	"auction-service/internal/model"
+	"auction-service/internal/service"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWatchlistService is a mock implementation of the WatchlistService interface
+type MockWatchlistService struct {
+	mock.Mock
+}
+
+func (m *MockWatchlistService) WatchAuction(userID, auctionID int) (model.WatchlistEntry, error) {
+	args := m.Called(userID, auctionID)
+	return args.Get(0).(model.WatchlistEntry), args.Error(1)
+}
+
+func (m *MockWatchlistService) UnwatchAuction(userID, auctionID int) error {
+	args := m.Called(userID, auctionID)
+	return args.Error(0)
+}
+
+func (m *MockWatchlistService) GetWatchlist(userID int) ([]model.Auction, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.Auction), args.Error(1)
+}
+
+func TestWatchAuction(t *testing.T) {
+	mockService := new(MockWatchlistService)
+	mockService.On("WatchAuction", 2, 1).Return(model.WatchlistEntry{ID: 1, UserID: 2, AuctionID: 1}, nil)
+	mockService.On("WatchAuction", 2, 2).Return(model.WatchlistEntry{}, service.ErrAuctionClosed)
+	mockService.On("WatchAuction", 2, 3).Return(model.WatchlistEntry{}, service.ErrNotFound)
+
+	watchlistHandler := handler.NewWatchlistHandler(mockService)
+
+	for auctionID, want := range map[int]int{1: http.StatusCreated, 2: http.StatusConflict, 3: http.StatusNotFound} {
+		req, err := http.NewRequest("POST", "/auctions/watch/"+strconv.Itoa(auctionID), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set(handler.UserIDHeader, "2")
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(watchlistHandler.WatchAuction)
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, want, rr.Code, "auction %d", auctionID)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestUnwatchAuction(t *testing.T) {
+	mockService := new(MockWatchlistService)
+	mockService.On("UnwatchAuction", 2, 1).Return(nil)
+
+	watchlistHandler := handler.NewWatchlistHandler(mockService)
+
+	req, err := http.NewRequest("POST", "/auctions/unwatch/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(handler.UserIDHeader, "2")
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(watchlistHandler.UnwatchAuction)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetWatchlist(t *testing.T) {
+	mockService := new(MockWatchlistService)
+	watched := []model.Auction{{ID: 1, Item: "Watched", UserID: 1, Status: model.AuctionStatusOpen}}
+	mockService.On("GetWatchlist", 2).Return(watched, nil)
+
+	watchlistHandler := handler.NewWatchlistHandler(mockService)
+
+	req, err := http.NewRequest("GET", "/watchlist", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(handler.UserIDHeader, "2")
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(watchlistHandler.GetWatchlist)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var returned []model.Auction
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returned))
+	if assert.Len(t, returned, 1) {
+		assert.Equal(t, "Watched", returned[0].Item)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestGetWatchlistRequiresUser(t *testing.T) {
+	mockService := new(MockWatchlistService)
+	watchlistHandler := handler.NewWatchlistHandler(mockService)
+
+	req, err := http.NewRequest("GET", "/watchlist", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(watchlistHandler.GetWatchlist)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	mockService.AssertNotCalled(t, "GetWatchlist")
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/service"
+	"encoding/json"
+	"net/http"
+)
+
+// WebhookHandler serves the admin endpoints that manage the webhook
+// subscriptions of partners. Routes are expected behind RequireAdmin.
+type WebhookHandler struct {
+	service service.WebhookService
+}
+
+func NewWebhookHandler(webhookService service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: webhookService}
+}
+
+// subscriptionRequest is the body of CreateSubscription and
+// UpdateSubscription. Secret is only read on creation.
+type subscriptionRequest struct {
+	URL         string   `json:"url"`
+	EventTypes  []string `json:"event_types"`
+	Description string   `json:"description"`
+	Secret      string   `json:"secret"`
+}
+
+// GetSubscriptions lists the webhook subscriptions.
+func (h *WebhookHandler) GetSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := h.service.GetSubscriptions()
+	if err != nil {
+		writeServiceError(w, "fetching webhook subscriptions", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(subscriptions)
+}
+
+// GetSubscription returns a webhook subscription.
+func (h *WebhookHandler) GetSubscription(w http.ResponseWriter, r *http.Request) {
+	id, ok := idFromPath(w, r, `^/admin/webhooks/(\d+)$`, "subscription")
+	if !ok {
+		return
+	}
+
+	subscription, err := h.service.GetSubscription(id)
+	if err != nil {
+		writeServiceError(w, "fetching webhook subscription", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(subscription)
+}
+
+// CreateSubscription registers a webhook endpoint. The response carries the
+// signing secret, which is not shown again.
+func (h *WebhookHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	var request subscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON request body", http.StatusBadRequest)
+		return
+	}
+
+	subscription, err := h.service.CreateSubscription(model.WebhookSubscription{
+		URL:         request.URL,
+		EventTypes:  request.EventTypes,
+		Description: request.Description,
+		Secret:      request.Secret,
+	})
+	if err != nil {
+		writeServiceError(w, "creating webhook subscription", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(subscription)
+}
+
+// UpdateSubscription changes the URL, event types and description of a
+// webhook subscription.
+func (h *WebhookHandler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	id, ok := idFromPath(w, r, `^/admin/webhooks/update/(\d+)$`, "subscription")
+	if !ok {
+		return
+	}
+
+	var request subscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON request body", http.StatusBadRequest)
+		return
+	}
+
+	subscription, err := h.service.UpdateSubscription(model.WebhookSubscription{
+		ID:          id,
+		URL:         request.URL,
+		EventTypes:  request.EventTypes,
+		Description: request.Description,
+	})
+	if err != nil {
+		writeServiceError(w, "updating webhook subscription", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(subscription)
+}
+
+// DeleteSubscription removes a webhook subscription and its delivery log.
+func (h *WebhookHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	id, ok := idFromPath(w, r, `^/admin/webhooks/delete/(\d+)$`, "subscription")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteSubscription(id); err != nil {
+		writeServiceError(w, "deleting webhook subscription", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// EnableSubscription turns a subscription disabled after failing deliveries
+// back on.
+func (h *WebhookHandler) EnableSubscription(w http.ResponseWriter, r *http.Request) {
+	id, ok := idFromPath(w, r, `^/admin/webhooks/enable/(\d+)$`, "subscription")
+	if !ok {
+		return
+	}
+
+	subscription, err := h.service.EnableSubscription(id)
+	if err != nil {
+		writeServiceError(w, "enabling webhook subscription", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(subscription)
+}
+
+// GetDeliveries returns the delivery log of a webhook subscription.
+func (h *WebhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, ok := idFromPath(w, r, `^/admin/webhooks/deliveries/(\d+)$`, "subscription")
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.service.GetDeliveries(id)
+	if err != nil {
+		writeServiceError(w, "fetching webhook deliveries", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deliveries)
+}
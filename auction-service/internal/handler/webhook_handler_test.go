@@ -0,0 +1,183 @@
+package handler_test
+
+import (
+	"auction-service/internal/handler"
+	"auction-service/internal/model"
+	"auction-service/internal/service"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWebhookService is a mock implementation of the WebhookService interface
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) CreateSubscription(subscription model.WebhookSubscription) (model.WebhookSubscription, error) {
+	args := m.Called(subscription)
+	return args.Get(0).(model.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookService) GetSubscriptions() ([]model.WebhookSubscription, error) {
+	args := m.Called()
+	return args.Get(0).([]model.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookService) GetSubscription(id int) (model.WebhookSubscription, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookService) UpdateSubscription(subscription model.WebhookSubscription) (model.WebhookSubscription, error) {
+	args := m.Called(subscription)
+	return args.Get(0).(model.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookService) DeleteSubscription(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) EnableSubscription(id int) (model.WebhookSubscription, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookService) GetDeliveries(subscriptionID int) ([]model.WebhookDelivery, error) {
+	args := m.Called(subscriptionID)
+	return args.Get(0).([]model.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookService) QueueEvent(event []byte) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func TestCreateWebhookSubscription(t *testing.T) {
+	mockService := new(MockWebhookService)
+	webhookHandler := handler.NewWebhookHandler(mockService)
+
+	request := model.WebhookSubscription{URL: "https://partner.example/hook", EventTypes: []string{model.EventBidPlaced}, Description: "Partner"}
+	created := request
+	created.ID = 1
+	created.Secret = "whsec_abc"
+	created.Active = true
+	mockService.On("CreateSubscription", request).Return(created, nil)
+
+	body := `{"url": "https://partner.example/hook", "event_types": ["bid.placed"], "description": "Partner"}`
+	req, _ := http.NewRequest("POST", "/admin/webhooks/create", bytes.NewBufferString(body))
+	rr := httptest.NewRecorder()
+	webhookHandler.CreateSubscription(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var returned model.WebhookSubscription
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returned))
+	assert.Equal(t, created, returned)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateWebhookSubscriptionInvalid(t *testing.T) {
+	mockService := new(MockWebhookService)
+	webhookHandler := handler.NewWebhookHandler(mockService)
+	mockService.On("CreateSubscription", mock.Anything).Return(model.WebhookSubscription{}, service.ErrInvalidInput)
+
+	req, _ := http.NewRequest("POST", "/admin/webhooks/create", bytes.NewBufferString(`{"url": "nope"}`))
+	rr := httptest.NewRecorder()
+	webhookHandler.CreateSubscription(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	req, _ = http.NewRequest("POST", "/admin/webhooks/create", bytes.NewBufferString(`{`))
+	rr = httptest.NewRecorder()
+	webhookHandler.CreateSubscription(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestUpdateWebhookSubscription(t *testing.T) {
+	mockService := new(MockWebhookService)
+	webhookHandler := handler.NewWebhookHandler(mockService)
+
+	changes := model.WebhookSubscription{ID: 1, URL: "https://partner.example/v2", EventTypes: []string{model.EventAuctionClosed}}
+	mockService.On("UpdateSubscription", changes).Return(changes, nil)
+	missing := changes
+	missing.ID = 2
+	mockService.On("UpdateSubscription", missing).Return(model.WebhookSubscription{}, service.ErrSubscriptionNotFound)
+
+	body := `{"url": "https://partner.example/v2", "event_types": ["auction.closed"], "secret": "ignored-on-update"}`
+	req, _ := http.NewRequest("PUT", "/admin/webhooks/update/1", bytes.NewBufferString(body))
+	rr := httptest.NewRecorder()
+	webhookHandler.UpdateSubscription(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	req, _ = http.NewRequest("PUT", "/admin/webhooks/update/2", bytes.NewBufferString(body))
+	rr = httptest.NewRecorder()
+	webhookHandler.UpdateSubscription(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestDeleteAndEnableWebhookSubscription(t *testing.T) {
+	mockService := new(MockWebhookService)
+	webhookHandler := handler.NewWebhookHandler(mockService)
+	mockService.On("DeleteSubscription", 1).Return(nil)
+	mockService.On("EnableSubscription", 2).Return(model.WebhookSubscription{ID: 2, Active: true}, nil)
+
+	req, _ := http.NewRequest("DELETE", "/admin/webhooks/delete/1", nil)
+	rr := httptest.NewRecorder()
+	webhookHandler.DeleteSubscription(rr, req)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	req, _ = http.NewRequest("POST", "/admin/webhooks/enable/2", nil)
+	rr = httptest.NewRecorder()
+	webhookHandler.EnableSubscription(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"Active":true`)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetWebhookDeliveries(t *testing.T) {
+	mockService := new(MockWebhookService)
+	webhookHandler := handler.NewWebhookHandler(mockService)
+	deliveries := []model.WebhookDelivery{{ID: 3, SubscriptionID: 1, EventID: "evt_1", EventType: model.EventBidPlaced, Status: model.WebhookDeliveryFailed, Attempts: 6, LastStatusCode: 500}}
+	mockService.On("GetDeliveries", 1).Return(deliveries, nil)
+	mockService.On("GetDeliveries", 2).Return([]model.WebhookDelivery(nil), service.ErrSubscriptionNotFound)
+
+	req, _ := http.NewRequest("GET", "/admin/webhooks/deliveries/1", nil)
+	rr := httptest.NewRecorder()
+	webhookHandler.GetDeliveries(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var returned []model.WebhookDelivery
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returned))
+	assert.Equal(t, deliveries, returned)
+
+	req, _ = http.NewRequest("GET", "/admin/webhooks/deliveries/2", nil)
+	rr = httptest.NewRecorder()
+	webhookHandler.GetDeliveries(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetWebhookSubscriptions(t *testing.T) {
+	mockService := new(MockWebhookService)
+	webhookHandler := handler.NewWebhookHandler(mockService)
+	subscriptions := []model.WebhookSubscription{{ID: 1, URL: "https://partner.example/hook", EventTypes: []string{model.EventBidPlaced}, Active: true}}
+	mockService.On("GetSubscriptions").Return(subscriptions, nil)
+	mockService.On("GetSubscription", 1).Return(subscriptions[0], nil)
+
+	req, _ := http.NewRequest("GET", "/admin/webhooks", nil)
+	rr := httptest.NewRecorder()
+	webhookHandler.GetSubscriptions(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "Secret")
+
+	req, _ = http.NewRequest("GET", "/admin/webhooks/1", nil)
+	rr = httptest.NewRecorder()
+	webhookHandler.GetSubscription(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
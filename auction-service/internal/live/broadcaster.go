@@ -0,0 +1,256 @@
+// Package live pushes auction events to the clients following an auction.
+// Events reach a replica twice: right after the commit when the change was
+// made on this replica, and through the relay broadcast that every replica
+// receives. They are told apart by the ID of their outbox message, which
+// also lets reconnecting clients resume where they left off. IDs are not
+// arrival order, though: the relay of another replica may deliver an event
+// after later ones made here, so resuming goes by what arrived after the
+// client's last event.
+package live
+
+import (
+	"encoding/json"
+	"log"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"auction-service/internal/messaging"
+	"auction-service/internal/model"
+)
+
+// DefaultHistorySize is how many recent events of each auction are kept for
+// clients that reconnect.
+const DefaultHistorySize = 100
+
+// DefaultMaxIdle is how long the events of an auction nobody follows are
+// kept after the last one, for auctions that are deleted or never close.
+const DefaultMaxIdle = time.Hour
+
+// subscriberBuffer is how many events may wait for a subscriber before it is
+// considered too slow and disconnected.
+const subscriberBuffer = 64
+
+// Event is an auction event as it is pushed to clients.
+type Event struct {
+	// ID is the ID of the outbox message of the event.
+	ID        int
+	Type      string
+	AuctionID int
+	// Data is the JSON encoded model.Event.
+	Data []byte
+}
+
+// Broadcaster keeps the recent events of every auction and hands new ones
+// to the subscriptions of the auction.
+type Broadcaster struct {
+	historySize int
+
+	mu    sync.Mutex
+	feeds map[int]*feed
+	// arrivals counts the events added, to order them by arrival.
+	arrivals int
+}
+
+type feed struct {
+	// history is ordered by ID.
+	history     []entry
+	subscribers map[*Subscription]bool
+	closed      bool
+	// active is when the feed last got an event or lost a subscriber.
+	active time.Time
+}
+
+type entry struct {
+	Event
+	arrival int
+}
+
+// NewBroadcaster creates a Broadcaster that keeps up to historySize events
+// per auction.
+func NewBroadcaster(historySize int) *Broadcaster {
+	return &Broadcaster{historySize: historySize, feeds: map[int]*feed{}}
+}
+
+// Notify receives the messages of a transaction committed on this replica.
+func (b *Broadcaster) Notify(messages []model.OutboxMessage) {
+	for _, message := range messages {
+		b.add(message.ID, message.Payload)
+	}
+}
+
+// Handle receives the model.LiveEvent messages broadcast by the outbox
+// relays. Malformed messages are logged and dropped.
+func (b *Broadcaster) Handle(d messaging.Delivery) error {
+	var event model.LiveEvent
+	if err := json.Unmarshal(d.Body, &event); err != nil {
+		log.Printf("Error decoding live event: %v", err)
+		return nil
+	}
+	b.add(event.ID, event.Event)
+	return nil
+}
+
+// Subscribe follows the events of an auction. It returns the kept events a
+// client that got lastEventID missed, to be sent before the ones arriving on
+// the subscription: those that arrived after it, in the order they arrived,
+// or, once it is no longer kept, those with a higher ID. The subscription
+// must be closed when no longer needed.
+func (b *Broadcaster) Subscribe(auctionID, lastEventID int) ([]Event, *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	f := b.feed(auctionID)
+	missed := f.since(lastEventID)
+
+	s := &Subscription{broadcaster: b, auctionID: auctionID, events: make(chan Event, subscriberBuffer)}
+	f.subscribers[s] = true
+	return missed, s
+}
+
+// Subscription receives the events of one auction.
+type Subscription struct {
+	broadcaster *Broadcaster
+	auctionID   int
+	events      chan Event
+}
+
+// Events returns the channel the events are delivered on. It is closed when
+// the subscription is closed, or when the subscriber fell too far behind;
+// the client can then resume from the last event it received.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close stops the subscription.
+func (s *Subscription) Close() {
+	b := s.broadcaster
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if f, ok := b.feeds[s.auctionID]; ok && f.subscribers[s] {
+		b.unsubscribe(s.auctionID, f, s)
+	}
+}
+
+// add records the event of an outbox message and hands it to the
+// subscribers, unless it is not streamed or was seen before.
+func (b *Broadcaster) add(id int, payload []byte) {
+	var event model.Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		log.Printf("Error decoding live event %d: %v", id, err)
+		return
+	}
+	if !streamed(event) {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	f := b.feed(event.AuctionID)
+	i := sort.Search(len(f.history), func(i int) bool { return f.history[i].ID >= id })
+	if i < len(f.history) && f.history[i].ID == id {
+		return
+	}
+	if i == 0 && len(f.history) >= b.historySize {
+		// Older than everything kept: it was seen and forgotten already.
+		return
+	}
+	e := Event{ID: id, Type: event.Type, AuctionID: event.AuctionID, Data: payload}
+	b.arrivals++
+	f.history = slices.Insert(f.history, i, entry{Event: e, arrival: b.arrivals})
+	if len(f.history) > b.historySize {
+		f.history = slices.Delete(f.history, 0, 1)
+	}
+	if event.Type == model.EventAuctionClosed {
+		f.closed = true
+	}
+	f.active = time.Now()
+
+	for s := range f.subscribers {
+		select {
+		case s.events <- e:
+		default:
+			log.Printf("Disconnecting slow live subscriber of auction %d", event.AuctionID)
+			b.unsubscribe(event.AuctionID, f, s)
+		}
+	}
+	b.forget(event.AuctionID, f)
+}
+
+// feed returns the feed of an auction, creating it if needed. It must be
+// called with b.mu held.
+func (b *Broadcaster) feed(auctionID int) *feed {
+	f, ok := b.feeds[auctionID]
+	if !ok {
+		f = &feed{subscribers: map[*Subscription]bool{}}
+		b.feeds[auctionID] = f
+	}
+	return f
+}
+
+// unsubscribe removes s from f and closes its channel. It must be called
+// with b.mu held.
+func (b *Broadcaster) unsubscribe(auctionID int, f *feed, s *Subscription) {
+	delete(f.subscribers, s)
+	close(s.events)
+	f.active = time.Now()
+	b.forget(auctionID, f)
+}
+
+// Expire drops the feeds nobody follows that have been idle since before
+// idleSince. It is meant to be called periodically.
+func (b *Broadcaster) Expire(idleSince time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for auctionID, f := range b.feeds {
+		if len(f.subscribers) == 0 && f.active.Before(idleSince) {
+			delete(b.feeds, auctionID)
+		}
+	}
+}
+
+// since returns the kept events a client that got lastEventID has not seen.
+func (f *feed) since(lastEventID int) []Event {
+	var missed []entry
+	i := sort.Search(len(f.history), func(i int) bool { return f.history[i].ID >= lastEventID })
+	if lastEventID > 0 && i < len(f.history) && f.history[i].ID == lastEventID {
+		last := f.history[i].arrival
+		for _, e := range f.history {
+			if e.arrival > last {
+				missed = append(missed, e)
+			}
+		}
+		slices.SortFunc(missed, func(a, b entry) int { return a.arrival - b.arrival })
+	} else {
+		missed = f.history[i:]
+	}
+	events := make([]Event, len(missed))
+	for i, e := range missed {
+		events[i] = e.Event
+	}
+	return events
+}
+
+// forget drops the feed of an auction once nobody follows it and it is
+// closed or has nothing to replay, so memory does not grow with every
+// auction ever streamed; Expire drops the others once idle. It must be
+// called with b.mu held.
+func (b *Broadcaster) forget(auctionID int, f *feed) {
+	if len(f.subscribers) == 0 && (f.closed || len(f.history) == 0) {
+		delete(b.feeds, auctionID)
+	}
+}
+
+// streamed tells whether clients are sent events of this kind.
+func streamed(event model.Event) bool {
+	switch event.Type {
+	case model.EventPriceChanged, model.EventAuctionExtended, model.EventAuctionClosed:
+		return true
+	case model.EventBidPlaced:
+		// Sealed bids carry no amount and stay hidden until the auction closes.
+		return event.Amount != nil
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+package live_test
+
+import (
+	"auction-service/internal/live"
+	"auction-service/internal/messaging"
+	"auction-service/internal/model"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func message(t *testing.T, id int, event model.Event) model.OutboxMessage {
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+	return model.OutboxMessage{ID: id, Type: event.Type, Payload: payload}
+}
+
+func priceChanged(t *testing.T, id, auctionID int, minor int64) model.OutboxMessage {
+	amount := model.NewMoney(minor, "USD")
+	return message(t, id, model.Event{Type: model.EventPriceChanged, AuctionID: auctionID, Amount: &amount})
+}
+
+func ids(events []live.Event) []int {
+	var ids []int
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+// received returns the events waiting on s without blocking.
+func received(s *live.Subscription) []live.Event {
+	var events []live.Event
+	for {
+		select {
+		case event, ok := <-s.Events():
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestBroadcasterStreamsAuctionEvents(t *testing.T) {
+	b := live.NewBroadcaster(live.DefaultHistorySize)
+	_, s := b.Subscribe(1, 0)
+	defer s.Close()
+
+	amount := model.NewMoney(2000, "USD")
+	b.Notify([]model.OutboxMessage{
+		message(t, 1, model.Event{Type: model.EventBidPlaced, AuctionID: 1, UserID: 2, Amount: &amount}),
+		priceChanged(t, 2, 1, 2000),
+		// Sealed bids and events of other kinds or auctions are not streamed.
+		message(t, 3, model.Event{Type: model.EventBidPlaced, AuctionID: 1, UserID: 3}),
+		message(t, 4, model.Event{Type: model.EventAuctionUpdated, AuctionID: 1}),
+		priceChanged(t, 5, 2, 100),
+		message(t, 6, model.Event{Type: model.EventAuctionClosed, AuctionID: 1}),
+	})
+
+	events := received(s)
+	assert.Equal(t, []int{1, 2, 6}, ids(events))
+	assert.Equal(t, model.EventBidPlaced, events[0].Type)
+	var event model.Event
+	require.NoError(t, json.Unmarshal(events[1].Data, &event))
+	assert.Equal(t, amount, *event.Amount)
+}
+
+func TestBroadcasterSkipsEventsSeenBefore(t *testing.T) {
+	b := live.NewBroadcaster(live.DefaultHistorySize)
+	_, s := b.Subscribe(1, 0)
+	defer s.Close()
+
+	// Changes made on this replica come back through the relay broadcast,
+	// interleaved with those of other replicas.
+	b.Notify([]model.OutboxMessage{priceChanged(t, 2, 1, 200)})
+	for _, m := range []model.OutboxMessage{priceChanged(t, 1, 1, 100), priceChanged(t, 2, 1, 200), priceChanged(t, 3, 1, 300)} {
+		body, err := json.Marshal(model.LiveEvent{ID: m.ID, Event: m.Payload})
+		require.NoError(t, err)
+		assert.NoError(t, b.Handle(messaging.Delivery{Body: body}))
+	}
+	assert.NoError(t, b.Handle(messaging.Delivery{Body: []byte("not json")}))
+
+	assert.Equal(t, []int{2, 1, 3}, ids(received(s)))
+
+	// Replays are in ID order.
+	missed, again := b.Subscribe(1, 0)
+	defer again.Close()
+	assert.Equal(t, []int{1, 2, 3}, ids(missed))
+}
+
+func TestBroadcasterResumesAfterLateEvents(t *testing.T) {
+	b := live.NewBroadcaster(live.DefaultHistorySize)
+	relayed := func(m model.OutboxMessage) {
+		body, err := json.Marshal(model.LiveEvent{ID: m.ID, Event: m.Payload})
+		require.NoError(t, err)
+		require.NoError(t, b.Handle(messaging.Delivery{Body: body}))
+	}
+
+	// A client got event 2, made here, and reconnects before event 1 of
+	// another replica comes in through the relay.
+	b.Notify([]model.OutboxMessage{priceChanged(t, 2, 1, 200)})
+	relayed(priceChanged(t, 1, 1, 100))
+	relayed(priceChanged(t, 3, 1, 300))
+
+	missed, s := b.Subscribe(1, 2)
+	defer s.Close()
+	assert.Equal(t, []int{1, 3}, ids(missed))
+
+	missed, again := b.Subscribe(1, 1)
+	defer again.Close()
+	assert.Equal(t, []int{3}, ids(missed))
+}
+
+func TestBroadcasterExpiresIdleFeeds(t *testing.T) {
+	b := live.NewBroadcaster(live.DefaultHistorySize)
+	_, followed := b.Subscribe(2, 0)
+	defer followed.Close()
+	b.Notify([]model.OutboxMessage{priceChanged(t, 1, 1, 100), priceChanged(t, 2, 2, 200)})
+
+	b.Expire(time.Now().Add(-time.Hour))
+	missed, s := b.Subscribe(1, 0)
+	assert.Equal(t, []int{1}, ids(missed))
+	s.Close()
+
+	// Followed auctions are kept however long they stay quiet.
+	b.Expire(time.Now().Add(time.Hour))
+	missed, s = b.Subscribe(1, 0)
+	defer s.Close()
+	assert.Empty(t, missed)
+	missed, again := b.Subscribe(2, 0)
+	defer again.Close()
+	assert.Equal(t, []int{2}, ids(missed))
+}
+
+func TestBroadcasterReplaysMissedEvents(t *testing.T) {
+	b := live.NewBroadcaster(3)
+	for id := 1; id <= 5; id++ {
+		b.Notify([]model.OutboxMessage{priceChanged(t, id, 1, int64(id))})
+	}
+
+	missed, s := b.Subscribe(1, 3)
+	defer s.Close()
+	assert.Equal(t, []int{4, 5}, ids(missed))
+
+	// Only the last three events are kept.
+	missed, s = b.Subscribe(1, 0)
+	defer s.Close()
+	assert.Equal(t, []int{3, 4, 5}, ids(missed))
+
+	// An event older than the kept ones was forgotten, not missed.
+	b.Notify([]model.OutboxMessage{priceChanged(t, 1, 1, 1)})
+	assert.Empty(t, received(s))
+}
+
+func TestBroadcasterDisconnectsSlowSubscribers(t *testing.T) {
+	b := live.NewBroadcaster(1000)
+	_, slow := b.Subscribe(1, 0)
+	defer slow.Close()
+
+	for id := 1; id <= 100; id++ {
+		b.Notify([]model.OutboxMessage{priceChanged(t, id, 1, int64(id))})
+	}
+
+	events := received(slow)
+	assert.Less(t, len(events), 100)
+	_, open := <-slow.Events()
+	assert.False(t, open)
+
+	// The subscriber resumes from the last event it got.
+	missed, s := b.Subscribe(1, events[len(events)-1].ID)
+	defer s.Close()
+	assert.Equal(t, 100, len(events)+len(missed))
+}
+
+func TestBroadcasterForgetsClosedAuctions(t *testing.T) {
+	b := live.NewBroadcaster(live.DefaultHistorySize)
+	_, s := b.Subscribe(1, 0)
+	b.Notify([]model.OutboxMessage{
+		priceChanged(t, 1, 1, 100),
+		message(t, 2, model.Event{Type: model.EventAuctionClosed, AuctionID: 1}),
+	})
+
+	// Followers of the auction still get to replay the end of it.
+	missed, other := b.Subscribe(1, 1)
+	assert.Equal(t, []int{2}, ids(missed))
+	other.Close()
+
+	s.Close()
+	missed, s = b.Subscribe(1, 0)
+	defer s.Close()
+	assert.Empty(t, missed)
+}
@@ -0,0 +1,76 @@
+package markdown
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+)
+
+// DefaultCacheSize is how many rendered sources a Cache keeps by default.
+const DefaultCacheSize = 1000
+
+// Cache keeps the HTML of recently rendered sources, keyed on the SHA-256
+// of the source, so sources read over and over are rendered once. It is
+// safe for concurrent use.
+type Cache struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]*list.Element
+	// recent orders the entries from the most to the least recently used.
+	recent *list.List
+}
+
+type cacheEntry struct {
+	key  [sha256.Size]byte
+	html string
+}
+
+// NewCache creates a Cache keeping the HTML of up to size sources.
+func NewCache(size int) *Cache {
+	return &Cache{size: max(size, 1), entries: map[[sha256.Size]byte]*list.Element{}, recent: list.New()}
+}
+
+// Render returns Render(source), rendering it only when it is not cached.
+func (c *Cache) Render(source string) string {
+	if source == "" {
+		return ""
+	}
+	key := sha256.Sum256([]byte(source))
+	if html, ok := c.get(key); ok {
+		return html
+	}
+
+	// Rendering happens outside the lock; two goroutines rendering the
+	// same source at once only do the work twice.
+	html := Render(source)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok {
+		c.entries[key] = c.recent.PushFront(&cacheEntry{key: key, html: html})
+		if c.recent.Len() > c.size {
+			oldest := c.recent.Back()
+			c.recent.Remove(oldest)
+			delete(c.entries, oldest.Value.(*cacheEntry).key)
+		}
+	}
+	return html
+}
+
+// Len returns how many sources are cached.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.recent.Len()
+}
+
+func (c *Cache) get(key [sha256.Size]byte) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	c.recent.MoveToFront(element)
+	return element.Value.(*cacheEntry).html, true
+}
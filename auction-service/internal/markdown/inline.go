@@ -0,0 +1,466 @@
+package markdown
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// maxLinkText bounds how far a [ looks for its ], so unbalanced brackets
+// cannot make rendering quadratic.
+const maxLinkText = 1000
+
+// Schemes links and images may point to. URLs without a scheme are
+// relative and allowed too.
+var (
+	linkSchemes  = []string{"http", "https", "mailto"}
+	imageSchemes = []string{"http", "https"}
+)
+
+var (
+	entityPattern   = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[A-Za-z][A-Za-z0-9]{1,31});`)
+	autolinkPattern = regexp.MustCompile(`^<([A-Za-z][A-Za-z0-9+.-]{1,31}:[^\s<>]*)>`)
+	emailPattern    = regexp.MustCompile(`^<([A-Za-z0-9.!#$%&'*+/=?^_{|}~-]+@[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-z0-9])?(?:\.[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-z0-9])?)*)>`)
+	tagPattern      = regexp.MustCompile(`^</?[A-Za-z][A-Za-z0-9-]*(?:\s[^<>]*)?/?>`)
+	markupPattern   = regexp.MustCompile(`<[^>]*>`)
+)
+
+// inline renders the inline content of a block.
+type inline struct {
+	b    *strings.Builder
+	text string
+	// inLink is set in the text of links, where links are not rendered.
+	inLink bool
+	// unclosed remembers, for each delimiter, the position from which it
+	// is known to have no closer. Whether a delimiter run can close only
+	// depends on the run, so a failed search settles every later one.
+	unclosed map[string]int
+}
+
+// renderInline renders text, the content of a paragraph or heading.
+func renderInline(b *strings.Builder, text string, inLink bool) {
+	p := inline{b: b, text: text, inLink: inLink, unclosed: map[string]int{}}
+	p.render()
+}
+
+func (p *inline) render() {
+	text := p.text
+	for i := 0; i < len(text); {
+		next := strings.IndexAny(text[i:], "\\`*_~![<& \n")
+		if next < 0 {
+			p.b.WriteString(html.EscapeString(text[i:]))
+			return
+		}
+		p.b.WriteString(html.EscapeString(text[i : i+next]))
+		i += next
+
+		switch c := text[i]; c {
+		case '\\':
+			i = p.escape(i)
+		case '`':
+			i = p.codeSpan(i)
+		case '*', '_', '~':
+			i = p.emphasis(i)
+		case '!':
+			if strings.HasPrefix(text[i:], "![") {
+				if end, ok := p.image(i); ok {
+					i = end
+					continue
+				}
+			}
+			p.b.WriteString("!")
+			i++
+		case '[':
+			if end, ok := p.link(i); ok {
+				i = end
+				continue
+			}
+			p.b.WriteString("[")
+			i++
+		case '<':
+			i = p.angle(i)
+		case '&':
+			if entity := entityPattern.FindString(text[i:]); entity != "" {
+				p.b.WriteString(html.EscapeString(html.UnescapeString(entity)))
+				i += len(entity)
+				continue
+			}
+			p.b.WriteString("&amp;")
+			i++
+		case ' ':
+			i = p.spaces(i)
+		case '\n':
+			p.b.WriteString("\n")
+			i++
+		}
+	}
+}
+
+// escape handles a backslash: before punctuation it makes the character
+// literal, at the end of a line it is a hard line break.
+func (p *inline) escape(i int) int {
+	if i+1 < len(p.text) {
+		next := p.text[i+1]
+		if next == '\n' {
+			p.b.WriteString("<br>\n")
+			return i + 2
+		}
+		if next < 0x80 && strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", next) >= 0 {
+			p.b.WriteString(html.EscapeString(string(next)))
+			return i + 2
+		}
+	}
+	p.b.WriteString("\\")
+	return i + 1
+}
+
+// spaces writes a run of spaces, or a hard line break when two or more end
+// a line.
+func (p *inline) spaces(i int) int {
+	j := i
+	for j < len(p.text) && p.text[j] == ' ' {
+		j++
+	}
+	if j < len(p.text) && p.text[j] == '\n' {
+		if j-i >= 2 {
+			p.b.WriteString("<br>\n")
+		} else {
+			p.b.WriteString("\n")
+		}
+		return j + 1
+	}
+	p.b.WriteString(p.text[i:j])
+	return j
+}
+
+// codeSpan renders a code span, closed by a run of as many backticks as
+// opened it. Unclosed runs are literal.
+func (p *inline) codeSpan(i int) int {
+	run := delimiterRun(p.text, i)
+	fence := p.text[i : i+run]
+	for j := i + run; ; {
+		k := strings.Index(p.text[j:], fence)
+		if k < 0 {
+			p.b.WriteString(fence)
+			return i + run
+		}
+		k += j
+		if closing := delimiterRun(p.text, k); closing != run {
+			j = k + closing
+			continue
+		}
+		code := strings.ReplaceAll(p.text[i+run:k], "\n", " ")
+		if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+			code = code[1 : len(code)-1]
+		}
+		p.b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+		return k + run
+	}
+}
+
+// emphasis renders *em*, _em_, **strong**, __strong__, ***both*** and
+// ~~strikethrough~~. Delimiters without a closer are literal.
+func (p *inline) emphasis(i int) int {
+	c := p.text[i]
+	run := delimiterRun(p.text, i)
+	if c == '~' && run != 2 || run > 3 || !p.canOpen(i, run) {
+		p.b.WriteString(p.text[i : i+run])
+		return i + run
+	}
+	delimiter := p.text[i : i+run]
+	end, ok := p.closer(i+run, delimiter)
+	if !ok {
+		p.b.WriteString(delimiter)
+		return i + run
+	}
+
+	var openTag, closeTag string
+	switch {
+	case c == '~':
+		openTag, closeTag = "<del>", "</del>"
+	case run == 1:
+		openTag, closeTag = "<em>", "</em>"
+	case run == 2:
+		openTag, closeTag = "<strong>", "</strong>"
+	default:
+		openTag, closeTag = "<em><strong>", "</strong></em>"
+	}
+	p.b.WriteString(openTag)
+	renderInline(p.b, p.text[i+run:end], p.inLink)
+	p.b.WriteString(closeTag)
+	return end + run
+}
+
+// canOpen reports whether the delimiter run of length run at i can open
+// emphasis: it is followed by a non-space, and an underscore is not inside
+// a word, as in snake_case.
+func (p *inline) canOpen(i, run int) bool {
+	after := i + run
+	if after >= len(p.text) || isSpace(p.text[after]) {
+		return false
+	}
+	return p.text[i] != '_' || i == 0 || !isWordByte(p.text[i-1])
+}
+
+// closer finds the next run of exactly delimiter from start that can close
+// emphasis and returns its position.
+func (p *inline) closer(start int, delimiter string) (int, bool) {
+	if from, ok := p.unclosed[delimiter]; ok && start >= from {
+		return 0, false
+	}
+	for j := start; ; {
+		k := strings.Index(p.text[j:], delimiter)
+		if k < 0 {
+			p.unclosed[delimiter] = start
+			return 0, false
+		}
+		k += j
+		run := delimiterRun(p.text, k)
+		end := k + run
+		if run == len(delimiter) && k > start && !isSpace(p.text[k-1]) && p.text[k-1] != '\\' &&
+			(delimiter[0] != '_' || end == len(p.text) || !isWordByte(p.text[end])) {
+			return k, true
+		}
+		j = end
+	}
+}
+
+// link renders [text](url "title") at i. It reports false when there is no
+// link there.
+func (p *inline) link(i int) (int, bool) {
+	text, destination, title, end, ok := linkParts(p.text, i+1)
+	if !ok {
+		return 0, false
+	}
+	href, safe := safeURL(destination, linkSchemes)
+	if p.inLink || !safe {
+		renderInline(p.b, text, p.inLink)
+		return end, true
+	}
+	p.b.WriteString(`<a href="` + html.EscapeString(href) + `"`)
+	if title != "" {
+		p.b.WriteString(` title="` + html.EscapeString(title) + `"`)
+	}
+	p.b.WriteString(` rel="nofollow ugc noopener">`)
+	renderInline(p.b, text, true)
+	p.b.WriteString("</a>")
+	return end, true
+}
+
+// image renders ![alt](url "title") at i. Images with unsafe URLs turn into
+// their alt text.
+func (p *inline) image(i int) (int, bool) {
+	alt, destination, title, end, ok := linkParts(p.text, i+2)
+	if !ok {
+		return 0, false
+	}
+	alt = plainText(alt)
+	src, safe := safeURL(destination, imageSchemes)
+	if !safe {
+		p.b.WriteString(html.EscapeString(alt))
+		return end, true
+	}
+	p.b.WriteString(`<img src="` + html.EscapeString(src) + `" alt="` + html.EscapeString(alt) + `"`)
+	if title != "" {
+		p.b.WriteString(` title="` + html.EscapeString(title) + `"`)
+	}
+	p.b.WriteString(">")
+	return end, true
+}
+
+// angle handles a < at i: autolinks become links, raw HTML tags are dropped,
+// and script-like elements and comments are dropped with their content.
+func (p *inline) angle(i int) int {
+	rest := p.text[i:]
+	if m := autolinkPattern.FindStringSubmatch(rest); m != nil {
+		if href, safe := safeURL(m[1], linkSchemes); safe && !p.inLink {
+			p.b.WriteString(`<a href="` + html.EscapeString(href) + `" rel="nofollow ugc noopener">` + html.EscapeString(m[1]) + "</a>")
+		} else {
+			p.b.WriteString(html.EscapeString(m[1]))
+		}
+		return i + len(m[0])
+	}
+	if m := emailPattern.FindStringSubmatch(rest); m != nil {
+		if p.inLink {
+			p.b.WriteString(html.EscapeString(m[1]))
+		} else {
+			p.b.WriteString(`<a href="mailto:` + html.EscapeString(m[1]) + `">` + html.EscapeString(m[1]) + "</a>")
+		}
+		return i + len(m[0])
+	}
+	if end := droppedHTMLEnd(rest); end != "" {
+		k := strings.Index(asciiLower(rest), end)
+		if k < 0 {
+			return len(p.text)
+		}
+		k += len(end)
+		if end != "-->" {
+			// Skip the rest of the closing tag, as in "</script >".
+			if gt := strings.IndexByte(rest[k:], '>'); gt >= 0 {
+				k += gt + 1
+			}
+		}
+		return i + k
+	}
+	if tag := tagPattern.FindString(rest); tag != "" {
+		return i + len(tag)
+	}
+	p.b.WriteString("&lt;")
+	return i + 1
+}
+
+// linkParts parses the "text](destination "title")" of a link or image
+// starting at start, just after its [, and returns where it ends.
+func linkParts(text string, start int) (label, destination, title string, end int, ok bool) {
+	depth := 0
+	closing := -1
+	for j := start; j < len(text) && j-start <= maxLinkText; j++ {
+		switch text[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+		if depth < 0 {
+			closing = j
+			break
+		}
+	}
+	if closing < 0 || closing+1 >= len(text) || text[closing+1] != '(' {
+		return "", "", "", 0, false
+	}
+	label = text[start:closing]
+
+	j := closing + 2
+	for j < len(text) && isSpace(text[j]) {
+		j++
+	}
+	if j < len(text) && text[j] == '<' {
+		k := strings.IndexAny(text[j+1:], ">\n")
+		if k < 0 || text[j+1+k] != '>' {
+			return "", "", "", 0, false
+		}
+		destination = text[j+1 : j+1+k]
+		j += k + 2
+	} else {
+		parens := 0
+		k := j
+		for ; k < len(text) && !isSpace(text[k]); k++ {
+			if text[k] == '\\' && k+1 < len(text) {
+				k++
+				continue
+			}
+			if text[k] == '(' {
+				parens++
+			}
+			if text[k] == ')' {
+				if parens == 0 {
+					break
+				}
+				parens--
+			}
+		}
+		destination = text[j:k]
+		j = k
+	}
+	for j < len(text) && isSpace(text[j]) {
+		j++
+	}
+	if j < len(text) && strings.IndexByte(`"'(`, text[j]) >= 0 {
+		quote := text[j]
+		if quote == '(' {
+			quote = ')'
+		}
+		k := j + 1
+		for ; k < len(text) && text[k] != quote; k++ {
+			if text[k] == '\\' {
+				k++
+			}
+		}
+		if k >= len(text) {
+			return "", "", "", 0, false
+		}
+		title = unescape(text[j+1 : k])
+		j = k + 1
+		for j < len(text) && isSpace(text[j]) {
+			j++
+		}
+	}
+	if j >= len(text) || text[j] != ')' {
+		return "", "", "", 0, false
+	}
+	return label, unescape(destination), title, j + 1, true
+}
+
+// safeURL returns the URL to use for a link or image, and false when its
+// scheme is not one of schemes. URLs without a scheme are relative and
+// safe.
+func safeURL(raw string, schemes []string) (string, bool) {
+	// Browsers ignore tabs and newlines in URLs, so "java\tscript:" must be
+	// checked as "javascript:"; control characters have no business there
+	// either.
+	cleaned := strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(raw))
+	if cleaned == "" {
+		return "", false
+	}
+	u, err := url.Parse(cleaned)
+	if err != nil {
+		return "", false
+	}
+	if u.Scheme == "" {
+		// Some browsers would see a scheme in a colon before any slash,
+		// question mark or hash.
+		if k := strings.IndexAny(cleaned, ":/?#"); k >= 0 && cleaned[k] == ':' {
+			return "", false
+		}
+		return cleaned, true
+	}
+	return cleaned, slices.Contains(schemes, strings.ToLower(u.Scheme))
+}
+
+// plainText strips the Markdown of the alt text of an image, which cannot
+// hold markup. The rendered text only has tags of its own, any < of the
+// source being escaped, so dropping everything between < and > is enough.
+func plainText(text string) string {
+	var b strings.Builder
+	renderInline(&b, text, true)
+	return html.UnescapeString(markupPattern.ReplaceAllString(b.String(), ""))
+}
+
+// unescape removes the backslashes of backslash escapes.
+func unescape(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' && i+1 < len(text) && strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", text[i+1]) >= 0 {
+			i++
+		}
+		b.WriteByte(text[i])
+	}
+	return b.String()
+}
+
+// delimiterRun returns how many times the character at i repeats from i.
+func delimiterRun(text string, i int) int {
+	j := i
+	for j < len(text) && text[j] == text[i] {
+		j++
+	}
+	return j - i
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\n'
+}
+
+func isWordByte(c byte) bool {
+	return c >= 0x80 || c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,417 @@
+// Package markdown renders the Markdown sellers write in auction
+// descriptions to HTML that is safe to embed in any page.
+//
+// It supports paragraphs, ATX headings, emphasis, strikethrough, code spans
+// and blocks, block quotes, lists, thematic breaks, hard line breaks, links,
+// images and autolinks. The HTML is built from those elements only: raw HTML
+// in the source is not copied through. Tags are dropped, keeping the text
+// around them, and script, style and similar elements are dropped with their
+// content. Links keep only http, https, mailto and relative URLs, and images
+// only http, https and relative ones, so the output never carries scripts,
+// inline event handlers or javascript: URLs.
+package markdown
+
+import (
+	"html"
+	"strconv"
+	"strings"
+)
+
+// Render turns Markdown source into sanitized HTML.
+func Render(source string) string {
+	var b strings.Builder
+	renderBlocks(&b, splitLines(source), false)
+	return b.String()
+}
+
+// splitLines normalizes line endings, tabs and NUL characters and splits
+// source into lines.
+func splitLines(source string) []string {
+	source = strings.NewReplacer("\r\n", "\n", "\r", "\n", "\x00", "�").Replace(source)
+	lines := strings.Split(source, "\n")
+	for i, line := range lines {
+		lines[i] = strings.ReplaceAll(line, "\t", "    ")
+	}
+	return lines
+}
+
+// renderBlocks renders lines as a sequence of blocks. In tight lists the
+// paragraphs of items are not wrapped in <p> elements.
+func renderBlocks(b *strings.Builder, lines []string, tight bool) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case isBlank(line):
+			i++
+		case indentOf(line) >= 4:
+			i = renderIndentedCode(b, lines, i)
+		case isFence(line):
+			i = renderFencedCode(b, lines, i)
+		case isHeading(line):
+			level, text := heading(line)
+			tag := "h" + strconv.Itoa(level)
+			b.WriteString("<" + tag + ">")
+			renderInline(b, text, false)
+			b.WriteString("</" + tag + ">\n")
+			i++
+		case isThematicBreak(line):
+			b.WriteString("<hr>\n")
+			i++
+		case isQuote(line):
+			i = renderQuote(b, lines, i)
+		case isListItem(line):
+			i = renderList(b, lines, i)
+		case isDroppedHTML(line):
+			i = skipDroppedHTML(lines, i)
+		default:
+			i = renderParagraph(b, lines, i, tight)
+		}
+	}
+}
+
+// renderIndentedCode renders the code block indented by four spaces that
+// starts at lines[i] and returns the index of the line after it.
+func renderIndentedCode(b *strings.Builder, lines []string, i int) int {
+	var code []string
+	for ; i < len(lines) && (isBlank(lines[i]) || indentOf(lines[i]) >= 4); i++ {
+		code = append(code, strings.TrimPrefix(lines[i], "    "))
+	}
+	for len(code) > 0 && isBlank(code[len(code)-1]) {
+		code = code[:len(code)-1]
+	}
+	writeCode(b, code, "")
+	return i
+}
+
+// renderFencedCode renders the code block fenced with ``` or ~~~ that starts
+// at lines[i] and returns the index of the line after it. An unclosed fence
+// runs to the end of the source.
+func renderFencedCode(b *strings.Builder, lines []string, i int) int {
+	opening := strings.TrimLeft(lines[i], " ")
+	fence := opening[:len(opening)-len(strings.TrimLeft(opening, opening[:1]))]
+	language, _, _ := strings.Cut(strings.TrimSpace(opening[len(fence):]), " ")
+
+	var code []string
+	for i++; i < len(lines); i++ {
+		closing := strings.TrimSpace(lines[i])
+		if indentOf(lines[i]) < 4 && strings.HasPrefix(closing, fence) && strings.Trim(closing, fence[:1]) == "" {
+			i++
+			break
+		}
+		code = append(code, lines[i])
+	}
+	writeCode(b, code, language)
+	return i
+}
+
+// writeCode writes a <pre> block. language becomes the class of the code
+// element when it is a plain word.
+func writeCode(b *strings.Builder, code []string, language string) {
+	b.WriteString("<pre><code")
+	if language != "" && strings.Trim(language, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_+-#.") == "" {
+		b.WriteString(` class="language-` + html.EscapeString(language) + `"`)
+	}
+	b.WriteString(">")
+	for _, line := range code {
+		b.WriteString(html.EscapeString(line))
+		b.WriteString("\n")
+	}
+	b.WriteString("</code></pre>\n")
+}
+
+// renderQuote renders the block quote that starts at lines[i] and returns
+// the index of the line after it. Lines without the > marker continue the
+// quote until a blank line.
+func renderQuote(b *strings.Builder, lines []string, i int) int {
+	var quoted []string
+	for ; i < len(lines) && !isBlank(lines[i]); i++ {
+		line := lines[i]
+		if isQuote(line) {
+			line = strings.TrimLeft(line, " ")[1:]
+			line = strings.TrimPrefix(line, " ")
+		} else if len(quoted) > 0 && startsBlock(line) {
+			break
+		}
+		quoted = append(quoted, line)
+	}
+	b.WriteString("<blockquote>\n")
+	renderBlocks(b, quoted, false)
+	b.WriteString("</blockquote>\n")
+	return i
+}
+
+// renderList renders the list that starts at lines[i] and returns the index
+// of the line after it. The list is loose, with every item in paragraphs,
+// when blank lines separate its items or the blocks inside them.
+func renderList(b *strings.Builder, lines []string, i int) int {
+	first, _ := listItem(lines[i])
+	var items [][]string
+	loose := false
+	for i < len(lines) {
+		marker, ok := listItem(lines[i])
+		if !ok || marker.ordered != first.ordered || marker.delimiter != first.delimiter {
+			break
+		}
+		item := []string{lines[i][marker.width:]}
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			switch {
+			case isBlank(line):
+				item = append(item, "")
+				continue
+			case indentOf(line) >= marker.width:
+				item = append(item, line[marker.width:])
+				continue
+			case !isBlank(item[len(item)-1]) && !startsBlock(line):
+				// A lazy continuation of the paragraph of the item.
+				item = append(item, strings.TrimLeft(line, " "))
+				continue
+			}
+			break
+		}
+
+		trailing := 0
+		for len(item) > 1 && isBlank(item[len(item)-1]) {
+			item = item[:len(item)-1]
+			trailing++
+		}
+		for _, line := range item {
+			if isBlank(line) {
+				loose = true
+			}
+		}
+		items = append(items, item)
+		if trailing > 0 && i < len(lines) {
+			if next, ok := listItem(lines[i]); ok && next.ordered == first.ordered && next.delimiter == first.delimiter {
+				loose = true
+			}
+		}
+	}
+
+	if first.ordered {
+		if first.start != 1 {
+			b.WriteString(`<ol start="` + strconv.Itoa(first.start) + `">` + "\n")
+		} else {
+			b.WriteString("<ol>\n")
+		}
+	} else {
+		b.WriteString("<ul>\n")
+	}
+	for _, item := range items {
+		b.WriteString("<li>")
+		renderBlocks(b, item, !loose)
+		b.WriteString("</li>\n")
+	}
+	if first.ordered {
+		b.WriteString("</ol>\n")
+	} else {
+		b.WriteString("</ul>\n")
+	}
+	return i
+}
+
+// renderParagraph renders the paragraph that starts at lines[i] and returns
+// the index of the line after it. Paragraphs left empty by dropping raw
+// HTML are left out.
+func renderParagraph(b *strings.Builder, lines []string, i int, tight bool) int {
+	var text []string
+	for ; i < len(lines) && !isBlank(lines[i]); i++ {
+		if len(text) > 0 && startsBlock(lines[i]) {
+			break
+		}
+		text = append(text, strings.TrimLeft(lines[i], " "))
+	}
+	var content strings.Builder
+	renderInline(&content, strings.TrimRight(strings.Join(text, "\n"), " "), false)
+	if strings.TrimSpace(content.String()) == "" {
+		return i
+	}
+	if tight {
+		b.WriteString(content.String() + "\n")
+	} else {
+		b.WriteString("<p>" + content.String() + "</p>\n")
+	}
+	return i
+}
+
+// skipDroppedHTML skips the raw HTML element that starts at lines[i], up to
+// the line that closes it, and returns the index of the line after it.
+func skipDroppedHTML(lines []string, i int) int {
+	end := droppedHTMLEnd(strings.TrimLeft(lines[i], " "))
+	for i++; i < len(lines); i++ {
+		if strings.Contains(asciiLower(lines[i]), end) {
+			return i + 1
+		}
+	}
+	return i
+}
+
+// startsBlock reports whether line starts a block that interrupts a
+// paragraph.
+func startsBlock(line string) bool {
+	if indentOf(line) >= 4 {
+		return false
+	}
+	return isFence(line) || isHeading(line) || isThematicBreak(line) || isQuote(line) || isListItem(line) || isDroppedHTML(line)
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// indentOf returns the number of spaces line starts with.
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func isFence(line string) bool {
+	line = strings.TrimLeft(line, " ")
+	if !strings.HasPrefix(line, "```") && !strings.HasPrefix(line, "~~~") {
+		return false
+	}
+	// Backticks cannot appear in the info string of a backtick fence, so
+	// ```code``` stays an inline code span.
+	return line[0] != '`' || !strings.Contains(strings.TrimLeft(line, "`"), "`")
+}
+
+func isHeading(line string) bool {
+	level, _ := heading(line)
+	return level > 0
+}
+
+// heading returns the level and text of an ATX heading like "## Details",
+// or 0 when line is not one.
+func heading(line string) (int, string) {
+	if indentOf(line) >= 4 {
+		return 0, ""
+	}
+	line = strings.TrimSpace(line)
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	if level < 1 || level > 6 || (len(line) > level && line[level] != ' ') {
+		return 0, ""
+	}
+	text := strings.TrimSpace(line[level:])
+	// An optional closing sequence of #s goes, but not #s that are part
+	// of the text, as in "C#".
+	if closing := strings.TrimRight(text, "#"); closing == "" || strings.HasSuffix(closing, " ") {
+		text = strings.TrimSpace(closing)
+	}
+	return level, text
+}
+
+// isThematicBreak reports whether line is three or more -, * or _
+// characters, optionally separated by spaces.
+func isThematicBreak(line string) bool {
+	if indentOf(line) >= 4 {
+		return false
+	}
+	line = strings.ReplaceAll(strings.TrimSpace(line), " ", "")
+	return len(line) >= 3 && strings.Count(line, line[:1]) == len(line) && strings.Contains("-*_", line[:1])
+}
+
+func isQuote(line string) bool {
+	return indentOf(line) < 4 && strings.HasPrefix(strings.TrimLeft(line, " "), ">")
+}
+
+// listMarker describes the marker of a list item.
+type listMarker struct {
+	ordered bool
+	// delimiter is the bullet of unordered items and the . or ) after the
+	// number of ordered ones; lists end when it changes.
+	delimiter byte
+	start     int
+	// width is how far the content of the item is indented.
+	width int
+}
+
+func isListItem(line string) bool {
+	_, ok := listItem(line)
+	return ok
+}
+
+// listItem parses the marker of a list item like "- text" or "2. text".
+func listItem(line string) (listMarker, bool) {
+	indent := indentOf(line)
+	if indent >= 4 {
+		return listMarker{}, false
+	}
+	rest := line[indent:]
+	var marker listMarker
+	switch {
+	case rest != "" && strings.IndexByte("-*+", rest[0]) >= 0:
+		marker.delimiter = rest[0]
+		rest = rest[1:]
+		marker.width = indent + 1
+	default:
+		digits := len(rest) - len(strings.TrimLeft(rest, "0123456789"))
+		if digits == 0 || digits > 9 || len(rest) == digits || (rest[digits] != '.' && rest[digits] != ')') {
+			return listMarker{}, false
+		}
+		marker.ordered = true
+		marker.start, _ = strconv.Atoi(rest[:digits])
+		marker.delimiter = rest[digits]
+		rest = rest[digits+1:]
+		marker.width = indent + digits + 1
+	}
+	if rest == "" {
+		return marker, true
+	}
+	if rest[0] != ' ' {
+		return listMarker{}, false
+	}
+	// The content starts after one to four spaces; with more, the extra
+	// ones indent a code block inside the item.
+	spaces := indentOf(rest)
+	if spaces > 4 || spaces == len(rest) {
+		spaces = 1
+	}
+	marker.width += spaces
+	return marker, true
+}
+
+// droppedElements are the HTML elements that are dropped with their content
+// rather than only losing their tags.
+var droppedElements = []string{"script", "style", "iframe", "object", "embed", "noscript", "template", "textarea", "svg", "math", "xmp", "title"}
+
+// isDroppedHTML reports whether line starts an HTML comment or one of the
+// droppedElements that runs over several lines. Those closed on the same
+// line are left to the inline rendering, which keeps the text after them.
+func isDroppedHTML(line string) bool {
+	if indentOf(line) >= 4 {
+		return false
+	}
+	line = strings.TrimLeft(line, " ")
+	end := droppedHTMLEnd(line)
+	return end != "" && !strings.Contains(asciiLower(line), end)
+}
+
+// droppedHTMLEnd returns what closes the HTML comment or dropped element
+// text starts with, in lower case, or "" when text starts with neither.
+func droppedHTMLEnd(text string) string {
+	if strings.HasPrefix(text, "<!--") {
+		return "-->"
+	}
+	lower := asciiLower(text)
+	for _, name := range droppedElements {
+		if !strings.HasPrefix(lower, "<"+name) {
+			continue
+		}
+		if rest := lower[len(name)+1:]; rest == "" || strings.IndexByte(" \n/>", rest[0]) >= 0 {
+			return "</" + name
+		}
+	}
+	return ""
+}
+
+// asciiLower lower cases the ASCII letters of s, which is how HTML matches
+// tag names. Unlike strings.ToLower it keeps every byte where it is, even in
+// invalid UTF-8, so offsets found in the result apply to s.
+func asciiLower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
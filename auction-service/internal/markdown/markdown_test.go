@@ -0,0 +1,133 @@
+package markdown_test
+
+import (
+	"auction-service/internal/markdown"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{"empty", "", ""},
+		{"paragraphs", "Mint condition.\nBarely used.\n\nShips in a box.", "<p>Mint condition.\nBarely used.</p>\n<p>Ships in a box.</p>\n"},
+		{"headings", "# Lamp\n### Details ###\n## C#", "<h1>Lamp</h1>\n<h3>Details</h3>\n<h2>C#</h2>\n"},
+		{"not a heading", "#hashtag", "<p>#hashtag</p>\n"},
+		{"emphasis", "*soft* _light_ **solid** __brass__ ***rare*** ~~cheap~~", "<p><em>soft</em> <em>light</em> <strong>solid</strong> <strong>brass</strong> <em><strong>rare</strong></em> <del>cheap</del></p>\n"},
+		{"nested emphasis", "**very *old* lamp**", "<p><strong>very <em>old</em> lamp</strong></p>\n"},
+		{"unclosed emphasis", "2 * 3 and *open and snake_case_name", "<p>2 * 3 and *open and snake_case_name</p>\n"},
+		{"escapes", `\*not emphasis\* 5 \< 6`, "<p>*not emphasis* 5 &lt; 6</p>\n"},
+		{"hard breaks", "line one  \nline two\\\nline three", "<p>line one<br>\nline two<br>\nline three</p>\n"},
+		{"code span", "run `rm -rf <dir>` now", "<p>run <code>rm -rf &lt;dir&gt;</code> now</p>\n"},
+		{"fenced code", "```go\nfmt.Println(\"<b>\")\n```\nafter", "<pre><code class=\"language-go\">fmt.Println(&#34;&lt;b&gt;&#34;)\n</code></pre>\n<p>after</p>\n"},
+		{"indented code", "    x := 1\n\n    y := 2\n\ntext", "<pre><code>x := 1\n\ny := 2\n</code></pre>\n<p>text</p>\n"},
+		{"block quote", "> Works great\nstill quoted\n\nnot quoted", "<blockquote>\n<p>Works great\nstill quoted</p>\n</blockquote>\n<p>not quoted</p>\n"},
+		{"thematic break", "above\n\n---\n\n* * *", "<p>above</p>\n<hr>\n<hr>\n"},
+		{"tight list", "- one\n- **two**\n  - nested\n- three", "<ul>\n<li>one\n</li>\n<li><strong>two</strong>\n<ul>\n<li>nested\n</li>\n</ul>\n</li>\n<li>three\n</li>\n</ul>\n"},
+		{"loose list", "1. first\n\n2. second", "<ol>\n<li><p>first</p>\n</li>\n<li><p>second</p>\n</li>\n</ol>\n"},
+		{"ordered start", "3) third\n4) fourth", "<ol start=\"3\">\n<li>third\n</li>\n<li>fourth\n</li>\n</ol>\n"},
+		{"link", `[shop](https://example.com/a?b=1&c=2 "Our shop")`, "<p><a href=\"https://example.com/a?b=1&amp;c=2\" title=\"Our shop\" rel=\"nofollow ugc noopener\">shop</a></p>\n"},
+		{"relative link", "[terms](/terms#returns)", "<p><a href=\"/terms#returns\" rel=\"nofollow ugc noopener\">terms</a></p>\n"},
+		{"image", "![the *lamp*](https://img.example.com/lamp.png)", "<p><img src=\"https://img.example.com/lamp.png\" alt=\"the lamp\"></p>\n"},
+		{"autolinks", "<https://example.com> <seller@example.com>", "<p><a href=\"https://example.com\" rel=\"nofollow ugc noopener\">https://example.com</a> <a href=\"mailto:seller@example.com\">seller@example.com</a></p>\n"},
+		{"entities", "&copy; 2024 &amp; &bogus; AT&T", "<p>© 2024 &amp; &amp;bogus; AT&amp;T</p>\n"},
+		{"not a link", "[sic] and [x] (y)", "<p>[sic] and [x] (y)</p>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, markdown.Render(tt.source))
+		})
+	}
+}
+
+func TestRenderSanitizes(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{"script block", "<script>\nalert(1)\n</script>\nSafe text", "<p>Safe text</p>\n"},
+		{"inline script", "Hi <SCRIPT src=x>alert(1)</script > there", "<p>Hi  there</p>\n"},
+		{"unclosed script", "Hi <script>alert(1)", "<p>Hi </p>\n"},
+		{"style and comment", "<style>body{display:none}</style>\n<!-- hidden\ncomment -->\nVisible", "<p>Visible</p>\n"},
+		{"event handler", `<img src=x onerror="alert(1)">Lamp <b onmouseover=alert(1)>bold</b>`, "<p>Lamp bold</p>\n"},
+		{"iframe", `<iframe src="https://evil.example"></iframe>text`, "<p>text</p>\n"},
+		{"javascript link", "[click](javascript:alert(1))", "<p>click</p>\n"},
+		{"obfuscated scheme", "[click](JaVaScRiPt:alert(1)) [one](java\x0bscript:alert(1)) [two](java&#58;script:alert(1))", "<p>click one <a href=\"java&amp;#58;script:alert(1)\" rel=\"nofollow ugc noopener\">two</a></p>\n"},
+		{"data link", "[x](data:text/html;base64,PHNjcmlwdD4=)", "<p>x</p>\n"},
+		{"vbscript autolink", "<vbscript:msgbox(1)>", "<p>vbscript:msgbox(1)</p>\n"},
+		{"data image", "![pixel](data:image/png;base64,AAAA)", "<p>pixel</p>\n"},
+		{"mailto image", "![me](mailto:a@b.c)", "<p>me</p>\n"},
+		{"quote in title", `[x](/a "\" onclick=\"alert(1)")`, "<p><a href=\"/a\" title=\"&#34; onclick=&#34;alert(1)\" rel=\"nofollow ugc noopener\">x</a></p>\n"},
+		{"quote in url", `[x](https://a.example/"onmouseover="alert(1))`, "<p><a href=\"https://a.example/&#34;onmouseover=&#34;alert(1)\" rel=\"nofollow ugc noopener\">x</a></p>\n"},
+		{"fence language", "```\"><script>\ncode\n```", "<pre><code>code\n</code></pre>\n"},
+		{"stray angle", "1 < 2 > 0", "<p>1 &lt; 2 &gt; 0</p>\n"},
+		{"non-ASCII in dropped element", "Hi <script>ȺȺȺ</SCRIPT>İ there", "<p>Hi İ there</p>\n"},
+		{"non-ASCII before unclosed tag", "<script>Ⱥ</script", ""},
+		{"non-ASCII in dropped block", "<style>\nȺȺȺ\n</style>\nVisible", "<p>Visible</p>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, markdown.Render(tt.source))
+		})
+	}
+}
+
+func TestRenderPathologicalInput(t *testing.T) {
+	sources := []string{
+		strings.Repeat("*a ", 20000),
+		strings.Repeat("_a ", 20000),
+		strings.Repeat("[", 20000) + strings.Repeat("]", 20000),
+		strings.Repeat("`a ", 20000),
+		strings.Repeat("- ", 5000),
+		strings.Repeat("> ", 5000) + "deep",
+	}
+	for _, source := range sources {
+		start := time.Now()
+		markdown.Render(source)
+		assert.Less(t, time.Since(start), 2*time.Second, source[:10])
+	}
+}
+
+func FuzzRender(f *testing.F) {
+	for _, seed := range []string{"**a** _b_ `c`", "<script>ȺȺȺ</script>", "<script>Ⱥ</script", "[x](<y>)", "<!-- İ -->\n# T", "\xc4<b>\xb0"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, source string) {
+		html := markdown.Render(source)
+		if utf8.ValidString(source) && !utf8.ValidString(html) {
+			t.Errorf("Render(%q) = %q, not valid UTF-8", source, html)
+		}
+	})
+}
+
+func TestCache(t *testing.T) {
+	cache := markdown.NewCache(2)
+	assert.Equal(t, "<p><em>one</em></p>\n", cache.Render("*one*"))
+	cache.Render("two")
+	cache.Render("*one*")
+	cache.Render("three")
+	assert.Equal(t, 2, cache.Len())
+	assert.Equal(t, "", cache.Render(""))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			source := fmt.Sprintf("item **%d**", i%3)
+			assert.Equal(t, markdown.Render(source), cache.Render(source))
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 2, cache.Len())
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// Attachment is a file the seller of an auction uploaded: a photo of the
+// item, which gets a thumbnail, or a PDF document.
+type Attachment struct {
+	ID        int `gorm:"primaryKey"`
+	AuctionID int `gorm:"index;not null"`
+	// Filename is the name the file was uploaded with.
+	Filename    string `gorm:"size:255"`
+	ContentType string `gorm:"size:100;not null"`
+	Size        int64  `gorm:"not null"`
+	// Width and Height are the dimensions of images, in pixels.
+	Width  int `gorm:"not null;default:0"`
+	Height int `gorm:"not null;default:0"`
+	// Key and ThumbnailKey locate the file and its thumbnail in the blob
+	// store. Documents have no thumbnail.
+	Key          string `gorm:"size:255;not null" json:"-"`
+	ThumbnailKey string `gorm:"size:255" json:"-"`
+	// URL and ThumbnailURL are where clients download the file and its
+	// thumbnail. They are worked out when the attachment is read and not
+	// stored.
+	URL          string `gorm:"-"`
+	ThumbnailURL string `gorm:"-" json:",omitempty"`
+	CreatedAt    time.Time
+}
+
+// IsImage reports whether the attachment is a photo.
+func (a Attachment) IsImage() bool {
+	return a.ThumbnailKey != ""
+}
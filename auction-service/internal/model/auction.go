@@ -1,19 +1,192 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// Auction states. An auction starts open, or scheduled until its start
+// time, and can only move on to closed.
+const (
+	AuctionStatusScheduled = "scheduled"
+	AuctionStatusOpen      = "open"
+	AuctionStatusClosed    = "closed"
+)
+
+// Auction formats. See service.AuctionFormat for the rules of each one.
+const (
+	AuctionFormatEnglish          = "english"
+	AuctionFormatDutch            = "dutch"
+	AuctionFormatSealedFirstPrice = "sealed_first_price"
+	AuctionFormatVickrey          = "vickrey"
+)
+
 type Auction struct {
-	ID        int `gorm:"primaryKey"`
-	Item      string
-	UserID    int
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	ID     int `gorm:"primaryKey"`
+	Item   string
+	UserID int
+	Status string `gorm:"size:32;not null;default:open"`
+	Format string `gorm:"size:32;not null;default:english"`
+	// Description tells bidders more about the item, in Markdown. Item and
+	// Description are what keyword searches look at.
+	Description string `gorm:"type:text"`
+	// DescriptionHTML is Description rendered to sanitized HTML. It is
+	// worked out when the auction is read and not stored.
+	DescriptionHTML string `gorm:"-" json:",omitempty"`
+	// CategoryID is the category the auction is listed in, if any, and
+	// Attributes the values of the attributes of that category.
+	CategoryID *int              `gorm:"index"`
+	Attributes map[string]string `gorm:"serializer:json"`
+	// Currency is the ISO 4217 code every price and bid of the auction is in.
+	Currency     string `gorm:"size:3;not null;default:USD"`
+	CurrentPrice Money  `gorm:"embedded;embeddedPrefix:current_price_"`
+	// StartPrice is the price the auction opened at.
+	StartPrice Money `gorm:"embedded;embeddedPrefix:start_price_"`
+	// A Dutch auction starts at StartPrice and drops by DutchDecrement every
+	// DutchIntervalMinutes, down to DutchFloorPrice.
+	DutchDecrement       Money `gorm:"embedded;embeddedPrefix:dutch_decrement_"`
+	DutchIntervalMinutes int   `gorm:"not null;default:0"`
+	DutchFloorPrice      Money `gorm:"embedded;embeddedPrefix:dutch_floor_price_"`
+	// BidIncrements overrides the increment table of the service for this
+	// auction when set.
+	BidIncrements IncrementTable `gorm:"serializer:json"`
+	// CategoryIncrements is the increment table of the category, copied
+	// when the auction is listed so later changes to the category do not
+	// move the rules of a running auction. BidIncrements takes precedence.
+	CategoryIncrements IncrementTable `gorm:"serializer:json" json:"-"`
+	// NextMinimumBid is the lowest bid accepted right now. It is worked out
+	// when the auction is read and not stored.
+	NextMinimumBid *Money `gorm:"-" json:",omitempty"`
+	// Display holds the prices converted to the display currency a client
+	// asked for. It is not stored.
+	Display *DisplayPrices `gorm:"-" json:",omitempty"`
+	// Attachments are the photos and documents of the auction, in upload
+	// order. They are loaded when the auction is read.
+	Attachments []Attachment `gorm:"-" json:",omitempty"`
+	// ReservePrice is the hidden minimum the seller accepts, 0 for none. It
+	// is left out of the JSON representation, see MarshalJSON.
+	ReservePrice Money `gorm:"embedded;embeddedPrefix:reserve_price_"`
+	// BuyNowPrice lets the first buyer close the auction at that price while
+	// bidding has not passed the reserve, or while there are no bids when
+	// there is no reserve. 0 disables it.
+	BuyNowPrice Money `gorm:"embedded;embeddedPrefix:buy_now_price_"`
+	// WinnerID is the buyer of a closed auction, 0 when it did not sell.
+	WinnerID int `gorm:"not null;default:0"`
+	// StartsAt is when a scheduled auction opens, nil for auctions open
+	// from the moment they are listed.
+	StartsAt *time.Time `gorm:"index"`
+	// EndsAt is when the auction closes on its own. Without it the auction
+	// stays open until the seller closes it.
+	EndsAt *time.Time `gorm:"index"`
+	// OriginalEndsAt is the end time chosen by the seller, before extensions.
+	OriginalEndsAt *time.Time
+	// A bid placed in the last SoftCloseWindowMinutes pushes EndsAt back by
+	// SoftCloseExtensionMinutes, but never more than SoftCloseCapMinutes past
+	// OriginalEndsAt when a cap is set.
+	SoftCloseWindowMinutes    int `gorm:"not null;default:0"`
+	SoftCloseExtensionMinutes int `gorm:"not null;default:0"`
+	SoftCloseCapMinutes       int `gorm:"not null;default:0"`
+	// Extensions counts how many times soft close moved EndsAt.
+	Extensions int `gorm:"not null;default:0"`
+	// RelistedFromID is the unsold auction this one relists, and
+	// OriginalAuctionID the first listing of the item, shared by every
+	// relist of it. Both are nil for auctions that are not relists.
+	RelistedFromID    *int `gorm:"index"`
+	OriginalAuctionID *int `gorm:"index"`
+	// EndingSoonNotified is set once the watchers were told the auction is
+	// about to end.
+	EndingSoonNotified bool `gorm:"not null;default:false" json:"-"`
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	DeletedAt          gorm.DeletedAt `gorm:"index"`
+}
+
+// IsOpen reports whether the auction still accepts bids and edits.
+func (a Auction) IsOpen() bool {
+	return a.Status == "" || a.Status == AuctionStatusOpen
+}
+
+// IsScheduled reports whether the auction waits for its start time. It
+// accepts edits but no bids yet.
+func (a Auction) IsScheduled() bool {
+	return a.Status == AuctionStatusScheduled
+}
+
+// OpensAt is when the auction opened or opens for bidding.
+func (a Auction) OpensAt() time.Time {
+	if a.StartsAt != nil {
+		return *a.StartsAt
+	}
+	return a.CreatedAt
 }
+
+// ReserveMet reports whether the current price reaches the reserve price.
+// Auctions without a reserve always meet it.
+func (a Auction) ReserveMet() bool {
+	return !a.ReservePrice.IsPositive() || !a.CurrentPrice.Less(a.ReservePrice)
+}
+
+// MarshalJSON hides the reserve price. Clients only learn whether the
+// auction has a reserve and whether bidding has met it.
+func (a Auction) MarshalJSON() ([]byte, error) {
+	type plain Auction
+	return json.Marshal(struct {
+		plain
+		ReservePrice *Money `json:",omitempty"`
+		HasReserve   bool
+		ReserveMet   bool
+	}{
+		plain:      plain(a),
+		HasReserve: a.ReservePrice.IsPositive(),
+		ReserveMet: a.ReserveMet(),
+	})
+}
+
+// HasEnded reports whether the end time of the auction has passed at now.
+func (a Auction) HasEnded(now time.Time) bool {
+	return a.EndsAt != nil && !now.Before(*a.EndsAt)
+}
+
+// SoftCloseEnd returns the end time a bid placed at now moves the auction
+// to, and false when the bid does not trigger an extension.
+func (a Auction) SoftCloseEnd(now time.Time) (time.Time, bool) {
+	if a.EndsAt == nil || a.SoftCloseWindowMinutes <= 0 || a.SoftCloseExtensionMinutes <= 0 {
+		return time.Time{}, false
+	}
+	if a.EndsAt.Sub(now) > time.Duration(a.SoftCloseWindowMinutes)*time.Minute {
+		return time.Time{}, false
+	}
+
+	end := a.EndsAt.Add(time.Duration(a.SoftCloseExtensionMinutes) * time.Minute)
+	if a.SoftCloseCapMinutes > 0 && a.OriginalEndsAt != nil {
+		limit := a.OriginalEndsAt.Add(time.Duration(a.SoftCloseCapMinutes) * time.Minute)
+		if end.After(limit) {
+			end = limit
+		}
+	}
+	if !end.After(*a.EndsAt) {
+		return time.Time{}, false
+	}
+	return end, true
+}
+
+// AuctionUpdate is the change an owner makes to an auction. Item,
+// Description, CategoryID and Attributes replace the current values; the
+// prices and increments left out (nil) stay as they are, since clients
+// never see the reserve price to send it back.
+type AuctionUpdate struct {
+	ID            int
+	Item          string
+	Description   string
+	CategoryID    *int
+	Attributes    map[string]string
+	ReservePrice  *Money
+	BuyNowPrice   *Money
+	BidIncrements *IncrementTable
+}
+
 type User struct {
 	ID        int    `gorm:"primaryKey"`
 	Name      string `gorm:"size:255;not null"`
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// Bid statuses. Withdrawn bids stay in the history but no longer count.
+const (
+	BidStatusActive = "active"
+	// BidStatusRetracted marks a bid withdrawn by its bidder.
+	BidStatusRetracted = "retracted"
+	// BidStatusCancelled marks a bid cancelled by the seller.
+	BidStatusCancelled = "cancelled"
+	// BidStatusVoided marks an automatic bid placed in answer to a bid that
+	// was later withdrawn. The proxy bids again against the new leader.
+	BidStatusVoided = "voided"
+)
+
+// Bid is an offer made by a user on an auction.
+type Bid struct {
+	ID        int   `gorm:"primaryKey"`
+	AuctionID int   `gorm:"index;not null"`
+	UserID    int   `gorm:"not null"`
+	Amount    Money `gorm:"embedded;embeddedPrefix:amount_"`
+	// Automatic is set on bids placed by a proxy bid on the user's behalf.
+	Automatic bool   `gorm:"not null;default:false"`
+	Status    string `gorm:"size:16;not null;default:active"`
+	CreatedAt time.Time
+}
+
+// IsActive reports whether the bid still counts towards the auction.
+func (b Bid) IsActive() bool {
+	return b.Status == "" || b.Status == BidStatusActive
+}
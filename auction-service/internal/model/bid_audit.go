@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// Bid audit actions.
+const (
+	BidAuditRetracted = "retracted"
+	BidAuditCancelled = "cancelled"
+)
+
+// BidAuditEntry records the withdrawal of a bid. Entries are only ever
+// appended: they are never changed or deleted.
+type BidAuditEntry struct {
+	ID        int `gorm:"primaryKey"`
+	AuctionID int `gorm:"index;not null"`
+	BidID     int `gorm:"not null"`
+	BidderID  int `gorm:"not null"`
+	// ActorID is the user who withdrew the bid: the bidder or the seller.
+	ActorID int    `gorm:"not null"`
+	Action  string `gorm:"size:16;not null"`
+	Reason  string
+	Amount  Money `gorm:"embedded;embeddedPrefix:amount_"`
+	// Withdrawn lists the IDs of every bid the action took out, the
+	// requested one first.
+	Withdrawn []int `gorm:"serializer:json"`
+	CreatedAt time.Time
+}
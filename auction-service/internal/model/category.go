@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// Attribute types. Values are always sent and stored as strings; the type
+// decides which strings are valid.
+const (
+	AttributeTypeText    = "text"
+	AttributeTypeNumber  = "number"
+	AttributeTypeBoolean = "boolean"
+	// AttributeTypeEnum values must be one of the Options of the attribute.
+	AttributeTypeEnum = "enum"
+)
+
+// AttributeSchema describes an attribute auctions in a category give their
+// item, e.g. its condition, brand or size.
+type AttributeSchema struct {
+	Name     string
+	Type     string
+	Required bool
+	Options  []string `json:",omitempty"`
+}
+
+// Category is a node of the category tree auctions are listed in. A category
+// inherits the attributes of its ancestors.
+type Category struct {
+	ID int `gorm:"primaryKey"`
+	// ParentID is the parent category, nil for top-level categories.
+	ParentID *int   `gorm:"index"`
+	Name     string `gorm:"size:100;not null"`
+	Slug     string `gorm:"size:100;uniqueIndex;not null"`
+	// Attributes are the attributes the category adds to those of its
+	// ancestors.
+	Attributes []AttributeSchema `gorm:"serializer:json"`
+	// BidIncrements overrides the increment table of the service for the
+	// auctions listed in the category or its subcategories, unless a
+	// subcategory or the auction has its own.
+	BidIncrements IncrementTable `gorm:"serializer:json"`
+	// Schema lists every attribute of the category, inherited ones first,
+	// and Children its subcategories. They are worked out when the category
+	// is read and not stored.
+	Schema    []AttributeSchema `gorm:"-" json:",omitempty"`
+	Children  []Category        `gorm:"-" json:",omitempty"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// Event types published by the auction service.
+const (
+	EventAuctionCreated  = "auction.created"
+	EventAuctionStarted  = "auction.started"
+	EventAuctionUpdated  = "auction.updated"
+	EventAuctionDeleted  = "auction.deleted"
+	EventAuctionClosed   = "auction.closed"
+	EventAuctionExtended = "auction.extended"
+	EventPriceChanged    = "auction.price_changed"
+	EventAuctionSold     = "auction.sold"
+	EventAuctionUnsold   = "auction.unsold"
+	EventBidPlaced       = "bid.placed"
+	EventBidRetracted    = "bid.retracted"
+	EventOrderCreated    = "order.created"
+	EventOrderPaid       = "order.paid"
+	EventOrderShipped    = "order.shipped"
+	EventOrderCompleted  = "order.completed"
+	EventOrderUnpaid     = "order.unpaid"
+
+	EventSecondChanceOffered  = "second_chance.offered"
+	EventSecondChanceAccepted = "second_chance.accepted"
+	EventSecondChanceDeclined = "second_chance.declined"
+	EventSecondChanceExpired  = "second_chance.expired"
+
+	// Notification intents ask the notifier to tell UserID about an
+	// auction. The notifier checks the user's preferences.
+	EventNotifyOutbid     = "notify.outbid"
+	EventNotifyEndingSoon = "notify.ending_soon"
+	EventNotifyWon        = "notify.won"
+	EventNotifyLost       = "notify.lost"
+)
+
+// Event is the message published to the broker whenever an auction changes.
+// For bid.retracted, Action tells whether the bidder retracted the bid or the
+// seller cancelled it, and Reason why.
+type Event struct {
+	Type       string     `json:"type"`
+	AuctionID  int        `json:"auction_id"`
+	UserID     int        `json:"user_id,omitempty"`
+	Amount     *Money     `json:"amount,omitempty"`
+	Automatic  bool       `json:"automatic,omitempty"`
+	BidID      int        `json:"bid_id,omitempty"`
+	OrderID    int        `json:"order_id,omitempty"`
+	OfferID    int        `json:"offer_id,omitempty"`
+	Action     string     `json:"action,omitempty"`
+	Reason     string     `json:"reason,omitempty"`
+	EndsAt     *time.Time `json:"ends_at,omitempty"`
+	OccurredAt time.Time  `json:"occurred_at"`
+}
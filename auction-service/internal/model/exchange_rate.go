@@ -0,0 +1,95 @@
+package model
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// ExchangeRate says how many units of To one unit of From buys.
+type ExchangeRate struct {
+	From string `gorm:"primaryKey;size:3" json:"from"`
+	To   string `gorm:"primaryKey;size:3" json:"to"`
+	// Rate is a decimal such as "0.9215". It is kept as text so it is never
+	// rounded through a float.
+	Rate      string    `gorm:"size:32;not null" json:"rate"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Ratio parses the rate. It fails unless the rate is a positive decimal.
+func (r ExchangeRate) Ratio() (*big.Rat, error) {
+	ratio, ok := new(big.Rat).SetString(r.Rate)
+	if !ok || ratio.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid exchange rate %q for %s/%s", r.Rate, r.From, r.To)
+	}
+	return ratio, nil
+}
+
+// Inverse returns the rate from To to From.
+func (r ExchangeRate) Inverse() (ExchangeRate, error) {
+	ratio, err := r.Ratio()
+	if err != nil {
+		return ExchangeRate{}, err
+	}
+	return ExchangeRate{From: r.To, To: r.From, Rate: ratio.Inv(ratio).FloatString(10), UpdatedAt: r.UpdatedAt}, nil
+}
+
+// Convert returns amount, which must be in From, in To. The result is
+// rounded half away from zero to the minor unit of To.
+func (r ExchangeRate) Convert(amount Money) (Money, error) {
+	if amount.Currency != r.From {
+		return Money{}, fmt.Errorf("cannot convert %s with a %s/%s rate", amount.Currency, r.From, r.To)
+	}
+	ratio, err := r.Ratio()
+	if err != nil {
+		return Money{}, err
+	}
+
+	converted := new(big.Rat).Mul(new(big.Rat).SetInt64(amount.Minor), ratio)
+	shift := currencyExponent(r.To) - currencyExponent(r.From)
+	return Money{Minor: roundRat(shiftRat(converted, shift)), Currency: r.To}, nil
+}
+
+// shiftRat multiplies x by 10 to the power of shift, which may be negative,
+// and returns it.
+func shiftRat(x *big.Rat, shift int) *big.Rat {
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(shift))), nil))
+	if shift >= 0 {
+		return x.Mul(x, scale)
+	}
+	return x.Quo(x, scale)
+}
+
+// roundRat rounds x half away from zero.
+func roundRat(x *big.Rat) int64 {
+	num, den := new(big.Int).Abs(x.Num()), x.Denom()
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Mul(rem, big.NewInt(2)).Cmp(den) >= 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	if x.Sign() < 0 {
+		quo.Neg(quo)
+	}
+	return quo.Int64()
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// DisplayPrices are the prices of an auction converted to the currency a
+// client asked for. They are indicative only: bids are placed and settled in
+// the currency of the auction.
+type DisplayPrices struct {
+	Currency       string    `json:"currency"`
+	Indicative     bool      `json:"indicative"`
+	Notice         string    `json:"notice"`
+	Rate           string    `json:"rate"`
+	RateUpdatedAt  time.Time `json:"rate_updated_at"`
+	CurrentPrice   Money     `json:"current_price"`
+	NextMinimumBid *Money    `json:"next_minimum_bid,omitempty"`
+	BuyNowPrice    *Money    `json:"buy_now_price,omitempty"`
+}
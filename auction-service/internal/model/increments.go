@@ -0,0 +1,58 @@
+package model
+
+// IncrementBand sets the minimum raise for prices from From up to the From of
+// the next band.
+type IncrementBand struct {
+	From      Money `json:"from"`
+	Increment Money `json:"increment"`
+}
+
+// IncrementTable lists the bid increments by price band, ordered by From and
+// starting at 0. All the bands are in one currency.
+type IncrementTable []IncrementBand
+
+// Currency returns the currency of the table, "" when no band has one.
+func (t IncrementTable) Currency() string {
+	for _, band := range t {
+		if currency := band.From.currencyOr(band.Increment); currency != "" {
+			return currency
+		}
+	}
+	return ""
+}
+
+// In returns the table in currency. The service and category tables apply
+// to auctions in every currency, so their amounts keep the same value in
+// major units: 5.00 USD reads as 5 JPY. Increments too small for the minor
+// unit of currency, such as 0.25 USD in JPY, become one minor unit.
+func (t IncrementTable) In(currency string) IncrementTable {
+	if t.Currency() == currency {
+		return t
+	}
+	rescaled := make(IncrementTable, len(t))
+	for i, band := range t {
+		increment := band.Increment.Rescale(currency)
+		if band.Increment.IsPositive() && !increment.IsPositive() {
+			increment = NewMoney(1, currency)
+		}
+		rescaled[i] = IncrementBand{From: band.From.Rescale(currency), Increment: increment}
+	}
+	return rescaled
+}
+
+// Increment returns the minimum raise over price, in its currency. It is at
+// least one minor unit, so every bid moves the price, even with a table that
+// was never validated.
+func (t IncrementTable) Increment(price Money) Money {
+	increment := NewMoney(1, price.Currency)
+	for _, band := range t.In(price.Currency) {
+		if price.Less(band.From) {
+			break
+		}
+		increment = band.Increment
+	}
+	if !increment.IsPositive() {
+		return NewMoney(1, price.Currency)
+	}
+	return increment
+}
@@ -0,0 +1,226 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// currencyExponents maps the ISO 4217 codes the service accepts to the number
+// of digits of their minor unit.
+var currencyExponents = map[string]int{
+	"AUD": 2, "BHD": 3, "BRL": 2, "CAD": 2, "CHF": 2, "CLP": 0, "CNY": 2,
+	"CZK": 2, "DKK": 2, "EUR": 2, "GBP": 2, "HKD": 2, "HUF": 2, "INR": 2,
+	"ISK": 0, "JPY": 0, "KRW": 0, "KWD": 3, "MXN": 2, "NOK": 2, "NZD": 2,
+	"PLN": 2, "SEK": 2, "SGD": 2, "TRY": 2, "USD": 2, "ZAR": 2,
+}
+
+// DefaultCurrency is the currency of auctions created without one.
+const DefaultCurrency = "USD"
+
+// ValidCurrency reports whether code is an ISO 4217 currency the service
+// supports.
+func ValidCurrency(code string) bool {
+	_, ok := currencyExponents[code]
+	return ok
+}
+
+// currencyExponent returns the minor unit digits of code, 2 when unknown.
+func currencyExponent(code string) int {
+	if exponent, ok := currencyExponents[code]; ok {
+		return exponent
+	}
+	return 2
+}
+
+// Money is an amount in the minor unit of a currency, e.g. cents for USD.
+// Stored in the database as two columns, <prefix>minor and <prefix>currency.
+type Money struct {
+	Minor    int64  `gorm:"not null;default:0"`
+	Currency string `gorm:"size:3;not null;default:''"`
+}
+
+// NewMoney returns minor units of currency.
+func NewMoney(minor int64, currency string) Money {
+	return Money{Minor: minor, Currency: currency}
+}
+
+// ParseMoney reads a decimal amount such as "12.50" in currency. It fails
+// when the amount has more decimals than the currency allows.
+func ParseMoney(amount, currency string) (Money, error) {
+	if !ValidCurrency(currency) {
+		return Money{}, fmt.Errorf("unknown currency %q", currency)
+	}
+	amount = strings.TrimSpace(amount)
+	negative := strings.HasPrefix(amount, "-")
+	whole, fraction, _ := strings.Cut(strings.TrimPrefix(amount, "-"), ".")
+	exponent := currencyExponent(currency)
+	if whole == "" || len(fraction) > exponent || strings.ContainsAny(whole+fraction, "+-eE") {
+		return Money{}, fmt.Errorf("invalid %s amount %q", currency, amount)
+	}
+
+	digits := whole + fraction + strings.Repeat("0", exponent-len(fraction))
+	minor, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid %s amount %q", currency, amount)
+	}
+	if negative {
+		minor = -minor
+	}
+	return Money{Minor: minor, Currency: currency}, nil
+}
+
+// MoneyFromMajor converts an amount in major units, e.g. dollars, to money,
+// rounding to the nearest minor unit.
+func MoneyFromMajor(amount float64, currency string) Money {
+	scale := math.Pow10(currencyExponent(currency))
+	return Money{Minor: int64(math.Round(amount * scale)), Currency: currency}
+}
+
+// Add returns m + o in the currency of m, or of o when m has none.
+func (m Money) Add(o Money) Money {
+	return Money{Minor: m.Minor + o.Minor, Currency: m.currencyOr(o)}
+}
+
+// Sub returns m - o in the currency of m, or of o when m has none.
+func (m Money) Sub(o Money) Money {
+	return Money{Minor: m.Minor - o.Minor, Currency: m.currencyOr(o)}
+}
+
+// Mul returns m times n.
+func (m Money) Mul(n int64) Money {
+	return Money{Minor: m.Minor * n, Currency: m.Currency}
+}
+
+// Rescale returns the amount with the same value in major units in
+// currency, e.g. 12.50 USD as 13 JPY, rounded half away from zero to the
+// minor unit of currency. It is not a conversion: see ExchangeRate.Convert.
+func (m Money) Rescale(currency string) Money {
+	shift := currencyExponent(currency) - currencyExponent(m.Currency)
+	return Money{Minor: roundRat(shiftRat(new(big.Rat).SetInt64(m.Minor), shift)), Currency: currency}
+}
+
+// Cmp compares the amounts of m and o: -1 when m is less, 0 when equal and +1
+// when greater. It compares minor units only and is meant for amounts known to
+// share a currency, such as the prices of one auction; compare amounts that
+// came from elsewhere with CmpChecked.
+func (m Money) Cmp(o Money) int {
+	switch {
+	case m.Minor < o.Minor:
+		return -1
+	case m.Minor > o.Minor:
+		return 1
+	}
+	return 0
+}
+
+// CmpChecked is Cmp for amounts that may be in different currencies, which
+// cannot be compared and return an error. An amount without a currency
+// compares with any currency.
+func (m Money) CmpChecked(o Money) (int, error) {
+	if m.Currency != "" && o.Currency != "" && m.Currency != o.Currency {
+		return 0, fmt.Errorf("cannot compare %s with %s", m, o)
+	}
+	return m.Cmp(o), nil
+}
+
+// Less reports whether m is less than o. Like Cmp, it expects amounts in one
+// currency.
+func (m Money) Less(o Money) bool { return m.Cmp(o) < 0 }
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool { return m.Minor == 0 }
+
+// IsPositive reports whether the amount is greater than zero.
+func (m Money) IsPositive() bool { return m.Minor > 0 }
+
+// IsNegative reports whether the amount is below zero.
+func (m Money) IsNegative() bool { return m.Minor < 0 }
+
+// MinMoney returns the smaller of a and b.
+func MinMoney(a, b Money) Money {
+	if b.Less(a) {
+		return b
+	}
+	return a
+}
+
+// MaxMoney returns the larger of a and b.
+func MaxMoney(a, b Money) Money {
+	if a.Less(b) {
+		return b
+	}
+	return a
+}
+
+// Decimal formats the amount with the minor unit digits of its currency,
+// e.g. "12.50" for USD and "1250" for JPY.
+func (m Money) Decimal() string {
+	exponent := currencyExponent(m.Currency)
+	minor := m.Minor
+	sign := ""
+	if minor < 0 {
+		sign, minor = "-", -minor
+	}
+	if exponent == 0 {
+		return sign + strconv.FormatInt(minor, 10)
+	}
+	scale := int64(math.Pow10(exponent))
+	return fmt.Sprintf("%s%d.%0*d", sign, minor/scale, exponent, minor%scale)
+}
+
+// String formats the money as amount and currency, e.g. "12.50 USD".
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Decimal()
+	}
+	return m.Decimal() + " " + m.Currency
+}
+
+func (m Money) currencyOr(o Money) string {
+	if m.Currency == "" {
+		return o.Currency
+	}
+	return m.Currency
+}
+
+type moneyJSON struct {
+	Amount   json.Number `json:"amount"`
+	Currency string      `json:"currency"`
+}
+
+// MarshalJSON encodes money as {"amount": "12.50", "currency": "USD"}. The
+// amount is a string so clients do not read it into a float.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{m.Decimal(), m.Currency})
+}
+
+// UnmarshalJSON decodes money written as by MarshalJSON. The amount may also
+// be a JSON number; either way it is read as a decimal, never as a float.
+// Only a zero amount may leave out the currency.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var v moneyJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Amount == "" {
+		return errors.New("money amount is required")
+	}
+	if v.Currency == "" && strings.Trim(v.Amount.String(), "-0.") == "" {
+		*m = Money{}
+		return nil
+	}
+	parsed, err := ParseMoney(v.Amount.String(), v.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
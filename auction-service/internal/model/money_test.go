@@ -0,0 +1,109 @@
+package model_test
+
+import (
+	"auction-service/internal/model"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		want     model.Money
+		wantErr  bool
+	}{
+		{amount: "12.50", currency: "USD", want: model.NewMoney(1250, "USD")},
+		{amount: "12.5", currency: "EUR", want: model.NewMoney(1250, "EUR")},
+		{amount: "12", currency: "EUR", want: model.NewMoney(1200, "EUR")},
+		{amount: "-0.05", currency: "GBP", want: model.NewMoney(-5, "GBP")},
+		{amount: "1250", currency: "JPY", want: model.NewMoney(1250, "JPY")},
+		{amount: "1.250", currency: "KWD", want: model.NewMoney(1250, "KWD")},
+		{amount: "12.505", currency: "USD", wantErr: true},
+		{amount: "12.5", currency: "JPY", wantErr: true},
+		{amount: "1e3", currency: "USD", wantErr: true},
+		{amount: "abc", currency: "USD", wantErr: true},
+		{amount: ".5", currency: "USD", wantErr: true},
+		{amount: "10", currency: "XXX", wantErr: true},
+		{amount: "10", currency: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.amount+" "+tt.currency, func(t *testing.T) {
+			got, err := model.ParseMoney(tt.amount, tt.currency)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	assert.Equal(t, "12.50 USD", model.NewMoney(1250, "USD").String())
+	assert.Equal(t, "-0.05 EUR", model.NewMoney(-5, "EUR").String())
+	assert.Equal(t, "1250 JPY", model.NewMoney(1250, "JPY").String())
+	assert.Equal(t, "1.250 BHD", model.NewMoney(1250, "BHD").String())
+}
+
+func TestMoneyJSON(t *testing.T) {
+	data, err := json.Marshal(model.NewMoney(1250, "USD"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount": "12.50", "currency": "USD"}`, string(data))
+
+	var decoded model.Money
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, model.NewMoney(1250, "USD"), decoded)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"amount": 99.99, "currency": "EUR"}`), &decoded))
+	assert.Equal(t, model.NewMoney(9999, "EUR"), decoded)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"amount": "0.00", "currency": ""}`), &decoded))
+	assert.Equal(t, model.Money{}, decoded)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"amount": "10", "currency": ""}`), &decoded))
+	assert.Error(t, json.Unmarshal([]byte(`{"amount": 0.001, "currency": "USD"}`), &decoded))
+}
+
+func TestMoneyCmpChecksCurrency(t *testing.T) {
+	assert.True(t, model.NewMoney(100, "USD").Less(model.NewMoney(200, "USD")))
+	assert.Equal(t, 1, model.NewMoney(100, "USD").Cmp(model.Money{}))
+	assert.NotPanics(t, func() { model.NewMoney(100, "USD").Less(model.NewMoney(200, "EUR")) })
+
+	cmp, err := model.NewMoney(100, "USD").CmpChecked(model.NewMoney(200, "USD"))
+	require.NoError(t, err)
+	assert.Equal(t, -1, cmp)
+	cmp, err = model.NewMoney(100, "USD").CmpChecked(model.Money{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, cmp)
+	_, err = model.NewMoney(100, "JPY").CmpChecked(model.NewMoney(100, "USD"))
+	assert.Error(t, err)
+}
+
+func TestMoneyRescale(t *testing.T) {
+	assert.Equal(t, model.NewMoney(13, "JPY"), model.NewMoney(1250, "USD").Rescale("JPY"))
+	assert.Equal(t, model.NewMoney(-1, "JPY"), model.NewMoney(-50, "USD").Rescale("JPY"))
+	assert.Equal(t, model.NewMoney(5000, "BHD"), model.NewMoney(500, "EUR").Rescale("BHD"))
+}
+
+func TestIncrementTableJSON(t *testing.T) {
+	var table model.IncrementTable
+	require.NoError(t, json.Unmarshal([]byte(`[{"from": {"amount": "0"}, "increment": {"amount": "0.50", "currency": "EUR"}},
+		{"from": {"amount": "100.00", "currency": "EUR"}, "increment": {"amount": "2.00", "currency": "EUR"}}]`), &table))
+	assert.Equal(t, model.IncrementTable{
+		{From: model.Money{}, Increment: model.NewMoney(50, "EUR")},
+		{From: model.NewMoney(10000, "EUR"), Increment: model.NewMoney(200, "EUR")},
+	}, table)
+	assert.Equal(t, "EUR", table.Currency())
+	assert.Equal(t, model.NewMoney(200, "EUR"), table.Increment(model.NewMoney(15000, "EUR")))
+	assert.Equal(t, model.NewMoney(2, "JPY"), table.Increment(model.NewMoney(150, "JPY")))
+
+	data, err := json.Marshal(table[1:])
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"from": {"amount": "100.00", "currency": "EUR"}, "increment": {"amount": "2.00", "currency": "EUR"}}]`, string(data))
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+// Order states. An order starts awaiting payment and either goes through
+// paid, shipped and completed, or becomes unpaid when the payment deadline
+// passes. The seller can then offer the item to the runner-up.
+const (
+	OrderStatusAwaitingPayment     = "awaiting_payment"
+	OrderStatusPaid                = "paid"
+	OrderStatusShipped             = "shipped"
+	OrderStatusCompleted           = "completed"
+	OrderStatusUnpaid              = "unpaid"
+	OrderStatusSecondChanceOffered = "second_chance_offered"
+)
+
+// orderTransitions lists the states each order state can move to.
+var orderTransitions = map[string][]string{
+	OrderStatusAwaitingPayment: {OrderStatusPaid, OrderStatusUnpaid},
+	OrderStatusPaid:            {OrderStatusShipped},
+	OrderStatusShipped:         {OrderStatusCompleted},
+	OrderStatusUnpaid:          {OrderStatusSecondChanceOffered},
+}
+
+// Order settles a sold auction between its seller and buyer.
+type Order struct {
+	ID        int `gorm:"primaryKey"`
+	AuctionID int `gorm:"index;not null"`
+	SellerID  int `gorm:"not null"`
+	BuyerID   int `gorm:"index;not null"`
+	// Amount is what the buyer owes, the closing price of the auction.
+	Amount Money  `gorm:"embedded;embeddedPrefix:amount_"`
+	Status string `gorm:"size:32;not null;default:awaiting_payment"`
+	// PaymentDueAt is when an order still awaiting payment becomes unpaid.
+	PaymentDueAt   time.Time `gorm:"index"`
+	PaidAt         *time.Time
+	ShippedAt      *time.Time
+	TrackingNumber string
+	CompletedAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// CanMoveTo reports whether the order can go from its current state to
+// status.
+func (o Order) CanMoveTo(status string) bool {
+	return slices.Contains(orderTransitions[o.Status], status)
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxMessage is an event stored in the same transaction as the change that
+// produced it. The outbox relay publishes it to the broker afterwards.
+type OutboxMessage struct {
+	ID          int    `gorm:"primaryKey"`
+	Type        string `gorm:"size:64;not null"`
+	Payload     []byte `gorm:"not null"`
+	CreatedAt   time.Time
+	PublishedAt *time.Time `gorm:"index"`
+}
+
+// LiveEvent is what the outbox relay broadcasts to every replica to feed
+// the live auction streams: the event and the ID of its outbox message,
+// which orders the events and lets clients resume a stream.
+type LiveEvent struct {
+	ID    int             `json:"id"`
+	Event json.RawMessage `json:"event"`
+}
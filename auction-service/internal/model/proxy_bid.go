@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// ProxyBid is the hidden maximum a user is willing to pay for an auction.
+// The service bids on the user's behalf, by the minimum increment, until the
+// maximum is reached. A user has at most one proxy bid per auction.
+type ProxyBid struct {
+	ID        int   `gorm:"primaryKey"`
+	AuctionID int   `gorm:"uniqueIndex:idx_proxy_bids_auction_user;not null"`
+	UserID    int   `gorm:"uniqueIndex:idx_proxy_bids_auction_user;not null"`
+	MaxAmount Money `gorm:"embedded;embeddedPrefix:max_amount_"`
+	CreatedAt time.Time
+}
@@ -0,0 +1,13 @@
+package model
+
+// SearchResult is an auction found by a keyword search.
+type SearchResult struct {
+	Auction Auction
+	// Rank orders the results, best first. It only compares results of the
+	// same search.
+	Rank float64
+	// HighlightedItem is the item, and Snippet the parts of the description
+	// that matched, as HTML with the matching words in <mark> elements.
+	HighlightedItem string
+	Snippet         string
+}
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// Second-chance offer states. An offer starts pending and is accepted,
+// declined or expires.
+const (
+	OfferStatusPending  = "pending"
+	OfferStatusAccepted = "accepted"
+	OfferStatusDeclined = "declined"
+	OfferStatusExpired  = "expired"
+)
+
+// SecondChanceOffer offers the item of an auction whose winner did not pay
+// to a runner-up bidder, at the price of their highest bid.
+type SecondChanceOffer struct {
+	ID        int `gorm:"primaryKey"`
+	AuctionID int `gorm:"index;not null"`
+	// OrderID is the unpaid order the offer replaces.
+	OrderID  int `gorm:"not null"`
+	SellerID int `gorm:"not null"`
+	BidderID int `gorm:"not null"`
+	// BidID is the bid of the runner-up that sets the price.
+	BidID     int       `gorm:"not null"`
+	Amount    Money     `gorm:"embedded;embeddedPrefix:amount_"`
+	Status    string    `gorm:"size:16;not null;default:pending"`
+	ExpiresAt time.Time `gorm:"index"`
+	// AcceptedOrderID is the order opened when the bidder accepted.
+	AcceptedOrderID int `gorm:"not null;default:0"`
+	RespondedAt     *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// IsPending reports whether the offer still waits for an answer.
+func (o SecondChanceOffer) IsPending() bool {
+	return o.Status == OfferStatusPending
+}
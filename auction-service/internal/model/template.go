@@ -0,0 +1,62 @@
+package model
+
+import "time"
+
+// AuctionTemplate is a listing a seller saved to list the same kind of item
+// again without filling everything in each time.
+type AuctionTemplate struct {
+	ID     int    `gorm:"primaryKey"`
+	UserID int    `gorm:"index;not null"`
+	Name   string `gorm:"size:100;not null"`
+	// The fields below are copied to the auctions listed from the template.
+	Item        string
+	Description string `gorm:"type:text"`
+	Format      string `gorm:"size:32;not null;default:english"`
+	CategoryID  *int
+	Attributes  map[string]string `gorm:"serializer:json"`
+	Currency    string            `gorm:"size:3;not null;default:USD"`
+	// StartPrice is the price auctions listed from the template open at.
+	StartPrice           Money          `gorm:"embedded;embeddedPrefix:start_price_"`
+	ReservePrice         Money          `gorm:"embedded;embeddedPrefix:reserve_price_"`
+	BuyNowPrice          Money          `gorm:"embedded;embeddedPrefix:buy_now_price_"`
+	DutchDecrement       Money          `gorm:"embedded;embeddedPrefix:dutch_decrement_"`
+	DutchIntervalMinutes int            `gorm:"not null;default:0"`
+	DutchFloorPrice      Money          `gorm:"embedded;embeddedPrefix:dutch_floor_price_"`
+	BidIncrements        IncrementTable `gorm:"serializer:json"`
+	// DurationMinutes is how long auctions listed from the template run, 0
+	// for auctions that stay open until the seller closes them.
+	DurationMinutes int `gorm:"not null;default:0"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Auction returns the auction the template lists, opening at startsAt when
+// it is set and otherwise at now.
+func (t AuctionTemplate) Auction(now time.Time, startsAt *time.Time) Auction {
+	auction := Auction{
+		Item:                 t.Item,
+		Description:          t.Description,
+		Format:               t.Format,
+		UserID:               t.UserID,
+		CategoryID:           t.CategoryID,
+		Attributes:           t.Attributes,
+		Currency:             t.Currency,
+		CurrentPrice:         t.StartPrice,
+		ReservePrice:         t.ReservePrice,
+		BuyNowPrice:          t.BuyNowPrice,
+		DutchDecrement:       t.DutchDecrement,
+		DutchIntervalMinutes: t.DutchIntervalMinutes,
+		DutchFloorPrice:      t.DutchFloorPrice,
+		BidIncrements:        t.BidIncrements,
+		StartsAt:             startsAt,
+	}
+	if t.DurationMinutes > 0 {
+		start := now
+		if startsAt != nil {
+			start = *startsAt
+		}
+		end := start.Add(time.Duration(t.DurationMinutes) * time.Minute)
+		auction.EndsAt = &end
+	}
+	return auction
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// WatchlistEntry records that a user watches an auction, to hear when it is
+// about to end. A user watches an auction at most once.
+type WatchlistEntry struct {
+	ID        int `gorm:"primaryKey"`
+	UserID    int `gorm:"uniqueIndex:idx_watchlist_user_auction;not null"`
+	AuctionID int `gorm:"uniqueIndex:idx_watchlist_user_auction;index;not null"`
+	CreatedAt time.Time
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+// WebhookEventTypes are the events partners can subscribe to. Notification
+// intents are left out: they are addressed to users, not to integrations.
+var WebhookEventTypes = []string{
+	EventAuctionCreated, EventAuctionStarted, EventAuctionUpdated, EventAuctionDeleted, EventAuctionClosed,
+	EventAuctionExtended, EventPriceChanged, EventAuctionSold, EventAuctionUnsold,
+	EventBidPlaced, EventBidRetracted,
+	EventOrderCreated, EventOrderPaid, EventOrderShipped, EventOrderCompleted, EventOrderUnpaid,
+	EventSecondChanceOffered, EventSecondChanceAccepted, EventSecondChanceDeclined, EventSecondChanceExpired,
+}
+
+// WebhookSubscription asks for the events of EventTypes to be POSTed to URL,
+// signed with Secret. Subscriptions whose endpoint keeps failing are disabled
+// until an admin enables them again.
+type WebhookSubscription struct {
+	ID          int      `gorm:"primaryKey"`
+	URL         string   `gorm:"not null"`
+	EventTypes  []string `gorm:"serializer:json;not null"`
+	Description string
+	// Secret signs the payloads. It is only returned when the subscription
+	// is created.
+	Secret string `gorm:"size:64;not null" json:",omitempty"`
+	Active bool   `gorm:"not null;default:true"`
+	// ConsecutiveFailures counts the failed attempts since the last
+	// successful delivery.
+	ConsecutiveFailures int `gorm:"not null;default:0"`
+	DisabledAt          *time.Time
+	DisabledReason      string
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// Wants reports whether the subscription receives events of eventType.
+func (s WebhookSubscription) Wants(eventType string) bool {
+	return slices.Contains(s.EventTypes, eventType)
+}
+
+// Webhook delivery states. A delivery is pending until it succeeds or runs
+// out of attempts.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookDelivery is one event sent to one subscription, together with the
+// outcome of its latest attempt. An event is delivered to a subscription at
+// most once.
+type WebhookDelivery struct {
+	ID             int        `gorm:"primaryKey"`
+	SubscriptionID int        `gorm:"uniqueIndex:idx_webhook_delivery_event;not null"`
+	EventID        string     `gorm:"size:64;uniqueIndex:idx_webhook_delivery_event;not null"`
+	EventType      string     `gorm:"size:64;not null"`
+	Payload        []byte     `gorm:"not null" json:"-"`
+	Status         string     `gorm:"size:16;index;not null"`
+	Attempts       int        `gorm:"not null;default:0"`
+	NextAttemptAt  *time.Time `gorm:"index"`
+	// LastStatusCode is the HTTP status of the latest attempt, 0 when the
+	// endpoint could not be reached.
+	LastStatusCode int
+	LastError      string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"errors"
+
+	"auction-service/internal/model"
+)
+
+// ErrAttachmentNotFound is returned when an attachment does not exist.
+var ErrAttachmentNotFound = errors.New("attachment not found")
+
+// AttachmentRepository stores the attachments of auctions. The files
+// themselves live in a blob.Store.
+type AttachmentRepository interface {
+	CreateAttachment(attachment model.Attachment) (model.Attachment, error)
+	GetAttachmentByID(id int) (model.Attachment, error)
+	// GetAttachmentsByAuctionIDs returns the attachments of the auctions,
+	// ordered by ID.
+	GetAttachmentsByAuctionIDs(auctionIDs []int) ([]model.Attachment, error)
+	CountAttachments(auctionID int) (int, error)
+	DeleteAttachment(id int) error
+	// DeleteAttachmentsByAuctionID deletes the attachments of an auction
+	// and returns them, so their blobs can be removed too.
+	DeleteAttachmentsByAuctionID(auctionID int) ([]model.Attachment, error)
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"errors"
+
+	"auction-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// AttachmentRepositoryImpl handles database operations related to
+// attachments.
+type AttachmentRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAttachmentRepository creates a new instance of AttachmentRepository.
+func NewAttachmentRepository(db *gorm.DB) *AttachmentRepositoryImpl {
+	return &AttachmentRepositoryImpl{db}
+}
+
+// Ensure AttachmentRepositoryImpl implements AttachmentRepository
+var _ AttachmentRepository = (*AttachmentRepositoryImpl)(nil)
+
+// CreateAttachment inserts a new attachment.
+func (ar *AttachmentRepositoryImpl) CreateAttachment(attachment model.Attachment) (model.Attachment, error) {
+	attachment.ID = 0
+	err := ar.db.Create(&attachment).Error
+	return attachment, err
+}
+
+// GetAttachmentByID returns an attachment by its ID.
+func (ar *AttachmentRepositoryImpl) GetAttachmentByID(id int) (model.Attachment, error) {
+	var attachment model.Attachment
+	err := ar.db.First(&attachment, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return attachment, ErrAttachmentNotFound
+	}
+	return attachment, err
+}
+
+// GetAttachmentsByAuctionIDs returns the attachments of the auctions.
+func (ar *AttachmentRepositoryImpl) GetAttachmentsByAuctionIDs(auctionIDs []int) ([]model.Attachment, error) {
+	var attachments []model.Attachment
+	if len(auctionIDs) == 0 {
+		return attachments, nil
+	}
+	err := ar.db.Where("auction_id IN ?", auctionIDs).Order("id").Find(&attachments).Error
+	return attachments, err
+}
+
+// CountAttachments returns how many attachments an auction has.
+func (ar *AttachmentRepositoryImpl) CountAttachments(auctionID int) (int, error) {
+	var count int64
+	err := ar.db.Model(&model.Attachment{}).Where("auction_id = ?", auctionID).Count(&count).Error
+	return int(count), err
+}
+
+// DeleteAttachment deletes an attachment.
+func (ar *AttachmentRepositoryImpl) DeleteAttachment(id int) error {
+	result := ar.db.Delete(&model.Attachment{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAttachmentNotFound
+	}
+	return nil
+}
+
+// DeleteAttachmentsByAuctionID deletes and returns the attachments of an
+// auction.
+func (ar *AttachmentRepositoryImpl) DeleteAttachmentsByAuctionID(auctionID int) ([]model.Attachment, error) {
+	var attachments []model.Attachment
+	if err := ar.db.Where("auction_id = ?", auctionID).Order("id").Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	if len(attachments) == 0 {
+		return attachments, nil
+	}
+	err := ar.db.Where("auction_id = ?", auctionID).Delete(&model.Attachment{}).Error
+	return attachments, err
+}
@@ -0,0 +1,60 @@
+package repository_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachmentRepository(t *testing.T) {
+	implementations := map[string]func() repository.AttachmentRepository{
+		"memory": func() repository.AttachmentRepository { return repository.NewMemoryStore().Attachments() },
+		"gorm": func() repository.AttachmentRepository {
+			return repository.NewAttachmentRepository(setupTestDB())
+		},
+	}
+
+	for name, newRepo := range implementations {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+
+			photo, err := repo.CreateAttachment(model.Attachment{AuctionID: 1, Filename: "front.jpg", ContentType: "image/jpeg", Size: 1024,
+				Width: 800, Height: 600, Key: "auctions/1/a.jpg", ThumbnailKey: "auctions/1/a_thumb.jpg"})
+			require.NoError(t, err)
+			assert.NotZero(t, photo.ID)
+			manual, err := repo.CreateAttachment(model.Attachment{AuctionID: 1, Filename: "manual.pdf", ContentType: "application/pdf", Size: 2048, Key: "auctions/1/b.pdf"})
+			require.NoError(t, err)
+			other, err := repo.CreateAttachment(model.Attachment{AuctionID: 2, Filename: "side.png", ContentType: "image/png", Size: 10, Key: "auctions/2/c.png"})
+			require.NoError(t, err)
+
+			fetched, err := repo.GetAttachmentByID(photo.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "auctions/1/a_thumb.jpg", fetched.ThumbnailKey)
+			assert.Equal(t, 800, fetched.Width)
+
+			attachments, err := repo.GetAttachmentsByAuctionIDs([]int{1, 2})
+			require.NoError(t, err)
+			if assert.Len(t, attachments, 3) {
+				assert.Equal(t, []int{photo.ID, manual.ID, other.ID}, []int{attachments[0].ID, attachments[1].ID, attachments[2].ID})
+			}
+			count, err := repo.CountAttachments(1)
+			require.NoError(t, err)
+			assert.Equal(t, 2, count)
+
+			require.NoError(t, repo.DeleteAttachment(other.ID))
+			assert.ErrorIs(t, repo.DeleteAttachment(other.ID), repository.ErrAttachmentNotFound)
+			_, err = repo.GetAttachmentByID(other.ID)
+			assert.ErrorIs(t, err, repository.ErrAttachmentNotFound)
+
+			deleted, err := repo.DeleteAttachmentsByAuctionID(1)
+			require.NoError(t, err)
+			assert.Len(t, deleted, 2)
+			attachments, err = repo.GetAttachmentsByAuctionIDs([]int{1})
+			require.NoError(t, err)
+			assert.Empty(t, attachments)
+		})
+	}
+}
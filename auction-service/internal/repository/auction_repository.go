@@ -2,13 +2,46 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
 	"auction-service/internal/model"
 )
 
+// ErrNotFound is returned when the requested auction does not exist.
+var ErrNotFound = errors.New("auction not found")
+
+// AuctionFilter narrows down the auctions FindAuctions returns. Zero fields
+// do not filter.
+type AuctionFilter struct {
+	// CategoryIDs keeps the auctions listed in any of these categories.
+	CategoryIDs []int
+	// OriginalAuctionID keeps the auction with this ID and its relists.
+	OriginalAuctionID int
+}
+
 // AuctionRepository defines the methods that any repository implementation must have.
 type AuctionRepository interface {
 	GetAllAuctions() ([]model.Auction, error)
+	// FindAuctions returns the auctions matching filter, ordered by ID.
+	FindAuctions(filter AuctionFilter) ([]model.Auction, error)
+	// SearchAuctions returns the auctions matching search, best match
+	// first.
+	SearchAuctions(search AuctionSearch) ([]model.SearchResult, error)
 	GetAuctionByID(id int) (model.Auction, error)
+	// GetAuctionByIDForUpdate is like GetAuctionByID but locks the row until
+	// the surrounding transaction ends.
+	GetAuctionByIDForUpdate(id int) (model.Auction, error)
+	// GetStartingAuctions returns the scheduled auctions whose start time is
+	// not after now, ordered by start time.
+	GetStartingAuctions(now time.Time) ([]model.Auction, error)
+	// GetEndedAuctions returns the open auctions whose end time is not after
+	// now, ordered by end time.
+	GetEndedAuctions(now time.Time) ([]model.Auction, error)
+	// GetEndingAuctions returns the open auctions ending after now and no
+	// later than until whose watchers were not told yet, ordered by end
+	// time.
+	GetEndingAuctions(now, until time.Time) ([]model.Auction, error)
 	CreateAuction(auction model.Auction) (model.Auction, error)
 	UpdateAuction(auction model.Auction) error
 	DeleteAuction(id int) error
@@ -2,6 +2,9 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
 	"auction-service/internal/model"
 
 	"gorm.io/gorm"
@@ -27,23 +30,112 @@ func (ar *AuctionRepositoryImpl) GetAllAuctions() ([]model.Auction, error) {
 	return auctions, err
 }
 
+// FindAuctions returns the auctions matching filter.
+func (ar *AuctionRepositoryImpl) FindAuctions(filter AuctionFilter) ([]model.Auction, error) {
+	var auctions []model.Auction
+	err := ar.filtered(filter).Order("id").Find(&auctions).Error
+	return auctions, err
+}
+
+// SearchAuctions searches the item and description of auctions. On SQLite,
+// which has no search vector, LIKE narrows down the auctions and the
+// matching is done in Go.
+func (ar *AuctionRepositoryImpl) SearchAuctions(search AuctionSearch) ([]model.SearchResult, error) {
+	if len(search.Terms) == 0 {
+		return []model.SearchResult{}, nil
+	}
+	query := ar.filtered(search.Filter)
+	if !isSQLite(ar.db) {
+		return searchPostgres(ar.db, query, search)
+	}
+
+	for _, term := range search.Terms {
+		pattern := "%" + escapeLike(term) + "%"
+		query = query.Where(`(item LIKE ? ESCAPE '\' OR description LIKE ? ESCAPE '\')`, pattern, pattern)
+	}
+	var auctions []model.Auction
+	if err := query.Order("id").Find(&auctions).Error; err != nil {
+		return nil, err
+	}
+	return searchMatches(auctions, search), nil
+}
+
+// filtered selects the auctions matching filter.
+func (ar *AuctionRepositoryImpl) filtered(filter AuctionFilter) *gorm.DB {
+	query := ar.db.Model(&model.Auction{})
+	if filter.CategoryIDs != nil {
+		query = query.Where("category_id IN ?", filter.CategoryIDs)
+	}
+	if filter.OriginalAuctionID != 0 {
+		query = query.Where("(id = ? OR original_auction_id = ?)", filter.OriginalAuctionID, filter.OriginalAuctionID)
+	}
+	return query
+}
+
 // GetAuctionByID returns an auction by its ID from the database.
 func (ar *AuctionRepositoryImpl) GetAuctionByID(id int) (model.Auction, error) {
 	var auction model.Auction
 	err := ar.db.First(&auction, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return auction, ErrNotFound
+	}
+	return auction, err
+}
+
+// GetAuctionByIDForUpdate returns an auction by its ID, locking its row until
+// the transaction ends.
+func (ar *AuctionRepositoryImpl) GetAuctionByIDForUpdate(id int) (model.Auction, error) {
+	var auction model.Auction
+	err := forUpdate(ar.db).First(&auction, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return auction, ErrNotFound
+	}
 	return auction, err
 }
 
+// GetStartingAuctions returns the scheduled auctions whose start time has
+// come.
+func (ar *AuctionRepositoryImpl) GetStartingAuctions(now time.Time) ([]model.Auction, error) {
+	var auctions []model.Auction
+	err := ar.db.Where("status = ? AND starts_at <= ?", model.AuctionStatusScheduled, now.UTC()).
+		Order("starts_at, id").Find(&auctions).Error
+	return auctions, err
+}
+
+// GetEndedAuctions returns the open auctions whose end time has passed.
+func (ar *AuctionRepositoryImpl) GetEndedAuctions(now time.Time) ([]model.Auction, error) {
+	var auctions []model.Auction
+	err := ar.db.Where("status = ? AND ends_at IS NOT NULL AND ends_at <= ?", model.AuctionStatusOpen, now.UTC()).
+		Order("ends_at, id").Find(&auctions).Error
+	return auctions, err
+}
+
+// GetEndingAuctions returns the open auctions ending in (now, until] that
+// are not marked EndingSoonNotified.
+func (ar *AuctionRepositoryImpl) GetEndingAuctions(now, until time.Time) ([]model.Auction, error) {
+	var auctions []model.Auction
+	err := ar.db.Where("status = ? AND ends_at > ? AND ends_at <= ? AND NOT ending_soon_notified", model.AuctionStatusOpen, now.UTC(), until.UTC()).
+		Order("ends_at, id").Find(&auctions).Error
+	return auctions, err
+}
+
 // CreateAuction creates a new auction in the database.
 func (ar *AuctionRepositoryImpl) CreateAuction(auction model.Auction) (model.Auction, error) {
 	err := ar.db.Create(&auction).Error
 	return auction, err
 }
 
-// UpdateAuction updates an existing auction in the database.
+// UpdateAuction updates an existing auction in the database. Unlike Save it
+// never inserts: updating a missing or deleted auction returns ErrNotFound.
 func (ar *AuctionRepositoryImpl) UpdateAuction(updatedAuction model.Auction) error {
-	err := ar.db.Save(&updatedAuction).Error
-	return err
+	result := ar.db.Model(&updatedAuction).Select("*").Omit("ID", "CreatedAt", "DeletedAt").Updates(&updatedAuction)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
 }
 
 // DeleteAuction deletes an existing auction from the database by its ID.
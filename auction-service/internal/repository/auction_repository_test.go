@@ -1,29 +1,38 @@
 package repository_test
 
 import (
-	"auction-service/internal/config"
+	"auction-service/internal/db"
 	"auction-service/internal/model"
 	"auction-service/internal/repository"
 	"log"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// setupTestDB connects to DATABASE_URL, or to a fresh in-memory SQLite
+// database when it is not set, and migrates the schema.
 func setupTestDB() *gorm.DB {
-	cfg := config.LoadConfig() // Get DatabaseURL from config
-
-	// Database connection details (use value from config)
-	dsn := cfg.DatabaseURL // Use DatabaseURL returned by LoadConfig
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		dsn = "sqlite://:memory:"
+	}
 
 	// Connect to the database
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{}) // Use postgres.Open with dsn
+	conn, err := db.Open(dsn)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
-	return db
+
+	if err := conn.AutoMigrate(&model.Auction{}, &model.Bid{}, &model.ProxyBid{}, &model.BidAuditEntry{}, &model.Order{}, &model.SecondChanceOffer{}, &model.WatchlistEntry{}, &model.OutboxMessage{}, &model.ExchangeRate{}, &model.WebhookSubscription{}, &model.WebhookDelivery{}, &model.Category{}, &model.Attachment{}, &model.AuctionTemplate{}); err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+	if err := repository.MigrateSearch(conn); err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+	return conn
 }
 
 func TestCreateAuctionRepo(t *testing.T) {
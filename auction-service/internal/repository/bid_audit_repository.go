@@ -0,0 +1,11 @@
+package repository
+
+import "auction-service/internal/model"
+
+// BidAuditRepository is the append-only log of withdrawn bids. It has no
+// methods to change or delete entries.
+type BidAuditRepository interface {
+	AppendEntry(entry model.BidAuditEntry) (model.BidAuditEntry, error)
+	// GetEntriesByAuctionID returns the entries of an auction, oldest first.
+	GetEntriesByAuctionID(auctionID int) ([]model.BidAuditEntry, error)
+}
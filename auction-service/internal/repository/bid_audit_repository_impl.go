@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"auction-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// BidAuditRepositoryImpl handles database operations related to the bid
+// audit log.
+type BidAuditRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewBidAuditRepository creates a new instance of BidAuditRepository.
+func NewBidAuditRepository(db *gorm.DB) *BidAuditRepositoryImpl {
+	return &BidAuditRepositoryImpl{db}
+}
+
+// Ensure BidAuditRepositoryImpl implements BidAuditRepository
+var _ BidAuditRepository = (*BidAuditRepositoryImpl)(nil)
+
+// AppendEntry stores a new entry in the audit log.
+func (ar *BidAuditRepositoryImpl) AppendEntry(entry model.BidAuditEntry) (model.BidAuditEntry, error) {
+	entry.ID = 0
+	err := ar.db.Create(&entry).Error
+	return entry, err
+}
+
+// GetEntriesByAuctionID returns the audit entries of an auction in the order
+// they were written.
+func (ar *BidAuditRepositoryImpl) GetEntriesByAuctionID(auctionID int) ([]model.BidAuditEntry, error) {
+	var entries []model.BidAuditEntry
+	err := ar.db.Where("auction_id = ?", auctionID).Order("id").Find(&entries).Error
+	return entries, err
+}
@@ -0,0 +1,77 @@
+package repository_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBidAuditLog(t *testing.T) {
+	implementations := map[string]func() repository.BidAuditRepository{
+		"memory": func() repository.BidAuditRepository { return repository.NewMemoryStore().BidAudit() },
+		"gorm":   func() repository.BidAuditRepository { return repository.NewBidAuditRepository(setupTestDB()) },
+	}
+
+	for name, newRepo := range implementations {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+
+			first, err := repo.AppendEntry(model.BidAuditEntry{AuctionID: 1, BidID: 3, BidderID: 2, ActorID: 2, Action: model.BidAuditRetracted, Amount: model.NewMoney(100000, "USD"), Withdrawn: []int{3, 4}})
+			require.NoError(t, err)
+			_, err = repo.AppendEntry(model.BidAuditEntry{AuctionID: 2, BidID: 5, BidderID: 2, ActorID: 9, Action: model.BidAuditCancelled, Reason: "unpaid items"})
+			require.NoError(t, err)
+			second, err := repo.AppendEntry(model.BidAuditEntry{AuctionID: 1, BidID: 6, BidderID: 4, ActorID: 9, Action: model.BidAuditCancelled, Reason: "buyer asked"})
+			require.NoError(t, err)
+
+			entries, err := repo.GetEntriesByAuctionID(1)
+			require.NoError(t, err)
+			if assert.Len(t, entries, 2) {
+				assert.Equal(t, first.ID, entries[0].ID)
+				assert.Equal(t, []int{3, 4}, entries[0].Withdrawn)
+				assert.Equal(t, model.NewMoney(100000, "USD"), entries[0].Amount)
+				assert.Equal(t, second.ID, entries[1].ID)
+				assert.Equal(t, "buyer asked", entries[1].Reason)
+			}
+		})
+	}
+}
+
+func TestSetBidStatus(t *testing.T) {
+	implementations := map[string]func() repository.BidRepository{
+		"memory": func() repository.BidRepository { return repository.NewMemoryStore().Bids() },
+		"gorm":   func() repository.BidRepository { return repository.NewBidRepository(setupTestDB()) },
+	}
+
+	for name, newRepo := range implementations {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+
+			first, err := repo.CreateBid(model.Bid{AuctionID: 1, UserID: 2, Amount: model.NewMoney(1000, "USD")})
+			require.NoError(t, err)
+			assert.Equal(t, model.BidStatusActive, first.Status)
+			second, err := repo.CreateBid(model.Bid{AuctionID: 1, UserID: 3, Amount: model.NewMoney(100000, "USD")})
+			require.NoError(t, err)
+
+			require.NoError(t, repo.SetBidStatus([]int{second.ID}, model.BidStatusRetracted))
+
+			latest, err := repo.GetLatestBid(1)
+			require.NoError(t, err)
+			assert.Equal(t, first.ID, latest.ID)
+			withdrawn, err := repo.GetBidByID(second.ID)
+			require.NoError(t, err)
+			assert.Equal(t, model.BidStatusRetracted, withdrawn.Status)
+			bids, err := repo.GetBidsByAuctionID(1)
+			require.NoError(t, err)
+			assert.Len(t, bids, 2)
+
+			require.NoError(t, repo.SetBidStatus([]int{first.ID}, model.BidStatusCancelled))
+			_, err = repo.GetLatestBid(1)
+			assert.ErrorIs(t, err, repository.ErrNotFound)
+			_, err = repo.GetBidByID(999)
+			assert.ErrorIs(t, err, repository.ErrNotFound)
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package repository
+
+import "auction-service/internal/model"
+
+// BidRepository defines the methods to store and read the bids of auctions.
+type BidRepository interface {
+	CreateBid(bid model.Bid) (model.Bid, error)
+	// GetBidByID returns a bid, or ErrNotFound when it does not exist.
+	GetBidByID(id int) (model.Bid, error)
+	// GetBidsByAuctionID returns every bid of an auction, withdrawn ones
+	// included, in the order they were placed.
+	GetBidsByAuctionID(auctionID int) ([]model.Bid, error)
+	// GetLatestBid returns the last active bid placed on an auction, which is
+	// the leading one, or ErrNotFound when there are no active bids.
+	GetLatestBid(auctionID int) (model.Bid, error)
+	// SetBidStatus sets the status of the bids with the given IDs.
+	SetBidStatus(ids []int, status string) error
+}
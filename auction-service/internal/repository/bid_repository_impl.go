@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"errors"
+
+	"auction-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// BidRepositoryImpl handles database operations related to bids.
+type BidRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewBidRepository creates a new instance of BidRepository.
+func NewBidRepository(db *gorm.DB) *BidRepositoryImpl {
+	return &BidRepositoryImpl{db}
+}
+
+// Ensure BidRepositoryImpl implements BidRepository
+var _ BidRepository = (*BidRepositoryImpl)(nil)
+
+// CreateBid stores a new bid in the database.
+func (br *BidRepositoryImpl) CreateBid(bid model.Bid) (model.Bid, error) {
+	err := br.db.Create(&bid).Error
+	return bid, err
+}
+
+// GetBidByID returns a bid by its ID.
+func (br *BidRepositoryImpl) GetBidByID(id int) (model.Bid, error) {
+	var bid model.Bid
+	err := br.db.First(&bid, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return bid, ErrNotFound
+	}
+	return bid, err
+}
+
+// GetBidsByAuctionID returns the bids of an auction in the order they were placed.
+func (br *BidRepositoryImpl) GetBidsByAuctionID(auctionID int) ([]model.Bid, error) {
+	var bids []model.Bid
+	err := br.db.Where("auction_id = ?", auctionID).Order("id").Find(&bids).Error
+	return bids, err
+}
+
+// GetLatestBid returns the last active bid placed on an auction.
+func (br *BidRepositoryImpl) GetLatestBid(auctionID int) (model.Bid, error) {
+	var bid model.Bid
+	err := br.db.Where("auction_id = ? AND status = ?", auctionID, model.BidStatusActive).Order("id desc").First(&bid).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return bid, ErrNotFound
+	}
+	return bid, err
+}
+
+// SetBidStatus sets the status of the bids with the given IDs.
+func (br *BidRepositoryImpl) SetBidStatus(ids []int, status string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return br.db.Model(&model.Bid{}).Where("id IN ?", ids).Update("status", status).Error
+}
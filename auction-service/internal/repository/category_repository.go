@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"errors"
+
+	"auction-service/internal/model"
+)
+
+// ErrCategoryNotFound is returned when a category does not exist.
+var ErrCategoryNotFound = errors.New("category not found")
+
+// CategoryRepository stores the category tree.
+type CategoryRepository interface {
+	CreateCategory(category model.Category) (model.Category, error)
+	GetCategoryByID(id int) (model.Category, error)
+	// GetCategories returns every category ordered by ID. The tree is small
+	// enough to be walked in memory.
+	GetCategories() ([]model.Category, error)
+	UpdateCategory(category model.Category) error
+	DeleteCategory(id int) error
+}
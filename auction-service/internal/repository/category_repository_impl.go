@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"errors"
+
+	"auction-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// CategoryRepositoryImpl handles database operations related to categories.
+type CategoryRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewCategoryRepository creates a new instance of CategoryRepository.
+func NewCategoryRepository(db *gorm.DB) *CategoryRepositoryImpl {
+	return &CategoryRepositoryImpl{db}
+}
+
+// Ensure CategoryRepositoryImpl implements CategoryRepository
+var _ CategoryRepository = (*CategoryRepositoryImpl)(nil)
+
+// CreateCategory inserts a new category.
+func (cr *CategoryRepositoryImpl) CreateCategory(category model.Category) (model.Category, error) {
+	category.ID = 0
+	err := cr.db.Create(&category).Error
+	return category, err
+}
+
+// GetCategoryByID returns a category by its ID.
+func (cr *CategoryRepositoryImpl) GetCategoryByID(id int) (model.Category, error) {
+	var category model.Category
+	err := cr.db.First(&category, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return category, ErrCategoryNotFound
+	}
+	return category, err
+}
+
+// GetCategories returns every category ordered by ID.
+func (cr *CategoryRepositoryImpl) GetCategories() ([]model.Category, error) {
+	var categories []model.Category
+	err := cr.db.Order("id").Find(&categories).Error
+	return categories, err
+}
+
+// UpdateCategory saves every field of the category.
+func (cr *CategoryRepositoryImpl) UpdateCategory(category model.Category) error {
+	result := cr.db.Model(&category).Select("*").Omit("ID", "CreatedAt").Updates(&category)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
+
+// DeleteCategory deletes a category. It does not check for subcategories or
+// auctions still using it.
+func (cr *CategoryRepositoryImpl) DeleteCategory(id int) error {
+	result := cr.db.Delete(&model.Category{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
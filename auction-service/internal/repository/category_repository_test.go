@@ -0,0 +1,57 @@
+package repository_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategoryRepository(t *testing.T) {
+	implementations := map[string]func() repository.CategoryRepository{
+		"memory": func() repository.CategoryRepository { return repository.NewMemoryStore().Categories() },
+		"gorm": func() repository.CategoryRepository {
+			return repository.NewCategoryRepository(setupTestDB())
+		},
+	}
+
+	for name, newRepo := range implementations {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+
+			root, err := repo.CreateCategory(model.Category{Name: "Books", Slug: "books", Attributes: []model.AttributeSchema{
+				{Name: "author", Type: model.AttributeTypeText, Required: true},
+			}})
+			require.NoError(t, err)
+			assert.NotZero(t, root.ID)
+			child, err := repo.CreateCategory(model.Category{ParentID: &root.ID, Name: "Comics", Slug: "comics",
+				BidIncrements: model.IncrementTable{{From: model.NewMoney(0, "USD"), Increment: model.NewMoney(50, "USD")}}})
+			require.NoError(t, err)
+
+			fetched, err := repo.GetCategoryByID(child.ID)
+			require.NoError(t, err)
+			assert.Equal(t, &root.ID, fetched.ParentID)
+			assert.Equal(t, child.BidIncrements, fetched.BidIncrements)
+
+			root.Name = "Books & Magazines"
+			root.Attributes = append(root.Attributes, model.AttributeSchema{Name: "format", Type: model.AttributeTypeEnum, Options: []string{"hardcover", "paperback"}})
+			require.NoError(t, repo.UpdateCategory(root))
+
+			categories, err := repo.GetCategories()
+			require.NoError(t, err)
+			if assert.Len(t, categories, 2) {
+				assert.Equal(t, "Books & Magazines", categories[0].Name)
+				assert.Equal(t, root.Attributes, categories[0].Attributes)
+				assert.Equal(t, child.ID, categories[1].ID)
+			}
+
+			require.NoError(t, repo.DeleteCategory(child.ID))
+			_, err = repo.GetCategoryByID(child.ID)
+			assert.ErrorIs(t, err, repository.ErrCategoryNotFound)
+			assert.ErrorIs(t, repo.DeleteCategory(child.ID), repository.ErrCategoryNotFound)
+			assert.ErrorIs(t, repo.UpdateCategory(model.Category{ID: 999999, Name: "Ghost"}), repository.ErrCategoryNotFound)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package repository_test
+
+import (
+	"auction-service/internal/repository"
+	"auction-service/internal/repository/repositorytest"
+	"testing"
+)
+
+func TestMemoryAuctionRepositoryContract(t *testing.T) {
+	repositorytest.RunAuctionRepositoryContract(t, func(t *testing.T) repository.AuctionRepository {
+		return repository.NewMemoryAuctionRepository()
+	})
+}
+
+func TestGormAuctionRepositoryContract(t *testing.T) {
+	db := setupTestDB()
+	repositorytest.RunAuctionRepositoryContract(t, func(t *testing.T) repository.AuctionRepository {
+		return repository.NewAuctionRepository(db)
+	})
+}
+
+func TestMemoryTxManagerContract(t *testing.T) {
+	repositorytest.RunTxManagerContract(t, func(t *testing.T) (repository.TxManager, repository.AuctionRepository) {
+		store := repository.NewMemoryStore()
+		return store, store.Auctions()
+	})
+}
+
+func TestGormTxManagerContract(t *testing.T) {
+	db := setupTestDB()
+	repositorytest.RunTxManagerContract(t, func(t *testing.T) (repository.TxManager, repository.AuctionRepository) {
+		return repository.NewTxManager(db), repository.NewAuctionRepository(db)
+	})
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/glebarez/go-sqlite"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// This file keeps the differences between the supported databases, Postgres
+// and SQLite, out of the repositories themselves.
+
+// SQLite result codes, see https://www.sqlite.org/rescode.html.
+const (
+	sqliteBusy   = 5
+	sqliteLocked = 6
+)
+
+// isSQLite reports whether db talks to SQLite.
+func isSQLite(db *gorm.DB) bool {
+	return db.Dialector.Name() == "sqlite"
+}
+
+// forUpdate locks the selected rows until the transaction ends. SQLite has no
+// row locks; there the database-wide write lock, taken by the single
+// connection the pool is limited to, already serializes transactions.
+func forUpdate(db *gorm.DB) *gorm.DB {
+	if isSQLite(db) {
+		return db
+	}
+	return db.Clauses(clause.Locking{Strength: "UPDATE"})
+}
+
+// skipLocked locks the selected rows like forUpdate but leaves out the rows
+// another transaction already locked, so concurrent workers claim different
+// rows instead of waiting for each other.
+func skipLocked(db *gorm.DB) *gorm.DB {
+	if isSQLite(db) {
+		return db
+	}
+	return db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+}
+
+// IsSerializationFailure reports whether err is a transient conflict that is
+// worth retrying: a Postgres serialization failure (40001) or deadlock
+// (40P01), or SQLite reporting the database as busy or locked.
+func IsSerializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "40001" || pgErr.Code == "40P01"
+	}
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		code := sqliteErr.Code() & 0xff
+		return code == sqliteBusy || code == sqliteLocked
+	}
+	return false
+}
@@ -0,0 +1,15 @@
+package repository
+
+import "auction-service/internal/model"
+
+// ExchangeRateRepository defines the methods to store and read exchange
+// rates.
+type ExchangeRateRepository interface {
+	// SaveRates stores rates, replacing stored rates for the same currency
+	// pairs. Either all of them are saved or none.
+	SaveRates(rates []model.ExchangeRate) error
+	// GetRate returns the rate from one currency to another, or ErrNotFound.
+	GetRate(from, to string) (model.ExchangeRate, error)
+	// GetAllRates returns every stored rate ordered by currency pair.
+	GetAllRates() ([]model.ExchangeRate, error)
+}
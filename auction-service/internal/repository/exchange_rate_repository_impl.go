@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"errors"
+
+	"auction-service/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ExchangeRateRepositoryImpl handles database operations related to exchange
+// rates.
+type ExchangeRateRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewExchangeRateRepository creates a new instance of ExchangeRateRepository.
+func NewExchangeRateRepository(db *gorm.DB) *ExchangeRateRepositoryImpl {
+	return &ExchangeRateRepositoryImpl{db}
+}
+
+// Ensure ExchangeRateRepositoryImpl implements ExchangeRateRepository
+var _ ExchangeRateRepository = (*ExchangeRateRepositoryImpl)(nil)
+
+// SaveRates upserts rates in a single statement.
+func (er *ExchangeRateRepositoryImpl) SaveRates(rates []model.ExchangeRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	return er.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "from"}, {Name: "to"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+	}).Create(&rates).Error
+}
+
+// GetRate returns the rate from one currency to another.
+func (er *ExchangeRateRepositoryImpl) GetRate(from, to string) (model.ExchangeRate, error) {
+	var rate model.ExchangeRate
+	err := er.db.Where(&model.ExchangeRate{From: from, To: to}).First(&rate).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return rate, ErrNotFound
+	}
+	return rate, err
+}
+
+// GetAllRates returns every stored rate ordered by currency pair.
+func (er *ExchangeRateRepositoryImpl) GetAllRates() ([]model.ExchangeRate, error) {
+	var rates []model.ExchangeRate
+	err := er.db.Order(clause.OrderByColumn{Column: clause.Column{Name: "from"}}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "to"}}).
+		Find(&rates).Error
+	return rates, err
+}
@@ -0,0 +1,43 @@
+package repository_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExchangeRates(t *testing.T) {
+	implementations := map[string]func() repository.ExchangeRateRepository{
+		"memory": func() repository.ExchangeRateRepository { return repository.NewMemoryStore().ExchangeRates() },
+		"gorm":   func() repository.ExchangeRateRepository { return repository.NewExchangeRateRepository(setupTestDB()) },
+	}
+
+	for name, newRepo := range implementations {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+			_, err := repo.GetRate("USD", "EUR")
+			assert.ErrorIs(t, err, repository.ErrNotFound)
+
+			require.NoError(t, repo.SaveRates([]model.ExchangeRate{
+				{From: "USD", To: "EUR", Rate: "0.92"},
+				{From: "EUR", To: "GBP", Rate: "0.85"},
+			}))
+			require.NoError(t, repo.SaveRates([]model.ExchangeRate{{From: "USD", To: "EUR", Rate: "0.9215"}}))
+
+			rate, err := repo.GetRate("USD", "EUR")
+			require.NoError(t, err)
+			assert.Equal(t, "0.9215", rate.Rate)
+			assert.False(t, rate.UpdatedAt.IsZero())
+
+			rates, err := repo.GetAllRates()
+			require.NoError(t, err)
+			if assert.Len(t, rates, 2) {
+				assert.Equal(t, "EUR", rates[0].From)
+				assert.Equal(t, "USD", rates[1].From)
+			}
+		})
+	}
+}
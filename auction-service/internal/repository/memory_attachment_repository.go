@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"slices"
+	"time"
+
+	"auction-service/internal/model"
+)
+
+// memoryAttachmentRepository is an in-memory AttachmentRepository.
+type memoryAttachmentRepository struct {
+	store *MemoryStore
+	inTx  bool
+}
+
+func (r *memoryAttachmentRepository) CreateAttachment(attachment model.Attachment) (model.Attachment, error) {
+	err := r.store.write(r.inTx, func(d *memoryData) error {
+		d.nextAttachID++
+		attachment.ID = d.nextAttachID
+		attachment.CreatedAt = time.Now()
+		d.attachments = append(d.attachments, attachment)
+		return nil
+	})
+	return attachment, err
+}
+
+func (r *memoryAttachmentRepository) GetAttachmentByID(id int) (model.Attachment, error) {
+	var attachment model.Attachment
+	err := r.store.read(func(d *memoryData) error {
+		for _, existing := range d.attachments {
+			if existing.ID == id {
+				attachment = existing
+				return nil
+			}
+		}
+		return ErrAttachmentNotFound
+	})
+	return attachment, err
+}
+
+func (r *memoryAttachmentRepository) GetAttachmentsByAuctionIDs(auctionIDs []int) ([]model.Attachment, error) {
+	var attachments []model.Attachment
+	r.store.read(func(d *memoryData) error {
+		for _, attachment := range d.attachments {
+			if slices.Contains(auctionIDs, attachment.AuctionID) {
+				attachments = append(attachments, attachment)
+			}
+		}
+		return nil
+	})
+	return attachments, nil
+}
+
+func (r *memoryAttachmentRepository) CountAttachments(auctionID int) (int, error) {
+	attachments, err := r.GetAttachmentsByAuctionIDs([]int{auctionID})
+	return len(attachments), err
+}
+
+func (r *memoryAttachmentRepository) DeleteAttachment(id int) error {
+	return r.store.write(r.inTx, func(d *memoryData) error {
+		before := len(d.attachments)
+		d.attachments = slices.DeleteFunc(d.attachments, func(attachment model.Attachment) bool { return attachment.ID == id })
+		if len(d.attachments) == before {
+			return ErrAttachmentNotFound
+		}
+		return nil
+	})
+}
+
+func (r *memoryAttachmentRepository) DeleteAttachmentsByAuctionID(auctionID int) ([]model.Attachment, error) {
+	var deleted []model.Attachment
+	err := r.store.write(r.inTx, func(d *memoryData) error {
+		d.attachments = slices.DeleteFunc(d.attachments, func(attachment model.Attachment) bool {
+			if attachment.AuctionID == auctionID {
+				deleted = append(deleted, attachment)
+				return true
+			}
+			return false
+		})
+		return nil
+	})
+	return deleted, err
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"auction-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// MemoryAuctionRepository is an in-memory AuctionRepository.
+type MemoryAuctionRepository struct {
+	store *MemoryStore
+	inTx  bool
+}
+
+// NewMemoryAuctionRepository creates an AuctionRepository backed by a new,
+// empty MemoryStore.
+func NewMemoryAuctionRepository() *MemoryAuctionRepository {
+	return &MemoryAuctionRepository{store: NewMemoryStore()}
+}
+
+// Ensure MemoryAuctionRepository implements AuctionRepository
+var _ AuctionRepository = (*MemoryAuctionRepository)(nil)
+
+// GetAllAuctions returns the auctions that are not deleted, ordered by ID.
+func (r *MemoryAuctionRepository) GetAllAuctions() ([]model.Auction, error) {
+	var auctions []model.Auction
+	r.store.read(func(d *memoryData) error {
+		for _, auction := range d.auctions {
+			if !auction.DeletedAt.Valid {
+				auctions = append(auctions, auction)
+			}
+		}
+		return nil
+	})
+	sort.Slice(auctions, func(i, j int) bool { return auctions[i].ID < auctions[j].ID })
+	return auctions, nil
+}
+
+// FindAuctions returns the auctions that are not deleted and match filter,
+// ordered by ID.
+func (r *MemoryAuctionRepository) FindAuctions(filter AuctionFilter) ([]model.Auction, error) {
+	auctions, err := r.GetAllAuctions()
+	if filter.CategoryIDs != nil {
+		auctions = slices.DeleteFunc(auctions, func(auction model.Auction) bool {
+			return auction.CategoryID == nil || !slices.Contains(filter.CategoryIDs, *auction.CategoryID)
+		})
+	}
+	if id := filter.OriginalAuctionID; id != 0 {
+		auctions = slices.DeleteFunc(auctions, func(auction model.Auction) bool {
+			return auction.ID != id && (auction.OriginalAuctionID == nil || *auction.OriginalAuctionID != id)
+		})
+	}
+	return auctions, err
+}
+
+// SearchAuctions matches the auctions that are not deleted and match the
+// filter of search.
+func (r *MemoryAuctionRepository) SearchAuctions(search AuctionSearch) ([]model.SearchResult, error) {
+	if len(search.Terms) == 0 {
+		return []model.SearchResult{}, nil
+	}
+	auctions, err := r.FindAuctions(search.Filter)
+	if err != nil {
+		return nil, err
+	}
+	return searchMatches(auctions, search), nil
+}
+
+// GetAuctionByID returns an auction by its ID.
+func (r *MemoryAuctionRepository) GetAuctionByID(id int) (model.Auction, error) {
+	var auction model.Auction
+	err := r.store.read(func(d *memoryData) error {
+		found, ok := d.auctions[id]
+		if !ok || found.DeletedAt.Valid {
+			return ErrNotFound
+		}
+		auction = found
+		return nil
+	})
+	return auction, err
+}
+
+// GetAuctionByIDForUpdate returns an auction by its ID. Transactions on a
+// MemoryStore are already exclusive so no extra locking is needed.
+func (r *MemoryAuctionRepository) GetAuctionByIDForUpdate(id int) (model.Auction, error) {
+	return r.GetAuctionByID(id)
+}
+
+// GetStartingAuctions returns the scheduled auctions whose start time has
+// come, ordered by start time.
+func (r *MemoryAuctionRepository) GetStartingAuctions(now time.Time) ([]model.Auction, error) {
+	var auctions []model.Auction
+	r.store.read(func(d *memoryData) error {
+		for _, auction := range d.auctions {
+			if !auction.DeletedAt.Valid && auction.IsScheduled() && auction.StartsAt != nil && !auction.StartsAt.After(now) {
+				auctions = append(auctions, auction)
+			}
+		}
+		return nil
+	})
+	sort.Slice(auctions, func(i, j int) bool {
+		if !auctions[i].StartsAt.Equal(*auctions[j].StartsAt) {
+			return auctions[i].StartsAt.Before(*auctions[j].StartsAt)
+		}
+		return auctions[i].ID < auctions[j].ID
+	})
+	return auctions, nil
+}
+
+// GetEndedAuctions returns the open auctions whose end time has passed,
+// ordered by end time.
+func (r *MemoryAuctionRepository) GetEndedAuctions(now time.Time) ([]model.Auction, error) {
+	var auctions []model.Auction
+	r.store.read(func(d *memoryData) error {
+		for _, auction := range d.auctions {
+			if !auction.DeletedAt.Valid && auction.Status == model.AuctionStatusOpen && auction.HasEnded(now) {
+				auctions = append(auctions, auction)
+			}
+		}
+		return nil
+	})
+	sort.Slice(auctions, func(i, j int) bool {
+		if !auctions[i].EndsAt.Equal(*auctions[j].EndsAt) {
+			return auctions[i].EndsAt.Before(*auctions[j].EndsAt)
+		}
+		return auctions[i].ID < auctions[j].ID
+	})
+	return auctions, nil
+}
+
+// GetEndingAuctions returns the open auctions ending in (now, until] whose
+// watchers were not told yet, ordered by end time.
+func (r *MemoryAuctionRepository) GetEndingAuctions(now, until time.Time) ([]model.Auction, error) {
+	var auctions []model.Auction
+	r.store.read(func(d *memoryData) error {
+		for _, auction := range d.auctions {
+			if !auction.DeletedAt.Valid && auction.Status == model.AuctionStatusOpen && !auction.EndingSoonNotified &&
+				auction.EndsAt != nil && auction.EndsAt.After(now) && !auction.EndsAt.After(until) {
+				auctions = append(auctions, auction)
+			}
+		}
+		return nil
+	})
+	sort.Slice(auctions, func(i, j int) bool {
+		if !auctions[i].EndsAt.Equal(*auctions[j].EndsAt) {
+			return auctions[i].EndsAt.Before(*auctions[j].EndsAt)
+		}
+		return auctions[i].ID < auctions[j].ID
+	})
+	return auctions, nil
+}
+
+// CreateAuction stores a new auction and assigns its ID.
+func (r *MemoryAuctionRepository) CreateAuction(auction model.Auction) (model.Auction, error) {
+	err := r.store.write(r.inTx, func(d *memoryData) error {
+		now := time.Now()
+		d.nextAuctionID++
+		auction.ID = d.nextAuctionID
+		auction.CreatedAt = now
+		auction.UpdatedAt = now
+		d.auctions[auction.ID] = auction
+		return nil
+	})
+	return auction, err
+}
+
+// UpdateAuction replaces an existing auction, keeping its creation time.
+func (r *MemoryAuctionRepository) UpdateAuction(updatedAuction model.Auction) error {
+	return r.store.write(r.inTx, func(d *memoryData) error {
+		existing, ok := d.auctions[updatedAuction.ID]
+		if !ok || existing.DeletedAt.Valid {
+			return ErrNotFound
+		}
+		updatedAuction.CreatedAt = existing.CreatedAt
+		updatedAuction.UpdatedAt = time.Now()
+		updatedAuction.DeletedAt = existing.DeletedAt
+		d.auctions[updatedAuction.ID] = updatedAuction
+		return nil
+	})
+}
+
+// DeleteAuction soft deletes an auction. Deleting a missing auction is not an
+// error, like with gorm.
+func (r *MemoryAuctionRepository) DeleteAuction(id int) error {
+	return r.store.write(r.inTx, func(d *memoryData) error {
+		auction, ok := d.auctions[id]
+		if !ok || auction.DeletedAt.Valid {
+			return nil
+		}
+		auction.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		d.auctions[id] = auction
+		return nil
+	})
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"time"
+
+	"auction-service/internal/model"
+)
+
+// memoryBidAuditRepository is an in-memory BidAuditRepository.
+type memoryBidAuditRepository struct {
+	store *MemoryStore
+	inTx  bool
+}
+
+func (r *memoryBidAuditRepository) AppendEntry(entry model.BidAuditEntry) (model.BidAuditEntry, error) {
+	err := r.store.write(r.inTx, func(d *memoryData) error {
+		d.nextAuditID++
+		entry.ID = d.nextAuditID
+		entry.CreatedAt = time.Now()
+		entry.Withdrawn = append([]int(nil), entry.Withdrawn...)
+		d.bidAudit = append(d.bidAudit, entry)
+		return nil
+	})
+	return entry, err
+}
+
+func (r *memoryBidAuditRepository) GetEntriesByAuctionID(auctionID int) ([]model.BidAuditEntry, error) {
+	var entries []model.BidAuditEntry
+	r.store.read(func(d *memoryData) error {
+		for _, entry := range d.bidAudit {
+			if entry.AuctionID == auctionID {
+				entries = append(entries, entry)
+			}
+		}
+		return nil
+	})
+	return entries, nil
+}
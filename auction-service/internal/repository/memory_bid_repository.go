@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"slices"
+	"time"
+
+	"auction-service/internal/model"
+)
+
+// memoryBidRepository is an in-memory BidRepository.
+type memoryBidRepository struct {
+	store *MemoryStore
+	inTx  bool
+}
+
+func (r *memoryBidRepository) CreateBid(bid model.Bid) (model.Bid, error) {
+	err := r.store.write(r.inTx, func(d *memoryData) error {
+		d.nextBidID++
+		bid.ID = d.nextBidID
+		bid.CreatedAt = time.Now()
+		if bid.Status == "" {
+			bid.Status = model.BidStatusActive
+		}
+		d.bids = append(d.bids, bid)
+		return nil
+	})
+	return bid, err
+}
+
+func (r *memoryBidRepository) GetBidByID(id int) (model.Bid, error) {
+	var bid model.Bid
+	err := r.store.read(func(d *memoryData) error {
+		for _, existing := range d.bids {
+			if existing.ID == id {
+				bid = existing
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+	return bid, err
+}
+
+func (r *memoryBidRepository) GetBidsByAuctionID(auctionID int) ([]model.Bid, error) {
+	var bids []model.Bid
+	r.store.read(func(d *memoryData) error {
+		for _, bid := range d.bids {
+			if bid.AuctionID == auctionID {
+				bids = append(bids, bid)
+			}
+		}
+		return nil
+	})
+	return bids, nil
+}
+
+func (r *memoryBidRepository) GetLatestBid(auctionID int) (model.Bid, error) {
+	var bid model.Bid
+	err := r.store.read(func(d *memoryData) error {
+		for i := len(d.bids) - 1; i >= 0; i-- {
+			if d.bids[i].AuctionID == auctionID && d.bids[i].IsActive() {
+				bid = d.bids[i]
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+	return bid, err
+}
+
+func (r *memoryBidRepository) SetBidStatus(ids []int, status string) error {
+	return r.store.write(r.inTx, func(d *memoryData) error {
+		for i := range d.bids {
+			if slices.Contains(ids, d.bids[i].ID) {
+				d.bids[i].Status = status
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"slices"
+	"time"
+
+	"auction-service/internal/model"
+)
+
+// memoryCategoryRepository is an in-memory CategoryRepository.
+type memoryCategoryRepository struct {
+	store *MemoryStore
+	inTx  bool
+}
+
+func (r *memoryCategoryRepository) CreateCategory(category model.Category) (model.Category, error) {
+	err := r.store.write(r.inTx, func(d *memoryData) error {
+		now := time.Now()
+		d.nextCategoryID++
+		category.ID = d.nextCategoryID
+		category.CreatedAt = now
+		category.UpdatedAt = now
+		d.categories = append(d.categories, category)
+		return nil
+	})
+	return category, err
+}
+
+func (r *memoryCategoryRepository) GetCategoryByID(id int) (model.Category, error) {
+	var category model.Category
+	err := r.store.read(func(d *memoryData) error {
+		for _, existing := range d.categories {
+			if existing.ID == id {
+				category = existing
+				return nil
+			}
+		}
+		return ErrCategoryNotFound
+	})
+	return category, err
+}
+
+func (r *memoryCategoryRepository) GetCategories() ([]model.Category, error) {
+	var categories []model.Category
+	r.store.read(func(d *memoryData) error {
+		categories = append(categories, d.categories...)
+		return nil
+	})
+	return categories, nil
+}
+
+func (r *memoryCategoryRepository) UpdateCategory(category model.Category) error {
+	return r.store.write(r.inTx, func(d *memoryData) error {
+		for i, existing := range d.categories {
+			if existing.ID == category.ID {
+				category.CreatedAt = existing.CreatedAt
+				category.UpdatedAt = time.Now()
+				d.categories[i] = category
+				return nil
+			}
+		}
+		return ErrCategoryNotFound
+	})
+}
+
+func (r *memoryCategoryRepository) DeleteCategory(id int) error {
+	return r.store.write(r.inTx, func(d *memoryData) error {
+		before := len(d.categories)
+		d.categories = slices.DeleteFunc(d.categories, func(category model.Category) bool { return category.ID == id })
+		if len(d.categories) == before {
+			return ErrCategoryNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"auction-service/internal/model"
+)
+
+// memoryExchangeRateRepository is an in-memory ExchangeRateRepository.
+type memoryExchangeRateRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryExchangeRateRepository) SaveRates(rates []model.ExchangeRate) error {
+	return r.store.write(false, func(d *memoryData) error {
+		for _, rate := range rates {
+			if rate.UpdatedAt.IsZero() {
+				rate.UpdatedAt = time.Now()
+			}
+			d.rates[[2]string{rate.From, rate.To}] = rate
+		}
+		return nil
+	})
+}
+
+func (r *memoryExchangeRateRepository) GetRate(from, to string) (model.ExchangeRate, error) {
+	var rate model.ExchangeRate
+	err := r.store.read(func(d *memoryData) error {
+		var ok bool
+		if rate, ok = d.rates[[2]string{from, to}]; !ok {
+			return ErrNotFound
+		}
+		return nil
+	})
+	return rate, err
+}
+
+func (r *memoryExchangeRateRepository) GetAllRates() ([]model.ExchangeRate, error) {
+	var rates []model.ExchangeRate
+	err := r.store.read(func(d *memoryData) error {
+		for _, rate := range d.rates {
+			rates = append(rates, rate)
+		}
+		return nil
+	})
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].From != rates[j].From {
+			return rates[i].From < rates[j].From
+		}
+		return rates[i].To < rates[j].To
+	})
+	return rates, err
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"auction-service/internal/model"
+)
+
+// memoryOrderRepository is an in-memory OrderRepository.
+type memoryOrderRepository struct {
+	store *MemoryStore
+	inTx  bool
+}
+
+func (r *memoryOrderRepository) CreateOrder(order model.Order) (model.Order, error) {
+	err := r.store.write(r.inTx, func(d *memoryData) error {
+		now := time.Now()
+		d.nextOrderID++
+		order.ID = d.nextOrderID
+		order.CreatedAt = now
+		order.UpdatedAt = now
+		if order.Status == "" {
+			order.Status = model.OrderStatusAwaitingPayment
+		}
+		d.orders = append(d.orders, order)
+		return nil
+	})
+	return order, err
+}
+
+func (r *memoryOrderRepository) GetOrderByID(id int) (model.Order, error) {
+	var order model.Order
+	err := r.store.read(func(d *memoryData) error {
+		for _, existing := range d.orders {
+			if existing.ID == id {
+				order = existing
+				return nil
+			}
+		}
+		return ErrOrderNotFound
+	})
+	return order, err
+}
+
+// GetOrderByIDForUpdate returns an order by its ID. Transactions on a
+// MemoryStore are already exclusive so no extra locking is needed.
+func (r *memoryOrderRepository) GetOrderByIDForUpdate(id int) (model.Order, error) {
+	return r.GetOrderByID(id)
+}
+
+func (r *memoryOrderRepository) GetOrdersByAuctionID(auctionID int) ([]model.Order, error) {
+	var orders []model.Order
+	r.store.read(func(d *memoryData) error {
+		for _, order := range d.orders {
+			if order.AuctionID == auctionID {
+				orders = append(orders, order)
+			}
+		}
+		return nil
+	})
+	return orders, nil
+}
+
+func (r *memoryOrderRepository) GetOverdueOrders(now time.Time) ([]model.Order, error) {
+	var orders []model.Order
+	r.store.read(func(d *memoryData) error {
+		for _, order := range d.orders {
+			if order.Status == model.OrderStatusAwaitingPayment && !order.PaymentDueAt.After(now) {
+				orders = append(orders, order)
+			}
+		}
+		return nil
+	})
+	sort.SliceStable(orders, func(i, j int) bool { return orders[i].PaymentDueAt.Before(orders[j].PaymentDueAt) })
+	return orders, nil
+}
+
+func (r *memoryOrderRepository) UpdateOrder(order model.Order) error {
+	return r.store.write(r.inTx, func(d *memoryData) error {
+		for i, existing := range d.orders {
+			if existing.ID == order.ID {
+				order.CreatedAt = existing.CreatedAt
+				order.UpdatedAt = time.Now()
+				d.orders[i] = order
+				return nil
+			}
+		}
+		return ErrOrderNotFound
+	})
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"time"
+
+	"auction-service/internal/model"
+)
+
+// memoryOutboxRepository is an in-memory OutboxRepository.
+type memoryOutboxRepository struct {
+	store *MemoryStore
+	inTx  bool
+}
+
+func (r *memoryOutboxRepository) AddMessage(message model.OutboxMessage) (model.OutboxMessage, error) {
+	err := r.store.write(r.inTx, func(d *memoryData) error {
+		d.nextOutboxID++
+		message.ID = d.nextOutboxID
+		message.CreatedAt = time.Now()
+		message.PublishedAt = nil
+		d.outbox = append(d.outbox, message)
+		return nil
+	})
+	return message, err
+}
+
+func (r *memoryOutboxRepository) GetPendingMessages(limit int) ([]model.OutboxMessage, error) {
+	var messages []model.OutboxMessage
+	r.store.read(func(d *memoryData) error {
+		for _, message := range d.outbox {
+			if message.PublishedAt == nil && len(messages) < limit {
+				messages = append(messages, message)
+			}
+		}
+		return nil
+	})
+	return messages, nil
+}
+
+func (r *memoryOutboxRepository) MarkPublished(id int) error {
+	return r.store.write(r.inTx, func(d *memoryData) error {
+		for i := range d.outbox {
+			if d.outbox[i].ID == id {
+				now := time.Now().UTC()
+				d.outbox[i].PublishedAt = &now
+			}
+		}
+		return nil
+	})
+}
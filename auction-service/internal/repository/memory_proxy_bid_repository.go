@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"slices"
+	"time"
+
+	"auction-service/internal/model"
+)
+
+// memoryProxyBidRepository is an in-memory ProxyBidRepository.
+type memoryProxyBidRepository struct {
+	store *MemoryStore
+	inTx  bool
+}
+
+func (r *memoryProxyBidRepository) ReplaceProxyBid(proxyBid model.ProxyBid) (model.ProxyBid, error) {
+	err := r.store.write(r.inTx, func(d *memoryData) error {
+		kept := d.proxyBids[:0]
+		for _, existing := range d.proxyBids {
+			if existing.AuctionID != proxyBid.AuctionID || existing.UserID != proxyBid.UserID {
+				kept = append(kept, existing)
+			}
+		}
+		d.nextProxyBidID++
+		proxyBid.ID = d.nextProxyBidID
+		proxyBid.CreatedAt = time.Now()
+		d.proxyBids = append(kept, proxyBid)
+		return nil
+	})
+	return proxyBid, err
+}
+
+func (r *memoryProxyBidRepository) GetProxyBidsByAuctionID(auctionID int) ([]model.ProxyBid, error) {
+	var proxyBids []model.ProxyBid
+	r.store.read(func(d *memoryData) error {
+		for _, proxyBid := range d.proxyBids {
+			if proxyBid.AuctionID == auctionID {
+				proxyBids = append(proxyBids, proxyBid)
+			}
+		}
+		return nil
+	})
+	return proxyBids, nil
+}
+
+func (r *memoryProxyBidRepository) DeleteProxyBid(auctionID, userID int) error {
+	return r.store.write(r.inTx, func(d *memoryData) error {
+		d.proxyBids = slices.DeleteFunc(d.proxyBids, func(proxyBid model.ProxyBid) bool {
+			return proxyBid.AuctionID == auctionID && proxyBid.UserID == userID
+		})
+		return nil
+	})
+}
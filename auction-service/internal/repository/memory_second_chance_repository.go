@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"auction-service/internal/model"
+)
+
+// memorySecondChanceOfferRepository is an in-memory
+// SecondChanceOfferRepository.
+type memorySecondChanceOfferRepository struct {
+	store *MemoryStore
+	inTx  bool
+}
+
+func (r *memorySecondChanceOfferRepository) CreateOffer(offer model.SecondChanceOffer) (model.SecondChanceOffer, error) {
+	err := r.store.write(r.inTx, func(d *memoryData) error {
+		now := time.Now()
+		d.nextOfferID++
+		offer.ID = d.nextOfferID
+		offer.CreatedAt = now
+		offer.UpdatedAt = now
+		if offer.Status == "" {
+			offer.Status = model.OfferStatusPending
+		}
+		d.offers = append(d.offers, offer)
+		return nil
+	})
+	return offer, err
+}
+
+// GetOfferByIDForUpdate returns an offer by its ID. Transactions on a
+// MemoryStore are already exclusive so no extra locking is needed.
+func (r *memorySecondChanceOfferRepository) GetOfferByIDForUpdate(id int) (model.SecondChanceOffer, error) {
+	var offer model.SecondChanceOffer
+	err := r.store.read(func(d *memoryData) error {
+		for _, existing := range d.offers {
+			if existing.ID == id {
+				offer = existing
+				return nil
+			}
+		}
+		return ErrOfferNotFound
+	})
+	return offer, err
+}
+
+func (r *memorySecondChanceOfferRepository) GetOffersByAuctionID(auctionID int) ([]model.SecondChanceOffer, error) {
+	var offers []model.SecondChanceOffer
+	r.store.read(func(d *memoryData) error {
+		for _, offer := range d.offers {
+			if offer.AuctionID == auctionID {
+				offers = append(offers, offer)
+			}
+		}
+		return nil
+	})
+	return offers, nil
+}
+
+func (r *memorySecondChanceOfferRepository) GetExpiredOffers(now time.Time) ([]model.SecondChanceOffer, error) {
+	var offers []model.SecondChanceOffer
+	r.store.read(func(d *memoryData) error {
+		for _, offer := range d.offers {
+			if offer.IsPending() && !offer.ExpiresAt.After(now) {
+				offers = append(offers, offer)
+			}
+		}
+		return nil
+	})
+	sort.SliceStable(offers, func(i, j int) bool { return offers[i].ExpiresAt.Before(offers[j].ExpiresAt) })
+	return offers, nil
+}
+
+func (r *memorySecondChanceOfferRepository) UpdateOffer(offer model.SecondChanceOffer) error {
+	return r.store.write(r.inTx, func(d *memoryData) error {
+		for i, existing := range d.offers {
+			if existing.ID == offer.ID {
+				offer.CreatedAt = existing.CreatedAt
+				offer.UpdatedAt = time.Now()
+				d.offers[i] = offer
+				return nil
+			}
+		}
+		return ErrOfferNotFound
+	})
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"sync"
+
+	"auction-service/internal/model"
+)
+
+// MemoryStore keeps auctions, bids, proxy bids, the bid audit log, orders,
+// second-chance offers, categories, attachments, templates, outbox messages,
+// exchange rates and webhooks in memory. It mirrors the behaviour of the gorm
+// repositories (soft delete, ErrNotFound) and is meant for tests and local
+// demos. It is safe for concurrent use.
+//
+// Transactions are serialized and rolled back by restoring a snapshot. Writes
+// made through the store's own repositories wait for running transactions, so
+// they must not be used from inside a Transaction callback: use the
+// UnitOfWork passed to it instead.
+type MemoryStore struct {
+	txMu sync.Mutex
+	mu   sync.RWMutex
+	data memoryData
+}
+
+type memoryData struct {
+	auctions       map[int]model.Auction
+	bids           []model.Bid
+	proxyBids      []model.ProxyBid
+	bidAudit       []model.BidAuditEntry
+	orders         []model.Order
+	offers         []model.SecondChanceOffer
+	watchlist      []model.WatchlistEntry
+	categories     []model.Category
+	attachments    []model.Attachment
+	templates      []model.AuctionTemplate
+	outbox         []model.OutboxMessage
+	rates          map[[2]string]model.ExchangeRate
+	webhooks       []model.WebhookSubscription
+	deliveries     []model.WebhookDelivery
+	nextAuctionID  int
+	nextBidID      int
+	nextProxyBidID int
+	nextAuditID    int
+	nextOrderID    int
+	nextOfferID    int
+	nextWatchID    int
+	nextCategoryID int
+	nextAttachID   int
+	nextTemplateID int
+	nextOutboxID   int
+	nextWebhookID  int
+	nextDeliveryID int
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: memoryData{auctions: map[int]model.Auction{}, rates: map[[2]string]model.ExchangeRate{}}}
+}
+
+// Ensure MemoryStore implements TxManager
+var _ TxManager = (*MemoryStore)(nil)
+
+// Auctions returns an AuctionRepository backed by the store.
+func (s *MemoryStore) Auctions() AuctionRepository { return &MemoryAuctionRepository{store: s} }
+
+// Bids returns a BidRepository backed by the store.
+func (s *MemoryStore) Bids() BidRepository { return &memoryBidRepository{store: s} }
+
+// ProxyBids returns a ProxyBidRepository backed by the store.
+func (s *MemoryStore) ProxyBids() ProxyBidRepository { return &memoryProxyBidRepository{store: s} }
+
+// BidAudit returns a BidAuditRepository backed by the store.
+func (s *MemoryStore) BidAudit() BidAuditRepository { return &memoryBidAuditRepository{store: s} }
+
+// Orders returns an OrderRepository backed by the store.
+func (s *MemoryStore) Orders() OrderRepository { return &memoryOrderRepository{store: s} }
+
+// SecondChanceOffers returns a SecondChanceOfferRepository backed by the
+// store.
+func (s *MemoryStore) SecondChanceOffers() SecondChanceOfferRepository {
+	return &memorySecondChanceOfferRepository{store: s}
+}
+
+// Watchlist returns a WatchlistRepository backed by the store.
+func (s *MemoryStore) Watchlist() WatchlistRepository { return &memoryWatchlistRepository{store: s} }
+
+// Categories returns a CategoryRepository backed by the store.
+func (s *MemoryStore) Categories() CategoryRepository { return &memoryCategoryRepository{store: s} }
+
+// Attachments returns an AttachmentRepository backed by the store.
+func (s *MemoryStore) Attachments() AttachmentRepository {
+	return &memoryAttachmentRepository{store: s}
+}
+
+// Templates returns a TemplateRepository backed by the store.
+func (s *MemoryStore) Templates() TemplateRepository { return &memoryTemplateRepository{store: s} }
+
+// Outbox returns an OutboxRepository backed by the store.
+func (s *MemoryStore) Outbox() OutboxRepository { return &memoryOutboxRepository{store: s} }
+
+// ExchangeRates returns an ExchangeRateRepository backed by the store.
+func (s *MemoryStore) ExchangeRates() ExchangeRateRepository {
+	return &memoryExchangeRateRepository{store: s}
+}
+
+// Webhooks returns a WebhookRepository backed by the store.
+func (s *MemoryStore) Webhooks() WebhookRepository { return &memoryWebhookRepository{store: s} }
+
+// Transaction runs fn with exclusive write access to the store and restores
+// the previous state if fn returns an error.
+func (s *MemoryStore) Transaction(fn func(uow UnitOfWork) error) error {
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+	return (&memoryUnitOfWork{store: s}).Transaction(fn)
+}
+
+// write runs fn under the write lock. Outside a transaction it also waits for
+// running transactions to finish.
+func (s *MemoryStore) write(inTx bool, fn func(d *memoryData) error) error {
+	if !inTx {
+		s.txMu.Lock()
+		defer s.txMu.Unlock()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(&s.data)
+}
+
+func (s *MemoryStore) read(fn func(d *memoryData) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn(&s.data)
+}
+
+// snapshot copies the current state so a transaction can be rolled back.
+func (s *MemoryStore) snapshot() memoryData {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	copied := s.data
+	copied.auctions = make(map[int]model.Auction, len(s.data.auctions))
+	for id, auction := range s.data.auctions {
+		copied.auctions[id] = auction
+	}
+	copied.bids = append([]model.Bid(nil), s.data.bids...)
+	copied.proxyBids = append([]model.ProxyBid(nil), s.data.proxyBids...)
+	copied.bidAudit = append([]model.BidAuditEntry(nil), s.data.bidAudit...)
+	copied.orders = append([]model.Order(nil), s.data.orders...)
+	copied.offers = append([]model.SecondChanceOffer(nil), s.data.offers...)
+	copied.watchlist = append([]model.WatchlistEntry(nil), s.data.watchlist...)
+	copied.categories = append([]model.Category(nil), s.data.categories...)
+	copied.attachments = append([]model.Attachment(nil), s.data.attachments...)
+	copied.templates = append([]model.AuctionTemplate(nil), s.data.templates...)
+	copied.outbox = append([]model.OutboxMessage(nil), s.data.outbox...)
+	copied.webhooks = append([]model.WebhookSubscription(nil), s.data.webhooks...)
+	copied.deliveries = append([]model.WebhookDelivery(nil), s.data.deliveries...)
+	copied.rates = make(map[[2]string]model.ExchangeRate, len(s.data.rates))
+	for pair, rate := range s.data.rates {
+		copied.rates[pair] = rate
+	}
+	return copied
+}
+
+// restore puts back a snapshot. ID counters keep advancing, like database
+// sequences, so rolled back IDs are never reused.
+func (s *MemoryStore) restore(data memoryData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data.nextAuctionID = s.data.nextAuctionID
+	data.nextBidID = s.data.nextBidID
+	data.nextProxyBidID = s.data.nextProxyBidID
+	data.nextAuditID = s.data.nextAuditID
+	data.nextOrderID = s.data.nextOrderID
+	data.nextOfferID = s.data.nextOfferID
+	data.nextWatchID = s.data.nextWatchID
+	data.nextCategoryID = s.data.nextCategoryID
+	data.nextAttachID = s.data.nextAttachID
+	data.nextTemplateID = s.data.nextTemplateID
+	data.nextOutboxID = s.data.nextOutboxID
+	data.nextWebhookID = s.data.nextWebhookID
+	data.nextDeliveryID = s.data.nextDeliveryID
+	s.data = data
+}
+
+// memoryUnitOfWork hands out repositories that write inside the running
+// transaction.
+type memoryUnitOfWork struct {
+	store *MemoryStore
+}
+
+func (u *memoryUnitOfWork) Auctions() AuctionRepository {
+	return &MemoryAuctionRepository{store: u.store, inTx: true}
+}
+
+func (u *memoryUnitOfWork) Bids() BidRepository {
+	return &memoryBidRepository{store: u.store, inTx: true}
+}
+
+func (u *memoryUnitOfWork) ProxyBids() ProxyBidRepository {
+	return &memoryProxyBidRepository{store: u.store, inTx: true}
+}
+
+func (u *memoryUnitOfWork) BidAudit() BidAuditRepository {
+	return &memoryBidAuditRepository{store: u.store, inTx: true}
+}
+
+func (u *memoryUnitOfWork) Orders() OrderRepository {
+	return &memoryOrderRepository{store: u.store, inTx: true}
+}
+
+func (u *memoryUnitOfWork) SecondChanceOffers() SecondChanceOfferRepository {
+	return &memorySecondChanceOfferRepository{store: u.store, inTx: true}
+}
+
+func (u *memoryUnitOfWork) Watchlist() WatchlistRepository {
+	return &memoryWatchlistRepository{store: u.store, inTx: true}
+}
+
+func (u *memoryUnitOfWork) Categories() CategoryRepository {
+	return &memoryCategoryRepository{store: u.store, inTx: true}
+}
+
+func (u *memoryUnitOfWork) Attachments() AttachmentRepository {
+	return &memoryAttachmentRepository{store: u.store, inTx: true}
+}
+
+func (u *memoryUnitOfWork) Templates() TemplateRepository {
+	return &memoryTemplateRepository{store: u.store, inTx: true}
+}
+
+func (u *memoryUnitOfWork) Outbox() OutboxRepository {
+	return &memoryOutboxRepository{store: u.store, inTx: true}
+}
+
+// Transaction works like a savepoint: only the changes made by fn are undone
+// when it fails.
+func (u *memoryUnitOfWork) Transaction(fn func(uow UnitOfWork) error) error {
+	saved := u.store.snapshot()
+	if err := fn(u); err != nil {
+		u.store.restore(saved)
+		return err
+	}
+	return nil
+}
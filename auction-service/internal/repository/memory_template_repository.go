@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"auction-service/internal/model"
+)
+
+// memoryTemplateRepository is an in-memory TemplateRepository.
+type memoryTemplateRepository struct {
+	store *MemoryStore
+	inTx  bool
+}
+
+func (r *memoryTemplateRepository) CreateTemplate(template model.AuctionTemplate) (model.AuctionTemplate, error) {
+	err := r.store.write(r.inTx, func(d *memoryData) error {
+		now := time.Now()
+		d.nextTemplateID++
+		template.ID = d.nextTemplateID
+		template.CreatedAt = now
+		template.UpdatedAt = now
+		d.templates = append(d.templates, template)
+		return nil
+	})
+	return template, err
+}
+
+func (r *memoryTemplateRepository) GetTemplateByID(id int) (model.AuctionTemplate, error) {
+	var template model.AuctionTemplate
+	err := r.store.read(func(d *memoryData) error {
+		for _, existing := range d.templates {
+			if existing.ID == id {
+				template = existing
+				return nil
+			}
+		}
+		return ErrTemplateNotFound
+	})
+	return template, err
+}
+
+func (r *memoryTemplateRepository) GetTemplatesByUserID(userID int) ([]model.AuctionTemplate, error) {
+	var templates []model.AuctionTemplate
+	r.store.read(func(d *memoryData) error {
+		for _, template := range d.templates {
+			if template.UserID == userID {
+				templates = append(templates, template)
+			}
+		}
+		return nil
+	})
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].Name != templates[j].Name {
+			return templates[i].Name < templates[j].Name
+		}
+		return templates[i].ID < templates[j].ID
+	})
+	return templates, nil
+}
+
+func (r *memoryTemplateRepository) UpdateTemplate(template model.AuctionTemplate) error {
+	return r.store.write(r.inTx, func(d *memoryData) error {
+		for i, existing := range d.templates {
+			if existing.ID == template.ID {
+				template.CreatedAt = existing.CreatedAt
+				template.UpdatedAt = time.Now()
+				d.templates[i] = template
+				return nil
+			}
+		}
+		return ErrTemplateNotFound
+	})
+}
+
+func (r *memoryTemplateRepository) DeleteTemplate(id int) error {
+	return r.store.write(r.inTx, func(d *memoryData) error {
+		before := len(d.templates)
+		d.templates = slices.DeleteFunc(d.templates, func(template model.AuctionTemplate) bool { return template.ID == id })
+		if len(d.templates) == before {
+			return ErrTemplateNotFound
+		}
+		return nil
+	})
+}
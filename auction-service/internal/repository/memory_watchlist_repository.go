@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"slices"
+	"time"
+
+	"auction-service/internal/model"
+)
+
+// memoryWatchlistRepository is an in-memory WatchlistRepository.
+type memoryWatchlistRepository struct {
+	store *MemoryStore
+	inTx  bool
+}
+
+func (r *memoryWatchlistRepository) AddEntry(entry model.WatchlistEntry) (model.WatchlistEntry, error) {
+	err := r.store.write(r.inTx, func(d *memoryData) error {
+		for _, existing := range d.watchlist {
+			if existing.UserID == entry.UserID && existing.AuctionID == entry.AuctionID {
+				entry = existing
+				return nil
+			}
+		}
+		d.nextWatchID++
+		entry.ID = d.nextWatchID
+		entry.CreatedAt = time.Now()
+		d.watchlist = append(d.watchlist, entry)
+		return nil
+	})
+	return entry, err
+}
+
+func (r *memoryWatchlistRepository) RemoveEntry(userID, auctionID int) error {
+	return r.store.write(r.inTx, func(d *memoryData) error {
+		d.watchlist = slices.DeleteFunc(d.watchlist, func(entry model.WatchlistEntry) bool {
+			return entry.UserID == userID && entry.AuctionID == auctionID
+		})
+		return nil
+	})
+}
+
+func (r *memoryWatchlistRepository) GetEntriesByUserID(userID int) ([]model.WatchlistEntry, error) {
+	var entries []model.WatchlistEntry
+	r.store.read(func(d *memoryData) error {
+		for _, entry := range d.watchlist {
+			if entry.UserID == userID {
+				entries = append(entries, entry)
+			}
+		}
+		return nil
+	})
+	return entries, nil
+}
+
+func (r *memoryWatchlistRepository) GetWatcherIDs(auctionID int) ([]int, error) {
+	var ids []int
+	r.store.read(func(d *memoryData) error {
+		for _, entry := range d.watchlist {
+			if entry.AuctionID == auctionID {
+				ids = append(ids, entry.UserID)
+			}
+		}
+		return nil
+	})
+	slices.Sort(ids)
+	return ids, nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"slices"
+	"time"
+
+	"auction-service/internal/model"
+)
+
+// memoryWebhookRepository is an in-memory WebhookRepository.
+type memoryWebhookRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryWebhookRepository) CreateSubscription(subscription model.WebhookSubscription) (model.WebhookSubscription, error) {
+	err := r.store.write(false, func(d *memoryData) error {
+		now := time.Now()
+		d.nextWebhookID++
+		subscription.ID = d.nextWebhookID
+		subscription.CreatedAt = now
+		subscription.UpdatedAt = now
+		d.webhooks = append(d.webhooks, subscription)
+		return nil
+	})
+	return subscription, err
+}
+
+func (r *memoryWebhookRepository) GetSubscriptionByID(id int) (model.WebhookSubscription, error) {
+	var subscription model.WebhookSubscription
+	err := r.store.read(func(d *memoryData) error {
+		for _, existing := range d.webhooks {
+			if existing.ID == id {
+				subscription = existing
+				return nil
+			}
+		}
+		return ErrSubscriptionNotFound
+	})
+	return subscription, err
+}
+
+func (r *memoryWebhookRepository) GetSubscriptions() ([]model.WebhookSubscription, error) {
+	var subscriptions []model.WebhookSubscription
+	r.store.read(func(d *memoryData) error {
+		subscriptions = append(subscriptions, d.webhooks...)
+		return nil
+	})
+	return subscriptions, nil
+}
+
+func (r *memoryWebhookRepository) UpdateSubscription(subscription model.WebhookSubscription) error {
+	return r.store.write(false, func(d *memoryData) error {
+		for i, existing := range d.webhooks {
+			if existing.ID == subscription.ID {
+				subscription.CreatedAt = existing.CreatedAt
+				subscription.UpdatedAt = time.Now()
+				d.webhooks[i] = subscription
+				return nil
+			}
+		}
+		return ErrSubscriptionNotFound
+	})
+}
+
+func (r *memoryWebhookRepository) DeleteSubscription(id int) error {
+	return r.store.write(false, func(d *memoryData) error {
+		before := len(d.webhooks)
+		d.webhooks = slices.DeleteFunc(d.webhooks, func(subscription model.WebhookSubscription) bool {
+			return subscription.ID == id
+		})
+		if len(d.webhooks) == before {
+			return ErrSubscriptionNotFound
+		}
+		d.deliveries = slices.DeleteFunc(d.deliveries, func(delivery model.WebhookDelivery) bool {
+			return delivery.SubscriptionID == id
+		})
+		return nil
+	})
+}
+
+func (r *memoryWebhookRepository) AddDelivery(delivery model.WebhookDelivery) (bool, error) {
+	added := false
+	err := r.store.write(false, func(d *memoryData) error {
+		for _, existing := range d.deliveries {
+			if existing.SubscriptionID == delivery.SubscriptionID && existing.EventID == delivery.EventID {
+				return nil
+			}
+		}
+		now := time.Now()
+		d.nextDeliveryID++
+		delivery.ID = d.nextDeliveryID
+		delivery.CreatedAt = now
+		delivery.UpdatedAt = now
+		d.deliveries = append(d.deliveries, delivery)
+		added = true
+		return nil
+	})
+	return added, err
+}
+
+func (r *memoryWebhookRepository) GetDueDeliveries(now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	r.store.read(func(d *memoryData) error {
+		for _, delivery := range d.deliveries {
+			due := delivery.NextAttemptAt == nil || !delivery.NextAttemptAt.After(now)
+			if delivery.Status == model.WebhookDeliveryPending && due && len(deliveries) < limit {
+				deliveries = append(deliveries, delivery)
+			}
+		}
+		return nil
+	})
+	return deliveries, nil
+}
+
+func (r *memoryWebhookRepository) GetDeliveriesBySubscriptionID(subscriptionID, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	r.store.read(func(d *memoryData) error {
+		for i := len(d.deliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+			if d.deliveries[i].SubscriptionID == subscriptionID {
+				deliveries = append(deliveries, d.deliveries[i])
+			}
+		}
+		return nil
+	})
+	return deliveries, nil
+}
+
+func (r *memoryWebhookRepository) UpdateDelivery(delivery model.WebhookDelivery) error {
+	return r.store.write(false, func(d *memoryData) error {
+		for i, existing := range d.deliveries {
+			if existing.ID == delivery.ID {
+				delivery.CreatedAt = existing.CreatedAt
+				delivery.UpdatedAt = time.Now()
+				d.deliveries[i] = delivery
+				return nil
+			}
+		}
+		return nil
+	})
+}
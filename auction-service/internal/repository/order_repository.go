@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"auction-service/internal/model"
+)
+
+// ErrOrderNotFound is returned when the requested order does not exist.
+var ErrOrderNotFound = errors.New("order not found")
+
+// OrderRepository defines the methods to store and read the orders that
+// settle sold auctions.
+type OrderRepository interface {
+	CreateOrder(order model.Order) (model.Order, error)
+	GetOrderByID(id int) (model.Order, error)
+	// GetOrderByIDForUpdate is like GetOrderByID but locks the row until the
+	// surrounding transaction ends.
+	GetOrderByIDForUpdate(id int) (model.Order, error)
+	// GetOrdersByAuctionID returns the orders of an auction, oldest first.
+	GetOrdersByAuctionID(auctionID int) ([]model.Order, error)
+	// GetOverdueOrders returns the orders still awaiting payment whose
+	// deadline is not after now, ordered by deadline.
+	GetOverdueOrders(now time.Time) ([]model.Order, error)
+	UpdateOrder(order model.Order) error
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"auction-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// OrderRepositoryImpl handles database operations related to orders.
+type OrderRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewOrderRepository creates a new instance of OrderRepository.
+func NewOrderRepository(db *gorm.DB) *OrderRepositoryImpl {
+	return &OrderRepositoryImpl{db}
+}
+
+// Ensure OrderRepositoryImpl implements OrderRepository
+var _ OrderRepository = (*OrderRepositoryImpl)(nil)
+
+// CreateOrder stores a new order in the database.
+func (or *OrderRepositoryImpl) CreateOrder(order model.Order) (model.Order, error) {
+	order.ID = 0
+	err := or.db.Create(&order).Error
+	return order, err
+}
+
+// GetOrderByID returns an order by its ID.
+func (or *OrderRepositoryImpl) GetOrderByID(id int) (model.Order, error) {
+	var order model.Order
+	err := or.db.First(&order, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return order, ErrOrderNotFound
+	}
+	return order, err
+}
+
+// GetOrderByIDForUpdate returns an order by its ID, locking its row until the
+// transaction ends.
+func (or *OrderRepositoryImpl) GetOrderByIDForUpdate(id int) (model.Order, error) {
+	var order model.Order
+	err := forUpdate(or.db).First(&order, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return order, ErrOrderNotFound
+	}
+	return order, err
+}
+
+// GetOrdersByAuctionID returns the orders of an auction in the order they
+// were created.
+func (or *OrderRepositoryImpl) GetOrdersByAuctionID(auctionID int) ([]model.Order, error) {
+	var orders []model.Order
+	err := or.db.Where("auction_id = ?", auctionID).Order("id").Find(&orders).Error
+	return orders, err
+}
+
+// GetOverdueOrders returns the orders awaiting payment past their deadline.
+func (or *OrderRepositoryImpl) GetOverdueOrders(now time.Time) ([]model.Order, error) {
+	var orders []model.Order
+	err := or.db.Where("status = ? AND payment_due_at <= ?", model.OrderStatusAwaitingPayment, now.UTC()).
+		Order("payment_due_at, id").Find(&orders).Error
+	return orders, err
+}
+
+// UpdateOrder updates an existing order, returning ErrOrderNotFound when it
+// does not exist.
+func (or *OrderRepositoryImpl) UpdateOrder(order model.Order) error {
+	result := or.db.Model(&order).Select("*").Omit("ID", "CreatedAt").Updates(&order)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOrderNotFound
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package repository_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRepository(t *testing.T) {
+	implementations := map[string]func() repository.OrderRepository{
+		"memory": func() repository.OrderRepository { return repository.NewMemoryStore().Orders() },
+		"gorm":   func() repository.OrderRepository { return repository.NewOrderRepository(setupTestDB()) },
+	}
+	due := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	for name, newRepo := range implementations {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+
+			late, err := repo.CreateOrder(model.Order{AuctionID: 1, SellerID: 1, BuyerID: 2, Amount: model.NewMoney(2000, "USD"), Status: model.OrderStatusAwaitingPayment, PaymentDueAt: due.Add(time.Hour)})
+			require.NoError(t, err)
+			early, err := repo.CreateOrder(model.Order{AuctionID: 2, SellerID: 1, BuyerID: 3, Amount: model.NewMoney(500, "USD"), Status: model.OrderStatusAwaitingPayment, PaymentDueAt: due})
+			require.NoError(t, err)
+			_, err = repo.CreateOrder(model.Order{AuctionID: 3, SellerID: 1, BuyerID: 3, Status: model.OrderStatusPaid, PaymentDueAt: due})
+			require.NoError(t, err)
+
+			overdue, err := repo.GetOverdueOrders(due.Add(2 * time.Hour))
+			require.NoError(t, err)
+			if assert.Len(t, overdue, 2) {
+				assert.Equal(t, early.ID, overdue[0].ID)
+				assert.Equal(t, late.ID, overdue[1].ID)
+			}
+
+			late.Status = model.OrderStatusUnpaid
+			require.NoError(t, repo.UpdateOrder(late))
+			stored, err := repo.GetOrderByIDForUpdate(late.ID)
+			require.NoError(t, err)
+			assert.Equal(t, model.OrderStatusUnpaid, stored.Status)
+			assert.Equal(t, model.NewMoney(2000, "USD"), stored.Amount)
+
+			orders, err := repo.GetOrdersByAuctionID(1)
+			require.NoError(t, err)
+			assert.Len(t, orders, 1)
+
+			_, err = repo.GetOrderByID(999)
+			assert.ErrorIs(t, err, repository.ErrOrderNotFound)
+			assert.ErrorIs(t, repo.UpdateOrder(model.Order{ID: 999, Status: model.OrderStatusPaid}), repository.ErrOrderNotFound)
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package repository
+
+import "auction-service/internal/model"
+
+// OutboxRepository stores events until the outbox relay publishes them.
+type OutboxRepository interface {
+	AddMessage(message model.OutboxMessage) (model.OutboxMessage, error)
+	GetPendingMessages(limit int) ([]model.OutboxMessage, error)
+	MarkPublished(id int) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import "auction-service/internal/model"
+
+// ProxyBidRepository defines the methods to store and read proxy bids.
+type ProxyBidRepository interface {
+	// ReplaceProxyBid stores proxyBid as the user's maximum on the auction,
+	// dropping the previous one. The replacement gets a new ID and creation
+	// time, so it loses ties against maximums placed before it.
+	ReplaceProxyBid(proxyBid model.ProxyBid) (model.ProxyBid, error)
+	// GetProxyBidsByAuctionID returns the proxy bids of an auction in the
+	// order they were placed.
+	GetProxyBidsByAuctionID(auctionID int) ([]model.ProxyBid, error)
+	// DeleteProxyBid drops the user's proxy bid on the auction, if any.
+	DeleteProxyBid(auctionID, userID int) error
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"auction-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ProxyBidRepositoryImpl handles database operations related to proxy bids.
+type ProxyBidRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewProxyBidRepository creates a new instance of ProxyBidRepository.
+func NewProxyBidRepository(db *gorm.DB) *ProxyBidRepositoryImpl {
+	return &ProxyBidRepositoryImpl{db}
+}
+
+// Ensure ProxyBidRepositoryImpl implements ProxyBidRepository
+var _ ProxyBidRepository = (*ProxyBidRepositoryImpl)(nil)
+
+// ReplaceProxyBid deletes the user's previous proxy bid on the auction and
+// stores the new one.
+func (pr *ProxyBidRepositoryImpl) ReplaceProxyBid(proxyBid model.ProxyBid) (model.ProxyBid, error) {
+	proxyBid.ID = 0
+	err := pr.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("auction_id = ? AND user_id = ?", proxyBid.AuctionID, proxyBid.UserID).
+			Delete(&model.ProxyBid{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(&proxyBid).Error
+	})
+	return proxyBid, err
+}
+
+// GetProxyBidsByAuctionID returns the proxy bids of an auction in the order they were placed.
+func (pr *ProxyBidRepositoryImpl) GetProxyBidsByAuctionID(auctionID int) ([]model.ProxyBid, error) {
+	var proxyBids []model.ProxyBid
+	err := pr.db.Where("auction_id = ?", auctionID).Order("id").Find(&proxyBids).Error
+	return proxyBids, err
+}
+
+// DeleteProxyBid deletes the user's proxy bid on the auction.
+func (pr *ProxyBidRepositoryImpl) DeleteProxyBid(auctionID, userID int) error {
+	return pr.db.Where("auction_id = ? AND user_id = ?", auctionID, userID).Delete(&model.ProxyBid{}).Error
+}
@@ -0,0 +1,48 @@
+package repository_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceProxyBid(t *testing.T) {
+	implementations := map[string]func() repository.ProxyBidRepository{
+		"memory": func() repository.ProxyBidRepository { return repository.NewMemoryStore().ProxyBids() },
+		"gorm":   func() repository.ProxyBidRepository { return repository.NewProxyBidRepository(setupTestDB()) },
+	}
+
+	for name, newRepo := range implementations {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+
+			first, err := repo.ReplaceProxyBid(model.ProxyBid{AuctionID: 1, UserID: 2, MaxAmount: model.NewMoney(5000, "USD")})
+			require.NoError(t, err)
+			_, err = repo.ReplaceProxyBid(model.ProxyBid{AuctionID: 1, UserID: 3, MaxAmount: model.NewMoney(4000, "USD")})
+			require.NoError(t, err)
+			raised, err := repo.ReplaceProxyBid(model.ProxyBid{AuctionID: 1, UserID: 2, MaxAmount: model.NewMoney(7000, "USD")})
+			require.NoError(t, err)
+			_, err = repo.ReplaceProxyBid(model.ProxyBid{AuctionID: 2, UserID: 2, MaxAmount: model.NewMoney(1000, "USD")})
+			require.NoError(t, err)
+
+			assert.Greater(t, raised.ID, first.ID)
+			proxies, err := repo.GetProxyBidsByAuctionID(1)
+			require.NoError(t, err)
+			if assert.Len(t, proxies, 2) {
+				assert.Equal(t, 3, proxies[0].UserID)
+				assert.Equal(t, 2, proxies[1].UserID)
+				assert.Equal(t, model.NewMoney(7000, "USD"), proxies[1].MaxAmount)
+			}
+
+			require.NoError(t, repo.DeleteProxyBid(1, 3))
+			proxies, err = repo.GetProxyBidsByAuctionID(1)
+			require.NoError(t, err)
+			if assert.Len(t, proxies, 1) {
+				assert.Equal(t, 2, proxies[0].UserID)
+			}
+		})
+	}
+}
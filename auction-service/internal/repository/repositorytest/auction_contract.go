@@ -0,0 +1,382 @@
+// Package repositorytest holds behaviour tests shared by every repository
+// implementation, so the in-memory and gorm versions cannot drift apart.
+package repositorytest
+
+import (
+	"testing"
+	"time"
+
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RunAuctionRepositoryContract checks the behaviour every AuctionRepository
+// must have. newRepo is called once per subtest; the repositories it returns
+// may share data with earlier subtests.
+func RunAuctionRepositoryContract(t *testing.T, newRepo func(t *testing.T) repository.AuctionRepository) {
+	t.Run("CreateAssignsID", func(t *testing.T) {
+		repo := newRepo(t)
+
+		created, err := repo.CreateAuction(model.Auction{Item: "Contract Item", UserID: 1})
+
+		require.NoError(t, err)
+		assert.NotZero(t, created.ID)
+		assert.Equal(t, "Contract Item", created.Item)
+		assert.False(t, created.CreatedAt.IsZero())
+	})
+
+	t.Run("GetByID", func(t *testing.T) {
+		repo := newRepo(t)
+		created, err := repo.CreateAuction(model.Auction{Item: "Contract Item", UserID: 2})
+		require.NoError(t, err)
+
+		fetched, err := repo.GetAuctionByID(created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, created.Item, fetched.Item)
+		assert.Equal(t, created.UserID, fetched.UserID)
+
+		locked, err := repo.GetAuctionByIDForUpdate(created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, locked.ID)
+	})
+
+	t.Run("GetByIDNotFound", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetAuctionByID(999999)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+
+		_, err = repo.GetAuctionByIDForUpdate(999999)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("GetAllSkipsDeleted", func(t *testing.T) {
+		repo := newRepo(t)
+		kept, err := repo.CreateAuction(model.Auction{Item: "Kept", UserID: 1})
+		require.NoError(t, err)
+		deleted, err := repo.CreateAuction(model.Auction{Item: "Deleted", UserID: 1})
+		require.NoError(t, err)
+		require.NoError(t, repo.DeleteAuction(deleted.ID))
+
+		auctions, err := repo.GetAllAuctions()
+		require.NoError(t, err)
+
+		ids := map[int]bool{}
+		for _, auction := range auctions {
+			ids[auction.ID] = true
+		}
+		assert.True(t, ids[kept.ID])
+		assert.False(t, ids[deleted.ID])
+	})
+
+	t.Run("FindByCategory", func(t *testing.T) {
+		repo := newRepo(t)
+		books, toys := 7001, 7002
+		book, err := repo.CreateAuction(model.Auction{Item: "Book", UserID: 1, CategoryID: &books, Attributes: map[string]string{"author": "Tolkien"}})
+		require.NoError(t, err)
+		toy, err := repo.CreateAuction(model.Auction{Item: "Toy", UserID: 1, CategoryID: &toys})
+		require.NoError(t, err)
+		_, err = repo.CreateAuction(model.Auction{Item: "Uncategorized", UserID: 1})
+		require.NoError(t, err)
+		deleted, err := repo.CreateAuction(model.Auction{Item: "Deleted", UserID: 1, CategoryID: &books})
+		require.NoError(t, err)
+		require.NoError(t, repo.DeleteAuction(deleted.ID))
+
+		auctions, err := repo.FindAuctions(repository.AuctionFilter{CategoryIDs: []int{books, toys}})
+		require.NoError(t, err)
+		if assert.Len(t, auctions, 2) {
+			assert.Equal(t, book.ID, auctions[0].ID)
+			assert.Equal(t, map[string]string{"author": "Tolkien"}, auctions[0].Attributes)
+			assert.Equal(t, toy.ID, auctions[1].ID)
+		}
+
+		auctions, err = repo.FindAuctions(repository.AuctionFilter{CategoryIDs: []int{}})
+		require.NoError(t, err)
+		assert.Empty(t, auctions)
+	})
+
+	t.Run("FindByOriginalAuction", func(t *testing.T) {
+		repo := newRepo(t)
+		original, err := repo.CreateAuction(model.Auction{Item: "Vase", UserID: 1, Status: model.AuctionStatusClosed})
+		require.NoError(t, err)
+		first, err := repo.CreateAuction(model.Auction{Item: "Vase", UserID: 1, Status: model.AuctionStatusClosed,
+			RelistedFromID: &original.ID, OriginalAuctionID: &original.ID})
+		require.NoError(t, err)
+		second, err := repo.CreateAuction(model.Auction{Item: "Vase", UserID: 1, Status: model.AuctionStatusScheduled,
+			RelistedFromID: &first.ID, OriginalAuctionID: &original.ID})
+		require.NoError(t, err)
+		_, err = repo.CreateAuction(model.Auction{Item: "Other vase", UserID: 1})
+		require.NoError(t, err)
+
+		auctions, err := repo.FindAuctions(repository.AuctionFilter{OriginalAuctionID: original.ID})
+		require.NoError(t, err)
+		if assert.Len(t, auctions, 3) {
+			assert.Equal(t, original.ID, auctions[0].ID)
+			assert.Equal(t, first.ID, auctions[1].ID)
+			assert.Equal(t, second.ID, auctions[2].ID)
+			assert.Equal(t, &first.ID, auctions[2].RelistedFromID)
+			assert.Equal(t, &original.ID, auctions[2].OriginalAuctionID)
+		}
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		repo := newRepo(t)
+		garden := 7101
+		scarf, err := repo.CreateAuction(model.Auction{Item: "Knitted scarf", Description: "A quokka pattern, fits <small> necks & wrists.", UserID: 1})
+		require.NoError(t, err)
+		plush, err := repo.CreateAuction(model.Auction{Item: "Quokka plush", Description: "Soft toy.", UserID: 1, CategoryID: &garden})
+		require.NoError(t, err)
+		deleted, err := repo.CreateAuction(model.Auction{Item: "Quokka mug", UserID: 1})
+		require.NoError(t, err)
+		require.NoError(t, repo.DeleteAuction(deleted.ID))
+
+		results, err := repo.SearchAuctions(repository.AuctionSearch{Terms: []string{"quokka"}})
+		require.NoError(t, err)
+		if assert.Len(t, results, 2) {
+			// Matches in the item rank higher than in the description.
+			assert.Equal(t, plush.ID, results[0].Auction.ID)
+			assert.Equal(t, "<mark>Quokka</mark> plush", results[0].HighlightedItem)
+			assert.Equal(t, scarf.ID, results[1].Auction.ID)
+			assert.Greater(t, results[0].Rank, results[1].Rank)
+			assert.Contains(t, results[1].Snippet, "<mark>quokka</mark>")
+			assert.Contains(t, results[1].Snippet, "&lt;small&gt;")
+		}
+
+		// Terms match the start of words, and all of them must match.
+		results, err = repo.SearchAuctions(repository.AuctionSearch{Terms: []string{"quok", "knit"}})
+		require.NoError(t, err)
+		if assert.Len(t, results, 1) {
+			assert.Equal(t, scarf.ID, results[0].Auction.ID)
+			assert.Equal(t, "<mark>Knitted</mark> scarf", results[0].HighlightedItem)
+		}
+		results, err = repo.SearchAuctions(repository.AuctionSearch{Terms: []string{"okka"}})
+		require.NoError(t, err)
+		assert.Empty(t, results)
+
+		results, err = repo.SearchAuctions(repository.AuctionSearch{Terms: []string{"quokka"}, Filter: repository.AuctionFilter{CategoryIDs: []int{garden}}})
+		require.NoError(t, err)
+		if assert.Len(t, results, 1) {
+			assert.Equal(t, plush.ID, results[0].Auction.ID)
+		}
+		results, err = repo.SearchAuctions(repository.AuctionSearch{Terms: []string{"quokka"}, Limit: 1})
+		require.NoError(t, err)
+		assert.Len(t, results, 1)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := newRepo(t)
+		created, err := repo.CreateAuction(model.Auction{Item: "Before", UserID: 1, Status: model.AuctionStatusOpen})
+		require.NoError(t, err)
+
+		created.Item = "After"
+		created.Status = model.AuctionStatusClosed
+		created.CurrentPrice = model.NewMoney(1250, "EUR")
+		created.BidIncrements = model.IncrementTable{{From: model.NewMoney(0, "USD"), Increment: model.NewMoney(50, "USD")}, {From: model.NewMoney(10000, "USD"), Increment: model.NewMoney(200, "USD")}}
+		require.NoError(t, repo.UpdateAuction(created))
+
+		fetched, err := repo.GetAuctionByID(created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "After", fetched.Item)
+		assert.Equal(t, model.AuctionStatusClosed, fetched.Status)
+		assert.Equal(t, model.NewMoney(1250, "EUR"), fetched.CurrentPrice)
+		assert.Equal(t, created.BidIncrements, fetched.BidIncrements)
+		assert.WithinDuration(t, created.CreatedAt, fetched.CreatedAt, time.Millisecond)
+	})
+
+	t.Run("UpdateMissingOrDeleted", func(t *testing.T) {
+		repo := newRepo(t)
+		assert.ErrorIs(t, repo.UpdateAuction(model.Auction{ID: 999999, Item: "Ghost"}), repository.ErrNotFound)
+
+		created, err := repo.CreateAuction(model.Auction{Item: "Deleted", UserID: 1})
+		require.NoError(t, err)
+		require.NoError(t, repo.DeleteAuction(created.ID))
+		assert.ErrorIs(t, repo.UpdateAuction(created), repository.ErrNotFound)
+	})
+
+	t.Run("SoftDelete", func(t *testing.T) {
+		repo := newRepo(t)
+		created, err := repo.CreateAuction(model.Auction{Item: "Deleted", UserID: 1})
+		require.NoError(t, err)
+
+		require.NoError(t, repo.DeleteAuction(created.ID))
+		_, err = repo.GetAuctionByID(created.ID)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+
+		// Deleting twice, or deleting an unknown ID, is not an error.
+		assert.NoError(t, repo.DeleteAuction(created.ID))
+		assert.NoError(t, repo.DeleteAuction(999999))
+	})
+
+	t.Run("GetEndedAuctions", func(t *testing.T) {
+		repo := newRepo(t)
+		now := time.Date(2000, 1, 2, 12, 0, 0, 0, time.UTC)
+		at := func(offset time.Duration) *time.Time {
+			end := now.Add(offset)
+			return &end
+		}
+
+		later, err := repo.CreateAuction(model.Auction{Item: "Ended later", UserID: 1, Status: model.AuctionStatusOpen, EndsAt: at(0)})
+		require.NoError(t, err)
+		earlier, err := repo.CreateAuction(model.Auction{Item: "Ended earlier", UserID: 1, Status: model.AuctionStatusOpen, EndsAt: at(-time.Hour)})
+		require.NoError(t, err)
+		running, err := repo.CreateAuction(model.Auction{Item: "Running", UserID: 1, Status: model.AuctionStatusOpen, EndsAt: at(time.Second)})
+		require.NoError(t, err)
+		closed, err := repo.CreateAuction(model.Auction{Item: "Closed", UserID: 1, Status: model.AuctionStatusClosed, EndsAt: at(-time.Hour)})
+		require.NoError(t, err)
+		deleted, err := repo.CreateAuction(model.Auction{Item: "Deleted", UserID: 1, Status: model.AuctionStatusOpen, EndsAt: at(-time.Hour)})
+		require.NoError(t, err)
+		require.NoError(t, repo.DeleteAuction(deleted.ID))
+
+		auctions, err := repo.GetEndedAuctions(now)
+		require.NoError(t, err)
+
+		mine := map[int]bool{later.ID: true, earlier.ID: true, running.ID: true, closed.ID: true, deleted.ID: true}
+		var ids []int
+		for _, auction := range auctions {
+			if mine[auction.ID] {
+				ids = append(ids, auction.ID)
+			}
+		}
+		assert.Equal(t, []int{earlier.ID, later.ID}, ids)
+	})
+
+	t.Run("GetStartingAuctions", func(t *testing.T) {
+		repo := newRepo(t)
+		now := time.Date(2002, 1, 2, 12, 0, 0, 0, time.UTC)
+		at := func(offset time.Duration) *time.Time {
+			start := now.Add(offset)
+			return &start
+		}
+
+		later, err := repo.CreateAuction(model.Auction{Item: "Starting now", UserID: 1, Status: model.AuctionStatusScheduled, StartsAt: at(0)})
+		require.NoError(t, err)
+		earlier, err := repo.CreateAuction(model.Auction{Item: "Started earlier", UserID: 1, Status: model.AuctionStatusScheduled, StartsAt: at(-time.Hour)})
+		require.NoError(t, err)
+		future, err := repo.CreateAuction(model.Auction{Item: "Starting later", UserID: 1, Status: model.AuctionStatusScheduled, StartsAt: at(time.Second)})
+		require.NoError(t, err)
+		opened, err := repo.CreateAuction(model.Auction{Item: "Opened", UserID: 1, Status: model.AuctionStatusOpen, StartsAt: at(-time.Hour)})
+		require.NoError(t, err)
+
+		auctions, err := repo.GetStartingAuctions(now)
+		require.NoError(t, err)
+
+		mine := map[int]bool{later.ID: true, earlier.ID: true, future.ID: true, opened.ID: true}
+		var ids []int
+		for _, auction := range auctions {
+			if mine[auction.ID] {
+				ids = append(ids, auction.ID)
+			}
+		}
+		assert.Equal(t, []int{earlier.ID, later.ID}, ids)
+	})
+
+	t.Run("GetEndingAuctions", func(t *testing.T) {
+		repo := newRepo(t)
+		now := time.Date(2001, 1, 2, 12, 0, 0, 0, time.UTC)
+		at := func(offset time.Duration) *time.Time {
+			end := now.Add(offset)
+			return &end
+		}
+
+		later, err := repo.CreateAuction(model.Auction{Item: "Ending later", UserID: 1, Status: model.AuctionStatusOpen, EndsAt: at(time.Hour)})
+		require.NoError(t, err)
+		earlier, err := repo.CreateAuction(model.Auction{Item: "Ending earlier", UserID: 1, Status: model.AuctionStatusOpen, EndsAt: at(time.Minute)})
+		require.NoError(t, err)
+		ended, err := repo.CreateAuction(model.Auction{Item: "Ended", UserID: 1, Status: model.AuctionStatusOpen, EndsAt: at(0)})
+		require.NoError(t, err)
+		distant, err := repo.CreateAuction(model.Auction{Item: "Distant", UserID: 1, Status: model.AuctionStatusOpen, EndsAt: at(2 * time.Hour)})
+		require.NoError(t, err)
+		notified, err := repo.CreateAuction(model.Auction{Item: "Notified", UserID: 1, Status: model.AuctionStatusOpen, EndsAt: at(time.Minute), EndingSoonNotified: true})
+		require.NoError(t, err)
+		closed, err := repo.CreateAuction(model.Auction{Item: "Closed", UserID: 1, Status: model.AuctionStatusClosed, EndsAt: at(time.Minute)})
+		require.NoError(t, err)
+
+		auctions, err := repo.GetEndingAuctions(now, now.Add(time.Hour))
+		require.NoError(t, err)
+
+		mine := map[int]bool{later.ID: true, earlier.ID: true, ended.ID: true, distant.ID: true, notified.ID: true, closed.ID: true}
+		var ids []int
+		for _, auction := range auctions {
+			if mine[auction.ID] {
+				ids = append(ids, auction.ID)
+			}
+		}
+		assert.Equal(t, []int{earlier.ID, later.ID}, ids)
+	})
+}
+
+// RunTxManagerContract checks commit, rollback and savepoint behaviour of a
+// TxManager. newTx returns the manager and a repository reading the same data
+// outside of transactions.
+func RunTxManagerContract(t *testing.T, newTx func(t *testing.T) (repository.TxManager, repository.AuctionRepository)) {
+	t.Run("Commit", func(t *testing.T) {
+		txManager, repo := newTx(t)
+
+		var auction model.Auction
+		err := txManager.Transaction(func(uow repository.UnitOfWork) error {
+			var err error
+			auction, err = uow.Auctions().CreateAuction(model.Auction{Item: "Committed", UserID: 1})
+			if err != nil {
+				return err
+			}
+			if _, err := uow.Bids().CreateBid(model.Bid{AuctionID: auction.ID, UserID: 2, Amount: model.NewMoney(1000, "USD")}); err != nil {
+				return err
+			}
+			_, err = uow.Outbox().AddMessage(model.OutboxMessage{Type: model.EventBidPlaced, Payload: []byte("{}")})
+			return err
+		})
+		require.NoError(t, err)
+
+		_, err = repo.GetAuctionByID(auction.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		txManager, repo := newTx(t)
+
+		var auction model.Auction
+		err := txManager.Transaction(func(uow repository.UnitOfWork) error {
+			var err error
+			auction, err = uow.Auctions().CreateAuction(model.Auction{Item: "Rolled Back", UserID: 1})
+			if err != nil {
+				return err
+			}
+			return assert.AnError
+		})
+		assert.ErrorIs(t, err, assert.AnError)
+
+		_, err = repo.GetAuctionByID(auction.ID)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("NestedRollback", func(t *testing.T) {
+		txManager, repo := newTx(t)
+
+		var outer, inner model.Auction
+		err := txManager.Transaction(func(uow repository.UnitOfWork) error {
+			var err error
+			outer, err = uow.Auctions().CreateAuction(model.Auction{Item: "Outer", UserID: 1})
+			if err != nil {
+				return err
+			}
+			nestedErr := uow.Transaction(func(nested repository.UnitOfWork) error {
+				inner, err = nested.Auctions().CreateAuction(model.Auction{Item: "Inner", UserID: 1})
+				if err != nil {
+					return err
+				}
+				return assert.AnError
+			})
+			assert.ErrorIs(t, nestedErr, assert.AnError)
+			return nil
+		})
+		require.NoError(t, err)
+
+		_, err = repo.GetAuctionByID(outer.ID)
+		assert.NoError(t, err)
+		_, err = repo.GetAuctionByID(inner.ID)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"html"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"auction-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// Keyword search works on the item and description of auctions. On Postgres
+// the auctions table carries a generated tsvector column with a GIN index,
+// see MigrateSearch; elsewhere the same matching is done, without stemming,
+// by the functions in this file.
+
+// AuctionSearch is a keyword search for auctions.
+type AuctionSearch struct {
+	// Terms are the words to look for, see SearchTerms. Every term must
+	// match the start of a word of the item or description.
+	Terms []string
+	// Filter narrows down the auctions searched.
+	Filter AuctionFilter
+	// Limit caps the number of results, 0 for no limit.
+	Limit int
+}
+
+// Highlights are marked with private-use characters while the text is still
+// plain, and turned into <mark> elements once it has been escaped.
+const (
+	highlightStart = "\uE000"
+	highlightStop  = "\uE001"
+)
+
+// snippetWords is the length of description snippets, in words.
+const snippetWords = 20
+
+var searchWord = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// SearchTerms splits a search query into lower-case words, dropping
+// punctuation and repeated words.
+func SearchTerms(query string) []string {
+	var terms []string
+	for _, word := range searchWord.FindAllString(strings.ToLower(query), -1) {
+		if !slices.Contains(terms, word) {
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+// highlightHTML escapes text and turns the highlight markers in it into
+// <mark> elements.
+func highlightHTML(text string) string {
+	text = html.EscapeString(text)
+	return strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>").Replace(text)
+}
+
+// textWord is a word of a text with its position.
+type textWord struct {
+	start, end int
+	matched    bool
+}
+
+// matchWords splits text into words and marks those starting with a term,
+// recording the terms that matched in found. Words are found in text itself
+// and only then lower cased, since lower casing can change the length of a
+// text and the positions must hold in text.
+func matchWords(text string, terms []string, found map[string]bool) []textWord {
+	var words []textWord
+	for _, bounds := range searchWord.FindAllStringIndex(text, -1) {
+		word := textWord{start: bounds[0], end: bounds[1]}
+		lower := strings.ToLower(text[word.start:word.end])
+		for _, term := range terms {
+			if strings.HasPrefix(lower, term) {
+				word.matched = true
+				found[term] = true
+			}
+		}
+		words = append(words, word)
+	}
+	return words
+}
+
+// mark returns text[from:to] with the matched words among words marked.
+func mark(text string, words []textWord, from, to int) string {
+	var b strings.Builder
+	at := from
+	for _, word := range words {
+		if !word.matched || word.start < from || word.end > to {
+			continue
+		}
+		b.WriteString(text[at:word.start])
+		b.WriteString(highlightStart + text[word.start:word.end] + highlightStop)
+		at = word.end
+	}
+	b.WriteString(text[at:to])
+	return b.String()
+}
+
+// matchAuction searches auction for terms without a full-text index. The
+// rank weighs matches in the item twice as much as in the description, like
+// the weights of the Postgres search vector.
+func matchAuction(auction model.Auction, terms []string) (model.SearchResult, bool) {
+	found := map[string]bool{}
+	itemWords := matchWords(auction.Item, terms, found)
+	descriptionWords := matchWords(auction.Description, terms, found)
+	if len(found) < len(terms) {
+		return model.SearchResult{}, false
+	}
+
+	result := model.SearchResult{
+		Auction:         auction,
+		HighlightedItem: highlightHTML(mark(auction.Item, itemWords, 0, len(auction.Item))),
+	}
+	for _, word := range itemWords {
+		if word.matched {
+			result.Rank += 2
+		}
+	}
+	first := -1
+	for i, word := range descriptionWords {
+		if word.matched {
+			result.Rank++
+			if first < 0 {
+				first = i
+			}
+		}
+	}
+	if len(descriptionWords) > 0 {
+		result.Rank /= float64(len(itemWords) + len(descriptionWords))
+		result.Snippet = highlightHTML(snippet(auction.Description, descriptionWords, max(first, 0)))
+	} else if len(itemWords) > 0 {
+		result.Rank /= float64(len(itemWords))
+	}
+	return result, true
+}
+
+// snippet returns about snippetWords words of text around the word at index
+// around, with ellipses where text was cut.
+func snippet(text string, words []textWord, around int) string {
+	from := max(around-snippetWords/4, 0)
+	to := min(from+snippetWords, len(words))
+	from = max(to-snippetWords, 0)
+
+	start, end := 0, len(text)
+	prefix, suffix := "", ""
+	if from > 0 {
+		start, prefix = words[from].start, "… "
+	}
+	if to < len(words) {
+		end, suffix = words[to-1].end, " …"
+	}
+	return prefix + strings.TrimFunc(mark(text, words, start, end), unicode.IsSpace) + suffix
+}
+
+// searchMatches runs matchAuction over auctions and returns the results best
+// first, ties broken by ID.
+func searchMatches(auctions []model.Auction, search AuctionSearch) []model.SearchResult {
+	results := []model.SearchResult{}
+	for _, auction := range auctions {
+		if result, ok := matchAuction(auction, search.Terms); ok {
+			results = append(results, result)
+		}
+	}
+	slices.SortStableFunc(results, func(a, b model.SearchResult) int {
+		switch {
+		case a.Rank > b.Rank:
+			return -1
+		case a.Rank < b.Rank:
+			return 1
+		}
+		return a.Auction.ID - b.Auction.ID
+	})
+	if search.Limit > 0 && len(results) > search.Limit {
+		results = results[:search.Limit]
+	}
+	return results
+}
+
+// MigrateSearch adds the generated search vector of auctions, with its GIN
+// index, on Postgres. Item words weigh more than description words. It runs
+// after AutoMigrate and can run any number of times.
+func MigrateSearch(db *gorm.DB) error {
+	if isSQLite(db) {
+		return nil
+	}
+	err := db.Exec(`ALTER TABLE auctions ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+		setweight(to_tsvector('english', coalesce(item, '')), 'A') ||
+		setweight(to_tsvector('english', coalesce(description, '')), 'B')) STORED`).Error
+	if err != nil {
+		return err
+	}
+	return db.Exec(`CREATE INDEX IF NOT EXISTS idx_auctions_search_vector ON auctions USING GIN (search_vector)`).Error
+}
+
+// searchPostgres runs search against the search vector of auctions, which
+// also stems words, so "running" finds "runs". query selects the auctions
+// the search is narrowed down to.
+func searchPostgres(db, query *gorm.DB, search AuctionSearch) ([]model.SearchResult, error) {
+	prefixes := make([]string, len(search.Terms))
+	for i, term := range search.Terms {
+		prefixes[i] = term + ":*"
+	}
+	tsquery := strings.Join(prefixes, " & ")
+	markers := `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `"`
+
+	var hits []struct {
+		ID              int
+		SearchRank      float64
+		HighlightedItem string
+		Snippet         string
+	}
+	query = query.Select(`id, ts_rank(search_vector, to_tsquery('english', @query)) AS search_rank,
+		ts_headline('english', coalesce(item, ''), to_tsquery('english', @query), @item) AS highlighted_item,
+		ts_headline('english', coalesce(description, ''), to_tsquery('english', @query), @snippet) AS snippet`,
+		sql.Named("query", tsquery),
+		sql.Named("item", markers+", HighlightAll=true"),
+		sql.Named("snippet", fmt.Sprintf(`%s, MaxFragments=2, MaxWords=%d, MinWords=5, FragmentDelimiter=" … "`, markers, snippetWords)),
+	).Where("search_vector @@ to_tsquery('english', ?)", tsquery).Order("search_rank DESC, id")
+	if search.Limit > 0 {
+		query = query.Limit(search.Limit)
+	}
+	if err := query.Scan(&hits).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	var auctions []model.Auction
+	if err := db.Where("id IN ?", ids).Find(&auctions).Error; err != nil {
+		return nil, err
+	}
+	byID := map[int]model.Auction{}
+	for _, auction := range auctions {
+		byID[auction.ID] = auction
+	}
+	results := []model.SearchResult{}
+	for _, hit := range hits {
+		auction, ok := byID[hit.ID]
+		if !ok {
+			continue
+		}
+		results = append(results, model.SearchResult{
+			Auction:         auction,
+			Rank:            hit.SearchRank,
+			HighlightedItem: highlightHTML(hit.HighlightedItem),
+			Snippet:         highlightHTML(hit.Snippet),
+		})
+	}
+	return results, nil
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern, with \ as the escape
+// character.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -0,0 +1,14 @@
+package repository_test
+
+import (
+	"auction-service/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTerms(t *testing.T) {
+	assert.Equal(t, []string{"red", "running", "shoes", "42"}, repository.SearchTerms(" Red, running-shoes (42) red!"))
+	assert.Equal(t, []string{"café", "crème"}, repository.SearchTerms("Café & Crème"))
+	assert.Empty(t, repository.SearchTerms(" %_ & "))
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"auction-service/internal/model"
+)
+
+// ErrOfferNotFound is returned when the requested second-chance offer does
+// not exist.
+var ErrOfferNotFound = errors.New("offer not found")
+
+// SecondChanceOfferRepository defines the methods to store and read the
+// second-chance offers of auctions.
+type SecondChanceOfferRepository interface {
+	CreateOffer(offer model.SecondChanceOffer) (model.SecondChanceOffer, error)
+	// GetOfferByIDForUpdate returns an offer and locks its row until the
+	// surrounding transaction ends.
+	GetOfferByIDForUpdate(id int) (model.SecondChanceOffer, error)
+	// GetOffersByAuctionID returns the offers of an auction, oldest first.
+	GetOffersByAuctionID(auctionID int) ([]model.SecondChanceOffer, error)
+	// GetExpiredOffers returns the pending offers whose expiry is not after
+	// now, ordered by expiry.
+	GetExpiredOffers(now time.Time) ([]model.SecondChanceOffer, error)
+	UpdateOffer(offer model.SecondChanceOffer) error
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"auction-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// SecondChanceOfferRepositoryImpl handles database operations related to
+// second-chance offers.
+type SecondChanceOfferRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewSecondChanceOfferRepository creates a new instance of
+// SecondChanceOfferRepository.
+func NewSecondChanceOfferRepository(db *gorm.DB) *SecondChanceOfferRepositoryImpl {
+	return &SecondChanceOfferRepositoryImpl{db}
+}
+
+// Ensure SecondChanceOfferRepositoryImpl implements SecondChanceOfferRepository
+var _ SecondChanceOfferRepository = (*SecondChanceOfferRepositoryImpl)(nil)
+
+// CreateOffer stores a new offer in the database.
+func (sr *SecondChanceOfferRepositoryImpl) CreateOffer(offer model.SecondChanceOffer) (model.SecondChanceOffer, error) {
+	offer.ID = 0
+	err := sr.db.Create(&offer).Error
+	return offer, err
+}
+
+// GetOfferByIDForUpdate returns an offer by its ID, locking its row until the
+// transaction ends.
+func (sr *SecondChanceOfferRepositoryImpl) GetOfferByIDForUpdate(id int) (model.SecondChanceOffer, error) {
+	var offer model.SecondChanceOffer
+	err := forUpdate(sr.db).First(&offer, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return offer, ErrOfferNotFound
+	}
+	return offer, err
+}
+
+// GetOffersByAuctionID returns the offers of an auction in the order they
+// were made.
+func (sr *SecondChanceOfferRepositoryImpl) GetOffersByAuctionID(auctionID int) ([]model.SecondChanceOffer, error) {
+	var offers []model.SecondChanceOffer
+	err := sr.db.Where("auction_id = ?", auctionID).Order("id").Find(&offers).Error
+	return offers, err
+}
+
+// GetExpiredOffers returns the pending offers past their expiry.
+func (sr *SecondChanceOfferRepositoryImpl) GetExpiredOffers(now time.Time) ([]model.SecondChanceOffer, error) {
+	var offers []model.SecondChanceOffer
+	err := sr.db.Where("status = ? AND expires_at <= ?", model.OfferStatusPending, now.UTC()).
+		Order("expires_at, id").Find(&offers).Error
+	return offers, err
+}
+
+// UpdateOffer updates an existing offer, returning ErrOfferNotFound when it
+// does not exist.
+func (sr *SecondChanceOfferRepositoryImpl) UpdateOffer(offer model.SecondChanceOffer) error {
+	result := sr.db.Model(&offer).Select("*").Omit("ID", "CreatedAt").Updates(&offer)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOfferNotFound
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package repository_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecondChanceOfferRepository(t *testing.T) {
+	implementations := map[string]func() repository.SecondChanceOfferRepository{
+		"memory": func() repository.SecondChanceOfferRepository { return repository.NewMemoryStore().SecondChanceOffers() },
+		"gorm": func() repository.SecondChanceOfferRepository {
+			return repository.NewSecondChanceOfferRepository(setupTestDB())
+		},
+	}
+	expires := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	for name, newRepo := range implementations {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+
+			offer, err := repo.CreateOffer(model.SecondChanceOffer{AuctionID: 1, OrderID: 1, SellerID: 1, BidderID: 3, BidID: 2, Amount: model.NewMoney(3000, "USD"), Status: model.OfferStatusPending, ExpiresAt: expires})
+			require.NoError(t, err)
+			answered, err := repo.CreateOffer(model.SecondChanceOffer{AuctionID: 1, OrderID: 1, SellerID: 1, BidderID: 2, BidID: 1, Status: model.OfferStatusDeclined, ExpiresAt: expires})
+			require.NoError(t, err)
+
+			expired, err := repo.GetExpiredOffers(expires)
+			require.NoError(t, err)
+			if assert.Len(t, expired, 1) {
+				assert.Equal(t, offer.ID, expired[0].ID)
+			}
+			none, err := repo.GetExpiredOffers(expires.Add(-time.Second))
+			require.NoError(t, err)
+			assert.Empty(t, none)
+
+			offer.Status = model.OfferStatusAccepted
+			offer.AcceptedOrderID = 7
+			require.NoError(t, repo.UpdateOffer(offer))
+			stored, err := repo.GetOfferByIDForUpdate(offer.ID)
+			require.NoError(t, err)
+			assert.Equal(t, model.OfferStatusAccepted, stored.Status)
+			assert.Equal(t, 7, stored.AcceptedOrderID)
+			assert.Equal(t, model.NewMoney(3000, "USD"), stored.Amount)
+
+			offers, err := repo.GetOffersByAuctionID(1)
+			require.NoError(t, err)
+			if assert.Len(t, offers, 2) {
+				assert.Equal(t, answered.ID, offers[1].ID)
+			}
+			_, err = repo.GetOfferByIDForUpdate(999)
+			assert.ErrorIs(t, err, repository.ErrOfferNotFound)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"errors"
+
+	"auction-service/internal/model"
+)
+
+// ErrTemplateNotFound is returned when an auction template does not exist.
+var ErrTemplateNotFound = errors.New("auction template not found")
+
+// TemplateRepository stores the auction templates of sellers.
+type TemplateRepository interface {
+	CreateTemplate(template model.AuctionTemplate) (model.AuctionTemplate, error)
+	GetTemplateByID(id int) (model.AuctionTemplate, error)
+	// GetTemplatesByUserID returns the templates of a seller ordered by
+	// name.
+	GetTemplatesByUserID(userID int) ([]model.AuctionTemplate, error)
+	UpdateTemplate(template model.AuctionTemplate) error
+	DeleteTemplate(id int) error
+}
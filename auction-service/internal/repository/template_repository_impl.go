@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"errors"
+
+	"auction-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// TemplateRepositoryImpl handles database operations related to auction
+// templates.
+type TemplateRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewTemplateRepository creates a new instance of TemplateRepository.
+func NewTemplateRepository(db *gorm.DB) *TemplateRepositoryImpl {
+	return &TemplateRepositoryImpl{db}
+}
+
+// Ensure TemplateRepositoryImpl implements TemplateRepository
+var _ TemplateRepository = (*TemplateRepositoryImpl)(nil)
+
+// CreateTemplate inserts a new template.
+func (tr *TemplateRepositoryImpl) CreateTemplate(template model.AuctionTemplate) (model.AuctionTemplate, error) {
+	template.ID = 0
+	err := tr.db.Create(&template).Error
+	return template, err
+}
+
+// GetTemplateByID returns a template by its ID.
+func (tr *TemplateRepositoryImpl) GetTemplateByID(id int) (model.AuctionTemplate, error) {
+	var template model.AuctionTemplate
+	err := tr.db.First(&template, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return template, ErrTemplateNotFound
+	}
+	return template, err
+}
+
+// GetTemplatesByUserID returns the templates of a seller ordered by name.
+func (tr *TemplateRepositoryImpl) GetTemplatesByUserID(userID int) ([]model.AuctionTemplate, error) {
+	var templates []model.AuctionTemplate
+	err := tr.db.Where("user_id = ?", userID).Order("name, id").Find(&templates).Error
+	return templates, err
+}
+
+// UpdateTemplate saves every field of the template.
+func (tr *TemplateRepositoryImpl) UpdateTemplate(template model.AuctionTemplate) error {
+	result := tr.db.Model(&template).Select("*").Omit("ID", "CreatedAt").Updates(&template)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
+
+// DeleteTemplate deletes a template.
+func (tr *TemplateRepositoryImpl) DeleteTemplate(id int) error {
+	result := tr.db.Delete(&model.AuctionTemplate{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package repository_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateRepository(t *testing.T) {
+	implementations := map[string]func() repository.TemplateRepository{
+		"memory": func() repository.TemplateRepository { return repository.NewMemoryStore().Templates() },
+		"gorm": func() repository.TemplateRepository {
+			return repository.NewTemplateRepository(setupTestDB())
+		},
+	}
+
+	for name, newRepo := range implementations {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+			category := 3
+
+			vinyl, err := repo.CreateTemplate(model.AuctionTemplate{UserID: 1, Name: "Vinyl", Item: "Record", CategoryID: &category,
+				Attributes: map[string]string{"speed": "33"}, Currency: "EUR", StartPrice: model.MoneyFromMajor(5, "EUR"),
+				BidIncrements: model.IncrementTable{{From: model.NewMoney(0, "USD"), Increment: model.NewMoney(50, "USD")}}, DurationMinutes: 60 * 24 * 7})
+			require.NoError(t, err)
+			assert.NotZero(t, vinyl.ID)
+			books, err := repo.CreateTemplate(model.AuctionTemplate{UserID: 1, Name: "Books", Item: "Book"})
+			require.NoError(t, err)
+			_, err = repo.CreateTemplate(model.AuctionTemplate{UserID: 2, Name: "Art", Item: "Print"})
+			require.NoError(t, err)
+
+			fetched, err := repo.GetTemplateByID(vinyl.ID)
+			require.NoError(t, err)
+			assert.Equal(t, &category, fetched.CategoryID)
+			assert.Equal(t, vinyl.Attributes, fetched.Attributes)
+			assert.Equal(t, vinyl.StartPrice, fetched.StartPrice)
+			assert.Equal(t, vinyl.BidIncrements, fetched.BidIncrements)
+			assert.Equal(t, 60*24*7, fetched.DurationMinutes)
+
+			vinyl.Name = "Vinyl LPs"
+			vinyl.DurationMinutes = 60
+			require.NoError(t, repo.UpdateTemplate(vinyl))
+
+			templates, err := repo.GetTemplatesByUserID(1)
+			require.NoError(t, err)
+			if assert.Len(t, templates, 2) {
+				assert.Equal(t, books.ID, templates[0].ID)
+				assert.Equal(t, "Vinyl LPs", templates[1].Name)
+				assert.Equal(t, 60, templates[1].DurationMinutes)
+			}
+
+			require.NoError(t, repo.DeleteTemplate(books.ID))
+			_, err = repo.GetTemplateByID(books.ID)
+			assert.ErrorIs(t, err, repository.ErrTemplateNotFound)
+			assert.ErrorIs(t, repo.DeleteTemplate(books.ID), repository.ErrTemplateNotFound)
+			assert.ErrorIs(t, repo.UpdateTemplate(model.AuctionTemplate{ID: 999999, Name: "Ghost"}), repository.ErrTemplateNotFound)
+		})
+	}
+}
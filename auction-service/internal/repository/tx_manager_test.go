@@ -0,0 +1,18 @@
+package repository_test
+
+import (
+	"auction-service/internal/repository"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSerializationFailure(t *testing.T) {
+	assert.True(t, repository.IsSerializationFailure(fmt.Errorf("commit: %w", &pgconn.PgError{Code: "40001"})))
+	assert.True(t, repository.IsSerializationFailure(&pgconn.PgError{Code: "40P01"}))
+	assert.False(t, repository.IsSerializationFailure(&pgconn.PgError{Code: "23505"}))
+	assert.False(t, repository.IsSerializationFailure(errors.New("boom")))
+}
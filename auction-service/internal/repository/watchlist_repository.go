@@ -0,0 +1,16 @@
+package repository
+
+import "auction-service/internal/model"
+
+// WatchlistRepository stores which users watch which auctions.
+type WatchlistRepository interface {
+	// AddEntry stores entry unless the user already watches the auction,
+	// and returns the stored entry either way.
+	AddEntry(entry model.WatchlistEntry) (model.WatchlistEntry, error)
+	// RemoveEntry stops the user watching the auction. Removing an entry
+	// that does not exist is not an error.
+	RemoveEntry(userID, auctionID int) error
+	GetEntriesByUserID(userID int) ([]model.WatchlistEntry, error)
+	// GetWatcherIDs returns the users watching an auction, ordered by ID.
+	GetWatcherIDs(auctionID int) ([]int, error)
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"auction-service/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WatchlistRepositoryImpl handles database operations related to
+// watchlists.
+type WatchlistRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewWatchlistRepository creates a new instance of WatchlistRepository.
+func NewWatchlistRepository(db *gorm.DB) *WatchlistRepositoryImpl {
+	return &WatchlistRepositoryImpl{db}
+}
+
+// Ensure WatchlistRepositoryImpl implements WatchlistRepository
+var _ WatchlistRepository = (*WatchlistRepositoryImpl)(nil)
+
+// AddEntry inserts the entry, relying on the unique index to ignore it when
+// the user already watches the auction.
+func (wr *WatchlistRepositoryImpl) AddEntry(entry model.WatchlistEntry) (model.WatchlistEntry, error) {
+	entry.ID = 0
+	err := wr.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&entry).Error
+	if err != nil {
+		return entry, err
+	}
+	var stored model.WatchlistEntry
+	err = wr.db.Where("user_id = ? AND auction_id = ?", entry.UserID, entry.AuctionID).First(&stored).Error
+	return stored, err
+}
+
+// RemoveEntry deletes the entry of the user for the auction, if any.
+func (wr *WatchlistRepositoryImpl) RemoveEntry(userID, auctionID int) error {
+	return wr.db.Where("user_id = ? AND auction_id = ?", userID, auctionID).Delete(&model.WatchlistEntry{}).Error
+}
+
+// GetEntriesByUserID returns the watchlist of a user, oldest entry first.
+func (wr *WatchlistRepositoryImpl) GetEntriesByUserID(userID int) ([]model.WatchlistEntry, error) {
+	var entries []model.WatchlistEntry
+	err := wr.db.Where("user_id = ?", userID).Order("id").Find(&entries).Error
+	return entries, err
+}
+
+// GetWatcherIDs returns the IDs of the users watching an auction.
+func (wr *WatchlistRepositoryImpl) GetWatcherIDs(auctionID int) ([]int, error) {
+	var ids []int
+	err := wr.db.Model(&model.WatchlistEntry{}).Where("auction_id = ?", auctionID).Order("user_id").Pluck("user_id", &ids).Error
+	return ids, err
+}
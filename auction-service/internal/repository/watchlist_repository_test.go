@@ -0,0 +1,53 @@
+package repository_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchlistRepository(t *testing.T) {
+	implementations := map[string]func() repository.WatchlistRepository{
+		"memory": func() repository.WatchlistRepository { return repository.NewMemoryStore().Watchlist() },
+		"gorm": func() repository.WatchlistRepository {
+			return repository.NewWatchlistRepository(setupTestDB())
+		},
+	}
+
+	for name, newRepo := range implementations {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+
+			entry, err := repo.AddEntry(model.WatchlistEntry{UserID: 3, AuctionID: 1})
+			require.NoError(t, err)
+			assert.NotZero(t, entry.ID)
+			again, err := repo.AddEntry(model.WatchlistEntry{UserID: 3, AuctionID: 1})
+			require.NoError(t, err)
+			assert.Equal(t, entry.ID, again.ID)
+			_, err = repo.AddEntry(model.WatchlistEntry{UserID: 2, AuctionID: 1})
+			require.NoError(t, err)
+			other, err := repo.AddEntry(model.WatchlistEntry{UserID: 3, AuctionID: 2})
+			require.NoError(t, err)
+
+			watchers, err := repo.GetWatcherIDs(1)
+			require.NoError(t, err)
+			assert.Equal(t, []int{2, 3}, watchers)
+
+			entries, err := repo.GetEntriesByUserID(3)
+			require.NoError(t, err)
+			if assert.Len(t, entries, 2) {
+				assert.Equal(t, entry.ID, entries[0].ID)
+				assert.Equal(t, other.ID, entries[1].ID)
+			}
+
+			require.NoError(t, repo.RemoveEntry(3, 1))
+			require.NoError(t, repo.RemoveEntry(3, 1))
+			watchers, err = repo.GetWatcherIDs(1)
+			require.NoError(t, err)
+			assert.Equal(t, []int{2}, watchers)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"auction-service/internal/model"
+)
+
+// ErrSubscriptionNotFound is returned when a webhook subscription does not
+// exist.
+var ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+
+// WebhookRepository stores webhook subscriptions and their deliveries.
+type WebhookRepository interface {
+	CreateSubscription(subscription model.WebhookSubscription) (model.WebhookSubscription, error)
+	GetSubscriptionByID(id int) (model.WebhookSubscription, error)
+	// GetSubscriptions returns every subscription ordered by ID.
+	GetSubscriptions() ([]model.WebhookSubscription, error)
+	UpdateSubscription(subscription model.WebhookSubscription) error
+	// DeleteSubscription removes a subscription and its deliveries.
+	DeleteSubscription(id int) error
+
+	// AddDelivery stores delivery unless the event was already queued for
+	// the subscription, and reports whether it was stored.
+	AddDelivery(delivery model.WebhookDelivery) (bool, error)
+	// GetDueDeliveries returns up to limit pending deliveries whose next
+	// attempt is due at now, oldest first.
+	GetDueDeliveries(now time.Time, limit int) ([]model.WebhookDelivery, error)
+	// GetDeliveriesBySubscriptionID returns the latest limit deliveries of
+	// a subscription, newest first.
+	GetDeliveriesBySubscriptionID(subscriptionID, limit int) ([]model.WebhookDelivery, error)
+	UpdateDelivery(delivery model.WebhookDelivery) error
+}
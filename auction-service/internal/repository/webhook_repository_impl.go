@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"auction-service/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookRepositoryImpl handles database operations related to webhooks.
+type WebhookRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepository.
+func NewWebhookRepository(db *gorm.DB) *WebhookRepositoryImpl {
+	return &WebhookRepositoryImpl{db}
+}
+
+// Ensure WebhookRepositoryImpl implements WebhookRepository
+var _ WebhookRepository = (*WebhookRepositoryImpl)(nil)
+
+// CreateSubscription inserts a new subscription.
+func (wr *WebhookRepositoryImpl) CreateSubscription(subscription model.WebhookSubscription) (model.WebhookSubscription, error) {
+	subscription.ID = 0
+	err := wr.db.Create(&subscription).Error
+	return subscription, err
+}
+
+// GetSubscriptionByID returns a subscription by its ID.
+func (wr *WebhookRepositoryImpl) GetSubscriptionByID(id int) (model.WebhookSubscription, error) {
+	var subscription model.WebhookSubscription
+	err := wr.db.First(&subscription, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return subscription, ErrSubscriptionNotFound
+	}
+	return subscription, err
+}
+
+// GetSubscriptions returns every subscription ordered by ID.
+func (wr *WebhookRepositoryImpl) GetSubscriptions() ([]model.WebhookSubscription, error) {
+	var subscriptions []model.WebhookSubscription
+	err := wr.db.Order("id").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// UpdateSubscription saves every field of the subscription.
+func (wr *WebhookRepositoryImpl) UpdateSubscription(subscription model.WebhookSubscription) error {
+	result := wr.db.Model(&subscription).Select("*").Omit("ID", "CreatedAt").Updates(&subscription)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+// DeleteSubscription deletes a subscription and its deliveries.
+func (wr *WebhookRepositoryImpl) DeleteSubscription(id int) error {
+	return wr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subscription_id = ?", id).Delete(&model.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&model.WebhookSubscription{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrSubscriptionNotFound
+		}
+		return nil
+	})
+}
+
+// AddDelivery inserts the delivery, relying on the unique index to skip
+// events already queued for the subscription.
+func (wr *WebhookRepositoryImpl) AddDelivery(delivery model.WebhookDelivery) (bool, error) {
+	delivery.ID = 0
+	result := wr.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&delivery)
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetDueDeliveries returns the pending deliveries due at now.
+func (wr *WebhookRepositoryImpl) GetDueDeliveries(now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	err := wr.db.Where("status = ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?)", model.WebhookDeliveryPending, now).
+		Order("id").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+// GetDeliveriesBySubscriptionID returns the latest deliveries of a
+// subscription, newest first.
+func (wr *WebhookRepositoryImpl) GetDeliveriesBySubscriptionID(subscriptionID, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	err := wr.db.Where("subscription_id = ?", subscriptionID).Order("id DESC").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+// UpdateDelivery saves every field of the delivery.
+func (wr *WebhookRepositoryImpl) UpdateDelivery(delivery model.WebhookDelivery) error {
+	return wr.db.Model(&delivery).Select("*").Omit("ID", "CreatedAt").Updates(&delivery).Error
+}
@@ -0,0 +1,110 @@
+package repository_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSubscriptions(t *testing.T) {
+	implementations := map[string]func() repository.WebhookRepository{
+		"memory": func() repository.WebhookRepository { return repository.NewMemoryStore().Webhooks() },
+		"gorm":   func() repository.WebhookRepository { return repository.NewWebhookRepository(setupTestDB()) },
+	}
+
+	for name, newRepo := range implementations {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+			_, err := repo.GetSubscriptionByID(1)
+			assert.ErrorIs(t, err, repository.ErrSubscriptionNotFound)
+
+			created, err := repo.CreateSubscription(model.WebhookSubscription{
+				URL: "https://partner.example/hook", EventTypes: []string{model.EventBidPlaced}, Secret: "s3cret", Active: true,
+			})
+			require.NoError(t, err)
+			assert.NotZero(t, created.ID)
+
+			created.Active = false
+			created.ConsecutiveFailures = 3
+			require.NoError(t, repo.UpdateSubscription(created))
+			stored, err := repo.GetSubscriptionByID(created.ID)
+			require.NoError(t, err)
+			assert.False(t, stored.Active)
+			assert.Equal(t, 3, stored.ConsecutiveFailures)
+			assert.Equal(t, []string{model.EventBidPlaced}, stored.EventTypes)
+
+			subscriptions, err := repo.GetSubscriptions()
+			require.NoError(t, err)
+			assert.Len(t, subscriptions, 1)
+
+			added, err := repo.AddDelivery(model.WebhookDelivery{SubscriptionID: created.ID, EventID: "evt_1", EventType: model.EventBidPlaced, Payload: []byte(`{}`), Status: model.WebhookDeliveryPending})
+			require.NoError(t, err)
+			assert.True(t, added)
+
+			require.NoError(t, repo.DeleteSubscription(created.ID))
+			assert.ErrorIs(t, repo.DeleteSubscription(created.ID), repository.ErrSubscriptionNotFound)
+			deliveries, err := repo.GetDeliveriesBySubscriptionID(created.ID, 10)
+			require.NoError(t, err)
+			assert.Empty(t, deliveries)
+		})
+	}
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	implementations := map[string]func() repository.WebhookRepository{
+		"memory": func() repository.WebhookRepository { return repository.NewMemoryStore().Webhooks() },
+		"gorm":   func() repository.WebhookRepository { return repository.NewWebhookRepository(setupTestDB()) },
+	}
+
+	for name, newRepo := range implementations {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+			now := time.Now().UTC().Truncate(time.Second)
+			subscription, err := repo.CreateSubscription(model.WebhookSubscription{URL: "https://partner.example/hook", EventTypes: []string{model.EventBidPlaced}, Secret: "s3cret", Active: true})
+			require.NoError(t, err)
+
+			delivery := model.WebhookDelivery{SubscriptionID: subscription.ID, EventID: "evt_1", EventType: model.EventBidPlaced, Payload: []byte(`{"id":"evt_1"}`), Status: model.WebhookDeliveryPending}
+			added, err := repo.AddDelivery(delivery)
+			require.NoError(t, err)
+			assert.True(t, added)
+			added, err = repo.AddDelivery(delivery)
+			require.NoError(t, err)
+			assert.False(t, added, "an event is queued once per subscription")
+
+			later := now.Add(time.Minute)
+			_, err = repo.AddDelivery(model.WebhookDelivery{SubscriptionID: subscription.ID, EventID: "evt_2", EventType: model.EventBidPlaced, Payload: []byte(`{}`), Status: model.WebhookDeliveryPending, NextAttemptAt: &later})
+			require.NoError(t, err)
+
+			due, err := repo.GetDueDeliveries(now, 10)
+			require.NoError(t, err)
+			require.Len(t, due, 1)
+			assert.Equal(t, "evt_1", due[0].EventID)
+			assert.Equal(t, []byte(`{"id":"evt_1"}`), due[0].Payload)
+
+			due[0].Status = model.WebhookDeliverySucceeded
+			due[0].Attempts = 1
+			due[0].LastStatusCode = 200
+			require.NoError(t, repo.UpdateDelivery(due[0]))
+
+			due, err = repo.GetDueDeliveries(later, 10)
+			require.NoError(t, err)
+			require.Len(t, due, 1)
+			assert.Equal(t, "evt_2", due[0].EventID)
+
+			deliveries, err := repo.GetDeliveriesBySubscriptionID(subscription.ID, 10)
+			require.NoError(t, err)
+			require.Len(t, deliveries, 2)
+			assert.Equal(t, "evt_2", deliveries[0].EventID)
+			assert.Equal(t, model.WebhookDeliverySucceeded, deliveries[1].Status)
+			assert.Equal(t, 200, deliveries[1].LastStatusCode)
+
+			deliveries, err = repo.GetDeliveriesBySubscriptionID(subscription.ID, 1)
+			require.NoError(t, err)
+			assert.Len(t, deliveries, 1)
+		})
+	}
+}
@@ -0,0 +1,388 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"auction-service/internal/blob"
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+)
+
+var (
+	// ErrAttachmentNotFound is returned when the attachment does not exist.
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	// ErrFileTooLarge is returned when an upload is over the size limit.
+	ErrFileTooLarge = errors.New("file is too large")
+	// ErrUnsupportedFileType is returned when an upload is neither a photo
+	// nor a PDF document.
+	ErrUnsupportedFileType = errors.New("unsupported file type")
+)
+
+// UploadLimits bounds what sellers can attach to their auctions.
+type UploadLimits struct {
+	// MaxSize is the size limit of each file, in bytes.
+	MaxSize int64
+	// MaxAttachments is how many files an auction can have.
+	MaxAttachments int
+	// MaxPixels is the largest width times height of photos.
+	MaxPixels int
+	// ThumbnailSize is the longest side of thumbnails, in pixels.
+	ThumbnailSize int
+}
+
+// DefaultUploadLimits allows 12 files of up to 10 MiB per auction.
+var DefaultUploadLimits = UploadLimits{MaxSize: 10 << 20, MaxAttachments: 12, MaxPixels: 50_000_000, ThumbnailSize: 320}
+
+// DefaultMediaURL is where the service serves blobs itself.
+const DefaultMediaURL = "/media"
+
+// attachmentTypes maps the content types accepted for attachments, as
+// sniffed from their first bytes, to the extension of their blobs.
+var attachmentTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"application/pdf": ".pdf",
+}
+
+// AttachmentService stores the photos and documents sellers attach to
+// their auctions. Files go to a blob.Store; photos get a JPEG thumbnail.
+type AttachmentService interface {
+	// AddAttachment stores a file for the owner of an open auction. Its
+	// type is worked out from its content, not its name.
+	AddAttachment(userID, auctionID int, filename string, file io.Reader) (model.Attachment, error)
+	// GetAttachments returns the attachments of an auction in upload
+	// order.
+	GetAttachments(auctionID int) ([]model.Attachment, error)
+	// DeleteAttachment lets the owner of the auction remove an attachment
+	// and its blobs.
+	DeleteAttachment(userID, attachmentID int) error
+}
+
+type attachmentService struct {
+	txManager repository.TxManager
+	blobs     blob.Store
+	limits    UploadLimits
+	mediaURL  string
+}
+
+// Ensure attachmentService implements AttachmentService
+var _ AttachmentService = (*attachmentService)(nil)
+
+// NewAttachmentService creates an AttachmentService keeping files in blobs.
+func NewAttachmentService(txManager repository.TxManager, blobs blob.Store, opts ...Option) AttachmentService {
+	o := newOptions(opts)
+	return &attachmentService{txManager: txManager, blobs: blobs, limits: o.uploadLimits, mediaURL: o.mediaURL}
+}
+
+func (s *attachmentService) AddAttachment(userID, auctionID int, filename string, file io.Reader) (model.Attachment, error) {
+	if userID <= 0 {
+		return model.Attachment{}, fmt.Errorf("%w: user id is required", ErrInvalidInput)
+	}
+	// Check the auction before reading the file, so strangers cannot make
+	// the service process uploads.
+	if err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		return s.canAttach(uow, userID, auctionID)
+	}); err != nil {
+		return model.Attachment{}, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, s.limits.MaxSize+1))
+	if err != nil {
+		return model.Attachment{}, err
+	}
+	if int64(len(data)) > s.limits.MaxSize {
+		return model.Attachment{}, fmt.Errorf("%w: files can be at most %d bytes", ErrFileTooLarge, s.limits.MaxSize)
+	}
+	if len(data) == 0 {
+		return model.Attachment{}, fmt.Errorf("%w: the file is empty", ErrInvalidInput)
+	}
+	contentType, _, _ := strings.Cut(http.DetectContentType(data), ";")
+	extension, ok := attachmentTypes[contentType]
+	if !ok {
+		return model.Attachment{}, fmt.Errorf("%w: %s, attach JPEG, PNG or GIF photos or PDF documents", ErrUnsupportedFileType, contentType)
+	}
+
+	name, err := newBlobName()
+	if err != nil {
+		return model.Attachment{}, err
+	}
+	attachment := model.Attachment{
+		AuctionID:   auctionID,
+		Filename:    cleanFilename(filename),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Key:         fmt.Sprintf("auctions/%d/%s%s", auctionID, name, extension),
+	}
+	var thumb []byte
+	if strings.HasPrefix(contentType, "image/") {
+		img, err := decodeImage(data, s.limits.MaxPixels)
+		if err != nil {
+			return model.Attachment{}, err
+		}
+		attachment.Width, attachment.Height = img.Bounds().Dx(), img.Bounds().Dy()
+		if thumb, err = thumbnail(img, s.limits.ThumbnailSize); err != nil {
+			return model.Attachment{}, err
+		}
+		attachment.ThumbnailKey = fmt.Sprintf("auctions/%d/%s_thumb.jpg", auctionID, name)
+	}
+
+	ctx := context.Background()
+	if err := s.blobs.Put(ctx, attachment.Key, contentType, data); err != nil {
+		return model.Attachment{}, err
+	}
+	if thumb != nil {
+		if err := s.blobs.Put(ctx, attachment.ThumbnailKey, "image/jpeg", thumb); err != nil {
+			removeBlobs(s.blobs, []model.Attachment{attachment})
+			return model.Attachment{}, err
+		}
+	}
+
+	err = s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		if err := s.canAttach(uow, userID, auctionID); err != nil {
+			return err
+		}
+		var err error
+		attachment, err = uow.Attachments().CreateAttachment(attachment)
+		return err
+	})
+	if err != nil {
+		removeBlobs(s.blobs, []model.Attachment{attachment})
+		return model.Attachment{}, err
+	}
+	return withMediaURLs(attachment, s.mediaURL), nil
+}
+
+// canAttach checks that userID owns the open or scheduled auction and that it has room
+// for another attachment.
+func (s *attachmentService) canAttach(uow repository.UnitOfWork, userID, auctionID int) error {
+	auction, err := ownedAuction(uow, userID, auctionID)
+	if err != nil {
+		return err
+	}
+	if !auction.IsOpen() && !auction.IsScheduled() {
+		return ErrAuctionClosed
+	}
+	count, err := uow.Attachments().CountAttachments(auctionID)
+	if err != nil {
+		return err
+	}
+	if count >= s.limits.MaxAttachments {
+		return fmt.Errorf("%w: auctions can have at most %d attachments", ErrInvalidInput, s.limits.MaxAttachments)
+	}
+	return nil
+}
+
+func (s *attachmentService) GetAttachments(auctionID int) ([]model.Attachment, error) {
+	var attachments []model.Attachment
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		if _, err := uow.Auctions().GetAuctionByID(auctionID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+		var err error
+		attachments, err = uow.Attachments().GetAttachmentsByAuctionIDs([]int{auctionID})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range attachments {
+		attachments[i] = withMediaURLs(attachments[i], s.mediaURL)
+	}
+	if attachments == nil {
+		attachments = []model.Attachment{}
+	}
+	return attachments, nil
+}
+
+func (s *attachmentService) DeleteAttachment(userID, attachmentID int) error {
+	var attachment model.Attachment
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		var err error
+		attachment, err = uow.Attachments().GetAttachmentByID(attachmentID)
+		if errors.Is(err, repository.ErrAttachmentNotFound) {
+			return ErrAttachmentNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := ownedAuction(uow, userID, attachment.AuctionID); err != nil {
+			return err
+		}
+		return uow.Attachments().DeleteAttachment(attachmentID)
+	})
+	if err != nil {
+		return err
+	}
+	removeBlobs(s.blobs, []model.Attachment{attachment})
+	return nil
+}
+
+// removeBlobs deletes the files of attachments whose records are gone.
+// Failures are only logged: the records are what clients see, and a blob
+// left behind costs storage, not correctness.
+func removeBlobs(blobs blob.Store, attachments []model.Attachment) {
+	if blobs == nil {
+		return
+	}
+	for _, attachment := range attachments {
+		for _, key := range []string{attachment.Key, attachment.ThumbnailKey} {
+			if key == "" {
+				continue
+			}
+			if err := blobs.Delete(context.Background(), key); err != nil {
+				log.Printf("Error deleting blob %s: %v", key, err)
+			}
+		}
+	}
+}
+
+// copyAttachments gives auction to copies of the attachments of auction
+// from. The copies get blobs of their own, so deleting either auction leaves
+// the files of the other. Failures are only logged: the seller can upload a
+// missing file again.
+func copyAttachments(txManager repository.TxManager, blobs blob.Store, from, to int) {
+	if blobs == nil {
+		return
+	}
+	var attachments []model.Attachment
+	err := txManager.Transaction(func(uow repository.UnitOfWork) error {
+		var err error
+		attachments, err = uow.Attachments().GetAttachmentsByAuctionIDs([]int{from})
+		return err
+	})
+	if err != nil {
+		log.Printf("Error loading the attachments of auction %d: %v", from, err)
+		return
+	}
+	for _, attachment := range attachments {
+		if err := copyAttachment(txManager, blobs, attachment, to); err != nil {
+			log.Printf("Error copying attachment %d to auction %d: %v", attachment.ID, to, err)
+		}
+	}
+}
+
+// copyAttachment copies attachment and its blobs to auctionID.
+func copyAttachment(txManager repository.TxManager, blobs blob.Store, attachment model.Attachment, auctionID int) error {
+	name, err := newBlobName()
+	if err != nil {
+		return err
+	}
+	copied := model.Attachment{
+		AuctionID:   auctionID,
+		Filename:    attachment.Filename,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		Width:       attachment.Width,
+		Height:      attachment.Height,
+	}
+	copied.Key = fmt.Sprintf("auctions/%d/%s%s", auctionID, name, path.Ext(attachment.Key))
+	if err := copyBlob(blobs, attachment.Key, copied.Key, attachment.ContentType); err != nil {
+		return err
+	}
+	if attachment.ThumbnailKey != "" {
+		copied.ThumbnailKey = fmt.Sprintf("auctions/%d/%s_thumb.jpg", auctionID, name)
+		if err := copyBlob(blobs, attachment.ThumbnailKey, copied.ThumbnailKey, "image/jpeg"); err != nil {
+			removeBlobs(blobs, []model.Attachment{{Key: copied.Key}})
+			return err
+		}
+	}
+
+	err = txManager.Transaction(func(uow repository.UnitOfWork) error {
+		_, err := uow.Attachments().CreateAttachment(copied)
+		return err
+	})
+	if err != nil {
+		removeBlobs(blobs, []model.Attachment{copied})
+	}
+	return err
+}
+
+// copyBlob stores a copy of the blob under from as to.
+func copyBlob(blobs blob.Store, from, to, contentType string) error {
+	ctx := context.Background()
+	r, err := blobs.Get(ctx, from)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return blobs.Put(ctx, to, contentType, data)
+}
+
+// withMediaURLs fills in the download URLs of attachment.
+func withMediaURLs(attachment model.Attachment, mediaURL string) model.Attachment {
+	base := strings.TrimSuffix(mediaURL, "/")
+	attachment.URL = base + "/" + attachment.Key
+	if attachment.ThumbnailKey != "" {
+		attachment.ThumbnailURL = base + "/" + attachment.ThumbnailKey
+	}
+	return attachment
+}
+
+// withAttachments loads the attachments of auctions.
+func withAttachments(uow repository.UnitOfWork, auctions []model.Auction, mediaURL string) error {
+	ids := make([]int, len(auctions))
+	for i, auction := range auctions {
+		ids[i] = auction.ID
+	}
+	attachments, err := uow.Attachments().GetAttachmentsByAuctionIDs(ids)
+	if err != nil {
+		return err
+	}
+	byAuction := map[int][]model.Attachment{}
+	for _, attachment := range attachments {
+		byAuction[attachment.AuctionID] = append(byAuction[attachment.AuctionID], withMediaURLs(attachment, mediaURL))
+	}
+	for i := range auctions {
+		auctions[i].Attachments = byAuction[auctions[i].ID]
+	}
+	return nil
+}
+
+// cleanFilename keeps the base name of an uploaded file, without control
+// characters and at most 255 bytes long.
+func cleanFilename(filename string) string {
+	filename = path.Base(filepath.ToSlash(strings.TrimSpace(filename)))
+	filename = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, filename)
+	if filename == "." || filename == "/" {
+		return ""
+	}
+	for len(filename) > 255 {
+		_, size := utf8.DecodeLastRuneInString(filename)
+		filename = filename[:len(filename)-size]
+	}
+	return filename
+}
+
+// newBlobName returns a random name, so blob keys cannot be guessed and
+// never collide.
+func newBlobName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,210 @@
+package service_test
+
+import (
+	"auction-service/internal/blob"
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"auction-service/internal/service"
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestAttachments returns an auction service and an attachment service
+// sharing a memory store and a blob store in a temporary directory.
+func newTestAttachments(t *testing.T, opts ...service.Option) (service.AuctionService, service.AttachmentService, *repository.MemoryStore, blob.Store) {
+	t.Helper()
+	blobs, err := blob.NewFileSystem(t.TempDir())
+	require.NoError(t, err)
+	store := repository.NewMemoryStore()
+	opts = append([]service.Option{service.WithBlobStore(blobs)}, opts...)
+	return service.NewAuctionService(store.Auctions(), store, opts...), service.NewAttachmentService(store, blobs, opts...), store, blobs
+}
+
+// pngImage encodes a width by height PNG.
+func pngImage(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// blobExists reports whether key is in blobs.
+func blobExists(t *testing.T, blobs blob.Store, key string) bool {
+	t.Helper()
+	reader, err := blobs.Get(context.Background(), key)
+	if err != nil {
+		require.ErrorIs(t, err, blob.ErrNotFound)
+		return false
+	}
+	reader.Close()
+	return true
+}
+
+func TestAddImageAttachment(t *testing.T) {
+	auctionService, attachmentService, store, blobs := newTestAttachments(t)
+	auction := seedAuction(t, store, model.Auction{Item: "Lamp", UserID: 1, Status: model.AuctionStatusOpen})
+
+	attachment, err := attachmentService.AddAttachment(1, auction.ID, "../photos/lamp.png", bytes.NewReader(pngImage(t, 800, 400)))
+	require.NoError(t, err)
+	assert.Equal(t, "lamp.png", attachment.Filename)
+	assert.Equal(t, "image/png", attachment.ContentType)
+	assert.Equal(t, 800, attachment.Width)
+	assert.Equal(t, 400, attachment.Height)
+	assert.True(t, strings.HasPrefix(attachment.URL, "/media/auctions/"), attachment.URL)
+	assert.True(t, strings.HasSuffix(attachment.URL, ".png"), attachment.URL)
+	assert.True(t, strings.HasSuffix(attachment.ThumbnailURL, "_thumb.jpg"), attachment.ThumbnailURL)
+
+	reader, err := blobs.Get(context.Background(), attachment.ThumbnailKey)
+	require.NoError(t, err)
+	defer reader.Close()
+	thumb, err := jpeg.Decode(reader)
+	require.NoError(t, err)
+	assert.Equal(t, 320, thumb.Bounds().Dx())
+	assert.Equal(t, 160, thumb.Bounds().Dy())
+
+	found, err := auctionService.GetAuctionByID(auction.ID)
+	require.NoError(t, err)
+	if assert.Len(t, found.Attachments, 1) {
+		assert.Equal(t, attachment.URL, found.Attachments[0].URL)
+	}
+}
+
+func TestAddDocumentAttachment(t *testing.T) {
+	_, attachmentService, store, blobs := newTestAttachments(t, service.WithMediaURL("https://cdn.example.com/"))
+	auction := seedAuction(t, store, model.Auction{Item: "Lamp", UserID: 1, Status: model.AuctionStatusOpen})
+
+	pdf := "%PDF-1.4\n% manual\n"
+	attachment, err := attachmentService.AddAttachment(1, auction.ID, "manual.pdf", strings.NewReader(pdf))
+	require.NoError(t, err)
+	assert.Equal(t, "application/pdf", attachment.ContentType)
+	assert.Equal(t, int64(len(pdf)), attachment.Size)
+	assert.Zero(t, attachment.Width)
+	assert.Empty(t, attachment.ThumbnailURL)
+	assert.Equal(t, "https://cdn.example.com/"+attachment.Key, attachment.URL)
+
+	reader, err := blobs.Get(context.Background(), attachment.Key)
+	require.NoError(t, err)
+	defer reader.Close()
+	stored, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, pdf, string(stored))
+}
+
+func TestAddAttachmentRejected(t *testing.T) {
+	limits := service.DefaultUploadLimits
+	limits.MaxSize = 1000
+	limits.MaxAttachments = 1
+	_, attachmentService, store, _ := newTestAttachments(t, service.WithUploadLimits(limits))
+	auction := seedAuction(t, store, model.Auction{Item: "Lamp", UserID: 1, Status: model.AuctionStatusOpen})
+	closed := seedAuction(t, store, model.Auction{Item: "Chair", UserID: 1, Status: model.AuctionStatusClosed})
+
+	// The content decides the type, whatever the file is called.
+	_, err := attachmentService.AddAttachment(1, auction.ID, "photo.jpg", strings.NewReader("<html><script>alert(1)</script></html>"))
+	assert.ErrorIs(t, err, service.ErrUnsupportedFileType)
+	_, err = attachmentService.AddAttachment(1, auction.ID, "big.pdf", strings.NewReader("%PDF-"+strings.Repeat("x", 1000)))
+	assert.ErrorIs(t, err, service.ErrFileTooLarge)
+	_, err = attachmentService.AddAttachment(1, auction.ID, "broken.png", bytes.NewReader(pngImage(t, 4, 4)[:40]))
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+	_, err = attachmentService.AddAttachment(2, auction.ID, "manual.pdf", strings.NewReader("%PDF-1.4"))
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	_, err = attachmentService.AddAttachment(1, closed.ID, "manual.pdf", strings.NewReader("%PDF-1.4"))
+	assert.ErrorIs(t, err, service.ErrAuctionClosed)
+	_, err = attachmentService.AddAttachment(1, 999, "manual.pdf", strings.NewReader("%PDF-1.4"))
+	assert.ErrorIs(t, err, service.ErrNotFound)
+
+	_, err = attachmentService.AddAttachment(1, auction.ID, "manual.pdf", strings.NewReader("%PDF-1.4"))
+	require.NoError(t, err)
+	_, err = attachmentService.AddAttachment(1, auction.ID, "manual.pdf", strings.NewReader("%PDF-1.4"))
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+}
+
+func TestDeleteAttachment(t *testing.T) {
+	_, attachmentService, store, blobs := newTestAttachments(t)
+	auction := seedAuction(t, store, model.Auction{Item: "Lamp", UserID: 1, Status: model.AuctionStatusOpen})
+	attachment, err := attachmentService.AddAttachment(1, auction.ID, "lamp.png", bytes.NewReader(pngImage(t, 10, 10)))
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, attachmentService.DeleteAttachment(2, attachment.ID), service.ErrForbidden)
+	require.NoError(t, attachmentService.DeleteAttachment(1, attachment.ID))
+	assert.False(t, blobExists(t, blobs, attachment.Key))
+	assert.False(t, blobExists(t, blobs, attachment.ThumbnailKey))
+	assert.ErrorIs(t, attachmentService.DeleteAttachment(1, attachment.ID), service.ErrAttachmentNotFound)
+
+	attachments, err := attachmentService.GetAttachments(auction.ID)
+	require.NoError(t, err)
+	assert.Empty(t, attachments)
+}
+
+func TestDeleteAuctionRemovesAttachments(t *testing.T) {
+	auctionService, attachmentService, store, blobs := newTestAttachments(t)
+	auction := seedAuction(t, store, model.Auction{Item: "Lamp", UserID: 1, Status: model.AuctionStatusOpen})
+	other := seedAuction(t, store, model.Auction{Item: "Chair", UserID: 1, Status: model.AuctionStatusOpen})
+	photo, err := attachmentService.AddAttachment(1, auction.ID, "lamp.png", bytes.NewReader(pngImage(t, 10, 10)))
+	require.NoError(t, err)
+	manual, err := attachmentService.AddAttachment(1, auction.ID, "manual.pdf", strings.NewReader("%PDF-1.4"))
+	require.NoError(t, err)
+	kept, err := attachmentService.AddAttachment(1, other.ID, "chair.png", bytes.NewReader(pngImage(t, 10, 10)))
+	require.NoError(t, err)
+
+	require.NoError(t, auctionService.DeleteAuction(1, auction.ID))
+
+	for _, key := range []string{photo.Key, photo.ThumbnailKey, manual.Key} {
+		assert.False(t, blobExists(t, blobs, key), key)
+	}
+	assert.True(t, blobExists(t, blobs, kept.Key))
+	_, err = attachmentService.GetAttachments(auction.ID)
+	assert.ErrorIs(t, err, service.ErrNotFound)
+	attachments, err := attachmentService.GetAttachments(other.ID)
+	require.NoError(t, err)
+	assert.Len(t, attachments, 1)
+}
+
+func TestRelistCopiesAttachments(t *testing.T) {
+	auctionService, attachmentService, store, blobs := newTestAttachments(t)
+	auction := seedAuction(t, store, model.Auction{Item: "Lamp", UserID: 1, Status: model.AuctionStatusOpen})
+	photo, err := attachmentService.AddAttachment(1, auction.ID, "lamp.png", bytes.NewReader(pngImage(t, 10, 10)))
+	require.NoError(t, err)
+	manual, err := attachmentService.AddAttachment(1, auction.ID, "manual.pdf", strings.NewReader("%PDF-1.4"))
+	require.NoError(t, err)
+	_, err = auctionService.CloseAuction(1, auction.ID)
+	require.NoError(t, err)
+
+	startsAt := time.Now().Add(time.Hour)
+	relist, err := auctionService.RelistAuction(1, auction.ID, &startsAt)
+
+	require.NoError(t, err)
+	if assert.Len(t, relist.Attachments, 2) {
+		assert.Equal(t, "lamp.png", relist.Attachments[0].Filename)
+		assert.NotEqual(t, photo.URL, relist.Attachments[0].URL)
+		assert.NotEmpty(t, relist.Attachments[0].ThumbnailURL)
+		assert.Equal(t, "manual.pdf", relist.Attachments[1].Filename)
+	}
+
+	// The copies have files of their own.
+	require.NoError(t, auctionService.DeleteAuction(1, auction.ID))
+	assert.False(t, blobExists(t, blobs, photo.Key))
+	assert.False(t, blobExists(t, blobs, manual.Key))
+	copies, err := attachmentService.GetAttachments(relist.ID)
+	require.NoError(t, err)
+	for _, attachment := range copies {
+		assert.True(t, blobExists(t, blobs, attachment.Key), attachment.Key)
+	}
+	assert.True(t, blobExists(t, blobs, copies[0].ThumbnailKey))
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+)
+
+// DefaultEndingSoonWindow is how long before the end of an auction its
+// watchers are told it is ending.
+const DefaultEndingSoonWindow = time.Hour
+
+// AuctionCloser opens scheduled auctions at their start time and closes the
+// auctions whose end time has passed, selling them to the leading bidder when
+// the reserve is met. Each auction is closed in its own transaction after
+// locking it and checking the end time again, so a bid that extended the
+// auction in the meantime wins.
+type AuctionCloser struct {
+	auctions  repository.AuctionRepository
+	txManager repository.TxManager
+	interval  time.Duration
+	now       func() time.Time
+	// paymentPeriod is how long winners get to pay.
+	paymentPeriod time.Duration
+	// endingSoon is how long before the end watchers are notified.
+	endingSoon time.Duration
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewAuctionCloser creates a closer that looks for ended auctions every
+// interval.
+func NewAuctionCloser(auctions repository.AuctionRepository, txManager repository.TxManager, interval time.Duration, opts ...Option) *AuctionCloser {
+	o := newOptions(opts)
+	return &AuctionCloser{
+		auctions:      auctions,
+		txManager:     o.transactions(txManager),
+		interval:      interval,
+		now:           o.now,
+		paymentPeriod: o.paymentPeriod,
+		endingSoon:    o.endingSoon,
+		stop:          make(chan struct{}),
+	}
+}
+
+// Start opens scheduled auctions, closes ended ones and notifies the watchers
+// of those about to end, in the background until Stop is called.
+func (c *AuctionCloser) Start() {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.stop:
+				return
+			case <-ticker.C:
+				if _, err := c.OpenScheduled(); err != nil {
+					log.Printf("Error opening scheduled auctions: %v", err)
+				}
+				if _, err := c.CloseEnded(); err != nil {
+					log.Printf("Error closing ended auctions: %v", err)
+				}
+				if _, err := c.NotifyEndingSoon(); err != nil {
+					log.Printf("Error notifying watchers of ending auctions: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop ends the polling started by Start and waits for it to finish.
+func (c *AuctionCloser) Stop() {
+	close(c.stop)
+	c.wg.Wait()
+}
+
+// OpenScheduled opens every scheduled auction whose start time has come and
+// returns how many it opened.
+func (c *AuctionCloser) OpenScheduled() (int, error) {
+	now := c.now()
+	starting, err := c.auctions.GetStartingAuctions(now)
+	if err != nil {
+		return 0, err
+	}
+
+	opened := 0
+	for _, candidate := range starting {
+		var done bool
+		err := c.txManager.Transaction(func(uow repository.UnitOfWork) error {
+			auction, err := lockAuction(uow, candidate.ID)
+			if errors.Is(err, ErrNotFound) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			done = auction.IsScheduled() && !auction.OpensAt().After(now)
+			if !done {
+				return nil
+			}
+
+			auction.Status = model.AuctionStatusOpen
+			if err := uow.Auctions().UpdateAuction(auction); err != nil {
+				return err
+			}
+			return enqueue(uow, model.Event{Type: model.EventAuctionStarted, AuctionID: auction.ID, UserID: auction.UserID})
+		})
+		if err != nil {
+			return opened, err
+		}
+		if done {
+			opened++
+		}
+	}
+	return opened, nil
+}
+
+// CloseEnded closes every auction that has ended and returns how many it
+// closed.
+func (c *AuctionCloser) CloseEnded() (int, error) {
+	now := c.now()
+	ended, err := c.auctions.GetEndedAuctions(now)
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	for _, candidate := range ended {
+		var done bool
+		err := c.txManager.Transaction(func(uow repository.UnitOfWork) error {
+			auction, err := lockAuction(uow, candidate.ID)
+			if errors.Is(err, ErrNotFound) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			// A bid may have closed or extended the auction since it was listed.
+			done = auction.IsOpen() && auction.HasEnded(now)
+			if !done {
+				return nil
+			}
+
+			_, err = closeAuction(uow, auction, 0, now.Add(c.paymentPeriod))
+			return err
+		})
+		if err != nil {
+			return closed, err
+		}
+		if done {
+			closed++
+		}
+	}
+	return closed, nil
+}
+
+// NotifyEndingSoon asks the notifier to tell the watchers of every auction
+// ending within the ending-soon window, and returns how many auctions it
+// announced. Each auction is announced once, in its own transaction.
+func (c *AuctionCloser) NotifyEndingSoon() (int, error) {
+	now := c.now()
+	ending, err := c.auctions.GetEndingAuctions(now, now.Add(c.endingSoon))
+	if err != nil {
+		return 0, err
+	}
+
+	notified := 0
+	for _, candidate := range ending {
+		var done bool
+		err := c.txManager.Transaction(func(uow repository.UnitOfWork) error {
+			auction, err := lockAuction(uow, candidate.ID)
+			if errors.Is(err, ErrNotFound) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			done = auction.IsOpen() && !auction.EndingSoonNotified && !auction.HasEnded(now)
+			if !done {
+				return nil
+			}
+
+			auction.EndingSoonNotified = true
+			if err := uow.Auctions().UpdateAuction(auction); err != nil {
+				return err
+			}
+			watchers, err := uow.Watchlist().GetWatcherIDs(auction.ID)
+			if err != nil {
+				return err
+			}
+			for _, userID := range watchers {
+				event := model.Event{Type: model.EventNotifyEndingSoon, AuctionID: auction.ID, UserID: userID, Amount: &auction.CurrentPrice, EndsAt: auction.EndsAt}
+				if err := enqueue(uow, event); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return notified, err
+		}
+		if done {
+			notified++
+		}
+	}
+	return notified, nil
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"auction-service/internal/model"
+)
+
+// AuctionFormat holds the rules that differ between auction formats. Bid
+// placement and closing consult the format of the auction; everything else
+// (ownership, locking, events) is shared.
+type AuctionFormat interface {
+	// Rules describes which features the format supports.
+	Rules() FormatRules
+	// Validate checks the format settings of a new auction.
+	Validate(auction model.Auction) error
+	// Price returns the price shown for the auction at now.
+	Price(auction model.Auction, now time.Time) model.Money
+	// MinimumBid returns the lowest bid AcceptBid takes at now.
+	MinimumBid(auction model.Auction, now time.Time) model.Money
+	// AcceptBid checks bid against the auction and the bids placed before it
+	// and returns the bid to record.
+	AcceptBid(auction model.Auction, bids []model.Bid, bid model.Bid, now time.Time) (model.Bid, error)
+	// Settle picks the winning bid and the price paid once the auction closes.
+	// sold is false when there is no winner, including when the reserve is
+	// not met.
+	Settle(auction model.Auction, bids []model.Bid) (winner model.Bid, price model.Money, sold bool)
+}
+
+// FormatRules are the features that depend on the auction format.
+type FormatRules struct {
+	// Ascending formats raise the visible price with every bid and support
+	// proxy bids, buy now, reserve prices and soft close.
+	Ascending bool
+	// SealedBids stay hidden until the auction closes and do not move the
+	// price.
+	SealedBids bool
+	// ClosesOnBid formats are won by the first accepted bid.
+	ClosesOnBid bool
+}
+
+// FormatOf returns the format of auction. Auctions without a format are
+// English auctions. Bids are raised by the increments of the auction, or of
+// its category, or by DefaultIncrements when neither has any.
+func FormatOf(auction model.Auction) AuctionFormat {
+	increments := auction.BidIncrements
+	if len(increments) == 0 {
+		increments = auction.CategoryIncrements
+	}
+	if len(increments) == 0 {
+		increments = DefaultIncrements
+	}
+	format, err := formatWith(auction.Format, increments)
+	if err != nil {
+		return EnglishFormat{Increments: increments}
+	}
+	return format
+}
+
+func formatByName(name string) (AuctionFormat, error) {
+	return formatWith(name, DefaultIncrements)
+}
+
+func formatWith(name string, increments model.IncrementTable) (AuctionFormat, error) {
+	switch name {
+	case "", model.AuctionFormatEnglish:
+		return EnglishFormat{Increments: increments}, nil
+	case model.AuctionFormatDutch:
+		return DutchFormat{}, nil
+	case model.AuctionFormatSealedFirstPrice:
+		return SealedBidFormat{Increments: increments}, nil
+	case model.AuctionFormatVickrey:
+		return SealedBidFormat{SecondPrice: true, Increments: increments}, nil
+	}
+	return nil, fmt.Errorf("%w: unknown auction format %q", ErrInvalidInput, name)
+}
+
+// EnglishFormat is the ascending auction: every bid must raise the current
+// price by at least the increment and the last bid wins when the auction
+// closes.
+type EnglishFormat struct {
+	Increments model.IncrementTable
+}
+
+func (EnglishFormat) Rules() FormatRules { return FormatRules{Ascending: true} }
+
+func (EnglishFormat) Validate(auction model.Auction) error { return nil }
+
+func (EnglishFormat) Price(auction model.Auction, now time.Time) model.Money {
+	return auction.CurrentPrice
+}
+
+func (f EnglishFormat) MinimumBid(auction model.Auction, now time.Time) model.Money {
+	return auction.CurrentPrice.Add(f.Increments.Increment(auction.CurrentPrice))
+}
+
+func (f EnglishFormat) AcceptBid(auction model.Auction, bids []model.Bid, bid model.Bid, now time.Time) (model.Bid, error) {
+	minimum := f.MinimumBid(auction, now)
+	if low, err := below(bid.Amount, minimum); err != nil {
+		return bid, err
+	} else if low {
+		return bid, fmt.Errorf("%w: bid must be at least %s", ErrInvalidInput, minimum)
+	}
+	return bid, nil
+}
+
+func (EnglishFormat) Settle(auction model.Auction, bids []model.Bid) (model.Bid, model.Money, bool) {
+	if len(bids) == 0 || !auction.ReserveMet() {
+		return model.Bid{}, model.Money{}, false
+	}
+	return bids[len(bids)-1], auction.CurrentPrice, true
+}
+
+// DutchFormat is the descending auction: the price drops on a schedule and
+// the first bidder to accept the current price wins.
+type DutchFormat struct{}
+
+func (DutchFormat) Rules() FormatRules { return FormatRules{ClosesOnBid: true} }
+
+func (DutchFormat) Validate(auction model.Auction) error {
+	if err := rejectAscendingOptions(auction); err != nil {
+		return err
+	}
+	if auction.ReservePrice.IsPositive() {
+		return fmt.Errorf("%w: dutch auctions use a floor price instead of a reserve", ErrInvalidInput)
+	}
+	if !auction.DutchDecrement.IsPositive() || auction.DutchIntervalMinutes <= 0 {
+		return fmt.Errorf("%w: dutch auctions need a price decrement and an interval", ErrInvalidInput)
+	}
+	if auction.DutchFloorPrice.IsNegative() || !auction.DutchFloorPrice.Less(auction.StartPrice) {
+		return fmt.Errorf("%w: dutch floor price must be between 0 and the starting price", ErrInvalidInput)
+	}
+	return nil
+}
+
+// Price is the starting price minus one decrement for every interval since
+// the auction opened, never below the floor price.
+func (DutchFormat) Price(auction model.Auction, now time.Time) model.Money {
+	if auction.DutchIntervalMinutes <= 0 {
+		return auction.CurrentPrice
+	}
+	interval := time.Duration(auction.DutchIntervalMinutes) * time.Minute
+	steps := int64(now.Sub(auction.OpensAt()) / interval)
+	if steps < 0 {
+		steps = 0
+	}
+	return model.MaxMoney(auction.DutchFloorPrice, auction.StartPrice.Sub(auction.DutchDecrement.Mul(steps)))
+}
+
+func (f DutchFormat) MinimumBid(auction model.Auction, now time.Time) model.Money {
+	return f.Price(auction, now)
+}
+
+func (f DutchFormat) AcceptBid(auction model.Auction, bids []model.Bid, bid model.Bid, now time.Time) (model.Bid, error) {
+	price := f.Price(auction, now)
+	if low, err := below(bid.Amount, price); err != nil {
+		return bid, err
+	} else if low {
+		return bid, fmt.Errorf("%w: bid must be at least the current price of %s", ErrInvalidInput, price)
+	}
+	bid.Amount = price
+	return bid, nil
+}
+
+func (DutchFormat) Settle(auction model.Auction, bids []model.Bid) (model.Bid, model.Money, bool) {
+	if len(bids) == 0 {
+		return model.Bid{}, model.Money{}, false
+	}
+	return bids[0], bids[0].Amount, true
+}
+
+// SealedBidFormat takes one hidden bid per bidder. The highest bid wins, the
+// earliest on ties, and pays its own amount or, with SecondPrice (a Vickrey
+// auction), the second highest bid. Bids must beat the starting price by at
+// least its increment.
+type SealedBidFormat struct {
+	SecondPrice bool
+	Increments  model.IncrementTable
+}
+
+func (SealedBidFormat) Rules() FormatRules { return FormatRules{SealedBids: true} }
+
+func (SealedBidFormat) Validate(auction model.Auction) error { return rejectAscendingOptions(auction) }
+
+func (SealedBidFormat) Price(auction model.Auction, now time.Time) model.Money {
+	return auction.CurrentPrice
+}
+
+func (f SealedBidFormat) MinimumBid(auction model.Auction, now time.Time) model.Money {
+	return auction.StartPrice.Add(f.Increments.Increment(auction.StartPrice))
+}
+
+func (f SealedBidFormat) AcceptBid(auction model.Auction, bids []model.Bid, bid model.Bid, now time.Time) (model.Bid, error) {
+	minimum := f.MinimumBid(auction, now)
+	if low, err := below(bid.Amount, minimum); err != nil {
+		return bid, err
+	} else if low {
+		return bid, fmt.Errorf("%w: bid must be at least %s", ErrInvalidInput, minimum)
+	}
+	for _, placed := range bids {
+		if placed.UserID == bid.UserID {
+			return bid, fmt.Errorf("%w: only one sealed bid per bidder", ErrInvalidInput)
+		}
+	}
+	return bid, nil
+}
+
+func (f SealedBidFormat) Settle(auction model.Auction, bids []model.Bid) (model.Bid, model.Money, bool) {
+	if len(bids) == 0 {
+		return model.Bid{}, model.Money{}, false
+	}
+
+	winner := 0
+	for i, bid := range bids {
+		if bids[winner].Amount.Less(bid.Amount) {
+			winner = i
+		}
+	}
+	if bids[winner].Amount.Less(auction.ReservePrice) {
+		return model.Bid{}, model.Money{}, false
+	}
+	if !f.SecondPrice {
+		return bids[winner], bids[winner].Amount, true
+	}
+
+	price := auction.StartPrice
+	for i, bid := range bids {
+		if i != winner && price.Less(bid.Amount) {
+			price = bid.Amount
+		}
+	}
+	return bids[winner], model.MaxMoney(price, auction.ReservePrice), true
+}
+
+// rejectAscendingOptions fails for settings that only make sense while the
+// price rises bid by bid.
+func rejectAscendingOptions(auction model.Auction) error {
+	if auction.BuyNowPrice.IsPositive() {
+		return fmt.Errorf("%w: %s auctions do not support buy now", ErrInvalidInput, auction.Format)
+	}
+	if auction.SoftCloseWindowMinutes > 0 {
+		return fmt.Errorf("%w: %s auctions do not support soft close", ErrInvalidInput, auction.Format)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package service_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatAcceptBid(t *testing.T) {
+	dutch := model.Auction{Format: model.AuctionFormatDutch, StartPrice: usd(100), CurrentPrice: usd(100), DutchDecrement: usd(10), DutchIntervalMinutes: 5, DutchFloorPrice: usd(40), CreatedAt: start}
+	sealed := model.Auction{Format: model.AuctionFormatSealedFirstPrice, StartPrice: usd(10), CurrentPrice: usd(10)}
+
+	tests := []struct {
+		name       string
+		auction    model.Auction
+		bids       []model.Bid
+		bid        model.Bid
+		at         time.Duration
+		wantAmount float64
+		wantErr    error
+	}{
+		{name: "english above price", auction: model.Auction{CurrentPrice: usd(10)}, bid: model.Bid{UserID: 2, Amount: usd(11)}, wantAmount: 11},
+		{name: "english at price", auction: model.Auction{CurrentPrice: usd(10)}, bid: model.Bid{UserID: 2, Amount: usd(10)}, wantErr: service.ErrInvalidInput},
+		{name: "english in another currency", auction: model.Auction{CurrentPrice: usd(10)}, bid: model.Bid{UserID: 2, Amount: model.NewMoney(5000, "JPY")}, wantErr: service.ErrInvalidInput},
+		{name: "dutch at start", auction: dutch, bid: model.Bid{UserID: 2, Amount: usd(100)}, wantAmount: 100},
+		{name: "dutch pays the current price", auction: dutch, bid: model.Bid{UserID: 2, Amount: usd(100)}, at: 12 * time.Minute, wantAmount: 80},
+		{name: "dutch below price", auction: dutch, bid: model.Bid{UserID: 2, Amount: usd(75)}, at: 12 * time.Minute, wantErr: service.ErrInvalidInput},
+		{name: "dutch in another currency", auction: dutch, bid: model.Bid{UserID: 2, Amount: model.NewMoney(10000, "EUR")}, wantErr: service.ErrInvalidInput},
+		{name: "dutch at floor", auction: dutch, bid: model.Bid{UserID: 2, Amount: usd(40)}, at: 10 * time.Hour, wantAmount: 40},
+		{name: "sealed above start", auction: sealed, bid: model.Bid{UserID: 2, Amount: usd(50)}, wantAmount: 50},
+		{name: "sealed below start", auction: sealed, bid: model.Bid{UserID: 2, Amount: usd(5)}, wantErr: service.ErrInvalidInput},
+		{name: "sealed in another currency", auction: sealed, bid: model.Bid{UserID: 2, Amount: model.NewMoney(5000, "EUR")}, wantErr: service.ErrInvalidInput},
+		{name: "sealed second bid", auction: sealed, bids: []model.Bid{{UserID: 2, Amount: usd(50)}}, bid: model.Bid{UserID: 2, Amount: usd(60)}, wantErr: service.ErrInvalidInput},
+		{name: "sealed below other bids", auction: sealed, bids: []model.Bid{{UserID: 3, Amount: usd(50)}}, bid: model.Bid{UserID: 2, Amount: usd(20)}, wantAmount: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bid, err := service.FormatOf(tt.auction).AcceptBid(tt.auction, tt.bids, tt.bid, start.Add(tt.at))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, usd(tt.wantAmount), bid.Amount)
+		})
+	}
+}
+
+func TestFormatSettle(t *testing.T) {
+	bids := []model.Bid{{UserID: 2, Amount: usd(30)}, {UserID: 3, Amount: usd(50)}, {UserID: 4, Amount: usd(50)}, {UserID: 5, Amount: usd(20)}}
+
+	tests := []struct {
+		name       string
+		auction    model.Auction
+		bids       []model.Bid
+		wantWinner int
+		wantPrice  float64
+		wantSold   bool
+	}{
+		{name: "english no bids", auction: model.Auction{CurrentPrice: usd(10)}},
+		{name: "english last bid", auction: model.Auction{CurrentPrice: usd(50)}, bids: bids[:2], wantWinner: 3, wantPrice: 50, wantSold: true},
+		{name: "english reserve not met", auction: model.Auction{CurrentPrice: usd(50), ReservePrice: usd(60)}, bids: bids[:2]},
+		{name: "dutch first bid", auction: model.Auction{Format: model.AuctionFormatDutch}, bids: bids[:1], wantWinner: 2, wantPrice: 30, wantSold: true},
+		{name: "dutch no bids", auction: model.Auction{Format: model.AuctionFormatDutch}},
+		{name: "first price", auction: model.Auction{Format: model.AuctionFormatSealedFirstPrice, StartPrice: usd(10)}, bids: bids, wantWinner: 3, wantPrice: 50, wantSold: true},
+		{name: "first price reserve not met", auction: model.Auction{Format: model.AuctionFormatSealedFirstPrice, StartPrice: usd(10), ReservePrice: usd(60)}, bids: bids},
+		{name: "vickrey pays the second bid", auction: model.Auction{Format: model.AuctionFormatVickrey, StartPrice: usd(10)}, bids: []model.Bid{{UserID: 2, Amount: usd(30)}, {UserID: 3, Amount: usd(50)}}, wantWinner: 3, wantPrice: 30, wantSold: true},
+		{name: "vickrey tie pays the tied amount", auction: model.Auction{Format: model.AuctionFormatVickrey, StartPrice: usd(10)}, bids: bids, wantWinner: 3, wantPrice: 50, wantSold: true},
+		{name: "vickrey single bid pays the start price", auction: model.Auction{Format: model.AuctionFormatVickrey, StartPrice: usd(10)}, bids: bids[:1], wantWinner: 2, wantPrice: 10, wantSold: true},
+		{name: "vickrey pays at least the reserve", auction: model.Auction{Format: model.AuctionFormatVickrey, StartPrice: usd(10), ReservePrice: usd(40)}, bids: []model.Bid{{UserID: 2, Amount: usd(30)}, {UserID: 3, Amount: usd(50)}}, wantWinner: 3, wantPrice: 40, wantSold: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			winner, price, sold := service.FormatOf(tt.auction).Settle(tt.auction, tt.bids)
+			assert.Equal(t, tt.wantSold, sold)
+			assert.Equal(t, tt.wantWinner, winner.UserID)
+			if tt.wantSold {
+				assert.Equal(t, usd(tt.wantPrice), price)
+			}
+		})
+	}
+}
+
+func TestCreateAuctionFormatValidation(t *testing.T) {
+	dutch := func(a model.Auction) model.Auction {
+		a.Format, a.DutchDecrement, a.DutchIntervalMinutes = model.AuctionFormatDutch, usd(5), 10
+		return a
+	}
+
+	tests := []struct {
+		name    string
+		auction model.Auction
+		wantErr bool
+	}{
+		{name: "default english", auction: model.Auction{}},
+		{name: "unknown format", auction: model.Auction{Format: "japanese"}, wantErr: true},
+		{name: "dutch", auction: dutch(model.Auction{DutchFloorPrice: usd(20)})},
+		{name: "dutch without decrement", auction: model.Auction{Format: model.AuctionFormatDutch, DutchIntervalMinutes: 10}, wantErr: true},
+		{name: "dutch floor above start", auction: dutch(model.Auction{DutchFloorPrice: usd(200)}), wantErr: true},
+		{name: "dutch with reserve", auction: dutch(model.Auction{ReservePrice: usd(150)}), wantErr: true},
+		{name: "sealed with reserve", auction: model.Auction{Format: model.AuctionFormatVickrey, ReservePrice: usd(150)}},
+		{name: "sealed with buy now", auction: model.Auction{Format: model.AuctionFormatSealedFirstPrice, BuyNowPrice: usd(500)}, wantErr: true},
+		{name: "sealed with soft close", auction: model.Auction{Format: model.AuctionFormatSealedFirstPrice, EndsAt: timeAt(time.Hour), SoftCloseWindowMinutes: 5, SoftCloseExtensionMinutes: 5}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auctionService, _ := newClockedService(&fakeClock{now: start})
+			tt.auction.Item, tt.auction.UserID, tt.auction.CurrentPrice = "Test Item", 1, usd(100)
+
+			created, err := auctionService.CreateAuction(tt.auction)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, service.ErrInvalidInput)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, created.Format)
+			assert.Equal(t, usd(100), created.StartPrice)
+		})
+	}
+}
+
+func TestDutchAuctionClosesOnFirstBid(t *testing.T) {
+	clock := &fakeClock{now: start}
+	auctionService, store := newClockedService(clock)
+	auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen, Format: model.AuctionFormatDutch,
+		StartPrice: usd(100), CurrentPrice: usd(100), DutchDecrement: usd(10), DutchIntervalMinutes: 5, DutchFloorPrice: usd(40)})
+
+	clock.Set(auction.CreatedAt.Add(11 * time.Minute))
+	shown, err := auctionService.GetAuctionByID(auction.ID)
+	require.NoError(t, err)
+	assert.Equal(t, usd(80), shown.CurrentPrice)
+
+	sold, err := auctionService.PlaceBid(auction.ID, 2, usd(80))
+	require.NoError(t, err)
+	assert.Equal(t, model.AuctionStatusClosed, sold.Status)
+	assert.Equal(t, 2, sold.WinnerID)
+	assert.Equal(t, usd(80), sold.CurrentPrice)
+
+	_, err = auctionService.PlaceBid(auction.ID, 3, usd(80))
+	assert.ErrorIs(t, err, service.ErrAuctionClosed)
+
+	var types []string
+	for _, event := range outboxEvents(t, store) {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []string{model.EventBidPlaced, model.EventPriceChanged, model.EventAuctionClosed, model.EventAuctionSold, model.EventOrderCreated, model.EventNotifyWon}, types)
+}
+
+func TestSealedBidsHiddenUntilClose(t *testing.T) {
+	auctionService, store := newTestService()
+	auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen, Format: model.AuctionFormatVickrey,
+		StartPrice: usd(10), CurrentPrice: usd(10)})
+
+	for bidder, amount := range map[int]float64{2: 40, 3: 70, 4: 55} {
+		updated, err := auctionService.PlaceBid(auction.ID, bidder, usd(amount))
+		require.NoError(t, err)
+		assert.Equal(t, usd(10), updated.CurrentPrice)
+	}
+	_, err := auctionService.PlaceBid(auction.ID, 2, usd(90))
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+	_, err = auctionService.PlaceProxyBid(auction.ID, 5, usd(90))
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+
+	bids, err := auctionService.GetBids(auction.ID)
+	require.NoError(t, err)
+	assert.Empty(t, bids)
+	for _, event := range outboxEvents(t, store) {
+		assert.Nil(t, event.Amount)
+	}
+
+	closed, err := auctionService.CloseAuction(1, auction.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, closed.WinnerID)
+	assert.Equal(t, usd(55), closed.CurrentPrice)
+
+	bids, err = auctionService.GetBids(auction.ID)
+	require.NoError(t, err)
+	assert.Len(t, bids, 3)
+}
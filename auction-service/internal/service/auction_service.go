@@ -1,44 +1,976 @@
 package service
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"auction-service/internal/blob"
+	"auction-service/internal/markdown"
 	"auction-service/internal/model"
 	"auction-service/internal/repository"
+)
+
+var (
+	// ErrNotFound is returned when the auction does not exist.
+	ErrNotFound = errors.New("auction not found")
+	// ErrForbidden is returned when the caller does not own the auction.
+	ErrForbidden = errors.New("not allowed to modify this auction")
+	// ErrInvalidInput is returned when the request fails validation.
+	ErrInvalidInput = errors.New("invalid input")
+	// ErrAuctionClosed is returned when an operation requires an open auction.
+	ErrAuctionClosed = errors.New("auction is closed")
+	// ErrAuctionNotStarted is returned when a scheduled auction is bid on
+	// before its start time.
+	ErrAuctionNotStarted = errors.New("auction has not started yet")
+	// ErrBuyNowUnavailable is returned when an auction can no longer be
+	// bought at its buy-now price.
+	ErrBuyNowUnavailable = errors.New("buy now is not available")
+	// ErrBidNotFound is returned when the bid does not exist.
+	ErrBidNotFound = errors.New("bid not found")
+	// ErrRetractionNotAllowed is returned when a bid can no longer be
+	// retracted under the retraction rules.
+	ErrRetractionNotAllowed = errors.New("bid can no longer be retracted")
+)
+
+// AuctionFilter narrows down the auctions FindAuctions returns. Zero fields
+// do not filter.
+type AuctionFilter struct {
+	// Category is the ID or slug of a category. Auctions in its
+	// subcategories match too.
+	Category string
+	// Attributes are attribute values the auctions must have.
+	Attributes map[string]string
+}
 
-	"github.com/streadway/amqp"
+// Search result limits.
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
 )
 
+// MaxDescriptionLength is the longest description accepted, in bytes.
+const MaxDescriptionLength = 20000
+
 type AuctionService interface {
-	CreateAuction(item string, userID int) (model.Auction, error)
+	GetAllAuctions() ([]model.Auction, error)
+	FindAuctions(filter AuctionFilter) ([]model.Auction, error)
+	SearchAuctions(query string, filter AuctionFilter, limit int) ([]model.SearchResult, error)
 	GetAuctionByID(id int) (model.Auction, error)
-	PlaceBid(auctionID int, bidAmount float64) error
+	CreateAuction(auction model.Auction) (model.Auction, error)
+	RelistAuction(userID, id int, startsAt *time.Time) (model.Auction, error)
+	GetRelistLineage(id int) ([]model.Auction, error)
+	UpdateAuction(userID int, update model.AuctionUpdate) (model.Auction, error)
+	DeleteAuction(userID, id int) error
+	CloseAuction(userID, id int) (model.Auction, error)
+	PlaceBid(auctionID, bidderID int, bidAmount model.Money) (model.Auction, error)
+	PlaceProxyBid(auctionID, bidderID int, maxAmount model.Money) (model.Auction, error)
+	BuyNow(auctionID, buyerID int) (model.Auction, error)
+	GetBids(auctionID int) ([]model.Bid, error)
+	RetractBid(bidderID, bidID int, reason string) (model.Auction, error)
+	CancelBid(sellerID, bidID int, reason string) (model.Auction, error)
+	GetBidAudit(auctionID int) ([]model.BidAuditEntry, error)
 }
 
 type auctionService struct {
 	auctionRepository repository.AuctionRepository
-	rabbitConn        *amqp.Connection
+	txManager         repository.TxManager
+	now               func() time.Time
+	increments        model.IncrementTable
+	retraction        RetractionRules
+	paymentPeriod     time.Duration
+	blobs             blob.Store
+	mediaURL          string
+	descriptions      *markdown.Cache
 }
 
-func NewAuctionService(auctionRepository repository.AuctionRepository, rabbitConn *amqp.Connection) AuctionService {
+// Ensure auctionService implements AuctionService
+var _ AuctionService = (*auctionService)(nil)
+
+// NewAuctionService creates an AuctionService. Reads go through
+// auctionRepository; every change runs in a txManager transaction together
+// with the outbox message announcing it.
+func NewAuctionService(auctionRepository repository.AuctionRepository, txManager repository.TxManager, opts ...Option) AuctionService {
+	o := newOptions(opts)
 	return &auctionService{
 		auctionRepository: auctionRepository,
-		rabbitConn:        rabbitConn,
+		txManager:         o.transactions(txManager),
+		now:               o.now,
+		increments:        o.increments,
+		retraction:        o.retraction,
+		paymentPeriod:     o.paymentPeriod,
+		blobs:             o.blobs,
+		mediaURL:          o.mediaURL,
+		descriptions:      markdown.NewCache(markdown.DefaultCacheSize),
+	}
+}
+
+func (s *auctionService) GetAllAuctions() ([]model.Auction, error) {
+	auctions, err := s.auctionRepository.GetAllAuctions()
+	if err != nil {
+		return auctions, err
 	}
+	for i := range auctions {
+		auctions[i] = s.withPrice(auctions[i])
+	}
+	return auctions, s.withDetails(auctions)
 }
 
-func (s *auctionService) CreateAuction(item string, userID int) (model.Auction, error) {
-	auction := &model.Auction{
-		Item:   item,
-		UserID: userID,
+func (s *auctionService) FindAuctions(filter AuctionFilter) ([]model.Auction, error) {
+	query, wanted, err := s.resolveFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	auctions, err := s.auctionRepository.FindAuctions(query)
+	if err != nil {
+		return nil, err
+	}
+	matching := []model.Auction{}
+	for _, auction := range auctions {
+		if hasAttributes(auction, wanted) {
+			matching = append(matching, s.withPrice(auction))
+		}
 	}
-	return s.auctionRepository.CreateAuction(*auction)
+	return matching, s.withDetails(matching)
+}
+
+// SearchAuctions finds the auctions whose item or description has words
+// starting with every word of query, best match first. limit caps the
+// results; 0 means DefaultSearchLimit.
+func (s *auctionService) SearchAuctions(query string, filter AuctionFilter, limit int) ([]model.SearchResult, error) {
+	terms := repository.SearchTerms(query)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("%w: search query is required", ErrInvalidInput)
+	}
+	if limit == 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit < 0 || limit > MaxSearchLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidInput, MaxSearchLimit)
+	}
+	categories, wanted, err := s.resolveFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	search := repository.AuctionSearch{Terms: terms, Filter: categories, Limit: limit}
+	if len(wanted) > 0 {
+		// Attributes are filtered here, so the limit can only be applied
+		// after.
+		search.Limit = 0
+	}
+	results, err := s.auctionRepository.SearchAuctions(search)
+	if err != nil {
+		return nil, err
+	}
+	matching := []model.SearchResult{}
+	for _, result := range results {
+		if len(matching) == limit {
+			break
+		}
+		if hasAttributes(result.Auction, wanted) {
+			result.Auction = s.withPrice(result.Auction)
+			matching = append(matching, result)
+		}
+	}
+	auctions := make([]model.Auction, len(matching))
+	for i, result := range matching {
+		auctions[i] = result.Auction
+	}
+	if err := s.withDetails(auctions); err != nil {
+		return nil, err
+	}
+	for i := range matching {
+		matching[i].Auction = auctions[i]
+	}
+	return matching, nil
+}
+
+// resolveFilter turns filter into the repository filter, with the IDs of the
+// category and its subcategories, and the attribute values to look for.
+func (s *auctionService) resolveFilter(filter AuctionFilter) (repository.AuctionFilter, map[string]string, error) {
+	var query repository.AuctionFilter
+	var schema []model.AttributeSchema
+	if filter.Category != "" {
+		err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+			tree, err := loadCategoryTree(uow)
+			if err != nil {
+				return err
+			}
+			category, ok := tree.find(filter.Category)
+			if !ok {
+				return ErrCategoryNotFound
+			}
+			query.CategoryIDs = tree.descendants(category.ID)
+			schema = tree.schema(category.ID)
+			return nil
+		})
+		if err != nil {
+			return query, nil, err
+		}
+	}
+
+	// Values are compared in the canonical form they are stored in, so
+	// "1.50" finds auctions with "1.5" when the category says the
+	// attribute is a number.
+	wanted := map[string]string{}
+	for name, value := range filter.Attributes {
+		value = strings.TrimSpace(value)
+		if i := slices.IndexFunc(schema, func(attribute model.AttributeSchema) bool { return attribute.Name == name }); i >= 0 {
+			var err error
+			if value, err = attributeValue(schema[i], value); err != nil {
+				return query, nil, err
+			}
+		}
+		wanted[name] = value
+	}
+	return query, wanted, nil
+}
+
+func hasAttributes(auction model.Auction, wanted map[string]string) bool {
+	for name, value := range wanted {
+		if auction.Attributes[name] != value {
+			return false
+		}
+	}
+	return true
 }
 
 func (s *auctionService) GetAuctionByID(id int) (model.Auction, error) {
-	return s.auctionRepository.GetAuctionByID(int(id))
+	auction, err := s.auctionRepository.GetAuctionByID(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return auction, ErrNotFound
+	}
+	if err != nil {
+		return auction, err
+	}
+	auctions := []model.Auction{s.withPrice(auction)}
+	if err := s.withDetails(auctions); err != nil {
+		return model.Auction{}, err
+	}
+	return auctions[0], nil
+}
+
+// withDetails renders the descriptions of auctions and loads their
+// attachments in one query.
+func (s *auctionService) withDetails(auctions []model.Auction) error {
+	if len(auctions) == 0 {
+		return nil
+	}
+	for i := range auctions {
+		auctions[i].DescriptionHTML = s.descriptions.Render(auctions[i].Description)
+	}
+	return s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		return withAttachments(uow, auctions, s.mediaURL)
+	})
+}
+
+// withPrice sets the current price of an open auction to the one its format
+// shows now, which for Dutch auctions drops over time, and the next minimum
+// bid so clients do not have to work out increments.
+func (s *auctionService) withPrice(auction model.Auction) model.Auction {
+	if auction.IsOpen() {
+		format := s.formatOf(auction)
+		now := s.now()
+		auction.CurrentPrice = format.Price(auction, now)
+		minimum := format.MinimumBid(auction, now)
+		auction.NextMinimumBid = &minimum
+	}
+	return auction
+}
+
+// CreateAuction lists a new auction. It opens right away, or at StartsAt
+// when that is set.
+func (s *auctionService) CreateAuction(auction model.Auction) (model.Auction, error) {
+	// Only relisting links auctions to earlier ones.
+	auction.RelistedFromID = nil
+	auction.OriginalAuctionID = nil
+	return s.createAuction(auction, nil)
+}
+
+// createAuction validates and stores a new auction. check, when set, runs
+// first in the transaction creating it.
+func (s *auctionService) createAuction(auction model.Auction, check func(uow repository.UnitOfWork) error) (model.Auction, error) {
+	// The store assigns these, whatever the client sent.
+	auction.ID = 0
+	auction.CreatedAt = time.Time{}
+	auction.UpdatedAt = time.Time{}
+	auction.DeletedAt.Time, auction.DeletedAt.Valid = time.Time{}, false
+	if err := validateListing(&auction); err != nil {
+		return model.Auction{}, err
+	}
+	if err := s.validateSchedule(&auction); err != nil {
+		return model.Auction{}, err
+	}
+	auction.Status = model.AuctionStatusOpen
+	if auction.StartsAt != nil {
+		auction.Status = model.AuctionStatusScheduled
+	}
+	auction.WinnerID = 0
+
+	var created model.Auction
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		if check != nil {
+			if err := check(uow); err != nil {
+				return err
+			}
+		}
+		if err := categorize(uow, &auction); err != nil {
+			return err
+		}
+		if err := FormatOf(auction).Validate(auction); err != nil {
+			return err
+		}
+		var err error
+		created, err = uow.Auctions().CreateAuction(auction)
+		if err != nil {
+			return err
+		}
+		return enqueue(uow, model.Event{Type: model.EventAuctionCreated, AuctionID: created.ID, UserID: created.UserID})
+	})
+	if err != nil {
+		return model.Auction{}, err
+	}
+	created.DescriptionHTML = s.descriptions.Render(created.Description)
+	return created, nil
+}
+
+// validateListing checks what the seller lists: the item, its description
+// and the prices of the format. It fills in the defaults.
+func validateListing(auction *model.Auction) error {
+	auction.Item = strings.TrimSpace(auction.Item)
+	auction.Description = strings.TrimSpace(auction.Description)
+	if auction.Item == "" {
+		return fmt.Errorf("%w: item is required", ErrInvalidInput)
+	}
+	if err := validateDescription(auction.Description); err != nil {
+		return err
+	}
+	if auction.UserID <= 0 {
+		return fmt.Errorf("%w: user id is required", ErrInvalidInput)
+	}
+	if err := validateCurrency(auction); err != nil {
+		return err
+	}
+	if auction.CurrentPrice.IsNegative() {
+		return fmt.Errorf("%w: price cannot be negative", ErrInvalidInput)
+	}
+	format, err := formatByName(auction.Format)
+	if err != nil {
+		return err
+	}
+	if auction.Format == "" {
+		auction.Format = model.AuctionFormatEnglish
+	}
+	auction.StartPrice = auction.CurrentPrice
+	if err := validatePrices(*auction); err != nil {
+		return err
+	}
+	if err := format.Validate(*auction); err != nil {
+		return err
+	}
+	return validateIncrements(auction.BidIncrements)
+}
+
+// UpdateAuction lets the owner change the item, description and attributes
+// of an open or scheduled auction, and its category, reserve and buy-now
+// prices and increments while nobody has bid. Prices and increments left
+// out of the update keep their value. Status and current price are not
+// editable through this method.
+func (s *auctionService) UpdateAuction(userID int, update model.AuctionUpdate) (model.Auction, error) {
+	item := strings.TrimSpace(update.Item)
+	if item == "" {
+		return model.Auction{}, fmt.Errorf("%w: item is required", ErrInvalidInput)
+	}
+	description := strings.TrimSpace(update.Description)
+	if err := validateDescription(description); err != nil {
+		return model.Auction{}, err
+	}
+
+	var updated model.Auction
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		existing, err := ownedAuction(uow, userID, update.ID)
+		if err != nil {
+			return err
+		}
+		if !existing.IsOpen() && !existing.IsScheduled() {
+			return ErrAuctionClosed
+		}
+
+		existing.Item = item
+		existing.Description = description
+		existing.Attributes = update.Attributes
+		if !equalIDs(update.CategoryID, existing.CategoryID) {
+			bid, err := hasBids(uow, existing.ID)
+			if err != nil {
+				return err
+			}
+			if bid {
+				return fmt.Errorf("%w: the category cannot change once bidding started", ErrInvalidInput)
+			}
+			existing.CategoryID = update.CategoryID
+			if err := categorize(uow, &existing); err != nil {
+				return err
+			}
+			if err := FormatOf(existing).Validate(existing); err != nil {
+				return err
+			}
+		} else {
+			// The auction keeps the increments it was listed with.
+			increments := existing.CategoryIncrements
+			if err := categorize(uow, &existing); err != nil {
+				return err
+			}
+			existing.CategoryIncrements = increments
+		}
+		reserve, buyNow, increments := existing.ReservePrice, existing.BuyNowPrice, existing.BidIncrements
+		if update.ReservePrice != nil {
+			reserve = *update.ReservePrice
+			if err := inCurrency(&reserve, existing.Currency); err != nil {
+				return err
+			}
+		}
+		if update.BuyNowPrice != nil {
+			buyNow = *update.BuyNowPrice
+			if err := inCurrency(&buyNow, existing.Currency); err != nil {
+				return err
+			}
+		}
+		if update.BidIncrements != nil {
+			increments = *update.BidIncrements
+			if err := incrementsIn(increments, existing.Currency); err != nil {
+				return err
+			}
+		}
+		if reserve != existing.ReservePrice || buyNow != existing.BuyNowPrice ||
+			!slices.Equal(increments, existing.BidIncrements) {
+			bid, err := hasBids(uow, existing.ID)
+			if err != nil {
+				return err
+			}
+			if bid {
+				return fmt.Errorf("%w: prices and increments cannot change once bidding started", ErrInvalidInput)
+			}
+			existing.ReservePrice = reserve
+			existing.BuyNowPrice = buyNow
+			existing.BidIncrements = increments
+			if err := validatePrices(existing); err != nil {
+				return err
+			}
+			if err := validateIncrements(existing.BidIncrements); err != nil {
+				return err
+			}
+			if err := FormatOf(existing).Validate(existing); err != nil {
+				return err
+			}
+		}
+		if err := uow.Auctions().UpdateAuction(existing); err != nil {
+			return err
+		}
+		updated = existing
+		return enqueue(uow, model.Event{Type: model.EventAuctionUpdated, AuctionID: existing.ID, UserID: userID})
+	})
+	if err != nil {
+		return model.Auction{}, err
+	}
+	updated.DescriptionHTML = s.descriptions.Render(updated.Description)
+	return updated, nil
+}
+
+// validateDescription checks the length of a description. Its Markdown is
+// not checked: anything renders, and what is unsafe is dropped then.
+func validateDescription(description string) error {
+	if len(description) > MaxDescriptionLength {
+		return fmt.Errorf("%w: description can be at most %d bytes", ErrInvalidInput, MaxDescriptionLength)
+	}
+	return nil
+}
+
+func equalIDs(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// DeleteAuction deletes an auction with its attachments. Their files are
+// removed from the blob store once the deletion is committed.
+func (s *auctionService) DeleteAuction(userID, id int) error {
+	var attachments []model.Attachment
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		if _, err := ownedAuction(uow, userID, id); err != nil {
+			return err
+		}
+		if err := uow.Auctions().DeleteAuction(id); err != nil {
+			return err
+		}
+		var err error
+		if attachments, err = uow.Attachments().DeleteAttachmentsByAuctionID(id); err != nil {
+			return err
+		}
+		return enqueue(uow, model.Event{Type: model.EventAuctionDeleted, AuctionID: id, UserID: userID})
+	})
+	if err != nil {
+		return err
+	}
+	removeBlobs(s.blobs, attachments)
+	return nil
+}
+
+// CloseAuction moves an open auction to the closed state. It sells to the
+// leading bidder when the reserve is met.
+func (s *auctionService) CloseAuction(userID, id int) (model.Auction, error) {
+	var closed model.Auction
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		auction, err := ownedAuction(uow, userID, id)
+		if err != nil {
+			return err
+		}
+		if auction.IsScheduled() {
+			return ErrAuctionNotStarted
+		}
+		if !auction.IsOpen() {
+			return ErrAuctionClosed
+		}
+
+		closed, err = closeAuction(uow, auction, userID, s.paymentDue())
+		return err
+	})
+	if err != nil {
+		return model.Auction{}, err
+	}
+	return closed, nil
+}
+
+// PlaceBid records a bid on an open auction in one transaction. Sellers cannot
+// bid on their own auctions; the auction format decides whether the bid is
+// accepted and what it does. In English auctions it raises the price and
+// proxy bids of other users answer it right away, in Dutch auctions it wins,
+// and sealed bids are only stored.
+func (s *auctionService) PlaceBid(auctionID, bidderID int, bidAmount model.Money) (model.Auction, error) {
+	if bidderID <= 0 {
+		return model.Auction{}, fmt.Errorf("%w: bidder id is required", ErrInvalidInput)
+	}
+
+	var updated model.Auction
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		auction, err := lockAuction(uow, auctionID)
+		if err != nil {
+			return err
+		}
+		if auction.IsScheduled() {
+			return ErrAuctionNotStarted
+		}
+		if !auction.IsOpen() || auction.HasEnded(s.now()) {
+			return ErrAuctionClosed
+		}
+		if auction.UserID == bidderID {
+			return fmt.Errorf("%w: sellers cannot bid on their own auctions", ErrForbidden)
+		}
+		if err := inCurrency(&bidAmount, auction.Currency); err != nil {
+			return err
+		}
+
+		format := s.formatOf(auction)
+		bids, err := uow.Bids().GetBidsByAuctionID(auction.ID)
+		if err != nil {
+			return err
+		}
+		bid, err := format.AcceptBid(auction, activeBids(bids), model.Bid{AuctionID: auction.ID, UserID: bidderID, Amount: bidAmount}, s.now())
+		if err != nil {
+			return err
+		}
+
+		switch rules := format.Rules(); {
+		case rules.SealedBids:
+			if _, err := uow.Bids().CreateBid(bid); err != nil {
+				return err
+			}
+			updated = auction
+			return enqueue(uow, model.Event{Type: model.EventBidPlaced, AuctionID: auction.ID, UserID: bidderID})
+		case rules.ClosesOnBid:
+			auction, err = s.placeBids(uow, auction, []model.Bid{bid})
+			if err != nil {
+				return err
+			}
+			updated, err = closeAuction(uow, auction, bidderID, s.paymentDue())
+			return err
+		default:
+			leader, err := leadingBidder(uow, auction.ID)
+			if err != nil {
+				return err
+			}
+			auction, err = s.placeBids(uow, auction, []model.Bid{bid})
+			if err != nil {
+				return err
+			}
+			if updated, err = s.applyProxyBids(uow, auction); err != nil {
+				return err
+			}
+			return notifyOutbid(uow, updated, leader)
+		}
+	})
+	if err != nil {
+		return model.Auction{}, err
+	}
+	return updated, nil
+}
+
+// PlaceProxyBid sets the hidden maximum bidderID is willing to pay and lets
+// the proxies bid against each other. The maximum must beat the current price
+// and can only be raised.
+func (s *auctionService) PlaceProxyBid(auctionID, bidderID int, maxAmount model.Money) (model.Auction, error) {
+	if bidderID <= 0 {
+		return model.Auction{}, fmt.Errorf("%w: bidder id is required", ErrInvalidInput)
+	}
+
+	var updated model.Auction
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		auction, err := lockAuction(uow, auctionID)
+		if err != nil {
+			return err
+		}
+		if auction.IsScheduled() {
+			return ErrAuctionNotStarted
+		}
+		if !auction.IsOpen() || auction.HasEnded(s.now()) {
+			return ErrAuctionClosed
+		}
+		if auction.UserID == bidderID {
+			return fmt.Errorf("%w: sellers cannot bid on their own auctions", ErrForbidden)
+		}
+		if !FormatOf(auction).Rules().Ascending {
+			return fmt.Errorf("%w: %s auctions do not take proxy bids", ErrInvalidInput, auction.Format)
+		}
+		if err := inCurrency(&maxAmount, auction.Currency); err != nil {
+			return err
+		}
+		minimum := s.formatOf(auction).MinimumBid(auction, s.now())
+		if low, err := below(maxAmount, minimum); err != nil {
+			return err
+		} else if low {
+			return fmt.Errorf("%w: maximum bid must be at least %s", ErrInvalidInput, minimum)
+		}
+
+		proxies, err := uow.ProxyBids().GetProxyBidsByAuctionID(auction.ID)
+		if err != nil {
+			return err
+		}
+		for _, proxy := range proxies {
+			if proxy.UserID == bidderID && !proxy.MaxAmount.Less(maxAmount) {
+				return fmt.Errorf("%w: maximum bid can only be raised", ErrInvalidInput)
+			}
+		}
+
+		leader, err := leadingBidder(uow, auction.ID)
+		if err != nil {
+			return err
+		}
+		if _, err := uow.ProxyBids().ReplaceProxyBid(model.ProxyBid{AuctionID: auction.ID, UserID: bidderID, MaxAmount: maxAmount}); err != nil {
+			return err
+		}
+		if updated, err = s.applyProxyBids(uow, auction); err != nil {
+			return err
+		}
+		return notifyOutbid(uow, updated, leader)
+	})
+	if err != nil {
+		return model.Auction{}, err
+	}
+	return updated, nil
+}
+
+// BuyNow sells the auction to buyerID at its buy-now price and closes it.
+func (s *auctionService) BuyNow(auctionID, buyerID int) (model.Auction, error) {
+	if buyerID <= 0 {
+		return model.Auction{}, fmt.Errorf("%w: buyer id is required", ErrInvalidInput)
+	}
+
+	var sold model.Auction
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		auction, err := lockAuction(uow, auctionID)
+		if err != nil {
+			return err
+		}
+		if auction.IsScheduled() {
+			return ErrAuctionNotStarted
+		}
+		if !auction.IsOpen() || auction.HasEnded(s.now()) {
+			return ErrAuctionClosed
+		}
+		if auction.UserID == buyerID {
+			return fmt.Errorf("%w: sellers cannot buy their own auctions", ErrForbidden)
+		}
+		if !auction.BuyNowPrice.IsPositive() {
+			return fmt.Errorf("%w: auction has no buy now price", ErrBuyNowUnavailable)
+		}
+		bid, err := hasBids(uow, auction.ID)
+		if err != nil {
+			return err
+		}
+		if bid && (!auction.ReservePrice.IsPositive() || auction.ReserveMet()) {
+			return fmt.Errorf("%w: bidding already passed the threshold", ErrBuyNowUnavailable)
+		}
+
+		auction, err = s.placeBids(uow, auction, []model.Bid{{AuctionID: auction.ID, UserID: buyerID, Amount: auction.BuyNowPrice}})
+		if err != nil {
+			return err
+		}
+		sold, err = closeAuction(uow, auction, buyerID, s.paymentDue())
+		return err
+	})
+	if err != nil {
+		return model.Auction{}, err
+	}
+	return sold, nil
+}
+
+// GetBids returns the bid history of an auction, oldest first. Proxy maximums
+// stay hidden: only the bids placed on their behalf are listed. Sealed bids
+// are not listed until the auction closes. Withdrawn bids are listed with
+// their status.
+func (s *auctionService) GetBids(auctionID int) ([]model.Bid, error) {
+	var bids []model.Bid
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		auction, err := uow.Auctions().GetAuctionByID(auctionID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+		if auction.IsOpen() && FormatOf(auction).Rules().SealedBids {
+			bids = []model.Bid{}
+			return nil
+		}
+		bids, err = uow.Bids().GetBidsByAuctionID(auctionID)
+		return err
+	})
+	return bids, err
+}
+
+// validateSchedule checks the start time, end time and soft-close settings
+// of a new auction and records its original end time.
+func (s *auctionService) validateSchedule(auction *model.Auction) error {
+	auction.OriginalEndsAt = nil
+	auction.Extensions = 0
+	if auction.StartsAt != nil {
+		if !auction.StartsAt.After(s.now()) {
+			return fmt.Errorf("%w: start time must be in the future", ErrInvalidInput)
+		}
+		start := auction.StartsAt.UTC()
+		auction.StartsAt = &start
+	}
+	if auction.SoftCloseWindowMinutes < 0 || auction.SoftCloseExtensionMinutes < 0 || auction.SoftCloseCapMinutes < 0 {
+		return fmt.Errorf("%w: soft close settings cannot be negative", ErrInvalidInput)
+	}
+	if auction.EndsAt == nil {
+		if auction.SoftCloseWindowMinutes > 0 {
+			return fmt.Errorf("%w: soft close needs an end time", ErrInvalidInput)
+		}
+		return nil
+	}
+	if !auction.EndsAt.After(s.now()) {
+		return fmt.Errorf("%w: end time must be in the future", ErrInvalidInput)
+	}
+	if auction.StartsAt != nil && !auction.EndsAt.After(*auction.StartsAt) {
+		return fmt.Errorf("%w: end time must be after the start time", ErrInvalidInput)
+	}
+	if auction.SoftCloseWindowMinutes > 0 && auction.SoftCloseExtensionMinutes == 0 {
+		return fmt.Errorf("%w: soft close needs an extension", ErrInvalidInput)
+	}
+
+	end := auction.EndsAt.UTC()
+	auction.EndsAt = &end
+	auction.OriginalEndsAt = &end
+	return nil
+}
+
+// validateCurrency sets the currency of a new auction, taken from its price
+// when not given, and puts every price of the auction in it.
+func validateCurrency(auction *model.Auction) error {
+	if auction.Currency == "" {
+		auction.Currency = auction.CurrentPrice.Currency
+	}
+	if auction.Currency == "" {
+		auction.Currency = model.DefaultCurrency
+	}
+	if !model.ValidCurrency(auction.Currency) {
+		return fmt.Errorf("%w: unknown currency %q", ErrInvalidInput, auction.Currency)
+	}
+	prices := []*model.Money{&auction.CurrentPrice, &auction.ReservePrice, &auction.BuyNowPrice, &auction.DutchDecrement, &auction.DutchFloorPrice}
+	for _, price := range prices {
+		if err := inCurrency(price, auction.Currency); err != nil {
+			return err
+		}
+	}
+	return incrementsIn(auction.BidIncrements, auction.Currency)
+}
+
+// inCurrency checks that amount is in currency. Amounts without a currency
+// are taken to be in it.
+func inCurrency(amount *model.Money, currency string) error {
+	if amount.Currency == "" {
+		amount.Currency = currency
+	}
+	if amount.Currency != currency {
+		return fmt.Errorf("%w: amount in %s, auction is in %s", ErrInvalidInput, amount.Currency, currency)
+	}
+	return nil
+}
+
+// below reports whether a bid amount is below minimum. Unlike Less it does
+// not trust the two to share a currency: bids come from outside and the
+// minimum from the database, so a mismatch is refused as invalid input.
+func below(amount, minimum model.Money) (bool, error) {
+	cmp, err := amount.CmpChecked(minimum)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	return cmp < 0, nil
 }
 
-func (s *auctionService) PlaceBid(auctionID int, bidAmount float64) error {
-	// Aquí es donde utilizarías RabbitMQ para enviar un mensaje
-	// al servicio de pujas para procesar la oferta.
+// validatePrices checks the reserve and buy-now prices against the current
+// price of an auction.
+func validatePrices(auction model.Auction) error {
+	if auction.ReservePrice.IsNegative() || auction.BuyNowPrice.IsNegative() {
+		return fmt.Errorf("%w: prices cannot be negative", ErrInvalidInput)
+	}
+	if auction.ReservePrice.IsPositive() && !auction.CurrentPrice.Less(auction.ReservePrice) {
+		return fmt.Errorf("%w: reserve price must be higher than the starting price", ErrInvalidInput)
+	}
+	if auction.BuyNowPrice.IsPositive() && !auction.CurrentPrice.Less(auction.BuyNowPrice) {
+		return fmt.Errorf("%w: buy now price must be higher than the starting price", ErrInvalidInput)
+	}
+	if auction.BuyNowPrice.IsPositive() && auction.BuyNowPrice.Less(auction.ReservePrice) {
+		return fmt.Errorf("%w: buy now price cannot be lower than the reserve price", ErrInvalidInput)
+	}
 	return nil
 }
+
+// hasBids reports whether the auction has any bid that was not withdrawn.
+func hasBids(uow repository.UnitOfWork, auctionID int) (bool, error) {
+	_, err := uow.Bids().GetLatestBid(auctionID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// leadingBidder returns the user holding the leading bid of an auction, 0
+// when nobody bid yet.
+func leadingBidder(uow repository.UnitOfWork, auctionID int) (int, error) {
+	leading, err := uow.Bids().GetLatestBid(auctionID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return 0, nil
+	}
+	return leading.UserID, err
+}
+
+// notifyOutbid asks the notifier to tell previousLeader that someone else
+// now leads auction. Nothing is sent when previousLeader still leads, as
+// after their proxy answered the new bid.
+func notifyOutbid(uow repository.UnitOfWork, auction model.Auction, previousLeader int) error {
+	if previousLeader == 0 {
+		return nil
+	}
+	leader, err := leadingBidder(uow, auction.ID)
+	if err != nil || leader == previousLeader {
+		return err
+	}
+	return enqueue(uow, model.Event{Type: model.EventNotifyOutbid, AuctionID: auction.ID, UserID: previousLeader, Amount: &auction.CurrentPrice})
+}
+
+// closeAuction closes an open auction and announces the outcome its format
+// settles on: sold to the winning bidder, or unsold. closedBy is the user who
+// closed it, 0 when it ended on its own. A sale opens an order the winner has
+// to pay by paymentDue. The winner and the other bidders are notified.
+func closeAuction(uow repository.UnitOfWork, auction model.Auction, closedBy int, paymentDue time.Time) (model.Auction, error) {
+	bids, err := uow.Bids().GetBidsByAuctionID(auction.ID)
+	if err != nil {
+		return auction, err
+	}
+	auction.Status = model.AuctionStatusClosed
+	auction.WinnerID = 0
+	if winner, price, sold := FormatOf(auction).Settle(auction, activeBids(bids)); sold {
+		auction.WinnerID = winner.UserID
+		auction.CurrentPrice = price
+	}
+
+	if err := uow.Auctions().UpdateAuction(auction); err != nil {
+		return auction, err
+	}
+	if err := enqueue(uow, model.Event{Type: model.EventAuctionClosed, AuctionID: auction.ID, UserID: closedBy}); err != nil {
+		return auction, err
+	}
+	if auction.WinnerID == 0 {
+		if err := enqueue(uow, model.Event{Type: model.EventAuctionUnsold, AuctionID: auction.ID}); err != nil {
+			return auction, err
+		}
+		return auction, notifyOutcome(uow, auction, activeBids(bids))
+	}
+	if err := enqueue(uow, model.Event{Type: model.EventAuctionSold, AuctionID: auction.ID, UserID: auction.WinnerID, Amount: &auction.CurrentPrice}); err != nil {
+		return auction, err
+	}
+	if _, err = openOrder(uow, auction, auction.WinnerID, auction.CurrentPrice, paymentDue); err != nil {
+		return auction, err
+	}
+	return auction, notifyOutcome(uow, auction, activeBids(bids))
+}
+
+// notifyOutcome asks the notifier to tell the winner of a closed auction
+// they won and every other bidder they lost, once each.
+func notifyOutcome(uow repository.UnitOfWork, auction model.Auction, bids []model.Bid) error {
+	if auction.WinnerID != 0 {
+		if err := enqueue(uow, model.Event{Type: model.EventNotifyWon, AuctionID: auction.ID, UserID: auction.WinnerID, Amount: &auction.CurrentPrice}); err != nil {
+			return err
+		}
+	}
+	told := map[int]bool{auction.WinnerID: true}
+	for _, bid := range bids {
+		if told[bid.UserID] {
+			continue
+		}
+		told[bid.UserID] = true
+		if err := enqueue(uow, model.Event{Type: model.EventNotifyLost, AuctionID: auction.ID, UserID: bid.UserID, Amount: &auction.CurrentPrice}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockAuction loads the auction for update inside uow.
+func lockAuction(uow repository.UnitOfWork, id int) (model.Auction, error) {
+	auction, err := uow.Auctions().GetAuctionByIDForUpdate(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return auction, ErrNotFound
+	}
+	return auction, err
+}
+
+// ownedAuction locks the auction and checks that userID is its seller.
+func ownedAuction(uow repository.UnitOfWork, userID, id int) (model.Auction, error) {
+	auction, err := lockAuction(uow, id)
+	if err != nil {
+		return model.Auction{}, err
+	}
+	if auction.UserID != userID {
+		return model.Auction{}, ErrForbidden
+	}
+	return auction, nil
+}
+
+// enqueue stores event in the outbox of uow so it is published only if the
+// transaction commits.
+func enqueue(uow repository.UnitOfWork, event model.Event) error {
+	event.OccurredAt = time.Now().UTC()
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = uow.Outbox().AddMessage(model.OutboxMessage{Type: event.Type, Payload: payload})
+	return err
+}
@@ -0,0 +1,313 @@
+package service_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"auction-service/internal/service"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestService() (service.AuctionService, *repository.MemoryStore) {
+	store := repository.NewMemoryStore()
+	return service.NewAuctionService(store.Auctions(), store), store
+}
+
+// outboxEvents decodes the events waiting in the outbox of store.
+func outboxEvents(t *testing.T, store *repository.MemoryStore) []model.Event {
+	messages, err := store.Outbox().GetPendingMessages(100)
+	require.NoError(t, err)
+
+	events := make([]model.Event, len(messages))
+	for i, message := range messages {
+		require.NoError(t, json.Unmarshal(message.Payload, &events[i]))
+	}
+	return events
+}
+
+// auctionEvents returns the outbox events of store without the
+// notification intents.
+func auctionEvents(t *testing.T, store *repository.MemoryStore) []model.Event {
+	var events []model.Event
+	for _, event := range outboxEvents(t, store) {
+		if !strings.HasPrefix(event.Type, "notify.") {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// seedAuction stores an auction directly, bypassing the service rules.
+// Auctions without a currency are in USD.
+func seedAuction(t *testing.T, store *repository.MemoryStore, auction model.Auction) model.Auction {
+	if auction.Currency == "" {
+		auction.Currency = "USD"
+	}
+	created, err := store.Auctions().CreateAuction(auction)
+	require.NoError(t, err)
+	return created
+}
+
+// usd returns an amount in dollars.
+func usd(dollars float64) model.Money {
+	return model.MoneyFromMajor(dollars, "USD")
+}
+
+func TestCreateAuctionOpensAndPublishes(t *testing.T) {
+	auctionService, store := newTestService()
+
+	created, err := auctionService.CreateAuction(model.Auction{Item: "  Test Item ", UserID: 1, Status: model.AuctionStatusClosed})
+
+	assert.NoError(t, err)
+	assert.NotZero(t, created.ID)
+	assert.Equal(t, "Test Item", created.Item)
+	assert.Equal(t, model.AuctionStatusOpen, created.Status)
+	events := outboxEvents(t, store)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, model.EventAuctionCreated, events[0].Type)
+		assert.Equal(t, created.ID, events[0].AuctionID)
+	}
+}
+
+func TestCreateAuctionIgnoresStoredFields(t *testing.T) {
+	auctionService, store := newTestService()
+	existing := seedAuction(t, store, model.Auction{Item: "Existing", UserID: 2, Status: model.AuctionStatusOpen})
+	past := start.AddDate(-10, 0, 0)
+
+	created, err := auctionService.CreateAuction(model.Auction{ID: existing.ID, Item: "New", UserID: 1, CreatedAt: past})
+
+	require.NoError(t, err)
+	assert.NotEqual(t, existing.ID, created.ID)
+	assert.True(t, created.CreatedAt.After(past))
+	kept, err := auctionService.GetAuctionByID(existing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Existing", kept.Item)
+}
+
+func TestCreateAuctionValidation(t *testing.T) {
+	auctionService, store := newTestService()
+
+	_, err := auctionService.CreateAuction(model.Auction{Item: " ", UserID: 1})
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+
+	_, err = auctionService.CreateAuction(model.Auction{Item: "Test Item"})
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+
+	auctions, _ := store.Auctions().GetAllAuctions()
+	assert.Empty(t, auctions)
+}
+
+func TestGetAuctionByIDNotFound(t *testing.T) {
+	auctionService, _ := newTestService()
+
+	_, err := auctionService.GetAuctionByID(1)
+
+	assert.ErrorIs(t, err, service.ErrNotFound)
+}
+
+func TestUpdateAuctionChecksOwnership(t *testing.T) {
+	auctionService, store := newTestService()
+	auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen})
+
+	_, err := auctionService.UpdateAuction(2, model.AuctionUpdate{ID: auction.ID, Item: "Stolen"})
+
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	stored, _ := store.Auctions().GetAuctionByID(auction.ID)
+	assert.Equal(t, "Test Item", stored.Item)
+	assert.Empty(t, outboxEvents(t, store))
+}
+
+func TestUpdateAuctionOnlyChangesItem(t *testing.T) {
+	auctionService, store := newTestService()
+	auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen, CurrentPrice: usd(10)})
+
+	result, err := auctionService.UpdateAuction(1, model.AuctionUpdate{ID: auction.ID, Item: "Updated Item"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Updated Item", result.Item)
+	assert.Equal(t, 1, result.UserID)
+	assert.Equal(t, usd(10), result.CurrentPrice)
+}
+
+func TestUpdateAuctionKeepsPricesLeftOut(t *testing.T) {
+	auctionService, store := newTestService()
+	increments := model.IncrementTable{{From: usd(0), Increment: usd(2)}}
+	auction := seedAuction(t, store, model.Auction{Item: "Lamp", UserID: 1, Status: model.AuctionStatusOpen, CurrentPrice: usd(10),
+		ReservePrice: usd(50), BuyNowPrice: usd(80), BidIncrements: increments})
+	_, err := auctionService.PlaceBid(auction.ID, 2, usd(20))
+	require.NoError(t, err)
+
+	result, err := auctionService.UpdateAuction(1, model.AuctionUpdate{ID: auction.ID, Item: "Brass lamp"})
+
+	require.NoError(t, err, "leaving the prices out does not change them, even after the first bid")
+	assert.Equal(t, "Brass lamp", result.Item)
+	stored, err := store.Auctions().GetAuctionByID(auction.ID)
+	require.NoError(t, err)
+	assert.Equal(t, usd(50), stored.ReservePrice)
+	assert.Equal(t, usd(80), stored.BuyNowPrice)
+	assert.Equal(t, increments, stored.BidIncrements)
+}
+
+func TestDeleteAuctionChecksOwnership(t *testing.T) {
+	auctionService, store := newTestService()
+	auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1})
+
+	assert.ErrorIs(t, auctionService.DeleteAuction(2, auction.ID), service.ErrForbidden)
+	assert.NoError(t, auctionService.DeleteAuction(1, auction.ID))
+
+	_, err := auctionService.GetAuctionByID(auction.ID)
+	assert.ErrorIs(t, err, service.ErrNotFound)
+}
+
+func TestCloseAuctionTransitions(t *testing.T) {
+	auctionService, store := newTestService()
+	auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen})
+
+	closed, err := auctionService.CloseAuction(1, auction.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.AuctionStatusClosed, closed.Status)
+
+	_, err = auctionService.CloseAuction(1, auction.ID)
+	assert.ErrorIs(t, err, service.ErrAuctionClosed)
+
+	events := outboxEvents(t, store)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, model.EventAuctionClosed, events[0].Type)
+		assert.Equal(t, model.EventAuctionUnsold, events[1].Type)
+	}
+}
+
+func TestPlaceBid(t *testing.T) {
+	open := model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen, CurrentPrice: usd(10)}
+
+	tests := []struct {
+		name     string
+		auction  model.Auction
+		bidderID int
+		amount   float64
+		wantErr  error
+	}{
+		{name: "accepted", auction: open, bidderID: 2, amount: 11},
+		{name: "too low", auction: open, bidderID: 2, amount: 10, wantErr: service.ErrInvalidInput},
+		{name: "seller bids", auction: open, bidderID: 1, amount: 20, wantErr: service.ErrForbidden},
+		{name: "closed", auction: model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusClosed}, bidderID: 2, amount: 20, wantErr: service.ErrAuctionClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auctionService, store := newTestService()
+			auction := seedAuction(t, store, tt.auction)
+
+			updated, err := auctionService.PlaceBid(auction.ID, tt.bidderID, usd(tt.amount))
+
+			bids, _ := store.Bids().GetBidsByAuctionID(auction.ID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, bids)
+				assert.Empty(t, outboxEvents(t, store))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, usd(tt.amount), updated.CurrentPrice)
+			assert.Len(t, bids, 1)
+			events := outboxEvents(t, store)
+			if assert.Len(t, events, 2) {
+				assert.Equal(t, model.EventBidPlaced, events[0].Type)
+				assert.Equal(t, tt.bidderID, events[0].UserID)
+				assert.Equal(t, model.EventPriceChanged, events[1].Type)
+				assert.Equal(t, usd(tt.amount), *events[1].Amount)
+			}
+		})
+	}
+}
+
+func TestPlaceBidOnMissingAuction(t *testing.T) {
+	auctionService, _ := newTestService()
+
+	_, err := auctionService.PlaceBid(42, 2, usd(5))
+
+	assert.ErrorIs(t, err, service.ErrNotFound)
+}
+
+func TestPlaceBidConcurrently(t *testing.T) {
+	auctionService, store := newTestService()
+	auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen})
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 20; i++ {
+		wg.Add(1)
+		go func(amount float64) {
+			defer wg.Done()
+			auctionService.PlaceBid(auction.ID, 2, usd(amount))
+		}(float64(i))
+	}
+	wg.Wait()
+
+	stored, err := auctionService.GetAuctionByID(auction.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, usd(20), stored.CurrentPrice)
+
+	bids, _ := store.Bids().GetBidsByAuctionID(auction.ID)
+	// Every bid announces itself and the new price.
+	assert.Len(t, outboxEvents(t, store), 2*len(bids))
+}
+
+func TestAuctionCurrency(t *testing.T) {
+	auctionService, _ := newTestService()
+
+	created, err := auctionService.CreateAuction(model.Auction{Item: "Test Item", UserID: 1, CurrentPrice: model.NewMoney(1000, "EUR"), BuyNowPrice: model.NewMoney(5000, "")})
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", created.Currency)
+	assert.Equal(t, model.NewMoney(5000, "EUR"), created.BuyNowPrice)
+
+	_, err = auctionService.PlaceBid(created.ID, 2, usd(20))
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+	_, err = auctionService.PlaceProxyBid(created.ID, 2, usd(20))
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+	updated, err := auctionService.PlaceBid(created.ID, 2, model.NewMoney(2000, "EUR"))
+	require.NoError(t, err)
+	assert.Equal(t, model.NewMoney(2000, "EUR"), updated.CurrentPrice)
+
+	_, err = auctionService.CreateAuction(model.Auction{Item: "Test Item", UserID: 1, Currency: "USD", CurrentPrice: model.NewMoney(1000, "EUR")})
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+	_, err = auctionService.CreateAuction(model.Auction{Item: "Test Item", UserID: 1, Currency: "ABC"})
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+
+	defaulted, err := auctionService.CreateAuction(model.Auction{Item: "Test Item", UserID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, model.DefaultCurrency, defaulted.Currency)
+}
+
+func TestAuctionDescriptionRendered(t *testing.T) {
+	auctionService, _ := newTestService()
+
+	created, err := auctionService.CreateAuction(model.Auction{Item: "Lamp", UserID: 1, Description: "**Brass** lamp <script>alert(1)</script>"})
+	require.NoError(t, err)
+	assert.Equal(t, "**Brass** lamp <script>alert(1)</script>", created.Description)
+	assert.Equal(t, "<p><strong>Brass</strong> lamp </p>\n", created.DescriptionHTML)
+
+	found, err := auctionService.GetAuctionByID(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created.DescriptionHTML, found.DescriptionHTML)
+
+	update := model.AuctionUpdate{ID: found.ID, Item: found.Item, Description: "[manual](javascript:alert(1))"}
+	updated, err := auctionService.UpdateAuction(1, update)
+	require.NoError(t, err)
+	assert.Equal(t, "<p>manual</p>\n", updated.DescriptionHTML)
+	auctions, err := auctionService.GetAllAuctions()
+	require.NoError(t, err)
+	if assert.Len(t, auctions, 1) {
+		assert.Equal(t, "<p>manual</p>\n", auctions[0].DescriptionHTML)
+	}
+
+	update.Description = strings.Repeat("a", service.MaxDescriptionLength+1)
+	_, err = auctionService.UpdateAuction(1, update)
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+	_, err = auctionService.CreateAuction(model.Auction{Item: "Lamp", UserID: 1, Description: update.Description})
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+}
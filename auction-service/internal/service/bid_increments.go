@@ -0,0 +1,99 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"auction-service/internal/model"
+)
+
+// DefaultIncrements is the increment table used when neither the service nor
+// the auction configures one. Like every table it applies to auctions in
+// other currencies in the same major units.
+var DefaultIncrements = model.IncrementTable{
+	{From: model.NewMoney(0, model.DefaultCurrency), Increment: model.NewMoney(100, model.DefaultCurrency)},
+	{From: model.NewMoney(10000, model.DefaultCurrency), Increment: model.NewMoney(500, model.DefaultCurrency)},
+	{From: model.NewMoney(100000, model.DefaultCurrency), Increment: model.NewMoney(1000, model.DefaultCurrency)},
+	{From: model.NewMoney(500000, model.DefaultCurrency), Increment: model.NewMoney(5000, model.DefaultCurrency)},
+}
+
+// ParseIncrementTable reads an increment table written as comma separated
+// from:increment pairs of decimal amounts in DefaultCurrency, e.g.
+// "0:1,100:5,1000:10".
+func ParseIncrementTable(s string) (model.IncrementTable, error) {
+	var table model.IncrementTable
+	for _, pair := range strings.Split(s, ",") {
+		from, increment, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: increment band %q is not from:increment", ErrInvalidInput, pair)
+		}
+		var band model.IncrementBand
+		var err error
+		if band.From, err = model.ParseMoney(from, model.DefaultCurrency); err != nil {
+			return nil, fmt.Errorf("%w: increment band %q: %v", ErrInvalidInput, pair, err)
+		}
+		if band.Increment, err = model.ParseMoney(increment, model.DefaultCurrency); err != nil {
+			return nil, fmt.Errorf("%w: increment band %q: %v", ErrInvalidInput, pair, err)
+		}
+		table = append(table, band)
+	}
+	return table, validateIncrements(table)
+}
+
+// validateIncrements checks that table is in a single currency, starts at 0,
+// has positive increments and rising bands.
+func validateIncrements(table model.IncrementTable) error {
+	currency := table.Currency()
+	for i, band := range table {
+		for _, amount := range []model.Money{band.From, band.Increment} {
+			if amount.Currency != "" && amount.Currency != currency {
+				return fmt.Errorf("%w: increment bands must all be in one currency", ErrInvalidInput)
+			}
+		}
+		if i == 0 && !band.From.IsZero() {
+			return fmt.Errorf("%w: the first increment band must start at 0", ErrInvalidInput)
+		}
+		if i > 0 && !table[i-1].From.Less(band.From) {
+			return fmt.Errorf("%w: increment bands must be in rising order", ErrInvalidInput)
+		}
+		if !band.Increment.IsPositive() {
+			return fmt.Errorf("%w: increments must be positive", ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// incrementsIn checks that the bands of the increment table of an auction
+// are in its currency. Amounts without a currency are taken to be in it.
+func incrementsIn(table model.IncrementTable, currency string) error {
+	for i := range table {
+		if err := inCurrency(&table[i].From, currency); err != nil {
+			return err
+		}
+		if err := inCurrency(&table[i].Increment, currency); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// incrementsFor returns the increment table of auction: its own when it has
+// one, then the one of its category, the service table otherwise.
+func (s *auctionService) incrementsFor(auction model.Auction) model.IncrementTable {
+	if len(auction.BidIncrements) > 0 {
+		return auction.BidIncrements
+	}
+	if len(auction.CategoryIncrements) > 0 {
+		return auction.CategoryIncrements
+	}
+	return s.increments
+}
+
+// formatOf returns the format of auction using its increment table.
+func (s *auctionService) formatOf(auction model.Auction) AuctionFormat {
+	format, err := formatWith(auction.Format, s.incrementsFor(auction))
+	if err != nil {
+		return EnglishFormat{Increments: s.incrementsFor(auction)}
+	}
+	return format
+}
@@ -0,0 +1,140 @@
+package service_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"auction-service/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIncrementTable(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    model.IncrementTable
+		wantErr bool
+	}{
+		{name: "bands", input: "0:1, 100:5,1000:10", want: model.IncrementTable{{From: usd(0), Increment: usd(1)}, {From: usd(100), Increment: usd(5)}, {From: usd(1000), Increment: usd(10)}}},
+		{name: "single band", input: "0:0.5", want: model.IncrementTable{{From: usd(0), Increment: usd(0.5)}}},
+		{name: "missing increment", input: "0:1,100", wantErr: true},
+		{name: "not a number", input: "0:one", wantErr: true},
+		{name: "not starting at zero", input: "10:1", wantErr: true},
+		{name: "out of order", input: "0:1,100:5,50:2", wantErr: true},
+		{name: "zero increment", input: "0:0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := service.ParseIncrementTable(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, service.ErrInvalidInput)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, table)
+		})
+	}
+}
+
+func TestIncrementTableBands(t *testing.T) {
+	for price, want := range map[float64]float64{0: 1, 99.99: 1, 100: 5, 999: 5, 1000: 10, 7500: 50} {
+		assert.Equal(t, usd(want), service.DefaultIncrements.Increment(usd(price)), "price %.2f", price)
+	}
+	assert.Equal(t, model.NewMoney(5, "JPY"), service.DefaultIncrements.Increment(model.NewMoney(250, "JPY")))
+}
+
+func TestBidIncrements(t *testing.T) {
+	custom := model.IncrementTable{{From: usd(0), Increment: usd(2)}, {From: usd(50), Increment: usd(10)}}
+
+	tests := []struct {
+		name        string
+		opts        []service.Option
+		increments  model.IncrementTable
+		price       float64
+		bid         float64
+		wantErr     bool
+		wantMinimum float64
+	}{
+		{name: "default table", price: 10, bid: 11, wantMinimum: 12},
+		{name: "below the increment", price: 150, bid: 154, wantErr: true},
+		{name: "default table upper band", price: 150, bid: 155, wantMinimum: 160},
+		{name: "service table", opts: []service.Option{service.WithIncrements(custom)}, price: 10, bid: 11, wantErr: true},
+		{name: "service table accepted", opts: []service.Option{service.WithIncrements(custom)}, price: 10, bid: 60, wantMinimum: 70},
+		{name: "auction table wins", opts: []service.Option{service.WithIncrements(custom)}, increments: model.IncrementTable{{From: usd(0), Increment: usd(0.5)}}, price: 10, bid: 10.5, wantMinimum: 11},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := repository.NewMemoryStore()
+			auctionService := service.NewAuctionService(store.Auctions(), store, tt.opts...)
+			auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen, CurrentPrice: usd(tt.price), BidIncrements: tt.increments})
+
+			_, err := auctionService.PlaceBid(auction.ID, 2, usd(tt.bid))
+			if tt.wantErr {
+				assert.ErrorIs(t, err, service.ErrInvalidInput)
+				return
+			}
+			require.NoError(t, err)
+
+			shown, err := auctionService.GetAuctionByID(auction.ID)
+			require.NoError(t, err)
+			assert.Equal(t, usd(tt.wantMinimum), *shown.NextMinimumBid)
+		})
+	}
+}
+
+func TestSubUnitIncrementsInZeroDecimalCurrency(t *testing.T) {
+	table, err := service.ParseIncrementTable("0:0.25")
+	require.NoError(t, err)
+	assert.Equal(t, model.NewMoney(1, "JPY"), table.Increment(model.NewMoney(500, "JPY")))
+
+	store := repository.NewMemoryStore()
+	auctionService := service.NewAuctionService(store.Auctions(), store, service.WithIncrements(table))
+	auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen, Currency: "JPY", CurrentPrice: model.NewMoney(500, "JPY")})
+
+	_, err = auctionService.PlaceBid(auction.ID, 2, model.NewMoney(500, "JPY"))
+	assert.ErrorIs(t, err, service.ErrInvalidInput, "a bid must move the price")
+
+	// With a 0 JPY increment the two proxies outbid each other forever.
+	_, err = auctionService.PlaceProxyBid(auction.ID, 2, model.NewMoney(600, "JPY"))
+	require.NoError(t, err)
+	updated, err := auctionService.PlaceProxyBid(auction.ID, 3, model.NewMoney(700, "JPY"))
+	require.NoError(t, err)
+	assert.Equal(t, model.NewMoney(601, "JPY"), updated.CurrentPrice)
+	shown, err := auctionService.GetAuctionByID(auction.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.NewMoney(602, "JPY"), *shown.NextMinimumBid)
+}
+
+func TestProxyBiddingUsesIncrementBands(t *testing.T) {
+	auctionService, store := newTestService()
+	auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen, CurrentPrice: usd(90)})
+
+	_, err := auctionService.PlaceProxyBid(auction.ID, 2, usd(300))
+	require.NoError(t, err)
+	updated, err := auctionService.PlaceBid(auction.ID, 3, usd(120))
+	require.NoError(t, err)
+
+	// The proxy answers 120 with the 100-1000 band increment of 5.
+	assert.Equal(t, usd(125), updated.CurrentPrice)
+	_, err = auctionService.PlaceProxyBid(auction.ID, 3, usd(127))
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+}
+
+func TestCreateAuctionRejectsInvalidIncrements(t *testing.T) {
+	auctionService, _ := newTestService()
+
+	_, err := auctionService.CreateAuction(model.Auction{Item: "Test Item", UserID: 1, CurrentPrice: usd(10), BidIncrements: model.IncrementTable{{From: usd(5), Increment: usd(1)}}})
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+
+	euros := model.IncrementTable{{From: model.Money{}, Increment: model.NewMoney(100, "EUR")}}
+	_, err = auctionService.CreateAuction(model.Auction{Item: "Test Item", UserID: 1, CurrentPrice: usd(10), BidIncrements: euros})
+	assert.ErrorIs(t, err, service.ErrInvalidInput, "the bands must be in the currency of the auction")
+
+	mixed := model.IncrementTable{{From: usd(0), Increment: usd(1)}, {From: model.NewMoney(10000, "EUR"), Increment: model.NewMoney(500, "EUR")}}
+	_, err = auctionService.CreateAuction(model.Auction{Item: "Test Item", UserID: 1, Currency: "EUR", BidIncrements: mixed})
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+)
+
+// RetractionRules decide when bidders may retract their own bids. Sellers
+// can cancel bids on their open auctions at any time.
+type RetractionRules struct {
+	// Window is how long after it was placed a bid can be retracted. Zero
+	// puts no limit on it.
+	Window time.Duration
+	// FinalPeriod is the time before the end of an auction in which bids can
+	// no longer be retracted. Zero allows retracting until the end.
+	FinalPeriod time.Duration
+}
+
+// DefaultRetractionRules allow retracting a bid within an hour of placing
+// it, but not in the last hour of the auction.
+var DefaultRetractionRules = RetractionRules{Window: time.Hour, FinalPeriod: time.Hour}
+
+// check returns ErrRetractionNotAllowed when bid can no longer be retracted
+// from auction at now.
+func (r RetractionRules) check(auction model.Auction, bid model.Bid, now time.Time) error {
+	if r.Window > 0 && now.Sub(bid.CreatedAt) > r.Window {
+		return fmt.Errorf("%w: bids can only be retracted within %s of being placed", ErrRetractionNotAllowed, r.Window)
+	}
+	if r.FinalPeriod > 0 && auction.EndsAt != nil && !now.Before(auction.EndsAt.Add(-r.FinalPeriod)) {
+		return fmt.Errorf("%w: bids cannot be retracted in the last %s of an auction", ErrRetractionNotAllowed, r.FinalPeriod)
+	}
+	return nil
+}
+
+// RetractBid withdraws a bid of bidderID, within the retraction rules.
+func (s *auctionService) RetractBid(bidderID, bidID int, reason string) (model.Auction, error) {
+	if bidderID <= 0 {
+		return model.Auction{}, fmt.Errorf("%w: bidder id is required", ErrInvalidInput)
+	}
+
+	var updated model.Auction
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		auction, bid, err := s.openBid(uow, bidID)
+		if err != nil {
+			return err
+		}
+		if bid.UserID != bidderID {
+			return fmt.Errorf("%w: only the bidder can retract a bid", ErrForbidden)
+		}
+		if err := s.retraction.check(auction, bid, s.now()); err != nil {
+			return err
+		}
+		updated, err = s.withdrawBid(uow, auction, bid, bidderID, model.BidAuditRetracted, strings.TrimSpace(reason))
+		return err
+	})
+	if err != nil {
+		return model.Auction{}, err
+	}
+	return updated, nil
+}
+
+// CancelBid lets the seller of an auction cancel a bid on it. A reason is
+// required and kept in the audit log.
+func (s *auctionService) CancelBid(sellerID, bidID int, reason string) (model.Auction, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return model.Auction{}, fmt.Errorf("%w: a reason is required", ErrInvalidInput)
+	}
+
+	var updated model.Auction
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		auction, bid, err := s.openBid(uow, bidID)
+		if err != nil {
+			return err
+		}
+		if auction.UserID != sellerID {
+			return ErrForbidden
+		}
+		updated, err = s.withdrawBid(uow, auction, bid, sellerID, model.BidAuditCancelled, reason)
+		return err
+	})
+	if err != nil {
+		return model.Auction{}, err
+	}
+	return updated, nil
+}
+
+// GetBidAudit returns the withdrawn bids log of an auction, oldest first.
+func (s *auctionService) GetBidAudit(auctionID int) ([]model.BidAuditEntry, error) {
+	var entries []model.BidAuditEntry
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		if _, err := uow.Auctions().GetAuctionByID(auctionID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+		var err error
+		entries, err = uow.BidAudit().GetEntriesByAuctionID(auctionID)
+		return err
+	})
+	if entries == nil {
+		entries = []model.BidAuditEntry{}
+	}
+	return entries, err
+}
+
+// openBid locks the auction of a bid, which must be open, and loads the bid,
+// which must be active. The bid is read again once the auction is locked, so
+// concurrent withdrawals of the same bid see each other.
+func (s *auctionService) openBid(uow repository.UnitOfWork, bidID int) (model.Auction, model.Bid, error) {
+	bid, err := uow.Bids().GetBidByID(bidID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.Auction{}, bid, ErrBidNotFound
+	}
+	if err != nil {
+		return model.Auction{}, bid, err
+	}
+	auction, err := lockAuction(uow, bid.AuctionID)
+	if err != nil {
+		return auction, bid, err
+	}
+	if bid, err = uow.Bids().GetBidByID(bidID); err != nil {
+		return auction, bid, err
+	}
+	if !auction.IsOpen() || auction.HasEnded(s.now()) {
+		return auction, bid, ErrAuctionClosed
+	}
+	if !bid.IsActive() {
+		return auction, bid, fmt.Errorf("%w: bid was already withdrawn", ErrInvalidInput)
+	}
+	return auction, bid, nil
+}
+
+// withdrawBid takes bid out of auction and puts the auction back in the state
+// it would be in without it. Later bids of the same bidder go too, as does
+// their proxy bid, which would otherwise bid again right away. In ascending
+// auctions the automatic bids placed after bid are voided and the price
+// falls back to the last remaining bid, after which the proxies bid again
+// against it. The withdrawal is written to the audit log and announced.
+func (s *auctionService) withdrawBid(uow repository.UnitOfWork, auction model.Auction, bid model.Bid, actorID int, action, reason string) (model.Auction, error) {
+	bids, err := uow.Bids().GetBidsByAuctionID(auction.ID)
+	if err != nil {
+		return auction, err
+	}
+	ascending := FormatOf(auction).Rules().Ascending
+
+	withdrawn := []int{bid.ID}
+	var voided []int
+	for _, later := range bids {
+		if later.ID <= bid.ID || !later.IsActive() {
+			continue
+		}
+		switch {
+		case later.UserID == bid.UserID:
+			withdrawn = append(withdrawn, later.ID)
+		case later.Automatic && ascending:
+			voided = append(voided, later.ID)
+		}
+	}
+	status := model.BidStatusRetracted
+	if action == model.BidAuditCancelled {
+		status = model.BidStatusCancelled
+	}
+	if err := uow.Bids().SetBidStatus(withdrawn, status); err != nil {
+		return auction, err
+	}
+	if err := uow.Bids().SetBidStatus(voided, model.BidStatusVoided); err != nil {
+		return auction, err
+	}
+	if err := uow.ProxyBids().DeleteProxyBid(auction.ID, bid.UserID); err != nil {
+		return auction, err
+	}
+
+	entry := model.BidAuditEntry{
+		AuctionID: auction.ID,
+		BidID:     bid.ID,
+		BidderID:  bid.UserID,
+		ActorID:   actorID,
+		Action:    action,
+		Reason:    reason,
+		Amount:    bid.Amount,
+		Withdrawn: append(withdrawn, voided...),
+	}
+	if _, err := uow.BidAudit().AppendEntry(entry); err != nil {
+		return auction, err
+	}
+	event := model.Event{Type: model.EventBidRetracted, AuctionID: auction.ID, UserID: bid.UserID, BidID: bid.ID, Amount: &bid.Amount, Action: action, Reason: reason}
+	if err := enqueue(uow, event); err != nil {
+		return auction, err
+	}
+	if !ascending {
+		return auction, nil
+	}
+
+	auction.CurrentPrice = auction.StartPrice
+	leading, err := uow.Bids().GetLatestBid(auction.ID)
+	if err == nil {
+		auction.CurrentPrice = leading.Amount
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return auction, err
+	}
+	if err := uow.Auctions().UpdateAuction(auction); err != nil {
+		return auction, err
+	}
+	if err := enqueue(uow, model.Event{Type: model.EventPriceChanged, AuctionID: auction.ID, Amount: &auction.CurrentPrice}); err != nil {
+		return auction, err
+	}
+	return s.applyProxyBids(uow, auction)
+}
+
+// activeBids returns the bids that were not withdrawn.
+func activeBids(bids []model.Bid) []model.Bid {
+	var active []model.Bid
+	for _, bid := range bids {
+		if bid.IsActive() {
+			active = append(active, bid)
+		}
+	}
+	return active
+}
@@ -0,0 +1,261 @@
+package service_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"auction-service/internal/service"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// activeHistory returns the bids of the auction that still count, as
+// placedBids.
+func activeHistory(t *testing.T, store *repository.MemoryStore, auctionID int) []placedBid {
+	bids, err := store.Bids().GetBidsByAuctionID(auctionID)
+	require.NoError(t, err)
+	var history []placedBid
+	for _, bid := range bids {
+		if bid.IsActive() {
+			history = append(history, placedBid{bid.UserID, float64(bid.Amount.Minor) / 100, bid.Automatic})
+		}
+	}
+	return history
+}
+
+// latestBidOf returns the ID of the last bid userID placed on the auction.
+func latestBidOf(t *testing.T, store *repository.MemoryStore, auctionID, userID int) int {
+	bids, err := store.Bids().GetBidsByAuctionID(auctionID)
+	require.NoError(t, err)
+	for i := len(bids) - 1; i >= 0; i-- {
+		if bids[i].UserID == userID {
+			return bids[i].ID
+		}
+	}
+	t.Fatalf("user %d has no bids", userID)
+	return 0
+}
+
+func TestRetractMistypedBid(t *testing.T) {
+	auctionService, store := newTestService()
+	auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen, CurrentPrice: usd(10), StartPrice: usd(10)})
+
+	_, err := auctionService.PlaceBid(auction.ID, 2, usd(20))
+	require.NoError(t, err)
+	_, err = auctionService.PlaceBid(auction.ID, 3, usd(1000))
+	require.NoError(t, err)
+	mistyped := latestBidOf(t, store, auction.ID, 3)
+
+	updated, err := auctionService.RetractBid(3, mistyped, "meant 100")
+
+	require.NoError(t, err)
+	assert.Equal(t, usd(20), updated.CurrentPrice)
+	assert.Equal(t, []placedBid{{2, 20, false}}, activeHistory(t, store, auction.ID))
+	bid, err := store.Bids().GetBidByID(mistyped)
+	require.NoError(t, err)
+	assert.Equal(t, model.BidStatusRetracted, bid.Status)
+
+	entries, err := auctionService.GetBidAudit(auction.ID)
+	require.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, model.BidAuditRetracted, entries[0].Action)
+		assert.Equal(t, 3, entries[0].ActorID)
+		assert.Equal(t, "meant 100", entries[0].Reason)
+		assert.Equal(t, usd(1000), entries[0].Amount)
+	}
+	events := outboxEvents(t, store)
+	retracted, repriced := events[len(events)-2], events[len(events)-1]
+	assert.Equal(t, model.EventBidRetracted, retracted.Type)
+	assert.Equal(t, mistyped, retracted.BidID)
+	assert.Equal(t, model.BidAuditRetracted, retracted.Action)
+	assert.Equal(t, model.EventPriceChanged, repriced.Type)
+	assert.Equal(t, usd(20), *repriced.Amount)
+
+	// The bidder can bid again after retracting.
+	updated, err = auctionService.PlaceBid(auction.ID, 3, usd(100))
+	require.NoError(t, err)
+	assert.Equal(t, usd(100), updated.CurrentPrice)
+}
+
+func TestWithdrawBidRebuildsProxies(t *testing.T) {
+	tests := []struct {
+		name     string
+		steps    []bidStep
+		withdraw int // user whose first bid is withdrawn
+		cancel   bool
+		want     []placedBid
+		price    float64
+	}{
+		{
+			name:     "answering automatic bids are voided",
+			steps:    []bidStep{{userID: 2, amount: 200, proxy: true}, {userID: 3, amount: 100, proxy: true}},
+			withdraw: 3,
+			want:     []placedBid{{2, 11, true}},
+			price:    11,
+		},
+		{
+			name:     "manual bids of others stay",
+			steps:    []bidStep{{userID: 2, amount: 50, proxy: true}, {userID: 3, amount: 20}, {userID: 4, amount: 70}},
+			withdraw: 3,
+			cancel:   true,
+			want:     []placedBid{{2, 11, true}, {4, 70, false}},
+			price:    70,
+		},
+		{
+			name:     "remaining proxies bid again",
+			steps:    []bidStep{{userID: 2, amount: 50, proxy: true}, {userID: 3, amount: 40, proxy: true}, {userID: 4, amount: 45, proxy: true}},
+			withdraw: 2,
+			cancel:   true,
+			want:     []placedBid{{4, 11, true}, {3, 40, true}, {4, 41, true}},
+			price:    41,
+		},
+		{
+			name:     "no bids left",
+			steps:    []bidStep{{userID: 2, amount: 50}},
+			withdraw: 2,
+			price:    10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auctionService, store := newTestService()
+			auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen, CurrentPrice: usd(10), StartPrice: usd(10)})
+			for _, step := range tt.steps {
+				var err error
+				if step.proxy {
+					_, err = auctionService.PlaceProxyBid(auction.ID, step.userID, usd(step.amount))
+				} else {
+					_, err = auctionService.PlaceBid(auction.ID, step.userID, usd(step.amount))
+				}
+				require.NoError(t, err)
+			}
+			bids, err := store.Bids().GetBidsByAuctionID(auction.ID)
+			require.NoError(t, err)
+			var target int
+			for _, bid := range bids {
+				if bid.UserID == tt.withdraw {
+					target = bid.ID
+					break
+				}
+			}
+
+			var updated model.Auction
+			if tt.cancel {
+				updated, err = auctionService.CancelBid(1, target, "shill bidding")
+			} else {
+				updated, err = auctionService.RetractBid(tt.withdraw, target, "")
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, activeHistory(t, store, auction.ID))
+			assert.Equal(t, usd(tt.price), updated.CurrentPrice)
+			proxies, err := store.ProxyBids().GetProxyBidsByAuctionID(auction.ID)
+			require.NoError(t, err)
+			for _, proxy := range proxies {
+				assert.NotEqual(t, tt.withdraw, proxy.UserID)
+			}
+		})
+	}
+}
+
+func TestRetractionRules(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	store := repository.NewMemoryStore()
+	auctionService := service.NewAuctionService(store.Auctions(), store, service.WithClock(clock.Now),
+		service.WithRetractionRules(service.RetractionRules{Window: time.Hour, FinalPeriod: time.Hour}))
+	ends := clock.Now().Add(3 * time.Hour)
+	auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen, CurrentPrice: usd(10), StartPrice: usd(10), EndsAt: &ends})
+
+	_, err := auctionService.PlaceBid(auction.ID, 2, usd(20))
+	require.NoError(t, err)
+	bidID := latestBidOf(t, store, auction.ID, 2)
+
+	_, err = auctionService.RetractBid(3, bidID, "")
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	_, err = auctionService.RetractBid(2, 999, "")
+	assert.ErrorIs(t, err, service.ErrBidNotFound)
+
+	clock.Set(clock.Now().Add(90 * time.Minute))
+	_, err = auctionService.RetractBid(2, bidID, "")
+	assert.ErrorIs(t, err, service.ErrRetractionNotAllowed)
+
+	_, err = auctionService.PlaceBid(auction.ID, 3, usd(30))
+	require.NoError(t, err)
+	clock.Set(ends.Add(-30 * time.Minute))
+	_, err = auctionService.RetractBid(3, latestBidOf(t, store, auction.ID, 3), "")
+	assert.ErrorIs(t, err, service.ErrRetractionNotAllowed)
+
+	entries, err := auctionService.GetBidAudit(auction.ID)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestWithdrawBidConcurrently(t *testing.T) {
+	auctionService, store := newTestService()
+	auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen, CurrentPrice: usd(10), StartPrice: usd(10)})
+	_, err := auctionService.PlaceBid(auction.ID, 2, usd(20))
+	require.NoError(t, err)
+	bidID := latestBidOf(t, store, auction.ID, 2)
+
+	var wg sync.WaitGroup
+	var withdrawn atomic.Int32
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := auctionService.RetractBid(2, bidID, ""); err == nil {
+				withdrawn.Add(1)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := auctionService.CancelBid(1, bidID, "duplicate"); err == nil {
+				withdrawn.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), withdrawn.Load())
+	entries, err := auctionService.GetBidAudit(auction.ID)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestCancelBid(t *testing.T) {
+	auctionService, store := newTestService()
+	auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen, CurrentPrice: usd(10), StartPrice: usd(10)})
+	_, err := auctionService.PlaceBid(auction.ID, 2, usd(20))
+	require.NoError(t, err)
+	bidID := latestBidOf(t, store, auction.ID, 2)
+
+	_, err = auctionService.CancelBid(1, bidID, " ")
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+	_, err = auctionService.CancelBid(2, bidID, "not mine")
+	assert.ErrorIs(t, err, service.ErrForbidden)
+
+	_, err = auctionService.CancelBid(1, bidID, "bidder has unpaid items")
+	require.NoError(t, err)
+	bid, err := store.Bids().GetBidByID(bidID)
+	require.NoError(t, err)
+	assert.Equal(t, model.BidStatusCancelled, bid.Status)
+	_, err = auctionService.CancelBid(1, bidID, "again")
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+
+	entries, err := auctionService.GetBidAudit(auction.ID)
+	require.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, model.BidAuditCancelled, entries[0].Action)
+		assert.Equal(t, 1, entries[0].ActorID)
+		assert.Equal(t, 2, entries[0].BidderID)
+	}
+
+	closed, err := auctionService.CloseAuction(1, auction.ID)
+	require.NoError(t, err)
+	assert.Zero(t, closed.WinnerID)
+}
@@ -0,0 +1,426 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+)
+
+var (
+	// ErrCategoryNotFound is returned when the category does not exist.
+	ErrCategoryNotFound = errors.New("category not found")
+	// ErrCategoryInUse is returned when deleting a category that still has
+	// subcategories or auctions.
+	ErrCategoryInUse = errors.New("category is in use")
+)
+
+var (
+	attributeName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	slugSeparator = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// CategoryService manages the category tree auctions are listed in. Each
+// category adds attributes to those of its ancestors, and can override the
+// increment table for the auctions listed under it.
+type CategoryService interface {
+	// GetCategoryTree returns the top-level categories with their
+	// subcategories as Children, ordered by ID at every level.
+	GetCategoryTree() ([]model.Category, error)
+	// GetCategory returns a category with its full Schema and its direct
+	// subcategories.
+	GetCategory(id int) (model.Category, error)
+	// CreateCategory stores a new category. The slug is made from the name
+	// when none is given.
+	CreateCategory(category model.Category) (model.Category, error)
+	// UpdateCategory changes every field of a category. Auctions listed
+	// before the change keep the increment table they were listed with.
+	UpdateCategory(category model.Category) (model.Category, error)
+	// DeleteCategory deletes a category without subcategories or auctions.
+	DeleteCategory(id int) error
+}
+
+type categoryService struct {
+	txManager repository.TxManager
+}
+
+// Ensure categoryService implements CategoryService
+var _ CategoryService = (*categoryService)(nil)
+
+// NewCategoryService creates a CategoryService working in txManager
+// transactions.
+func NewCategoryService(txManager repository.TxManager) CategoryService {
+	return &categoryService{txManager: txManager}
+}
+
+func (s *categoryService) GetCategoryTree() ([]model.Category, error) {
+	var roots []model.Category
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		tree, err := loadCategoryTree(uow)
+		if err != nil {
+			return err
+		}
+		roots = tree.subtree(nil)
+		return nil
+	})
+	return roots, err
+}
+
+func (s *categoryService) GetCategory(id int) (model.Category, error) {
+	var category model.Category
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		tree, err := loadCategoryTree(uow)
+		if err != nil {
+			return err
+		}
+		found, ok := tree.byID[id]
+		if !ok {
+			return ErrCategoryNotFound
+		}
+		category = found
+		category.Schema = tree.schema(id)
+		for _, childID := range tree.children[id] {
+			category.Children = append(category.Children, tree.byID[childID])
+		}
+		return nil
+	})
+	return category, err
+}
+
+func (s *categoryService) CreateCategory(category model.Category) (model.Category, error) {
+	var created model.Category
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		tree, err := loadCategoryTree(uow)
+		if err != nil {
+			return err
+		}
+		category.ID = 0
+		if err := tree.validate(&category); err != nil {
+			return err
+		}
+		created, err = uow.Categories().CreateCategory(category)
+		return err
+	})
+	return created, err
+}
+
+func (s *categoryService) UpdateCategory(category model.Category) (model.Category, error) {
+	var updated model.Category
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		tree, err := loadCategoryTree(uow)
+		if err != nil {
+			return err
+		}
+		existing, ok := tree.byID[category.ID]
+		if !ok {
+			return ErrCategoryNotFound
+		}
+		if err := tree.validate(&category); err != nil {
+			return err
+		}
+		category.CreatedAt = existing.CreatedAt
+		if err := uow.Categories().UpdateCategory(category); err != nil {
+			return err
+		}
+		updated, err = uow.Categories().GetCategoryByID(category.ID)
+		return err
+	})
+	return updated, err
+}
+
+func (s *categoryService) DeleteCategory(id int) error {
+	return s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		tree, err := loadCategoryTree(uow)
+		if err != nil {
+			return err
+		}
+		if _, ok := tree.byID[id]; !ok {
+			return ErrCategoryNotFound
+		}
+		if len(tree.children[id]) > 0 {
+			return fmt.Errorf("%w: it has subcategories", ErrCategoryInUse)
+		}
+		auctions, err := uow.Auctions().FindAuctions(repository.AuctionFilter{CategoryIDs: []int{id}})
+		if err != nil {
+			return err
+		}
+		if len(auctions) > 0 {
+			return fmt.Errorf("%w: auctions are listed in it", ErrCategoryInUse)
+		}
+		return uow.Categories().DeleteCategory(id)
+	})
+}
+
+// categoryTree indexes every category by ID and parent. The tree is read in
+// full whenever it is needed; it is small and rarely changes.
+type categoryTree struct {
+	byID map[int]model.Category
+	// children maps a category ID, 0 for the top level, to the IDs of its
+	// subcategories in ID order.
+	children map[int][]int
+}
+
+func loadCategoryTree(uow repository.UnitOfWork) (categoryTree, error) {
+	categories, err := uow.Categories().GetCategories()
+	if err != nil {
+		return categoryTree{}, err
+	}
+	tree := categoryTree{byID: map[int]model.Category{}, children: map[int][]int{}}
+	for _, category := range categories {
+		tree.byID[category.ID] = category
+		parent := 0
+		if category.ParentID != nil {
+			parent = *category.ParentID
+		}
+		tree.children[parent] = append(tree.children[parent], category.ID)
+	}
+	return tree, nil
+}
+
+// find returns the category with the given ID or slug.
+func (t categoryTree) find(ref string) (model.Category, bool) {
+	if id, err := strconv.Atoi(ref); err == nil {
+		category, ok := t.byID[id]
+		return category, ok
+	}
+	for _, category := range t.byID {
+		if category.Slug == ref {
+			return category, true
+		}
+	}
+	return model.Category{}, false
+}
+
+// path returns the ancestors of category id, top-level first, ending with
+// the category itself.
+func (t categoryTree) path(id int) []model.Category {
+	var path []model.Category
+	for category, ok := t.byID[id]; ok; {
+		path = append([]model.Category{category}, path...)
+		if category.ParentID == nil {
+			break
+		}
+		category, ok = t.byID[*category.ParentID]
+	}
+	return path
+}
+
+// schema returns every attribute of category id, inherited ones first.
+func (t categoryTree) schema(id int) []model.AttributeSchema {
+	var schema []model.AttributeSchema
+	for _, category := range t.path(id) {
+		schema = append(schema, category.Attributes...)
+	}
+	return schema
+}
+
+// increments returns the increment table of the nearest category on the path
+// to id that has one.
+func (t categoryTree) increments(id int) model.IncrementTable {
+	path := t.path(id)
+	for i := len(path) - 1; i >= 0; i-- {
+		if len(path[i].BidIncrements) > 0 {
+			return path[i].BidIncrements
+		}
+	}
+	return nil
+}
+
+// descendants returns id and the IDs of every category below it.
+func (t categoryTree) descendants(id int) []int {
+	ids := []int{id}
+	for _, childID := range t.children[id] {
+		ids = append(ids, t.descendants(childID)...)
+	}
+	return ids
+}
+
+// subtree returns the subcategories of parent, or the top-level categories
+// when parent is nil, with their own subcategories filled in.
+func (t categoryTree) subtree(parent *int) []model.Category {
+	key := 0
+	if parent != nil {
+		key = *parent
+	}
+	categories := []model.Category{}
+	for _, id := range t.children[key] {
+		category := t.byID[id]
+		category.Children = t.subtree(&id)
+		if len(category.Children) == 0 {
+			category.Children = nil
+		}
+		categories = append(categories, category)
+	}
+	return categories
+}
+
+// validate checks a new or changed category against the rest of the tree and
+// fills in its slug.
+func (t categoryTree) validate(category *model.Category) error {
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+	if len(category.Name) > 100 {
+		return fmt.Errorf("%w: name is too long", ErrInvalidInput)
+	}
+	slug := category.Slug
+	if slug == "" {
+		slug = category.Name
+	}
+	category.Slug = strings.Trim(slugSeparator.ReplaceAllString(strings.ToLower(slug), "-"), "-")
+	if category.Slug == "" || len(category.Slug) > 100 {
+		return fmt.Errorf("%w: slug %q is not valid", ErrInvalidInput, slug)
+	}
+	if _, err := strconv.Atoi(category.Slug); err == nil {
+		return fmt.Errorf("%w: slug cannot be a number", ErrInvalidInput)
+	}
+	if other, ok := t.find(category.Slug); ok && other.ID != category.ID {
+		return fmt.Errorf("%w: slug %q is taken", ErrInvalidInput, category.Slug)
+	}
+
+	if category.ParentID != nil {
+		if _, ok := t.byID[*category.ParentID]; !ok {
+			return fmt.Errorf("%w: parent category %d does not exist", ErrInvalidInput, *category.ParentID)
+		}
+		if category.ID != 0 {
+			for _, ancestor := range t.path(*category.ParentID) {
+				if ancestor.ID == category.ID {
+					return fmt.Errorf("%w: a category cannot be moved below itself", ErrInvalidInput)
+				}
+			}
+		}
+	}
+
+	// Attribute names must be unique along every path through the tree, so
+	// both the ancestors and, for existing categories, the descendants
+	// count.
+	taken := map[string]bool{}
+	if category.ParentID != nil {
+		for _, attribute := range t.schema(*category.ParentID) {
+			taken[attribute.Name] = true
+		}
+	}
+	if category.ID != 0 {
+		for _, id := range t.descendants(category.ID)[1:] {
+			for _, attribute := range t.byID[id].Attributes {
+				taken[attribute.Name] = true
+			}
+		}
+	}
+	for i := range category.Attributes {
+		attribute := &category.Attributes[i]
+		attribute.Name = strings.TrimSpace(attribute.Name)
+		if !attributeName.MatchString(attribute.Name) {
+			return fmt.Errorf("%w: attribute name %q must be lower case letters, digits and underscores", ErrInvalidInput, attribute.Name)
+		}
+		if taken[attribute.Name] {
+			return fmt.Errorf("%w: attribute %q is defined twice", ErrInvalidInput, attribute.Name)
+		}
+		taken[attribute.Name] = true
+		switch attribute.Type {
+		case model.AttributeTypeText, model.AttributeTypeNumber, model.AttributeTypeBoolean:
+			attribute.Options = nil
+		case model.AttributeTypeEnum:
+			if len(attribute.Options) == 0 {
+				return fmt.Errorf("%w: enum attribute %q needs options", ErrInvalidInput, attribute.Name)
+			}
+			for i, option := range attribute.Options {
+				option = strings.TrimSpace(option)
+				if option == "" || slices.Contains(attribute.Options[:i], option) {
+					return fmt.Errorf("%w: options of attribute %q must be distinct and not empty", ErrInvalidInput, attribute.Name)
+				}
+				attribute.Options[i] = option
+			}
+		default:
+			return fmt.Errorf("%w: attribute %q has unknown type %q", ErrInvalidInput, attribute.Name, attribute.Type)
+		}
+	}
+	return validateIncrements(category.BidIncrements)
+}
+
+// categorize checks the category and attributes of auction, normalizes the
+// attribute values and copies the increment table of the category.
+func categorize(uow repository.UnitOfWork, auction *model.Auction) error {
+	auction.CategoryIncrements = nil
+	if auction.CategoryID == nil {
+		if len(auction.Attributes) > 0 {
+			return fmt.Errorf("%w: attributes need a category", ErrInvalidInput)
+		}
+		auction.Attributes = nil
+		return nil
+	}
+	tree, err := loadCategoryTree(uow)
+	if err != nil {
+		return err
+	}
+	if _, ok := tree.byID[*auction.CategoryID]; !ok {
+		return fmt.Errorf("%w: category %d does not exist", ErrInvalidInput, *auction.CategoryID)
+	}
+	attributes, err := validateAttributes(tree.schema(*auction.CategoryID), auction.Attributes)
+	if err != nil {
+		return err
+	}
+	auction.Attributes = attributes
+	auction.CategoryIncrements = tree.increments(*auction.CategoryID)
+	return nil
+}
+
+// validateAttributes checks values against schema and returns them
+// normalized: trimmed, with numbers and booleans in canonical form and empty
+// values dropped.
+func validateAttributes(schema []model.AttributeSchema, values map[string]string) (map[string]string, error) {
+	for name := range values {
+		if !slices.ContainsFunc(schema, func(attribute model.AttributeSchema) bool { return attribute.Name == name }) {
+			return nil, fmt.Errorf("%w: unknown attribute %q", ErrInvalidInput, name)
+		}
+	}
+	normalized := map[string]string{}
+	for _, attribute := range schema {
+		value := strings.TrimSpace(values[attribute.Name])
+		if value == "" {
+			if attribute.Required {
+				return nil, fmt.Errorf("%w: attribute %q is required", ErrInvalidInput, attribute.Name)
+			}
+			continue
+		}
+		value, err := attributeValue(attribute, value)
+		if err != nil {
+			return nil, err
+		}
+		normalized[attribute.Name] = value
+	}
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+	return normalized, nil
+}
+
+// attributeValue returns value in the canonical form of its type, so that
+// filtering can compare strings.
+func attributeValue(attribute model.AttributeSchema, value string) (string, error) {
+	switch attribute.Type {
+	case model.AttributeTypeNumber:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", fmt.Errorf("%w: attribute %q must be a number", ErrInvalidInput, attribute.Name)
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), nil
+	case model.AttributeTypeBoolean:
+		boolean, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("%w: attribute %q must be true or false", ErrInvalidInput, attribute.Name)
+		}
+		return strconv.FormatBool(boolean), nil
+	case model.AttributeTypeEnum:
+		if !slices.Contains(attribute.Options, value) {
+			return "", fmt.Errorf("%w: attribute %q must be one of %s", ErrInvalidInput, attribute.Name, strings.Join(attribute.Options, ", "))
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,225 @@
+package service_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"auction-service/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedCategories creates Fashion > Shoes > Sneakers, where Fashion has a
+// brand, Shoes a size and Sneakers a condition, and Books next to Fashion.
+func seedCategories(t *testing.T, categoryService service.CategoryService) (fashion, shoes, sneakers, books model.Category) {
+	t.Helper()
+	var err error
+	fashion, err = categoryService.CreateCategory(model.Category{Name: "Fashion", Attributes: []model.AttributeSchema{
+		{Name: "brand", Type: model.AttributeTypeText},
+	}})
+	require.NoError(t, err)
+	shoes, err = categoryService.CreateCategory(model.Category{ParentID: &fashion.ID, Name: "Shoes", Attributes: []model.AttributeSchema{
+		{Name: "size", Type: model.AttributeTypeNumber, Required: true},
+	}, BidIncrements: model.IncrementTable{{From: usd(0), Increment: usd(5)}}})
+	require.NoError(t, err)
+	sneakers, err = categoryService.CreateCategory(model.Category{ParentID: &shoes.ID, Name: "Sneakers & Trainers", Attributes: []model.AttributeSchema{
+		{Name: "condition", Type: model.AttributeTypeEnum, Options: []string{"new", "used"}},
+		{Name: "limited", Type: model.AttributeTypeBoolean},
+	}})
+	require.NoError(t, err)
+	books, err = categoryService.CreateCategory(model.Category{Name: "Books"})
+	require.NoError(t, err)
+	return fashion, shoes, sneakers, books
+}
+
+func TestCategoryTree(t *testing.T) {
+	store := repository.NewMemoryStore()
+	categoryService := service.NewCategoryService(store)
+	fashion, shoes, sneakers, books := seedCategories(t, categoryService)
+
+	assert.Equal(t, "fashion", fashion.Slug)
+	assert.Equal(t, "sneakers-trainers", sneakers.Slug)
+
+	tree, err := categoryService.GetCategoryTree()
+	require.NoError(t, err)
+	if assert.Len(t, tree, 2) {
+		assert.Equal(t, fashion.ID, tree[0].ID)
+		assert.Equal(t, books.ID, tree[1].ID)
+		assert.Empty(t, tree[1].Children)
+		if assert.Len(t, tree[0].Children, 1) && assert.Len(t, tree[0].Children[0].Children, 1) {
+			assert.Equal(t, sneakers.ID, tree[0].Children[0].Children[0].ID)
+		}
+	}
+
+	category, err := categoryService.GetCategory(sneakers.ID)
+	require.NoError(t, err)
+	var names []string
+	for _, attribute := range category.Schema {
+		names = append(names, attribute.Name)
+	}
+	assert.Equal(t, []string{"brand", "size", "condition", "limited"}, names)
+
+	category, err = categoryService.GetCategory(shoes.ID)
+	require.NoError(t, err)
+	if assert.Len(t, category.Children, 1) {
+		assert.Equal(t, sneakers.ID, category.Children[0].ID)
+	}
+
+	_, err = categoryService.GetCategory(999)
+	assert.ErrorIs(t, err, service.ErrCategoryNotFound)
+}
+
+func TestCategoryValidation(t *testing.T) {
+	store := repository.NewMemoryStore()
+	categoryService := service.NewCategoryService(store)
+	fashion, shoes, sneakers, books := seedCategories(t, categoryService)
+	missing := 999
+
+	tests := []struct {
+		name     string
+		category model.Category
+	}{
+		{"no name", model.Category{Name: " "}},
+		{"taken slug", model.Category{Name: "Other", Slug: "Books"}},
+		{"numeric slug", model.Category{Name: "2024"}},
+		{"unknown parent", model.Category{ParentID: &missing, Name: "Orphan"}},
+		{"bad attribute name", model.Category{Name: "Toys", Attributes: []model.AttributeSchema{{Name: "Age Range", Type: model.AttributeTypeText}}}},
+		{"unknown attribute type", model.Category{Name: "Toys", Attributes: []model.AttributeSchema{{Name: "age", Type: "date"}}}},
+		{"enum without options", model.Category{Name: "Toys", Attributes: []model.AttributeSchema{{Name: "age", Type: model.AttributeTypeEnum}}}},
+		{"attribute of an ancestor", model.Category{ParentID: &shoes.ID, Name: "Boots", Attributes: []model.AttributeSchema{{Name: "brand", Type: model.AttributeTypeText}}}},
+		{"bad increments", model.Category{Name: "Toys", BidIncrements: model.IncrementTable{{From: usd(10), Increment: usd(1)}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := categoryService.CreateCategory(tt.category)
+			assert.ErrorIs(t, err, service.ErrInvalidInput)
+		})
+	}
+
+	// Moving Fashion below one of its descendants would make a cycle.
+	fashion.ParentID = &sneakers.ID
+	_, err := categoryService.UpdateCategory(fashion)
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+
+	// Fashion cannot take an attribute its descendants already have.
+	fashion.ParentID = nil
+	fashion.Attributes = append(fashion.Attributes, model.AttributeSchema{Name: "size", Type: model.AttributeTypeText})
+	_, err = categoryService.UpdateCategory(fashion)
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+
+	// Moving Shoes below Books is fine and keeps its subcategories.
+	shoes.ParentID = &books.ID
+	shoes.Name = "Shoe Books"
+	shoes.Slug = ""
+	updated, err := categoryService.UpdateCategory(shoes)
+	require.NoError(t, err)
+	assert.Equal(t, "shoe-books", updated.Slug)
+	category, err := categoryService.GetCategory(sneakers.ID)
+	require.NoError(t, err)
+	assert.Len(t, category.Schema, 3)
+
+	_, err = categoryService.UpdateCategory(model.Category{ID: 999, Name: "Ghost"})
+	assert.ErrorIs(t, err, service.ErrCategoryNotFound)
+}
+
+func TestDeleteCategory(t *testing.T) {
+	store := repository.NewMemoryStore()
+	categoryService := service.NewCategoryService(store)
+	_, shoes, sneakers, books := seedCategories(t, categoryService)
+	seedAuction(t, store, model.Auction{Item: "Runners", UserID: 1, CategoryID: &sneakers.ID})
+
+	assert.ErrorIs(t, categoryService.DeleteCategory(shoes.ID), service.ErrCategoryInUse)
+	assert.ErrorIs(t, categoryService.DeleteCategory(sneakers.ID), service.ErrCategoryInUse)
+	assert.ErrorIs(t, categoryService.DeleteCategory(999), service.ErrCategoryNotFound)
+
+	require.NoError(t, categoryService.DeleteCategory(books.ID))
+	_, err := categoryService.GetCategory(books.ID)
+	assert.ErrorIs(t, err, service.ErrCategoryNotFound)
+}
+
+func TestAuctionAttributes(t *testing.T) {
+	auctionService, store := newTestService()
+	categoryService := service.NewCategoryService(store)
+	_, _, sneakers, books := seedCategories(t, categoryService)
+
+	invalid := []model.Auction{
+		{Item: "No category", UserID: 1, Attributes: map[string]string{"size": "42"}},
+		{Item: "Unknown category", UserID: 1, CategoryID: new(int)},
+		{Item: "Missing size", UserID: 1, CategoryID: &sneakers.ID},
+		{Item: "Bad size", UserID: 1, CategoryID: &sneakers.ID, Attributes: map[string]string{"size": "large"}},
+		{Item: "Bad condition", UserID: 1, CategoryID: &sneakers.ID, Attributes: map[string]string{"size": "42", "condition": "worn"}},
+		{Item: "Bad boolean", UserID: 1, CategoryID: &sneakers.ID, Attributes: map[string]string{"size": "42", "limited": "maybe"}},
+		{Item: "Unknown attribute", UserID: 1, CategoryID: &sneakers.ID, Attributes: map[string]string{"size": "42", "color": "red"}},
+	}
+	for _, auction := range invalid {
+		_, err := auctionService.CreateAuction(auction)
+		assert.ErrorIs(t, err, service.ErrInvalidInput, auction.Item)
+	}
+
+	created, err := auctionService.CreateAuction(model.Auction{Item: "Runners", UserID: 1, CurrentPrice: usd(10), CategoryID: &sneakers.ID,
+		Attributes: map[string]string{"size": " 42.50 ", "limited": "1", "brand": ""}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"size": "42.5", "limited": "true"}, created.Attributes)
+
+	// Shoes raise bids by 5, and the auction keeps that after the category
+	// changes.
+	shoes, err := categoryService.GetCategory(*sneakers.ParentID)
+	require.NoError(t, err)
+	shoes.BidIncrements = model.IncrementTable{{From: usd(0), Increment: usd(50)}}
+	_, err = categoryService.UpdateCategory(shoes)
+	require.NoError(t, err)
+	fetched, err := auctionService.GetAuctionByID(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, usd(15), *fetched.NextMinimumBid)
+
+	// Attributes can change while the auction runs, the category only
+	// until the first bid.
+	_, err = auctionService.PlaceBid(created.ID, 2, usd(15))
+	require.NoError(t, err)
+	updated, err := auctionService.UpdateAuction(1, model.AuctionUpdate{ID: created.ID, Item: "Runners", CategoryID: &sneakers.ID,
+		Attributes: map[string]string{"size": "43", "condition": "used"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"size": "43", "condition": "used"}, updated.Attributes)
+	fetched, err = auctionService.GetAuctionByID(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, usd(20), *fetched.NextMinimumBid)
+
+	_, err = auctionService.UpdateAuction(1, model.AuctionUpdate{ID: created.ID, Item: "Runners", CategoryID: &books.ID})
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+}
+
+func TestFindAuctions(t *testing.T) {
+	auctionService, store := newTestService()
+	categoryService := service.NewCategoryService(store)
+	fashion, shoes, sneakers, books := seedCategories(t, categoryService)
+	seed := func(item string, category int, attributes map[string]string) model.Auction {
+		return seedAuction(t, store, model.Auction{Item: item, UserID: 1, Status: model.AuctionStatusOpen, CategoryID: &category, Attributes: attributes})
+	}
+	hat := seed("Hat", fashion.ID, map[string]string{"brand": "Acme"})
+	boots := seed("Boots", shoes.ID, map[string]string{"brand": "Acme", "size": "42"})
+	runners := seed("Runners", sneakers.ID, map[string]string{"size": "42.5", "condition": "new"})
+	seed("Novel", books.ID, nil)
+
+	ids := func(filter service.AuctionFilter) []int {
+		t.Helper()
+		auctions, err := auctionService.FindAuctions(filter)
+		require.NoError(t, err)
+		ids := []int{}
+		for _, auction := range auctions {
+			ids = append(ids, auction.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []int{hat.ID, boots.ID, runners.ID}, ids(service.AuctionFilter{Category: "fashion"}))
+	assert.Equal(t, []int{boots.ID, runners.ID}, ids(service.AuctionFilter{Category: "2"}))
+	assert.Equal(t, []int{hat.ID, boots.ID}, ids(service.AuctionFilter{Attributes: map[string]string{"brand": "Acme"}}))
+	assert.Equal(t, []int{runners.ID}, ids(service.AuctionFilter{Category: "shoes", Attributes: map[string]string{"size": "42.50"}}))
+	assert.Empty(t, ids(service.AuctionFilter{Category: "sneakers-trainers", Attributes: map[string]string{"condition": "used"}}))
+
+	_, err := auctionService.FindAuctions(service.AuctionFilter{Category: "garden"})
+	assert.ErrorIs(t, err, service.ErrCategoryNotFound)
+	_, err = auctionService.FindAuctions(service.AuctionFilter{Category: "shoes", Attributes: map[string]string{"size": "big"}})
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+)
+
+// ErrRateUnavailable is returned when there is no exchange rate between two
+// currencies.
+var ErrRateUnavailable = errors.New("exchange rate not available")
+
+// DisplayNotice labels converted prices in auction responses.
+const DisplayNotice = "Converted prices are indicative. Bids are placed and settled in the auction currency."
+
+// ExchangeRateProvider looks up the rate to convert amounts from one
+// currency to another.
+type ExchangeRateProvider interface {
+	Rate(from, to string) (model.ExchangeRate, error)
+}
+
+// RateTable is an ExchangeRateProvider backed by stored rates. When only the
+// opposite pair is stored it uses the inverse of that rate.
+type RateTable struct {
+	rates repository.ExchangeRateRepository
+}
+
+// NewRateTable creates a RateTable reading from rates.
+func NewRateTable(rates repository.ExchangeRateRepository) *RateTable {
+	return &RateTable{rates: rates}
+}
+
+// Ensure RateTable implements ExchangeRateProvider
+var _ ExchangeRateProvider = (*RateTable)(nil)
+
+// Rate returns the rate from one currency to another.
+func (t *RateTable) Rate(from, to string) (model.ExchangeRate, error) {
+	if from == to {
+		return model.ExchangeRate{From: from, To: to, Rate: "1"}, nil
+	}
+	rate, err := t.rates.GetRate(from, to)
+	if err == nil {
+		return rate, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return model.ExchangeRate{}, err
+	}
+
+	inverse, err := t.rates.GetRate(to, from)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.ExchangeRate{}, fmt.Errorf("%w: %s to %s", ErrRateUnavailable, from, to)
+	}
+	if err != nil {
+		return model.ExchangeRate{}, err
+	}
+	return inverse.Inverse()
+}
+
+// ExchangeRateService manages exchange rates and converts auction prices for
+// display.
+type ExchangeRateService interface {
+	ImportRates(rates []model.ExchangeRate) error
+	GetRates() ([]model.ExchangeRate, error)
+	ConvertForDisplay(auction model.Auction, currency string) (model.Auction, error)
+}
+
+type exchangeRateService struct {
+	rates    repository.ExchangeRateRepository
+	provider ExchangeRateProvider
+}
+
+// Ensure exchangeRateService implements ExchangeRateService
+var _ ExchangeRateService = (*exchangeRateService)(nil)
+
+// NewExchangeRateService creates an ExchangeRateService that imports rates
+// into rates and converts prices with provider.
+func NewExchangeRateService(rates repository.ExchangeRateRepository, provider ExchangeRateProvider) ExchangeRateService {
+	return &exchangeRateService{rates: rates, provider: provider}
+}
+
+// ImportRates validates and stores rates, replacing the stored rates of the
+// same currency pairs.
+func (s *exchangeRateService) ImportRates(rates []model.ExchangeRate) error {
+	if len(rates) == 0 {
+		return fmt.Errorf("%w: no exchange rates given", ErrInvalidInput)
+	}
+	for i := range rates {
+		rate := &rates[i]
+		rate.From, rate.To = strings.ToUpper(strings.TrimSpace(rate.From)), strings.ToUpper(strings.TrimSpace(rate.To))
+		rate.Rate = strings.TrimSpace(rate.Rate)
+		if !model.ValidCurrency(rate.From) || !model.ValidCurrency(rate.To) || rate.From == rate.To {
+			return fmt.Errorf("%w: invalid currency pair %s/%s", ErrInvalidInput, rate.From, rate.To)
+		}
+		if _, err := rate.Ratio(); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+	}
+	return s.rates.SaveRates(rates)
+}
+
+func (s *exchangeRateService) GetRates() ([]model.ExchangeRate, error) {
+	return s.rates.GetAllRates()
+}
+
+// ConvertForDisplay fills in auction.Display with its prices in currency.
+// The auction itself is left in its own currency.
+func (s *exchangeRateService) ConvertForDisplay(auction model.Auction, currency string) (model.Auction, error) {
+	currency = strings.ToUpper(currency)
+	if !model.ValidCurrency(currency) {
+		return auction, fmt.Errorf("%w: unknown currency %q", ErrInvalidInput, currency)
+	}
+	rate, err := s.provider.Rate(auction.Currency, currency)
+	if err != nil {
+		return auction, err
+	}
+
+	display := model.DisplayPrices{Currency: currency, Indicative: true, Notice: DisplayNotice, Rate: rate.Rate, RateUpdatedAt: rate.UpdatedAt}
+	if display.CurrentPrice, err = rate.Convert(auction.CurrentPrice); err != nil {
+		return auction, err
+	}
+	if auction.NextMinimumBid != nil {
+		minimum, err := rate.Convert(*auction.NextMinimumBid)
+		if err != nil {
+			return auction, err
+		}
+		display.NextMinimumBid = &minimum
+	}
+	if auction.BuyNowPrice.IsPositive() {
+		buyNow, err := rate.Convert(auction.BuyNowPrice)
+		if err != nil {
+			return auction, err
+		}
+		display.BuyNowPrice = &buyNow
+	}
+	auction.Display = &display
+	return auction, nil
+}
+
+// ParseRatesCSV reads exchange rates written as from,to,rate lines, e.g.
+// "USD,EUR,0.9215". A header line starting with "from" is skipped.
+func ParseRatesCSV(r io.Reader) ([]model.ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	var rates []model.ExchangeRate
+	for i, record := range records {
+		if i == 0 && strings.EqualFold(record[0], "from") {
+			continue
+		}
+		rates = append(rates, model.ExchangeRate{From: record[0], To: record[1], Rate: record[2]})
+	}
+	return rates, nil
+}
@@ -0,0 +1,115 @@
+package service_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"auction-service/internal/service"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newExchangeRateService(t *testing.T, rates ...model.ExchangeRate) service.ExchangeRateService {
+	repo := repository.NewMemoryStore().ExchangeRates()
+	require.NoError(t, repo.SaveRates(rates))
+	return service.NewExchangeRateService(repo, service.NewRateTable(repo))
+}
+
+func TestRateTable(t *testing.T) {
+	repo := repository.NewMemoryStore().ExchangeRates()
+	require.NoError(t, repo.SaveRates([]model.ExchangeRate{{From: "EUR", To: "USD", Rate: "1.25"}}))
+	table := service.NewRateTable(repo)
+
+	rate, err := table.Rate("EUR", "USD")
+	require.NoError(t, err)
+	assert.Equal(t, "1.25", rate.Rate)
+
+	inverse, err := table.Rate("USD", "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, "0.8000000000", inverse.Rate)
+
+	same, err := table.Rate("GBP", "GBP")
+	require.NoError(t, err)
+	assert.Equal(t, "1", same.Rate)
+
+	_, err = table.Rate("USD", "JPY")
+	assert.ErrorIs(t, err, service.ErrRateUnavailable)
+}
+
+func TestConvertForDisplay(t *testing.T) {
+	rates := newExchangeRateService(t,
+		model.ExchangeRate{From: "USD", To: "EUR", Rate: "0.9215"},
+		model.ExchangeRate{From: "USD", To: "JPY", Rate: "151.37"},
+	)
+	minimum := usd(13)
+	auction := model.Auction{ID: 1, Currency: "USD", CurrentPrice: usd(12.5), NextMinimumBid: &minimum, BuyNowPrice: usd(100)}
+
+	tests := []struct {
+		currency    string
+		wantCurrent model.Money
+		wantMinimum model.Money
+		wantBuyNow  model.Money
+	}{
+		{currency: "EUR", wantCurrent: model.NewMoney(1152, "EUR"), wantMinimum: model.NewMoney(1198, "EUR"), wantBuyNow: model.NewMoney(9215, "EUR")},
+		{currency: "jpy", wantCurrent: model.NewMoney(1892, "JPY"), wantMinimum: model.NewMoney(1968, "JPY"), wantBuyNow: model.NewMoney(15137, "JPY")},
+		{currency: "USD", wantCurrent: usd(12.5), wantMinimum: usd(13), wantBuyNow: usd(100)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.currency, func(t *testing.T) {
+			converted, err := rates.ConvertForDisplay(auction, tt.currency)
+			require.NoError(t, err)
+
+			assert.Equal(t, usd(12.5), converted.CurrentPrice, "the auction keeps its own currency")
+			require.NotNil(t, converted.Display)
+			assert.True(t, converted.Display.Indicative)
+			assert.NotEmpty(t, converted.Display.Notice)
+			assert.Equal(t, tt.wantCurrent, converted.Display.CurrentPrice)
+			assert.Equal(t, tt.wantMinimum, *converted.Display.NextMinimumBid)
+			assert.Equal(t, tt.wantBuyNow, *converted.Display.BuyNowPrice)
+		})
+	}
+
+	_, err := rates.ConvertForDisplay(auction, "GBP")
+	assert.ErrorIs(t, err, service.ErrRateUnavailable)
+	_, err = rates.ConvertForDisplay(auction, "XYZ")
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+}
+
+func TestImportRates(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		wantErr bool
+	}{
+		{name: "with header", csv: "from,to,rate\nUSD,EUR,0.92\nEUR,GBP,0.85\n"},
+		{name: "lower case", csv: "usd, eur, 0.92"},
+		{name: "missing column", csv: "USD,EUR", wantErr: true},
+		{name: "unknown currency", csv: "USD,XYZ,2", wantErr: true},
+		{name: "same currency", csv: "USD,USD,1", wantErr: true},
+		{name: "zero rate", csv: "USD,EUR,0", wantErr: true},
+		{name: "not a number", csv: "USD,EUR,abc", wantErr: true},
+		{name: "empty", csv: "from,to,rate\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rates := newExchangeRateService(t)
+
+			parsed, err := service.ParseRatesCSV(strings.NewReader(tt.csv))
+			if err == nil {
+				err = rates.ImportRates(parsed)
+			}
+			if tt.wantErr {
+				assert.ErrorIs(t, err, service.ErrInvalidInput)
+				return
+			}
+			require.NoError(t, err)
+			stored, err := rates.GetRates()
+			require.NoError(t, err)
+			assert.Len(t, stored, len(parsed))
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+
+	// Register the decoders of the image types attachments can have.
+	_ "image/gif"
+	_ "image/png"
+)
+
+// thumbnailQuality is the JPEG quality of thumbnails.
+const thumbnailQuality = 80
+
+// decodeImage decodes a photo, refusing images with more than maxPixels
+// pixels before decoding them so small files cannot expand into huge
+// bitmaps.
+func decodeImage(data []byte, maxPixels int) (image.Image, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: the image cannot be read", ErrInvalidInput)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxPixels {
+		return nil, fmt.Errorf("%w: images can have at most %d pixels", ErrInvalidInput, maxPixels)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: the image cannot be read", ErrInvalidInput)
+	}
+	return img, nil
+}
+
+// thumbnail scales img down to fit in a size by size square, averaging the
+// pixels each thumbnail pixel covers, and encodes it as a JPEG. Transparent
+// parts turn white. Images that already fit are only re-encoded.
+func thumbnail(img image.Image, size int) ([]byte, error) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > size || height > size {
+		if width >= height {
+			width, height = size, max(height*size/width, 1)
+		} else {
+			width, height = max(width*size/height, 1), size
+		}
+	}
+
+	source := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(source, source.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(source, source.Bounds(), img, bounds.Min, draw.Over)
+
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := y*bounds.Dy()/height, max((y+1)*bounds.Dy()/height, y*bounds.Dy()/height+1)
+		for x := 0; x < width; x++ {
+			x0, x1 := x*bounds.Dx()/width, max((x+1)*bounds.Dx()/width, x*bounds.Dx()/width+1)
+			var r, g, b, n int
+			for sy := y0; sy < y1; sy++ {
+				row := source.Pix[sy*source.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					r += int(row[sx*4])
+					g += int(row[sx*4+1])
+					b += int(row[sx*4+2])
+					n++
+				}
+			}
+			i := scaled.PixOffset(x, y)
+			scaled.Pix[i] = uint8(r / n)
+			scaled.Pix[i+1] = uint8(g / n)
+			scaled.Pix[i+2] = uint8(b / n)
+			scaled.Pix[i+3] = 0xff
+		}
+	}
+
+	var out bytes.Buffer
+	if err := jpeg.Encode(&out, scaled, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+)
+
+// LiveNotifier is told about the outbox messages of each committed
+// transaction, in the order they were added. It must not block: it runs on
+// the request path, right after the commit.
+type LiveNotifier interface {
+	Notify(messages []model.OutboxMessage)
+}
+
+// transactions returns txManager wrapped so that the configured notifier, if
+// any, sees the outbox messages of every transaction that commits.
+func (o options) transactions(txManager repository.TxManager) repository.TxManager {
+	if o.notifier == nil {
+		return txManager
+	}
+	return &notifyingTxManager{TxManager: txManager, notifier: o.notifier}
+}
+
+// notifyingTxManager records the messages added to the outbox inside a
+// transaction and passes them to the notifier once it commits.
+type notifyingTxManager struct {
+	repository.TxManager
+	notifier LiveNotifier
+}
+
+func (m *notifyingTxManager) Transaction(fn func(uow repository.UnitOfWork) error) error {
+	var added []model.OutboxMessage
+	err := m.TxManager.Transaction(func(uow repository.UnitOfWork) error {
+		// The transaction may be retried; only the last attempt counts.
+		added = added[:0]
+		return fn(&recordingUnitOfWork{UnitOfWork: uow, added: &added})
+	})
+	if err == nil && len(added) > 0 {
+		m.notifier.Notify(added)
+	}
+	return err
+}
+
+// recordingUnitOfWork appends the messages its outbox stores to added.
+type recordingUnitOfWork struct {
+	repository.UnitOfWork
+	added *[]model.OutboxMessage
+}
+
+func (u *recordingUnitOfWork) Outbox() repository.OutboxRepository {
+	return &recordingOutbox{OutboxRepository: u.UnitOfWork.Outbox(), added: u.added}
+}
+
+// Transaction forgets the messages of a savepoint that is rolled back.
+func (u *recordingUnitOfWork) Transaction(fn func(uow repository.UnitOfWork) error) error {
+	mark := len(*u.added)
+	err := u.UnitOfWork.Transaction(func(uow repository.UnitOfWork) error {
+		return fn(&recordingUnitOfWork{UnitOfWork: uow, added: u.added})
+	})
+	if err != nil {
+		*u.added = (*u.added)[:mark]
+	}
+	return err
+}
+
+type recordingOutbox struct {
+	repository.OutboxRepository
+	added *[]model.OutboxMessage
+}
+
+func (o *recordingOutbox) AddMessage(message model.OutboxMessage) (model.OutboxMessage, error) {
+	message, err := o.OutboxRepository.AddMessage(message)
+	if err == nil {
+		*o.added = append(*o.added, message)
+	}
+	return message, err
+}
@@ -0,0 +1,40 @@
+package service_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"auction-service/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingNotifier struct {
+	batches [][]model.OutboxMessage
+}
+
+func (n *recordingNotifier) Notify(messages []model.OutboxMessage) {
+	n.batches = append(n.batches, messages)
+}
+
+func TestLiveNotifierSeesCommittedEvents(t *testing.T) {
+	store := repository.NewMemoryStore()
+	notifier := &recordingNotifier{}
+	auctionService := service.NewAuctionService(store.Auctions(), store, service.WithLiveNotifier(notifier))
+	auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen})
+
+	_, err := auctionService.PlaceBid(auction.ID, 2, usd(20))
+	require.NoError(t, err)
+	// A refused bid commits nothing.
+	_, err = auctionService.PlaceBid(auction.ID, 3, usd(10))
+	require.Error(t, err)
+
+	pending, err := store.Outbox().GetPendingMessages(100)
+	require.NoError(t, err)
+	if assert.Len(t, notifier.batches, 1) {
+		assert.Equal(t, pending, notifier.batches[0])
+		assert.Equal(t, model.EventBidPlaced, notifier.batches[0][0].Type)
+		assert.Equal(t, model.EventPriceChanged, notifier.batches[0][1].Type)
+	}
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"time"
+
+	"auction-service/internal/blob"
+	"auction-service/internal/model"
+)
+
+// Option customizes the services and workers of this package.
+type Option func(*options)
+
+type options struct {
+	now           func() time.Time
+	increments    model.IncrementTable
+	retraction    RetractionRules
+	paymentPeriod time.Duration
+	offerPeriod   time.Duration
+	notifier      LiveNotifier
+	endingSoon    time.Duration
+	webhooks      WebhookPolicy
+	blobs         blob.Store
+	uploadLimits  UploadLimits
+	mediaURL      string
+}
+
+// WithClock makes the service read the current time from now instead of
+// time.Now. Tests use it to control end times.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) { o.now = now }
+}
+
+// WithIncrements sets the bid increment table for auctions that do not have
+// their own.
+func WithIncrements(table model.IncrementTable) Option {
+	return func(o *options) { o.increments = table }
+}
+
+// WithRetractionRules sets when bidders may retract their bids.
+func WithRetractionRules(rules RetractionRules) Option {
+	return func(o *options) { o.retraction = rules }
+}
+
+// WithPaymentPeriod sets how long the winner of an auction has to pay before
+// the order becomes unpaid.
+func WithPaymentPeriod(period time.Duration) Option {
+	return func(o *options) { o.paymentPeriod = period }
+}
+
+// WithOfferPeriod sets how long runner-up bidders have to answer a
+// second-chance offer.
+func WithOfferPeriod(period time.Duration) Option {
+	return func(o *options) { o.offerPeriod = period }
+}
+
+// WithLiveNotifier hands the outbox messages of every committed change to
+// notifier, so live clients hear about them before the relay publishes them.
+func WithLiveNotifier(notifier LiveNotifier) Option {
+	return func(o *options) { o.notifier = notifier }
+}
+
+// WithEndingSoonWindow sets how long before the end of an auction its
+// watchers are told it is ending.
+func WithEndingSoonWindow(window time.Duration) Option {
+	return func(o *options) { o.endingSoon = window }
+}
+
+// WithWebhookPolicy sets how webhook deliveries are retried and when failing
+// endpoints are disabled.
+func WithWebhookPolicy(policy WebhookPolicy) Option {
+	return func(o *options) { o.webhooks = policy }
+}
+
+// WithBlobStore makes the auction service delete the files of auctions it
+// deletes from blobs.
+func WithBlobStore(blobs blob.Store) Option {
+	return func(o *options) { o.blobs = blobs }
+}
+
+// WithUploadLimits sets the size and number of files sellers can attach.
+func WithUploadLimits(limits UploadLimits) Option {
+	return func(o *options) { o.uploadLimits = limits }
+}
+
+// WithMediaURL sets the base URL of attachment links, e.g. the public URL
+// of the bucket when clients download straight from it.
+func WithMediaURL(base string) Option {
+	return func(o *options) { o.mediaURL = base }
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		now:           time.Now,
+		increments:    DefaultIncrements,
+		retraction:    DefaultRetractionRules,
+		paymentPeriod: DefaultPaymentPeriod,
+		offerPeriod:   DefaultOfferPeriod,
+		endingSoon:    DefaultEndingSoonWindow,
+		webhooks:      DefaultWebhookPolicy,
+		uploadLimits:  DefaultUploadLimits,
+		mediaURL:      DefaultMediaURL,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,134 @@
+package service_test
+
+import (
+	"auction-service/internal/messaging"
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"auction-service/internal/service"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type flakyPublisher struct {
+	sent    []string
+	topics  []string
+	failOn  string
+	failErr error
+}
+
+func (p *flakyPublisher) Publish(topic string, message []byte) error {
+	if string(message) == p.failOn {
+		return p.failErr
+	}
+	p.sent = append(p.sent, string(message))
+	p.topics = append(p.topics, topic)
+	return nil
+}
+
+func TestOutboxRelayFlushPublishesInOrder(t *testing.T) {
+	store := repository.NewMemoryStore()
+	outbox := store.Outbox()
+	outbox.AddMessage(model.OutboxMessage{Type: "a", Payload: []byte("first")})
+	outbox.AddMessage(model.OutboxMessage{Type: "b", Payload: []byte("second")})
+	outbox.AddMessage(model.OutboxMessage{Type: "c", Payload: []byte("third")})
+
+	publisher := &flakyPublisher{failOn: "second", failErr: errors.New("broker down")}
+	relay := service.NewOutboxRelay(store, publisher, "auction_events", 0)
+
+	sent, err := relay.Flush()
+	assert.Error(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []string{"first"}, publisher.sent)
+
+	publisher.failOn = ""
+	sent, err = relay.Flush()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	assert.Equal(t, []string{"first", "second", "third"}, publisher.sent)
+
+	sent, err = relay.Flush()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+}
+
+func TestOutboxRelaysOfSeveralReplicasPublishOnce(t *testing.T) {
+	store := repository.NewMemoryStore()
+	for i := 0; i < 250; i++ {
+		_, err := store.Outbox().AddMessage(model.OutboxMessage{Type: "a", Payload: []byte(fmt.Sprint(i))})
+		require.NoError(t, err)
+	}
+
+	publisher := &flakyPublisher{}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		relay := service.NewOutboxRelay(store, publisher, "auction_events", 0)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				sent, err := relay.Flush()
+				if err != nil || sent == 0 {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, publisher.sent, 250)
+	for i, message := range publisher.sent {
+		assert.Equal(t, fmt.Sprint(i), message)
+	}
+}
+
+func TestOutboxRelayPublishesCopies(t *testing.T) {
+	store := repository.NewMemoryStore()
+	outbox := store.Outbox()
+	outbox.AddMessage(model.OutboxMessage{Type: model.EventBidPlaced, Payload: []byte("first")})
+
+	publisher := &flakyPublisher{}
+	relay := service.NewOutboxRelay(store, publisher, "auction_events", 0)
+	relay.AlsoPublishTo("auction_webhooks")
+
+	sent, err := relay.Flush()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []string{"auction_events", "auction_webhooks"}, publisher.topics)
+	assert.Equal(t, []string{"first", "first"}, publisher.sent)
+}
+
+func TestOutboxRelayBroadcastsLiveEvents(t *testing.T) {
+	store := repository.NewMemoryStore()
+	outbox := store.Outbox()
+	added, err := outbox.AddMessage(model.OutboxMessage{Type: model.EventPriceChanged, Payload: []byte(`{"type":"auction.price_changed"}`)})
+	require.NoError(t, err)
+
+	bus := messaging.NewMemoryBus()
+	defer bus.Close()
+	var received []model.LiveEvent
+	bus.SubscribeBroadcast("auction_live", func(d messaging.Delivery) error {
+		var event model.LiveEvent
+		if err := json.Unmarshal(d.Body, &event); err != nil {
+			return err
+		}
+		received = append(received, event)
+		return nil
+	})
+
+	relay := service.NewOutboxRelay(store, bus, "auction_events", 0)
+	relay.BroadcastTo(bus, "auction_live")
+	_, err = relay.Flush()
+	require.NoError(t, err)
+	bus.Wait()
+
+	if assert.Len(t, received, 1) {
+		assert.Equal(t, added.ID, received[0].ID)
+		assert.JSONEq(t, string(added.Payload), string(received[0].Event))
+	}
+}
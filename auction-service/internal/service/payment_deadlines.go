@@ -0,0 +1,146 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+)
+
+// PaymentDeadlines marks the orders whose payment deadline passed as unpaid
+// and expires the second-chance offers nobody answered in time. Each order
+// or offer is changed in its own transaction after locking it and checking
+// it again, so a payment or answer made in the meantime wins.
+type PaymentDeadlines struct {
+	orders    repository.OrderRepository
+	offers    repository.SecondChanceOfferRepository
+	txManager repository.TxManager
+	interval  time.Duration
+	now       func() time.Time
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewPaymentDeadlines creates a scheduler that looks for overdue orders and
+// offers every interval.
+func NewPaymentDeadlines(orders repository.OrderRepository, offers repository.SecondChanceOfferRepository, txManager repository.TxManager, interval time.Duration, opts ...Option) *PaymentDeadlines {
+	o := newOptions(opts)
+	return &PaymentDeadlines{
+		orders:    orders,
+		offers:    offers,
+		txManager: txManager,
+		interval:  interval,
+		now:       o.now,
+		stop:      make(chan struct{}),
+	}
+}
+
+// Start marks overdue orders and offers in the background until Stop is
+// called.
+func (p *PaymentDeadlines) Start() {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				if _, err := p.ExpireOverdue(); err != nil {
+					log.Printf("Error expiring unpaid orders: %v", err)
+				}
+				if _, err := p.ExpireOffers(); err != nil {
+					log.Printf("Error expiring second-chance offers: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop ends the polling started by Start and waits for it to finish.
+func (p *PaymentDeadlines) Stop() {
+	close(p.stop)
+	p.wg.Wait()
+}
+
+// ExpireOverdue marks every overdue order as unpaid and returns how many it
+// marked.
+func (p *PaymentDeadlines) ExpireOverdue() (int, error) {
+	now := p.now()
+	overdue, err := p.orders.GetOverdueOrders(now)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, candidate := range overdue {
+		var done bool
+		err := p.txManager.Transaction(func(uow repository.UnitOfWork) error {
+			order, err := uow.Orders().GetOrderByIDForUpdate(candidate.ID)
+			if errors.Is(err, repository.ErrOrderNotFound) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			done = order.Status == model.OrderStatusAwaitingPayment && !order.PaymentDueAt.After(now)
+			if !done {
+				return nil
+			}
+			return moveOrder(uow, &order, model.OrderStatusUnpaid, model.EventOrderUnpaid)
+		})
+		if err != nil {
+			return expired, err
+		}
+		if done {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// ExpireOffers expires every pending second-chance offer past its expiry and
+// returns how many it expired.
+func (p *PaymentDeadlines) ExpireOffers() (int, error) {
+	now := p.now()
+	overdue, err := p.offers.GetExpiredOffers(now)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, candidate := range overdue {
+		var done bool
+		err := p.txManager.Transaction(func(uow repository.UnitOfWork) error {
+			offer, err := uow.SecondChanceOffers().GetOfferByIDForUpdate(candidate.ID)
+			if errors.Is(err, repository.ErrOfferNotFound) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			done = offer.IsPending() && !offer.ExpiresAt.After(now)
+			if !done {
+				return nil
+			}
+			offer.Status = model.OfferStatusExpired
+			if err := uow.SecondChanceOffers().UpdateOffer(offer); err != nil {
+				return err
+			}
+			return announceOffer(uow, offer, model.EventSecondChanceExpired)
+		})
+		if err != nil {
+			return expired, err
+		}
+		if done {
+			expired++
+		}
+	}
+	return expired, nil
+}
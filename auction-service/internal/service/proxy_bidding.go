@@ -0,0 +1,155 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+)
+
+// resolveProxyBids works out the automatic bids the proxies place after the
+// leading bid, made by leaderID at price (leaderID is 0 when there are no
+// bids yet). The strongest proxy, the highest maximum and on ties the
+// earliest, ends up leading at the lowest price that beats every competitor:
+// their maximum plus the increment from increments, capped at its own
+// maximum. Losing proxies bid their whole maximum so the history shows why
+// they lost, as long as it is at least the minimum bid over the current
+// price; a challenger short of it cannot bid and leaves the leader alone.
+func resolveProxyBids(price model.Money, leaderID int, leaderSince time.Time, proxies []model.ProxyBid, increments model.IncrementTable) []model.Bid {
+	ranked := append([]model.ProxyBid(nil), proxies...)
+	sort.SliceStable(ranked, func(i, j int) bool { return beats(ranked[i], ranked[j]) })
+
+	var placed []model.Bid
+	for {
+		defender := proxyOf(ranked, leaderID, price)
+		challenger := strongestChallenger(ranked, price, leaderID, leaderSince, defender)
+		if challenger == nil {
+			return placed
+		}
+
+		minimum := price.Add(increments.Increment(price))
+		var bids []model.Bid
+		if defender != nil && beats(*defender, *challenger) {
+			if challenger.MaxAmount.Less(minimum) {
+				return placed
+			}
+			// The leader's proxy holds: the challenger goes all in and is outbid.
+			bids = append(bids,
+				automaticBid(*challenger, challenger.MaxAmount),
+				automaticBid(*defender, model.MinMoney(defender.MaxAmount, challenger.MaxAmount.Add(increments.Increment(challenger.MaxAmount)))))
+		} else {
+			ceiling := price
+			if defender != nil && price.Less(defender.MaxAmount) {
+				if !defender.MaxAmount.Less(minimum) {
+					bids = append(bids, automaticBid(*defender, defender.MaxAmount))
+				}
+				ceiling = defender.MaxAmount
+			}
+			bids = append(bids, automaticBid(*challenger, model.MinMoney(challenger.MaxAmount, ceiling.Add(increments.Increment(ceiling)))))
+		}
+
+		placed = append(placed, bids...)
+		last := bids[len(bids)-1]
+		price, leaderID = last.Amount, last.UserID
+	}
+}
+
+// beats reports whether proxy a outranks proxy b: a higher maximum wins and
+// the earlier proxy wins ties.
+func beats(a, b model.ProxyBid) bool {
+	if a.MaxAmount != b.MaxAmount {
+		return b.MaxAmount.Less(a.MaxAmount)
+	}
+	return a.ID < b.ID
+}
+
+// proxyOf returns the proxy of userID if it can still cover price.
+func proxyOf(proxies []model.ProxyBid, userID int, price model.Money) *model.ProxyBid {
+	for i := range proxies {
+		if proxies[i].UserID == userID && !proxies[i].MaxAmount.Less(price) {
+			return &proxies[i]
+		}
+	}
+	return nil
+}
+
+// strongestChallenger returns the best ranked proxy of another user that can
+// take the lead. Matching the price is enough for a proxy placed before the
+// leading bid, or before the proxy defending it.
+func strongestChallenger(ranked []model.ProxyBid, price model.Money, leaderID int, leaderSince time.Time, defender *model.ProxyBid) *model.ProxyBid {
+	for i := range ranked {
+		candidate := &ranked[i]
+		if candidate.UserID == leaderID {
+			continue
+		}
+		if price.Less(candidate.MaxAmount) {
+			return candidate
+		}
+		if candidate.MaxAmount.Less(price) || leaderID == 0 {
+			return nil
+		}
+		if defender != nil && candidate.ID < defender.ID {
+			return candidate
+		}
+		if defender == nil && candidate.CreatedAt.Before(leaderSince) {
+			return candidate
+		}
+	}
+	return nil
+}
+
+func automaticBid(proxy model.ProxyBid, amount model.Money) model.Bid {
+	return model.Bid{AuctionID: proxy.AuctionID, UserID: proxy.UserID, Amount: amount, Automatic: true}
+}
+
+// applyProxyBids lets the proxies of auction answer its leading bid.
+func (s *auctionService) applyProxyBids(uow repository.UnitOfWork, auction model.Auction) (model.Auction, error) {
+	proxies, err := uow.ProxyBids().GetProxyBidsByAuctionID(auction.ID)
+	if err != nil || len(proxies) == 0 {
+		return auction, err
+	}
+
+	leading, err := uow.Bids().GetLatestBid(auction.ID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return auction, err
+	}
+	return s.placeBids(uow, auction, resolveProxyBids(auction.CurrentPrice, leading.UserID, leading.CreatedAt, proxies, s.incrementsFor(auction)))
+}
+
+// placeBids stores bids in order, announces each of them and moves the price
+// of auction to the last one. Bids landing in the soft-close window extend
+// the auction in the same transaction, so the closer sees the new end time.
+func (s *auctionService) placeBids(uow repository.UnitOfWork, auction model.Auction, bids []model.Bid) (model.Auction, error) {
+	if len(bids) == 0 {
+		return auction, nil
+	}
+
+	for _, bid := range bids {
+		if _, err := uow.Bids().CreateBid(bid); err != nil {
+			return auction, err
+		}
+		event := model.Event{Type: model.EventBidPlaced, AuctionID: auction.ID, UserID: bid.UserID, Amount: &bid.Amount, Automatic: bid.Automatic}
+		if err := enqueue(uow, event); err != nil {
+			return auction, err
+		}
+	}
+
+	auction.CurrentPrice = bids[len(bids)-1].Amount
+	end, extended := auction.SoftCloseEnd(s.now())
+	if extended {
+		auction.EndsAt = &end
+		auction.Extensions++
+	}
+	if err := uow.Auctions().UpdateAuction(auction); err != nil {
+		return auction, err
+	}
+	if err := enqueue(uow, model.Event{Type: model.EventPriceChanged, AuctionID: auction.ID, Amount: &auction.CurrentPrice}); err != nil {
+		return auction, err
+	}
+	if extended {
+		return auction, enqueue(uow, model.Event{Type: model.EventAuctionExtended, AuctionID: auction.ID, EndsAt: auction.EndsAt})
+	}
+	return auction, nil
+}
@@ -0,0 +1,145 @@
+package service_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bidStep is a manual bid, or a proxy bid when proxy is set.
+type bidStep struct {
+	userID int
+	amount float64
+	proxy  bool
+}
+
+type placedBid struct {
+	userID    int
+	amount    float64
+	automatic bool
+}
+
+func TestProxyBidding(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []bidStep
+		want  []placedBid
+	}{
+		{
+			name:  "single proxy opens at the increment",
+			steps: []bidStep{{userID: 2, amount: 50, proxy: true}},
+			want:  []placedBid{{2, 11, true}},
+		},
+		{
+			name:  "weaker proxy is outbid by the increment",
+			steps: []bidStep{{userID: 2, amount: 50, proxy: true}, {userID: 3, amount: 30, proxy: true}},
+			want:  []placedBid{{2, 11, true}, {3, 30, true}, {2, 31, true}},
+		},
+		{
+			name:  "stronger proxy takes the lead",
+			steps: []bidStep{{userID: 2, amount: 30, proxy: true}, {userID: 3, amount: 50, proxy: true}},
+			want:  []placedBid{{2, 11, true}, {2, 30, true}, {3, 31, true}},
+		},
+		{
+			name:  "losing maximum below the minimum bid is not recorded",
+			steps: []bidStep{{userID: 2, amount: 11.5, proxy: true}, {userID: 3, amount: 50, proxy: true}},
+			want:  []placedBid{{2, 11, true}, {3, 12.5, true}},
+		},
+		{
+			name:  "earliest proxy wins ties",
+			steps: []bidStep{{userID: 2, amount: 50, proxy: true}, {userID: 3, amount: 50, proxy: true}},
+			want:  []placedBid{{2, 11, true}, {3, 50, true}, {2, 50, true}},
+		},
+		{
+			name:  "proxy answers a manual bid",
+			steps: []bidStep{{userID: 2, amount: 50, proxy: true}, {userID: 3, amount: 20}},
+			want:  []placedBid{{2, 11, true}, {3, 20, false}, {2, 21, true}},
+		},
+		{
+			name:  "proxy wins a manual bid matching its maximum",
+			steps: []bidStep{{userID: 2, amount: 50, proxy: true}, {userID: 3, amount: 50}},
+			want:  []placedBid{{2, 11, true}, {3, 50, false}, {2, 50, true}},
+		},
+		{
+			name:  "manual bid above the maximum leads",
+			steps: []bidStep{{userID: 2, amount: 50, proxy: true}, {userID: 3, amount: 60}},
+			want:  []placedBid{{2, 11, true}, {3, 60, false}},
+		},
+		{
+			name:  "leader raising the maximum does not bid against itself",
+			steps: []bidStep{{userID: 2, amount: 50, proxy: true}, {userID: 2, amount: 80, proxy: true}, {userID: 3, amount: 60, proxy: true}},
+			want:  []placedBid{{2, 11, true}, {3, 60, true}, {2, 61, true}},
+		},
+		{
+			name:  "proxy takes over a manual lead",
+			steps: []bidStep{{userID: 3, amount: 20}, {userID: 2, amount: 50, proxy: true}},
+			want:  []placedBid{{3, 20, false}, {2, 21, true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auctionService, store := newTestService()
+			auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen, CurrentPrice: usd(10)})
+
+			var updated model.Auction
+			var err error
+			for _, step := range tt.steps {
+				if step.proxy {
+					updated, err = auctionService.PlaceProxyBid(auction.ID, step.userID, usd(step.amount))
+				} else {
+					updated, err = auctionService.PlaceBid(auction.ID, step.userID, usd(step.amount))
+				}
+				require.NoError(t, err)
+			}
+
+			bids, err := auctionService.GetBids(auction.ID)
+			require.NoError(t, err)
+			got := make([]placedBid, len(bids))
+			for i, bid := range bids {
+				got[i] = placedBid{bid.UserID, float64(bid.Amount.Minor) / 100, bid.Automatic}
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, usd(tt.want[len(tt.want)-1].amount), updated.CurrentPrice)
+
+			var events []model.Event
+			for _, event := range outboxEvents(t, store) {
+				if event.Type == model.EventBidPlaced {
+					events = append(events, event)
+				}
+			}
+			if assert.Len(t, events, len(tt.want)) {
+				for i, event := range events {
+					assert.Equal(t, model.EventBidPlaced, event.Type)
+					assert.Equal(t, tt.want[i].userID, event.UserID)
+					assert.Equal(t, tt.want[i].automatic, event.Automatic)
+				}
+			}
+		})
+	}
+}
+
+func TestPlaceProxyBidValidation(t *testing.T) {
+	auctionService, store := newTestService()
+	auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen, CurrentPrice: usd(10)})
+
+	_, err := auctionService.PlaceProxyBid(auction.ID, 2, usd(10))
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+
+	_, err = auctionService.PlaceProxyBid(auction.ID, 1, usd(50))
+	assert.ErrorIs(t, err, service.ErrForbidden)
+
+	_, err = auctionService.PlaceProxyBid(auction.ID, 2, usd(50))
+	require.NoError(t, err)
+	_, err = auctionService.PlaceProxyBid(auction.ID, 2, usd(40))
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+
+	_, err = auctionService.PlaceProxyBid(42, 2, usd(50))
+	assert.ErrorIs(t, err, service.ErrNotFound)
+
+	_, err = auctionService.GetBids(42)
+	assert.ErrorIs(t, err, service.ErrNotFound)
+}
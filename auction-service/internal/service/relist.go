@@ -0,0 +1,141 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+)
+
+// ErrNotRelistable is returned when an auction cannot be relisted: it is
+// not closed, it sold, or it was relisted already.
+var ErrNotRelistable = errors.New("auction cannot be relisted")
+
+// RelistAuction lists an unsold closed auction of userID again. The new
+// auction copies its item, category, prices, increments, soft-close settings
+// and attachments, and runs as long as the original was meant to. It is
+// scheduled to open at startsAt, which is required, so the seller can review
+// it before bidding starts. Each auction can be relisted once, so the relists
+// of an item form a chain back to the original.
+func (s *auctionService) RelistAuction(userID, id int, startsAt *time.Time) (model.Auction, error) {
+	original, err := s.auctionRepository.GetAuctionByID(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.Auction{}, ErrNotFound
+	}
+	if err != nil {
+		return model.Auction{}, err
+	}
+	if original.UserID != userID {
+		return model.Auction{}, ErrForbidden
+	}
+	if err := relistable(original); err != nil {
+		return model.Auction{}, err
+	}
+	if startsAt == nil {
+		return model.Auction{}, fmt.Errorf("%w: a relist needs a start time", ErrInvalidInput)
+	}
+
+	root := original.ID
+	if original.OriginalAuctionID != nil {
+		root = *original.OriginalAuctionID
+	}
+	relist := model.Auction{
+		Item:                      original.Item,
+		Description:               original.Description,
+		Format:                    original.Format,
+		UserID:                    original.UserID,
+		CategoryID:                original.CategoryID,
+		Attributes:                original.Attributes,
+		Currency:                  original.Currency,
+		CurrentPrice:              original.StartPrice,
+		ReservePrice:              original.ReservePrice,
+		BuyNowPrice:               original.BuyNowPrice,
+		DutchDecrement:            original.DutchDecrement,
+		DutchIntervalMinutes:      original.DutchIntervalMinutes,
+		DutchFloorPrice:           original.DutchFloorPrice,
+		BidIncrements:             original.BidIncrements,
+		SoftCloseWindowMinutes:    original.SoftCloseWindowMinutes,
+		SoftCloseExtensionMinutes: original.SoftCloseExtensionMinutes,
+		SoftCloseCapMinutes:       original.SoftCloseCapMinutes,
+		StartsAt:                  startsAt,
+		RelistedFromID:            &original.ID,
+		OriginalAuctionID:         &root,
+	}
+	// The duration leaves out soft-close extensions: they depended on the
+	// bidding of the original.
+	if end := original.OriginalEndsAt; end != nil {
+		if duration := end.Sub(original.OpensAt()); duration > 0 {
+			endsAt := startsAt.Add(duration)
+			relist.EndsAt = &endsAt
+		}
+	}
+
+	created, err := s.createAuction(relist, func(uow repository.UnitOfWork) error {
+		// Lock the original so two relists of it cannot both pass.
+		locked, err := ownedAuction(uow, userID, id)
+		if err != nil {
+			return err
+		}
+		if err := relistable(locked); err != nil {
+			return err
+		}
+		lineage, err := uow.Auctions().FindAuctions(repository.AuctionFilter{OriginalAuctionID: root})
+		if err != nil {
+			return err
+		}
+		for _, auction := range lineage {
+			if auction.RelistedFromID != nil && *auction.RelistedFromID == id {
+				return fmt.Errorf("%w: it was relisted as auction %d", ErrNotRelistable, auction.ID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return model.Auction{}, err
+	}
+
+	copyAttachments(s.txManager, s.blobs, id, created.ID)
+	relisted := []model.Auction{s.withPrice(created)}
+	if err := s.withDetails(relisted); err != nil {
+		return model.Auction{}, err
+	}
+	return relisted[0], nil
+}
+
+// GetRelistLineage returns the auction an auction was first listed as and
+// all its relists, oldest first.
+func (s *auctionService) GetRelistLineage(id int) ([]model.Auction, error) {
+	auction, err := s.auctionRepository.GetAuctionByID(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	root := auction.ID
+	if auction.OriginalAuctionID != nil {
+		root = *auction.OriginalAuctionID
+	}
+
+	lineage, err := s.auctionRepository.FindAuctions(repository.AuctionFilter{OriginalAuctionID: root})
+	if err != nil {
+		return nil, err
+	}
+	for i := range lineage {
+		lineage[i] = s.withPrice(lineage[i])
+	}
+	return lineage, s.withDetails(lineage)
+}
+
+// relistable checks that auction closed without selling.
+func relistable(auction model.Auction) error {
+	if auction.Status != model.AuctionStatusClosed {
+		return fmt.Errorf("%w: it has not closed yet", ErrNotRelistable)
+	}
+	if auction.WinnerID != 0 {
+		return fmt.Errorf("%w: it sold", ErrNotRelistable)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package service_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"auction-service/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedUnsold stores an auction that ran from start for two hours, was
+// extended by an hour and closed without selling.
+func seedUnsold(t *testing.T, store *repository.MemoryStore) model.Auction {
+	return seedAuction(t, store, model.Auction{Item: "Brass Lamp", Description: "*Working*", UserID: 1, Status: model.AuctionStatusClosed,
+		StartPrice: usd(10), CurrentPrice: usd(45), ReservePrice: usd(50), BuyNowPrice: usd(80),
+		BidIncrements: model.IncrementTable{{From: usd(0), Increment: usd(2)}}, SoftCloseWindowMinutes: 5, SoftCloseExtensionMinutes: 2,
+		StartsAt: timeAt(0), EndsAt: timeAt(3 * time.Hour), OriginalEndsAt: timeAt(2 * time.Hour), Extensions: 30})
+}
+
+func TestRelistAuction(t *testing.T) {
+	clock := &fakeClock{now: start.Add(4 * time.Hour)}
+	auctionService, store := newClockedService(clock)
+	original := seedUnsold(t, store)
+
+	_, err := auctionService.RelistAuction(1, original.ID, nil)
+	assert.ErrorIs(t, err, service.ErrInvalidInput, "relists are scheduled")
+
+	relist, err := auctionService.RelistAuction(1, original.ID, timeAt(5*time.Hour))
+
+	require.NoError(t, err)
+	assert.NotEqual(t, original.ID, relist.ID)
+	assert.Equal(t, model.AuctionStatusScheduled, relist.Status)
+	assert.Equal(t, "Brass Lamp", relist.Item)
+	assert.Equal(t, "<p><em>Working</em></p>\n", relist.DescriptionHTML)
+	assert.Equal(t, usd(10), relist.CurrentPrice)
+	assert.Equal(t, usd(50), relist.ReservePrice)
+	assert.Equal(t, usd(80), relist.BuyNowPrice)
+	assert.Equal(t, original.BidIncrements, relist.BidIncrements)
+	assert.Equal(t, 5, relist.SoftCloseWindowMinutes)
+	assert.Zero(t, relist.Extensions)
+	if assert.NotNil(t, relist.EndsAt) {
+		assert.Equal(t, start.Add(7*time.Hour), *relist.EndsAt, "runs as long as the original was meant to")
+	}
+	assert.Equal(t, &original.ID, relist.RelistedFromID)
+	assert.Equal(t, &original.ID, relist.OriginalAuctionID)
+	events := auctionEvents(t, store)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, model.EventAuctionCreated, events[0].Type)
+	}
+
+	_, err = auctionService.RelistAuction(1, original.ID, timeAt(5*time.Hour))
+	assert.ErrorIs(t, err, service.ErrNotRelistable, "each auction is relisted once")
+}
+
+func TestRelistAuctionRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		auction model.Auction
+		userID  int
+		wantErr error
+	}{
+		{"open", model.Auction{Item: "Open", UserID: 1, Status: model.AuctionStatusOpen}, 1, service.ErrNotRelistable},
+		{"scheduled", model.Auction{Item: "Later", UserID: 1, Status: model.AuctionStatusScheduled, StartsAt: timeAt(time.Hour)}, 1, service.ErrNotRelistable},
+		{"sold", model.Auction{Item: "Sold", UserID: 1, Status: model.AuctionStatusClosed, WinnerID: 2}, 1, service.ErrNotRelistable},
+		{"not the seller", model.Auction{Item: "Unsold", UserID: 1, Status: model.AuctionStatusClosed}, 2, service.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auctionService, store := newClockedService(&fakeClock{now: start})
+			auction := seedAuction(t, store, tt.auction)
+
+			_, err := auctionService.RelistAuction(tt.userID, auction.ID, nil)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	auctionService, _ := newTestService()
+	_, err := auctionService.RelistAuction(1, 99, nil)
+	assert.ErrorIs(t, err, service.ErrNotFound)
+}
+
+func TestScheduledRelist(t *testing.T) {
+	clock := &fakeClock{now: start.Add(4 * time.Hour)}
+	store := repository.NewMemoryStore()
+	auctionService := service.NewAuctionService(store.Auctions(), store, service.WithClock(clock.Now))
+	closer := service.NewAuctionCloser(store.Auctions(), store, time.Second, service.WithClock(clock.Now))
+	original := seedUnsold(t, store)
+
+	relist, err := auctionService.RelistAuction(1, original.ID, timeAt(24*time.Hour))
+
+	require.NoError(t, err)
+	assert.Equal(t, model.AuctionStatusScheduled, relist.Status)
+	assert.Nil(t, relist.NextMinimumBid)
+	if assert.NotNil(t, relist.EndsAt) {
+		assert.Equal(t, start.Add(26*time.Hour), *relist.EndsAt)
+	}
+	_, err = auctionService.PlaceBid(relist.ID, 2, usd(20))
+	assert.ErrorIs(t, err, service.ErrAuctionNotStarted)
+	_, err = auctionService.BuyNow(relist.ID, 2)
+	assert.ErrorIs(t, err, service.ErrAuctionNotStarted)
+	reserve := usd(50)
+	_, err = auctionService.UpdateAuction(1, model.AuctionUpdate{ID: relist.ID, Item: "Brass Desk Lamp", ReservePrice: &reserve})
+	assert.NoError(t, err, "scheduled auctions can be edited")
+
+	opened, err := closer.OpenScheduled()
+	require.NoError(t, err)
+	assert.Zero(t, opened)
+
+	clock.Set(start.Add(24 * time.Hour))
+	opened, err = closer.OpenScheduled()
+	require.NoError(t, err)
+	assert.Equal(t, 1, opened)
+	events := auctionEvents(t, store)
+	if assert.NotEmpty(t, events) {
+		last := events[len(events)-1]
+		assert.Equal(t, model.EventAuctionStarted, last.Type)
+		assert.Equal(t, relist.ID, last.AuctionID)
+	}
+
+	bid, err := auctionService.PlaceBid(relist.ID, 2, usd(20))
+	require.NoError(t, err)
+	assert.Equal(t, model.AuctionStatusOpen, bid.Status)
+	assert.Equal(t, usd(20), bid.CurrentPrice)
+
+	// The reserve was not met, so the relist can be relisted in turn.
+	clock.Set(start.Add(26 * time.Hour))
+	closed, err := closer.CloseEnded()
+	require.NoError(t, err)
+	assert.Equal(t, 1, closed)
+	second, err := auctionService.RelistAuction(1, relist.ID, timeAt(48*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, &relist.ID, second.RelistedFromID)
+	assert.Equal(t, &original.ID, second.OriginalAuctionID, "relists point back to the first listing")
+
+	lineage, err := auctionService.GetRelistLineage(relist.ID)
+	require.NoError(t, err)
+	ids := []int{}
+	for _, auction := range lineage {
+		ids = append(ids, auction.ID)
+	}
+	assert.Equal(t, []int{original.ID, relist.ID, second.ID}, ids)
+}
+
+func TestCreateScheduledAuction(t *testing.T) {
+	auctionService, _ := newClockedService(&fakeClock{now: start})
+
+	created, err := auctionService.CreateAuction(model.Auction{Item: "Later", UserID: 1, StartsAt: timeAt(time.Hour), EndsAt: timeAt(2 * time.Hour),
+		RelistedFromID: new(int), OriginalAuctionID: new(int)})
+	require.NoError(t, err)
+	assert.Equal(t, model.AuctionStatusScheduled, created.Status)
+	assert.Nil(t, created.RelistedFromID, "only relisting sets the lineage")
+	assert.Nil(t, created.OriginalAuctionID)
+
+	_, err = auctionService.CreateAuction(model.Auction{Item: "Past", UserID: 1, StartsAt: timeAt(-time.Minute)})
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+	_, err = auctionService.CreateAuction(model.Auction{Item: "Backwards", UserID: 1, StartsAt: timeAt(2 * time.Hour), EndsAt: timeAt(time.Hour)})
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+}
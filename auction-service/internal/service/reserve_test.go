@@ -0,0 +1,129 @@
+package service_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloseAuctionOutcome(t *testing.T) {
+	tests := []struct {
+		name       string
+		reserve    float64
+		bids       []float64
+		wantEvent  string
+		wantWinner int
+	}{
+		{name: "no bids", wantEvent: model.EventAuctionUnsold},
+		{name: "no reserve", bids: []float64{15}, wantEvent: model.EventAuctionSold, wantWinner: 2},
+		{name: "reserve not met", reserve: 50, bids: []float64{15, 30}, wantEvent: model.EventAuctionUnsold},
+		{name: "reserve met", reserve: 50, bids: []float64{15, 50}, wantEvent: model.EventAuctionSold, wantWinner: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auctionService, store := newTestService()
+			auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen, CurrentPrice: usd(10), ReservePrice: usd(tt.reserve)})
+			for _, amount := range tt.bids {
+				_, err := auctionService.PlaceBid(auction.ID, 2, usd(amount))
+				require.NoError(t, err)
+			}
+
+			closed, err := auctionService.CloseAuction(1, auction.ID)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantWinner, closed.WinnerID)
+			events := auctionEvents(t, store)
+			if tt.wantWinner != 0 {
+				// A sale opens an order for the winner, announced last.
+				assert.Equal(t, model.EventOrderCreated, events[len(events)-1].Type)
+				events = events[:len(events)-1]
+			}
+			last := events[len(events)-1]
+			assert.Equal(t, tt.wantEvent, last.Type)
+			if tt.wantWinner != 0 {
+				assert.Equal(t, tt.wantWinner, last.UserID)
+				assert.Equal(t, closed.CurrentPrice, *last.Amount)
+			}
+		})
+	}
+}
+
+func TestBuyNow(t *testing.T) {
+	tests := []struct {
+		name    string
+		auction model.Auction
+		bids    []float64
+		buyerID int
+		wantErr error
+	}{
+		{name: "no bids", auction: model.Auction{BuyNowPrice: usd(100)}, buyerID: 3},
+		{name: "bids below the reserve", auction: model.Auction{ReservePrice: usd(50), BuyNowPrice: usd(100)}, bids: []float64{20}, buyerID: 3},
+		{name: "reserve met", auction: model.Auction{ReservePrice: usd(50), BuyNowPrice: usd(100)}, bids: []float64{60}, buyerID: 3, wantErr: service.ErrBuyNowUnavailable},
+		{name: "bid without reserve", auction: model.Auction{BuyNowPrice: usd(100)}, bids: []float64{20}, buyerID: 3, wantErr: service.ErrBuyNowUnavailable},
+		{name: "no buy now price", auction: model.Auction{}, buyerID: 3, wantErr: service.ErrBuyNowUnavailable},
+		{name: "seller", auction: model.Auction{BuyNowPrice: usd(100)}, buyerID: 1, wantErr: service.ErrForbidden},
+		{name: "closed", auction: model.Auction{BuyNowPrice: usd(100), Status: model.AuctionStatusClosed}, buyerID: 3, wantErr: service.ErrAuctionClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auctionService, store := newTestService()
+			tt.auction.Item, tt.auction.UserID, tt.auction.CurrentPrice = "Test Item", 1, usd(10)
+			if tt.auction.Status == "" {
+				tt.auction.Status = model.AuctionStatusOpen
+			}
+			auction := seedAuction(t, store, tt.auction)
+			for _, amount := range tt.bids {
+				_, err := auctionService.PlaceBid(auction.ID, 2, usd(amount))
+				require.NoError(t, err)
+			}
+
+			sold, err := auctionService.BuyNow(auction.ID, tt.buyerID)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				stored, _ := auctionService.GetAuctionByID(auction.ID)
+				assert.Equal(t, tt.auction.Status, stored.Status)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, model.AuctionStatusClosed, sold.Status)
+			assert.Equal(t, tt.buyerID, sold.WinnerID)
+			assert.Equal(t, usd(100), sold.CurrentPrice)
+			events := auctionEvents(t, store)
+			assert.Equal(t, model.EventAuctionSold, events[len(events)-2].Type)
+			assert.Equal(t, model.EventOrderCreated, events[len(events)-1].Type)
+		})
+	}
+}
+
+func TestReserveAndBuyNowValidation(t *testing.T) {
+	auctionService, store := newTestService()
+
+	invalid := []model.Auction{
+		{Item: "Test Item", UserID: 1, CurrentPrice: usd(10), ReservePrice: usd(10)},
+		{Item: "Test Item", UserID: 1, CurrentPrice: usd(10), BuyNowPrice: usd(5)},
+		{Item: "Test Item", UserID: 1, CurrentPrice: usd(10), ReservePrice: usd(50), BuyNowPrice: usd(40)},
+		{Item: "Test Item", UserID: 1, ReservePrice: usd(-1)},
+	}
+	for _, auction := range invalid {
+		_, err := auctionService.CreateAuction(auction)
+		assert.ErrorIs(t, err, service.ErrInvalidInput)
+	}
+
+	auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen, CurrentPrice: usd(10), ReservePrice: usd(50)})
+	reserve, buyNow := usd(40), usd(80)
+	updated, err := auctionService.UpdateAuction(1, model.AuctionUpdate{ID: auction.ID, Item: "Test Item", ReservePrice: &reserve, BuyNowPrice: &buyNow})
+	require.NoError(t, err)
+	assert.Equal(t, usd(40), updated.ReservePrice)
+
+	_, err = auctionService.PlaceBid(auction.ID, 2, usd(20))
+	require.NoError(t, err)
+	reserve = usd(30)
+	_, err = auctionService.UpdateAuction(1, model.AuctionUpdate{ID: auction.ID, Item: "Test Item", ReservePrice: &reserve, BuyNowPrice: &buyNow})
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+}
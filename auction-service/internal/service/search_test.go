@@ -0,0 +1,87 @@
+package service_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/service"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchAuctions(t *testing.T) {
+	auctionService, store := newTestService()
+	categoryService := service.NewCategoryService(store)
+	_, shoes, sneakers, _ := seedCategories(t, categoryService)
+
+	var filler []string
+	for i := 0; i < 40; i++ {
+		filler = append(filler, "word")
+	}
+	long := strings.Join(filler, " ") + " red laces " + strings.Join(filler, " ")
+	runners := seedAuction(t, store, model.Auction{Item: "Trail runners", Description: long, UserID: 1, Status: model.AuctionStatusOpen, CurrentPrice: usd(10),
+		CategoryID: &sneakers.ID, Attributes: map[string]string{"size": "42", "condition": "used"}})
+	boots := seedAuction(t, store, model.Auction{Item: "Red boots", UserID: 1, Status: model.AuctionStatusOpen,
+		CategoryID: &shoes.ID, Attributes: map[string]string{"size": "41"}})
+	seedAuction(t, store, model.Auction{Item: "Red scarf", UserID: 1, Status: model.AuctionStatusOpen})
+
+	results, err := auctionService.SearchAuctions("red", service.AuctionFilter{Category: "shoes"}, 0)
+	require.NoError(t, err)
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, boots.ID, results[0].Auction.ID)
+		assert.Equal(t, runners.ID, results[1].Auction.ID)
+		assert.Equal(t, usd(11), *results[1].Auction.NextMinimumBid)
+		snippet := results[1].Snippet
+		assert.True(t, strings.HasPrefix(snippet, "… word"), snippet)
+		assert.True(t, strings.HasSuffix(snippet, "word …"), snippet)
+		assert.Contains(t, snippet, "<mark>red</mark> laces")
+		assert.Len(t, strings.Fields(snippet), 22)
+	}
+
+	results, err = auctionService.SearchAuctions("RED", service.AuctionFilter{Attributes: map[string]string{"size": "41"}}, 0)
+	require.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, boots.ID, results[0].Auction.ID)
+	}
+
+	// The limit applies after the attribute filter.
+	results, err = auctionService.SearchAuctions("red", service.AuctionFilter{Category: "fashion", Attributes: map[string]string{"condition": "used"}}, 1)
+	require.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, runners.ID, results[0].Auction.ID)
+	}
+
+	_, err = auctionService.SearchAuctions(" ?! ", service.AuctionFilter{}, 0)
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+	_, err = auctionService.SearchAuctions("red", service.AuctionFilter{}, service.MaxSearchLimit+1)
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+	_, err = auctionService.SearchAuctions("red", service.AuctionFilter{Category: "garden"}, 0)
+	assert.ErrorIs(t, err, service.ErrCategoryNotFound)
+}
+
+func TestSearchAuctionsWithLengthChangingCase(t *testing.T) {
+	auctionService, store := newTestService()
+	// Ⱥ takes two bytes and its lower case ⱥ three; İ lower cases to i and
+	// a combining dot, three bytes instead of two.
+	rug := seedAuction(t, store, model.Auction{Item: "Rug", Description: "carpet " + strings.Repeat("ȺȺȺȺ ", 25), UserID: 1, Status: model.AuctionStatusOpen})
+	mat := seedAuction(t, store, model.Auction{Item: "İİİİ İİİİ carpet", UserID: 1, Status: model.AuctionStatusOpen})
+
+	results, err := auctionService.SearchAuctions("carpet", service.AuctionFilter{}, 0)
+	require.NoError(t, err)
+	if assert.Len(t, results, 2) {
+		byID := map[int]model.SearchResult{results[0].Auction.ID: results[0], results[1].Auction.ID: results[1]}
+		assert.Equal(t, "İİİİ İİİİ <mark>carpet</mark>", byID[mat.ID].HighlightedItem)
+		snippet := byID[rug.ID].Snippet
+		assert.True(t, utf8.ValidString(snippet), snippet)
+		assert.True(t, strings.HasPrefix(snippet, "<mark>carpet</mark> ȺȺȺȺ"), snippet)
+		assert.True(t, strings.HasSuffix(snippet, "ȺȺȺȺ …"), snippet)
+	}
+
+	results, err = auctionService.SearchAuctions("ⱥⱥ", service.AuctionFilter{}, 0)
+	require.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.True(t, strings.HasPrefix(results[0].Snippet, "carpet <mark>ȺȺȺȺ</mark> <mark>ȺȺȺȺ</mark>"), results[0].Snippet)
+	}
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+)
+
+var (
+	// ErrOfferNotFound is returned when the second-chance offer does not
+	// exist or was made to another user.
+	ErrOfferNotFound = errors.New("offer not found")
+	// ErrOfferClosed is returned when a second-chance offer was already
+	// answered or has expired.
+	ErrOfferClosed = errors.New("offer is no longer open")
+	// ErrNoRunnerUp is returned when no other bidder is left to make a
+	// second-chance offer to.
+	ErrNoRunnerUp = errors.New("no runner-up bidder left")
+)
+
+// DefaultOfferPeriod is how long runner-up bidders have to accept a
+// second-chance offer.
+const DefaultOfferPeriod = 48 * time.Hour
+
+// OfferSecondChance offers the item of a closed auction whose buyer did not
+// pay to the best bidder not asked yet, at the price of their highest bid.
+// Bidders who already had an order or an offer for the auction are skipped.
+func (s *settlementService) OfferSecondChance(sellerID, auctionID int) (model.SecondChanceOffer, error) {
+	var offer model.SecondChanceOffer
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		auction, err := ownedAuction(uow, sellerID, auctionID)
+		if err != nil {
+			return err
+		}
+		orders, err := uow.Orders().GetOrdersByAuctionID(auction.ID)
+		if err != nil {
+			return err
+		}
+		if len(orders) == 0 {
+			return fmt.Errorf("%w: auction has no unpaid order", ErrOrderState)
+		}
+		unpaid := orders[len(orders)-1]
+		if unpaid.Status != model.OrderStatusUnpaid && unpaid.Status != model.OrderStatusSecondChanceOffered {
+			return fmt.Errorf("%w: order is %s", ErrOrderState, unpaid.Status)
+		}
+
+		offers, err := uow.SecondChanceOffers().GetOffersByAuctionID(auction.ID)
+		if err != nil {
+			return err
+		}
+		asked := map[int]bool{}
+		for _, order := range orders {
+			asked[order.BuyerID] = true
+		}
+		for _, previous := range offers {
+			if previous.IsPending() {
+				return fmt.Errorf("%w: an offer is still waiting for an answer", ErrOrderState)
+			}
+			asked[previous.BidderID] = true
+		}
+
+		bids, err := uow.Bids().GetBidsByAuctionID(auction.ID)
+		if err != nil {
+			return err
+		}
+		runnerUp, ok := bestBidExcept(activeBids(bids), asked)
+		if !ok {
+			return ErrNoRunnerUp
+		}
+
+		offer, err = uow.SecondChanceOffers().CreateOffer(model.SecondChanceOffer{
+			AuctionID: auction.ID,
+			OrderID:   unpaid.ID,
+			SellerID:  auction.UserID,
+			BidderID:  runnerUp.UserID,
+			BidID:     runnerUp.ID,
+			Amount:    runnerUp.Amount,
+			Status:    model.OfferStatusPending,
+			ExpiresAt: s.now().Add(s.offerPeriod).UTC(),
+		})
+		if err != nil {
+			return err
+		}
+		if unpaid.Status == model.OrderStatusUnpaid {
+			if err := setOrderStatus(uow, &unpaid, model.OrderStatusSecondChanceOffered); err != nil {
+				return err
+			}
+		}
+		return announceOffer(uow, offer, model.EventSecondChanceOffered)
+	})
+	if err != nil {
+		return model.SecondChanceOffer{}, err
+	}
+	return offer, nil
+}
+
+// AcceptSecondChance makes the bidder of a pending offer the buyer of the
+// auction and opens an order for them at the offered price.
+func (s *settlementService) AcceptSecondChance(bidderID, offerID int) (model.Order, error) {
+	var order model.Order
+	err := s.answerOffer(bidderID, offerID, model.OfferStatusAccepted, func(uow repository.UnitOfWork, offer *model.SecondChanceOffer) error {
+		auction, err := lockAuction(uow, offer.AuctionID)
+		if err != nil {
+			return err
+		}
+		auction.WinnerID = offer.BidderID
+		auction.CurrentPrice = offer.Amount
+		if err := uow.Auctions().UpdateAuction(auction); err != nil {
+			return err
+		}
+		order, err = openOrder(uow, auction, offer.BidderID, offer.Amount, s.now().Add(s.paymentPeriod))
+		offer.AcceptedOrderID = order.ID
+		return err
+	})
+	if err != nil {
+		return model.Order{}, err
+	}
+	return order, nil
+}
+
+// DeclineSecondChance records that the bidder turned a pending offer down.
+func (s *settlementService) DeclineSecondChance(bidderID, offerID int) (model.SecondChanceOffer, error) {
+	var declined model.SecondChanceOffer
+	err := s.answerOffer(bidderID, offerID, model.OfferStatusDeclined, func(uow repository.UnitOfWork, offer *model.SecondChanceOffer) error {
+		declined = *offer
+		return nil
+	})
+	if err != nil {
+		return model.SecondChanceOffer{}, err
+	}
+	declined.Status = model.OfferStatusDeclined
+	return declined, nil
+}
+
+// GetSecondChanceOffers returns every offer of an auction to its seller and
+// their own offers to other users.
+func (s *settlementService) GetSecondChanceOffers(userID, auctionID int) ([]model.SecondChanceOffer, error) {
+	offers := []model.SecondChanceOffer{}
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		auction, err := uow.Auctions().GetAuctionByID(auctionID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		all, err := uow.SecondChanceOffers().GetOffersByAuctionID(auctionID)
+		for _, offer := range all {
+			if auction.UserID == userID || offer.BidderID == userID {
+				offers = append(offers, offer)
+			}
+		}
+		return err
+	})
+	return offers, err
+}
+
+// answerOffer locks a pending offer made to bidderID, runs apply and records
+// the answer.
+func (s *settlementService) answerOffer(bidderID, offerID int, status string, apply func(uow repository.UnitOfWork, offer *model.SecondChanceOffer) error) error {
+	return s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		offer, err := uow.SecondChanceOffers().GetOfferByIDForUpdate(offerID)
+		if errors.Is(err, repository.ErrOfferNotFound) || err == nil && offer.BidderID != bidderID {
+			return ErrOfferNotFound
+		}
+		if err != nil {
+			return err
+		}
+		now := s.now().UTC()
+		if !offer.IsPending() || !now.Before(offer.ExpiresAt) {
+			return ErrOfferClosed
+		}
+		if err := apply(uow, &offer); err != nil {
+			return err
+		}
+		offer.Status = status
+		offer.RespondedAt = &now
+		if err := uow.SecondChanceOffers().UpdateOffer(offer); err != nil {
+			return err
+		}
+		eventType := model.EventSecondChanceDeclined
+		if status == model.OfferStatusAccepted {
+			eventType = model.EventSecondChanceAccepted
+		}
+		return announceOffer(uow, offer, eventType)
+	})
+}
+
+// bestBidExcept returns the highest bid of a bidder not in excluded, the
+// earliest one on ties.
+func bestBidExcept(bids []model.Bid, excluded map[int]bool) (model.Bid, bool) {
+	var best model.Bid
+	found := false
+	for _, bid := range bids {
+		if excluded[bid.UserID] {
+			continue
+		}
+		if !found || best.Amount.Less(bid.Amount) {
+			best, found = bid, true
+		}
+	}
+	return best, found
+}
+
+// announceOffer publishes eventType for offer.
+func announceOffer(uow repository.UnitOfWork, offer model.SecondChanceOffer, eventType string) error {
+	return enqueue(uow, model.Event{Type: eventType, AuctionID: offer.AuctionID, UserID: offer.BidderID, OrderID: offer.OrderID, OfferID: offer.ID, Amount: &offer.Amount})
+}
@@ -0,0 +1,127 @@
+package service_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"auction-service/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unpaidAuction closes an auction with bids of 20, 30 and 40 from users 2, 3
+// and 4, and lets the payment deadline of the winner pass.
+func unpaidAuction(t *testing.T, clock *fakeClock) (service.SettlementService, service.AuctionService, *service.PaymentDeadlines, *repository.MemoryStore, model.Auction) {
+	store := repository.NewMemoryStore()
+	opts := []service.Option{service.WithClock(clock.Now), service.WithPaymentPeriod(48 * time.Hour), service.WithOfferPeriod(24 * time.Hour)}
+	auctionService := service.NewAuctionService(store.Auctions(), store, opts...)
+	settlement := service.NewSettlementService(store, opts...)
+	deadlines := service.NewPaymentDeadlines(store.Orders(), store.SecondChanceOffers(), store, time.Minute, opts...)
+
+	auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen, CurrentPrice: usd(10), StartPrice: usd(10)})
+	for i, amount := range []float64{20, 30, 40} {
+		_, err := auctionService.PlaceBid(auction.ID, i+2, usd(amount))
+		require.NoError(t, err)
+	}
+	_, err := auctionService.CloseAuction(1, auction.ID)
+	require.NoError(t, err)
+
+	_, err = settlement.OfferSecondChance(1, auction.ID)
+	assert.ErrorIs(t, err, service.ErrOrderState)
+
+	clock.Set(clock.Now().Add(48 * time.Hour))
+	expired, err := deadlines.ExpireOverdue()
+	require.NoError(t, err)
+	require.Equal(t, 1, expired)
+	return settlement, auctionService, deadlines, store, auction
+}
+
+func TestSecondChanceAccepted(t *testing.T) {
+	clock := &fakeClock{now: start}
+	settlement, auctionService, _, store, auction := unpaidAuction(t, clock)
+
+	_, err := settlement.OfferSecondChance(2, auction.ID)
+	assert.ErrorIs(t, err, service.ErrForbidden)
+
+	offer, err := settlement.OfferSecondChance(1, auction.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, offer.BidderID)
+	assert.Equal(t, usd(30), offer.Amount)
+	assert.Equal(t, model.OfferStatusPending, offer.Status)
+	assert.Equal(t, clock.Now().Add(24*time.Hour), offer.ExpiresAt)
+
+	_, err = settlement.OfferSecondChance(1, auction.ID)
+	assert.ErrorIs(t, err, service.ErrOrderState)
+	_, err = settlement.AcceptSecondChance(2, offer.ID)
+	assert.ErrorIs(t, err, service.ErrOfferNotFound)
+
+	order, err := settlement.AcceptSecondChance(3, offer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, order.BuyerID)
+	assert.Equal(t, usd(30), order.Amount)
+	assert.Equal(t, model.OrderStatusAwaitingPayment, order.Status)
+
+	sold, err := auctionService.GetAuctionByID(auction.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, sold.WinnerID)
+	assert.Equal(t, usd(30), sold.CurrentPrice)
+
+	orders, err := store.Orders().GetOrdersByAuctionID(auction.ID)
+	require.NoError(t, err)
+	if assert.Len(t, orders, 2) {
+		assert.Equal(t, model.OrderStatusSecondChanceOffered, orders[0].Status)
+	}
+	offers, err := settlement.GetSecondChanceOffers(3, auction.ID)
+	require.NoError(t, err)
+	if assert.Len(t, offers, 1) {
+		assert.Equal(t, model.OfferStatusAccepted, offers[0].Status)
+		assert.Equal(t, order.ID, offers[0].AcceptedOrderID)
+	}
+	offers, err = settlement.GetSecondChanceOffers(2, auction.ID)
+	require.NoError(t, err)
+	assert.Empty(t, offers)
+
+	var types []string
+	for _, event := range outboxEvents(t, store) {
+		if event.OfferID != 0 {
+			types = append(types, event.Type)
+		}
+	}
+	assert.Equal(t, []string{model.EventSecondChanceOffered, model.EventSecondChanceAccepted}, types)
+}
+
+func TestSecondChanceDeclinedAndExpired(t *testing.T) {
+	clock := &fakeClock{now: start}
+	settlement, _, deadlines, _, auction := unpaidAuction(t, clock)
+
+	first, err := settlement.OfferSecondChance(1, auction.ID)
+	require.NoError(t, err)
+	declined, err := settlement.DeclineSecondChance(3, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OfferStatusDeclined, declined.Status)
+	_, err = settlement.AcceptSecondChance(3, first.ID)
+	assert.ErrorIs(t, err, service.ErrOfferClosed)
+
+	second, err := settlement.OfferSecondChance(1, auction.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, second.BidderID)
+	assert.Equal(t, usd(20), second.Amount)
+
+	clock.Set(clock.Now().Add(24 * time.Hour))
+	_, err = settlement.AcceptSecondChance(2, second.ID)
+	assert.ErrorIs(t, err, service.ErrOfferClosed)
+	expired, err := deadlines.ExpireOffers()
+	require.NoError(t, err)
+	assert.Equal(t, 1, expired)
+
+	offers, err := settlement.GetSecondChanceOffers(1, auction.ID)
+	require.NoError(t, err)
+	if assert.Len(t, offers, 2) {
+		assert.Equal(t, model.OfferStatusExpired, offers[1].Status)
+	}
+
+	_, err = settlement.OfferSecondChance(1, auction.ID)
+	assert.ErrorIs(t, err, service.ErrNoRunnerUp)
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+)
+
+var (
+	// ErrOrderNotFound is returned when the order does not exist or belongs
+	// to other users.
+	ErrOrderNotFound = errors.New("order not found")
+	// ErrOrderState is returned when an order cannot move to the requested
+	// state from the one it is in.
+	ErrOrderState = errors.New("order cannot move to that state")
+)
+
+// DefaultPaymentPeriod is how long winners have to pay for an auction.
+const DefaultPaymentPeriod = 72 * time.Hour
+
+// SettlementService moves the orders of sold auctions through payment,
+// shipping and completion, and lets sellers offer the item to a runner-up
+// when the buyer does not pay.
+type SettlementService interface {
+	// GetOrder returns an order to its buyer or seller.
+	GetOrder(userID, id int) (model.Order, error)
+	// GetAuctionOrders returns the orders of an auction to its seller.
+	GetAuctionOrders(sellerID, auctionID int) ([]model.Order, error)
+	PayOrder(buyerID, id int) (model.Order, error)
+	ShipOrder(sellerID, id int, trackingNumber string) (model.Order, error)
+	CompleteOrder(buyerID, id int) (model.Order, error)
+	OfferSecondChance(sellerID, auctionID int) (model.SecondChanceOffer, error)
+	AcceptSecondChance(bidderID, offerID int) (model.Order, error)
+	DeclineSecondChance(bidderID, offerID int) (model.SecondChanceOffer, error)
+	GetSecondChanceOffers(userID, auctionID int) ([]model.SecondChanceOffer, error)
+}
+
+type settlementService struct {
+	txManager     repository.TxManager
+	now           func() time.Time
+	paymentPeriod time.Duration
+	offerPeriod   time.Duration
+}
+
+// Ensure settlementService implements SettlementService
+var _ SettlementService = (*settlementService)(nil)
+
+// NewSettlementService creates a SettlementService. Every transition runs in
+// a txManager transaction together with the event announcing it.
+func NewSettlementService(txManager repository.TxManager, opts ...Option) SettlementService {
+	o := newOptions(opts)
+	return &settlementService{txManager: txManager, now: o.now, paymentPeriod: o.paymentPeriod, offerPeriod: o.offerPeriod}
+}
+
+func (s *settlementService) GetOrder(userID, id int) (model.Order, error) {
+	var order model.Order
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		var err error
+		order, err = uow.Orders().GetOrderByID(id)
+		if errors.Is(err, repository.ErrOrderNotFound) || err == nil && userID != order.BuyerID && userID != order.SellerID {
+			return ErrOrderNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return model.Order{}, err
+	}
+	return order, nil
+}
+
+func (s *settlementService) GetAuctionOrders(sellerID, auctionID int) ([]model.Order, error) {
+	var orders []model.Order
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		auction, err := uow.Auctions().GetAuctionByID(auctionID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if auction.UserID != sellerID {
+			return ErrForbidden
+		}
+		orders, err = uow.Orders().GetOrdersByAuctionID(auctionID)
+		return err
+	})
+	if orders == nil {
+		orders = []model.Order{}
+	}
+	return orders, err
+}
+
+// PayOrder records the payment of the buyer. Payments after the deadline are
+// refused even when the order was not marked unpaid yet.
+func (s *settlementService) PayOrder(buyerID, id int) (model.Order, error) {
+	return s.transition(id, model.OrderStatusPaid, model.EventOrderPaid, func(order *model.Order, now time.Time) error {
+		if order.BuyerID != buyerID {
+			return ErrOrderNotFound
+		}
+		if order.Status == model.OrderStatusAwaitingPayment && !now.Before(order.PaymentDueAt) {
+			return fmt.Errorf("%w: the payment deadline has passed", ErrOrderState)
+		}
+		order.PaidAt = &now
+		return nil
+	})
+}
+
+// ShipOrder records that the seller shipped a paid order.
+func (s *settlementService) ShipOrder(sellerID, id int, trackingNumber string) (model.Order, error) {
+	return s.transition(id, model.OrderStatusShipped, model.EventOrderShipped, func(order *model.Order, now time.Time) error {
+		if order.SellerID != sellerID {
+			return ErrOrderNotFound
+		}
+		order.ShippedAt = &now
+		order.TrackingNumber = strings.TrimSpace(trackingNumber)
+		return nil
+	})
+}
+
+// CompleteOrder records that the buyer received a shipped order.
+func (s *settlementService) CompleteOrder(buyerID, id int) (model.Order, error) {
+	return s.transition(id, model.OrderStatusCompleted, model.EventOrderCompleted, func(order *model.Order, now time.Time) error {
+		if order.BuyerID != buyerID {
+			return ErrOrderNotFound
+		}
+		order.CompletedAt = &now
+		return nil
+	})
+}
+
+// transition locks the order, lets apply check the caller and fill in the
+// details of the new state, then moves the order to status and announces it
+// with eventType.
+func (s *settlementService) transition(id int, status, eventType string, apply func(order *model.Order, now time.Time) error) (model.Order, error) {
+	var order model.Order
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		var err error
+		order, err = uow.Orders().GetOrderByIDForUpdate(id)
+		if errors.Is(err, repository.ErrOrderNotFound) {
+			return ErrOrderNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if err := apply(&order, s.now().UTC()); err != nil {
+			return err
+		}
+		return moveOrder(uow, &order, status, eventType)
+	})
+	if err != nil {
+		return model.Order{}, err
+	}
+	return order, nil
+}
+
+// moveOrder moves order to status and announces the change with eventType.
+func moveOrder(uow repository.UnitOfWork, order *model.Order, status, eventType string) error {
+	if err := setOrderStatus(uow, order, status); err != nil {
+		return err
+	}
+	return enqueue(uow, model.Event{Type: eventType, AuctionID: order.AuctionID, UserID: order.BuyerID, OrderID: order.ID, Amount: &order.Amount})
+}
+
+// setOrderStatus moves order to status if its current state allows it.
+func setOrderStatus(uow repository.UnitOfWork, order *model.Order, status string) error {
+	if !order.CanMoveTo(status) {
+		return fmt.Errorf("%w: order is %s", ErrOrderState, order.Status)
+	}
+	order.Status = status
+	return uow.Orders().UpdateOrder(*order)
+}
+
+// openOrder creates the order buyerID has to pay amount for by paymentDue.
+func openOrder(uow repository.UnitOfWork, auction model.Auction, buyerID int, amount model.Money, paymentDue time.Time) (model.Order, error) {
+	order, err := uow.Orders().CreateOrder(model.Order{
+		AuctionID:    auction.ID,
+		SellerID:     auction.UserID,
+		BuyerID:      buyerID,
+		Amount:       amount,
+		Status:       model.OrderStatusAwaitingPayment,
+		PaymentDueAt: paymentDue.UTC(),
+	})
+	if err != nil {
+		return order, err
+	}
+	return order, enqueue(uow, model.Event{Type: model.EventOrderCreated, AuctionID: auction.ID, UserID: buyerID, OrderID: order.ID, Amount: &order.Amount})
+}
+
+// paymentDue is the payment deadline of an auction sold now.
+func (s *auctionService) paymentDue() time.Time {
+	return s.now().Add(s.paymentPeriod)
+}
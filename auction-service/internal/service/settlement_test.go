@@ -0,0 +1,132 @@
+package service_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"auction-service/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// soldAuction closes an auction won by user 2 at 20 and returns the order
+// opened for it.
+func soldAuction(t *testing.T, clock *fakeClock) (service.SettlementService, *repository.MemoryStore, model.Order) {
+	store := repository.NewMemoryStore()
+	opts := []service.Option{service.WithClock(clock.Now), service.WithPaymentPeriod(48 * time.Hour)}
+	auctionService := service.NewAuctionService(store.Auctions(), store, opts...)
+	auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen, CurrentPrice: usd(10), StartPrice: usd(10)})
+	_, err := auctionService.PlaceBid(auction.ID, 2, usd(20))
+	require.NoError(t, err)
+	_, err = auctionService.CloseAuction(1, auction.ID)
+	require.NoError(t, err)
+
+	orders, err := store.Orders().GetOrdersByAuctionID(auction.ID)
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	return service.NewSettlementService(store, opts...), store, orders[0]
+}
+
+func TestSettlementHappyPath(t *testing.T) {
+	clock := &fakeClock{now: start}
+	settlement, store, order := soldAuction(t, clock)
+
+	assert.Equal(t, model.OrderStatusAwaitingPayment, order.Status)
+	assert.Equal(t, 1, order.SellerID)
+	assert.Equal(t, 2, order.BuyerID)
+	assert.Equal(t, usd(20), order.Amount)
+	assert.Equal(t, start.Add(48*time.Hour), order.PaymentDueAt)
+
+	_, err := settlement.ShipOrder(1, order.ID, "TRACK-1")
+	assert.ErrorIs(t, err, service.ErrOrderState)
+	_, err = settlement.PayOrder(3, order.ID)
+	assert.ErrorIs(t, err, service.ErrOrderNotFound)
+
+	paid, err := settlement.PayOrder(2, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusPaid, paid.Status)
+	assert.NotNil(t, paid.PaidAt)
+
+	_, err = settlement.ShipOrder(2, order.ID, "TRACK-1")
+	assert.ErrorIs(t, err, service.ErrOrderNotFound)
+	shipped, err := settlement.ShipOrder(1, order.ID, " TRACK-1 ")
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusShipped, shipped.Status)
+	assert.Equal(t, "TRACK-1", shipped.TrackingNumber)
+
+	completed, err := settlement.CompleteOrder(2, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusCompleted, completed.Status)
+	_, err = settlement.CompleteOrder(2, order.ID)
+	assert.ErrorIs(t, err, service.ErrOrderState)
+
+	var types []string
+	for _, event := range outboxEvents(t, store) {
+		if event.OrderID != 0 {
+			types = append(types, event.Type)
+		}
+	}
+	assert.Equal(t, []string{model.EventOrderCreated, model.EventOrderPaid, model.EventOrderShipped, model.EventOrderCompleted}, types)
+
+	got, err := settlement.GetOrder(1, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusCompleted, got.Status)
+	_, err = settlement.GetOrder(3, order.ID)
+	assert.ErrorIs(t, err, service.ErrOrderNotFound)
+	orders, err := settlement.GetAuctionOrders(1, order.AuctionID)
+	require.NoError(t, err)
+	assert.Len(t, orders, 1)
+	_, err = settlement.GetAuctionOrders(2, order.AuctionID)
+	assert.ErrorIs(t, err, service.ErrForbidden)
+}
+
+func TestPaymentDeadlines(t *testing.T) {
+	clock := &fakeClock{now: start}
+	settlement, store, order := soldAuction(t, clock)
+	deadlines := service.NewPaymentDeadlines(store.Orders(), store.SecondChanceOffers(), store, time.Minute, service.WithClock(clock.Now))
+
+	clock.Set(start.Add(47 * time.Hour))
+	expired, err := deadlines.ExpireOverdue()
+	require.NoError(t, err)
+	assert.Zero(t, expired)
+
+	clock.Set(start.Add(48 * time.Hour))
+	_, err = settlement.PayOrder(2, order.ID)
+	assert.ErrorIs(t, err, service.ErrOrderState)
+
+	expired, err = deadlines.ExpireOverdue()
+	require.NoError(t, err)
+	assert.Equal(t, 1, expired)
+	unpaid, err := settlement.GetOrder(2, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusUnpaid, unpaid.Status)
+	events := outboxEvents(t, store)
+	assert.Equal(t, model.EventOrderUnpaid, events[len(events)-1].Type)
+
+	expired, err = deadlines.ExpireOverdue()
+	require.NoError(t, err)
+	assert.Zero(t, expired)
+}
+
+func TestOrderTransitions(t *testing.T) {
+	tests := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{model.OrderStatusAwaitingPayment, model.OrderStatusPaid, true},
+		{model.OrderStatusAwaitingPayment, model.OrderStatusUnpaid, true},
+		{model.OrderStatusAwaitingPayment, model.OrderStatusShipped, false},
+		{model.OrderStatusPaid, model.OrderStatusShipped, true},
+		{model.OrderStatusPaid, model.OrderStatusUnpaid, false},
+		{model.OrderStatusShipped, model.OrderStatusCompleted, true},
+		{model.OrderStatusUnpaid, model.OrderStatusSecondChanceOffered, true},
+		{model.OrderStatusUnpaid, model.OrderStatusPaid, false},
+		{model.OrderStatusCompleted, model.OrderStatusPaid, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.allowed, model.Order{Status: tt.from}.CanMoveTo(tt.to), "%s -> %s", tt.from, tt.to)
+	}
+}
@@ -0,0 +1,182 @@
+package service_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"auction-service/internal/service"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a settable clock for service.WithClock.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+var start = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func newClockedService(clock *fakeClock) (service.AuctionService, *repository.MemoryStore) {
+	store := repository.NewMemoryStore()
+	return service.NewAuctionService(store.Auctions(), store, service.WithClock(clock.Now)), store
+}
+
+func timeAt(offset time.Duration) *time.Time {
+	t := start.Add(offset)
+	return &t
+}
+
+func TestSoftCloseExtension(t *testing.T) {
+	tests := []struct {
+		name      string
+		auction   model.Auction
+		bidAt     time.Duration
+		wantEnd   time.Duration
+		wantCount int
+	}{
+		{
+			name:    "bid outside the window",
+			auction: model.Auction{EndsAt: timeAt(time.Hour), SoftCloseWindowMinutes: 5, SoftCloseExtensionMinutes: 2},
+			bidAt:   50 * time.Minute,
+			wantEnd: time.Hour,
+		},
+		{
+			name:      "bid inside the window",
+			auction:   model.Auction{EndsAt: timeAt(time.Hour), SoftCloseWindowMinutes: 5, SoftCloseExtensionMinutes: 2},
+			bidAt:     58 * time.Minute,
+			wantEnd:   62 * time.Minute,
+			wantCount: 1,
+		},
+		{
+			name:      "extension limited by the cap",
+			auction:   model.Auction{EndsAt: timeAt(time.Hour), SoftCloseWindowMinutes: 5, SoftCloseExtensionMinutes: 2, SoftCloseCapMinutes: 1},
+			bidAt:     59 * time.Minute,
+			wantEnd:   61 * time.Minute,
+			wantCount: 1,
+		},
+		{
+			name:    "cap already reached",
+			auction: model.Auction{EndsAt: timeAt(61 * time.Minute), OriginalEndsAt: timeAt(time.Hour), SoftCloseWindowMinutes: 5, SoftCloseExtensionMinutes: 2, SoftCloseCapMinutes: 1},
+			bidAt:   60 * time.Minute,
+			wantEnd: 61 * time.Minute,
+		},
+		{
+			name:    "no soft close",
+			auction: model.Auction{EndsAt: timeAt(time.Hour)},
+			bidAt:   59 * time.Minute,
+			wantEnd: time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{now: start}
+			auctionService, store := newClockedService(clock)
+			tt.auction.Item, tt.auction.UserID, tt.auction.Status = "Test Item", 1, model.AuctionStatusOpen
+			if tt.auction.OriginalEndsAt == nil {
+				tt.auction.OriginalEndsAt = tt.auction.EndsAt
+			}
+			auction := seedAuction(t, store, tt.auction)
+
+			clock.Set(start.Add(tt.bidAt))
+			updated, err := auctionService.PlaceBid(auction.ID, 2, usd(20))
+			require.NoError(t, err)
+
+			assert.Equal(t, start.Add(tt.wantEnd), *updated.EndsAt)
+			assert.Equal(t, tt.wantCount, updated.Extensions)
+			var extended []model.Event
+			for _, event := range outboxEvents(t, store) {
+				if event.Type == model.EventAuctionExtended {
+					extended = append(extended, event)
+				}
+			}
+			if assert.Len(t, extended, tt.wantCount) && tt.wantCount > 0 {
+				assert.Equal(t, start.Add(tt.wantEnd), *extended[0].EndsAt)
+			}
+		})
+	}
+}
+
+func TestPlaceBidAfterEnd(t *testing.T) {
+	clock := &fakeClock{now: start}
+	auctionService, store := newClockedService(clock)
+	auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen, EndsAt: timeAt(time.Hour)})
+
+	clock.Set(start.Add(time.Hour))
+
+	_, err := auctionService.PlaceBid(auction.ID, 2, usd(20))
+	assert.ErrorIs(t, err, service.ErrAuctionClosed)
+	_, err = auctionService.PlaceProxyBid(auction.ID, 2, usd(20))
+	assert.ErrorIs(t, err, service.ErrAuctionClosed)
+}
+
+func TestCreateAuctionSchedule(t *testing.T) {
+	clock := &fakeClock{now: start}
+	auctionService, _ := newClockedService(clock)
+
+	created, err := auctionService.CreateAuction(model.Auction{Item: "Test Item", UserID: 1, EndsAt: timeAt(time.Hour), SoftCloseWindowMinutes: 5, SoftCloseExtensionMinutes: 2})
+	require.NoError(t, err)
+	assert.Equal(t, start.Add(time.Hour), *created.OriginalEndsAt)
+
+	invalid := []model.Auction{
+		{Item: "Test Item", UserID: 1, EndsAt: timeAt(0)},
+		{Item: "Test Item", UserID: 1, SoftCloseWindowMinutes: 5, SoftCloseExtensionMinutes: 2},
+		{Item: "Test Item", UserID: 1, EndsAt: timeAt(time.Hour), SoftCloseWindowMinutes: 5},
+		{Item: "Test Item", UserID: 1, EndsAt: timeAt(time.Hour), SoftCloseCapMinutes: -1},
+	}
+	for _, auction := range invalid {
+		_, err := auctionService.CreateAuction(auction)
+		assert.ErrorIs(t, err, service.ErrInvalidInput)
+	}
+}
+
+func TestAuctionCloserRespectsExtensions(t *testing.T) {
+	clock := &fakeClock{now: start}
+	store := repository.NewMemoryStore()
+	auctionService := service.NewAuctionService(store.Auctions(), store, service.WithClock(clock.Now))
+	closer := service.NewAuctionCloser(store.Auctions(), store, time.Second, service.WithClock(clock.Now))
+	auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen,
+		EndsAt: timeAt(time.Minute), OriginalEndsAt: timeAt(time.Minute), SoftCloseWindowMinutes: 5, SoftCloseExtensionMinutes: 2})
+	untimed := seedAuction(t, store, model.Auction{Item: "No End", UserID: 1, Status: model.AuctionStatusOpen})
+
+	clock.Set(start.Add(30 * time.Second))
+	_, err := auctionService.PlaceBid(auction.ID, 2, usd(20))
+	require.NoError(t, err)
+
+	clock.Set(start.Add(2 * time.Minute))
+	closed, err := closer.CloseEnded()
+	assert.NoError(t, err)
+	assert.Zero(t, closed)
+
+	clock.Set(start.Add(3 * time.Minute))
+	closed, err = closer.CloseEnded()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, closed)
+
+	stored, _ := auctionService.GetAuctionByID(auction.ID)
+	assert.Equal(t, model.AuctionStatusClosed, stored.Status)
+	stillOpen, _ := auctionService.GetAuctionByID(untimed.ID)
+	assert.Equal(t, model.AuctionStatusOpen, stillOpen.Status)
+
+	events := auctionEvents(t, store)
+	assert.Equal(t, model.EventAuctionClosed, events[len(events)-3].Type)
+	assert.Equal(t, model.EventAuctionSold, events[len(events)-2].Type)
+	assert.Equal(t, model.EventOrderCreated, events[len(events)-1].Type)
+	assert.Equal(t, 2, stored.WinnerID)
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+)
+
+// ErrTemplateNotFound is returned when the auction template does not exist
+// or belongs to another seller.
+var ErrTemplateNotFound = errors.New("auction template not found")
+
+// MaxTemplateNameLength is the longest template name accepted, in
+// characters.
+const MaxTemplateNameLength = 100
+
+// TemplateService keeps the auction templates sellers save to list the same
+// kind of item repeatedly. Templates are private: sellers only see and use
+// their own.
+type TemplateService interface {
+	// CreateTemplate stores a template after checking that it lists a
+	// valid auction.
+	CreateTemplate(template model.AuctionTemplate) (model.AuctionTemplate, error)
+	// GetTemplates returns the templates of userID ordered by name.
+	GetTemplates(userID int) ([]model.AuctionTemplate, error)
+	GetTemplate(userID, id int) (model.AuctionTemplate, error)
+	// UpdateTemplate replaces the name and listing of a template of userID.
+	UpdateTemplate(userID int, template model.AuctionTemplate) (model.AuctionTemplate, error)
+	DeleteTemplate(userID, id int) error
+	// ListFromTemplate creates an auction from a template of userID. It
+	// opens at startsAt when that is set and right away otherwise.
+	ListFromTemplate(userID, id int, startsAt *time.Time) (model.Auction, error)
+}
+
+type templateService struct {
+	txManager repository.TxManager
+	auctions  AuctionService
+	now       func() time.Time
+}
+
+// Ensure templateService implements TemplateService
+var _ TemplateService = (*templateService)(nil)
+
+// NewTemplateService creates a TemplateService that lists auctions through
+// auctions.
+func NewTemplateService(txManager repository.TxManager, auctions AuctionService, opts ...Option) TemplateService {
+	o := newOptions(opts)
+	return &templateService{txManager: txManager, auctions: auctions, now: o.now}
+}
+
+func (s *templateService) CreateTemplate(template model.AuctionTemplate) (model.AuctionTemplate, error) {
+	var created model.AuctionTemplate
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		if err := s.validateTemplate(uow, &template); err != nil {
+			return err
+		}
+		var err error
+		created, err = uow.Templates().CreateTemplate(template)
+		return err
+	})
+	if err != nil {
+		return model.AuctionTemplate{}, err
+	}
+	return created, nil
+}
+
+func (s *templateService) GetTemplates(userID int) ([]model.AuctionTemplate, error) {
+	var templates []model.AuctionTemplate
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		var err error
+		templates, err = uow.Templates().GetTemplatesByUserID(userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if templates == nil {
+		templates = []model.AuctionTemplate{}
+	}
+	return templates, nil
+}
+
+func (s *templateService) GetTemplate(userID, id int) (model.AuctionTemplate, error) {
+	var template model.AuctionTemplate
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		var err error
+		template, err = ownedTemplate(uow, userID, id)
+		return err
+	})
+	if err != nil {
+		return model.AuctionTemplate{}, err
+	}
+	return template, nil
+}
+
+func (s *templateService) UpdateTemplate(userID int, template model.AuctionTemplate) (model.AuctionTemplate, error) {
+	var updated model.AuctionTemplate
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		existing, err := ownedTemplate(uow, userID, template.ID)
+		if err != nil {
+			return err
+		}
+		template.UserID = existing.UserID
+		template.CreatedAt = existing.CreatedAt
+		if err := s.validateTemplate(uow, &template); err != nil {
+			return err
+		}
+		if err := uow.Templates().UpdateTemplate(template); err != nil {
+			return err
+		}
+		updated, err = uow.Templates().GetTemplateByID(template.ID)
+		return err
+	})
+	if err != nil {
+		return model.AuctionTemplate{}, err
+	}
+	return updated, nil
+}
+
+func (s *templateService) DeleteTemplate(userID, id int) error {
+	return s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		if _, err := ownedTemplate(uow, userID, id); err != nil {
+			return err
+		}
+		return uow.Templates().DeleteTemplate(id)
+	})
+}
+
+func (s *templateService) ListFromTemplate(userID, id int, startsAt *time.Time) (model.Auction, error) {
+	template, err := s.GetTemplate(userID, id)
+	if err != nil {
+		return model.Auction{}, err
+	}
+	return s.auctions.CreateAuction(template.Auction(s.now(), startsAt))
+}
+
+// validateTemplate checks the name and duration of template and that it
+// lists a valid auction, and normalizes its listing the way CreateAuction
+// would.
+func (s *templateService) validateTemplate(uow repository.UnitOfWork, template *model.AuctionTemplate) error {
+	template.Name = strings.TrimSpace(template.Name)
+	if template.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(template.Name) > MaxTemplateNameLength {
+		return fmt.Errorf("%w: name can be at most %d characters", ErrInvalidInput, MaxTemplateNameLength)
+	}
+	if template.DurationMinutes < 0 {
+		return fmt.Errorf("%w: duration cannot be negative", ErrInvalidInput)
+	}
+
+	auction := template.Auction(s.now(), nil)
+	if err := validateListing(&auction); err != nil {
+		return err
+	}
+	if err := categorize(uow, &auction); err != nil {
+		return err
+	}
+	if err := FormatOf(auction).Validate(auction); err != nil {
+		return err
+	}
+	template.Item = auction.Item
+	template.Description = auction.Description
+	template.Format = auction.Format
+	template.Attributes = auction.Attributes
+	template.Currency = auction.Currency
+	template.StartPrice = auction.StartPrice
+	template.ReservePrice = auction.ReservePrice
+	template.BuyNowPrice = auction.BuyNowPrice
+	template.DutchDecrement = auction.DutchDecrement
+	template.DutchFloorPrice = auction.DutchFloorPrice
+	template.BidIncrements = auction.BidIncrements
+	return nil
+}
+
+// ownedTemplate returns a template of userID. Templates of other sellers
+// are reported as missing.
+func ownedTemplate(uow repository.UnitOfWork, userID, id int) (model.AuctionTemplate, error) {
+	template, err := uow.Templates().GetTemplateByID(id)
+	if errors.Is(err, repository.ErrTemplateNotFound) {
+		return model.AuctionTemplate{}, ErrTemplateNotFound
+	}
+	if err != nil {
+		return model.AuctionTemplate{}, err
+	}
+	if template.UserID != userID {
+		return model.AuctionTemplate{}, ErrTemplateNotFound
+	}
+	return template, nil
+}
@@ -0,0 +1,124 @@
+package service_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"auction-service/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTemplates(clock *fakeClock) (service.TemplateService, service.AuctionService, *repository.MemoryStore) {
+	store := repository.NewMemoryStore()
+	auctionService := service.NewAuctionService(store.Auctions(), store, service.WithClock(clock.Now))
+	return service.NewTemplateService(store, auctionService, service.WithClock(clock.Now)), auctionService, store
+}
+
+func lampTemplate() model.AuctionTemplate {
+	return model.AuctionTemplate{Name: " Lamps ", UserID: 1, Item: "Brass Lamp", StartPrice: usd(10), ReservePrice: usd(30),
+		BidIncrements: model.IncrementTable{{From: usd(0), Increment: usd(2)}}, DurationMinutes: 7 * 24 * 60}
+}
+
+func TestCreateTemplate(t *testing.T) {
+	templates, _, _ := newTestTemplates(&fakeClock{now: start})
+
+	created, err := templates.CreateTemplate(lampTemplate())
+
+	require.NoError(t, err)
+	assert.NotZero(t, created.ID)
+	assert.Equal(t, "Lamps", created.Name)
+	assert.Equal(t, model.AuctionFormatEnglish, created.Format)
+	assert.Equal(t, "USD", created.Currency)
+
+	own, err := templates.GetTemplates(1)
+	require.NoError(t, err)
+	assert.Len(t, own, 1)
+	others, err := templates.GetTemplates(2)
+	require.NoError(t, err)
+	assert.Empty(t, others)
+	_, err = templates.GetTemplate(2, created.ID)
+	assert.ErrorIs(t, err, service.ErrTemplateNotFound, "templates are private")
+}
+
+func TestCreateTemplateValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*model.AuctionTemplate)
+	}{
+		{"no name", func(template *model.AuctionTemplate) { template.Name = " " }},
+		{"no item", func(template *model.AuctionTemplate) { template.Item = "" }},
+		{"negative duration", func(template *model.AuctionTemplate) { template.DurationMinutes = -1 }},
+		{"reserve below start", func(template *model.AuctionTemplate) { template.ReservePrice = usd(5) }},
+		{"unknown category", func(template *model.AuctionTemplate) { category := 42; template.CategoryID = &category }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templates, _, _ := newTestTemplates(&fakeClock{now: start})
+			template := lampTemplate()
+			tt.modify(&template)
+
+			_, err := templates.CreateTemplate(template)
+
+			assert.ErrorIs(t, err, service.ErrInvalidInput)
+		})
+	}
+}
+
+func TestUpdateAndDeleteTemplate(t *testing.T) {
+	templates, _, _ := newTestTemplates(&fakeClock{now: start})
+	created, err := templates.CreateTemplate(lampTemplate())
+	require.NoError(t, err)
+
+	changes := lampTemplate()
+	changes.ID = created.ID
+	changes.Name = "Desk lamps"
+	changes.UserID = 2
+	_, err = templates.UpdateTemplate(2, changes)
+	assert.ErrorIs(t, err, service.ErrTemplateNotFound)
+
+	updated, err := templates.UpdateTemplate(1, changes)
+	require.NoError(t, err)
+	assert.Equal(t, "Desk lamps", updated.Name)
+	assert.Equal(t, 1, updated.UserID, "templates keep their owner")
+
+	assert.ErrorIs(t, templates.DeleteTemplate(2, created.ID), service.ErrTemplateNotFound)
+	require.NoError(t, templates.DeleteTemplate(1, created.ID))
+	_, err = templates.GetTemplate(1, created.ID)
+	assert.ErrorIs(t, err, service.ErrTemplateNotFound)
+}
+
+func TestListFromTemplate(t *testing.T) {
+	templates, auctionService, _ := newTestTemplates(&fakeClock{now: start})
+	template, err := templates.CreateTemplate(lampTemplate())
+	require.NoError(t, err)
+
+	auction, err := templates.ListFromTemplate(1, template.ID, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, model.AuctionStatusOpen, auction.Status)
+	assert.Equal(t, "Brass Lamp", auction.Item)
+	assert.Equal(t, 1, auction.UserID)
+	assert.Equal(t, usd(10), auction.CurrentPrice)
+	assert.Equal(t, usd(30), auction.ReservePrice)
+	assert.Equal(t, template.BidIncrements, auction.BidIncrements)
+	if assert.NotNil(t, auction.EndsAt) {
+		assert.Equal(t, start.Add(7*24*time.Hour), *auction.EndsAt)
+	}
+
+	scheduled, err := templates.ListFromTemplate(1, template.ID, timeAt(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, model.AuctionStatusScheduled, scheduled.Status)
+	if assert.NotNil(t, scheduled.EndsAt) {
+		assert.Equal(t, start.Add(time.Hour+7*24*time.Hour), *scheduled.EndsAt)
+	}
+
+	_, err = templates.ListFromTemplate(2, template.ID, nil)
+	assert.ErrorIs(t, err, service.ErrTemplateNotFound)
+	all, err := auctionService.GetAllAuctions()
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+)
+
+// WatchlistService keeps the auctions users watch. Watchers hear from the
+// notifier when a watched auction is about to end.
+type WatchlistService interface {
+	// WatchAuction adds an open auction to the watchlist of userID.
+	// Watching an auction twice is not an error.
+	WatchAuction(userID, auctionID int) (model.WatchlistEntry, error)
+	UnwatchAuction(userID, auctionID int) error
+	// GetWatchlist returns the auctions userID watches, in the order they
+	// were added. Deleted auctions are left out.
+	GetWatchlist(userID int) ([]model.Auction, error)
+}
+
+type watchlistService struct {
+	txManager repository.TxManager
+	// prices shows the auctions with the price their format shows now.
+	prices auctionService
+}
+
+// Ensure watchlistService implements WatchlistService
+var _ WatchlistService = (*watchlistService)(nil)
+
+// NewWatchlistService creates a WatchlistService working in txManager
+// transactions.
+func NewWatchlistService(txManager repository.TxManager, opts ...Option) WatchlistService {
+	o := newOptions(opts)
+	return &watchlistService{
+		txManager: txManager,
+		prices:    auctionService{now: o.now, increments: o.increments},
+	}
+}
+
+func (s *watchlistService) WatchAuction(userID, auctionID int) (model.WatchlistEntry, error) {
+	if userID <= 0 {
+		return model.WatchlistEntry{}, fmt.Errorf("%w: user id is required", ErrInvalidInput)
+	}
+
+	var entry model.WatchlistEntry
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		auction, err := uow.Auctions().GetAuctionByID(auctionID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if !auction.IsOpen() && !auction.IsScheduled() {
+			return ErrAuctionClosed
+		}
+		entry, err = uow.Watchlist().AddEntry(model.WatchlistEntry{UserID: userID, AuctionID: auction.ID})
+		return err
+	})
+	if err != nil {
+		return model.WatchlistEntry{}, err
+	}
+	return entry, nil
+}
+
+func (s *watchlistService) UnwatchAuction(userID, auctionID int) error {
+	return s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		return uow.Watchlist().RemoveEntry(userID, auctionID)
+	})
+}
+
+func (s *watchlistService) GetWatchlist(userID int) ([]model.Auction, error) {
+	auctions := []model.Auction{}
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		entries, err := uow.Watchlist().GetEntriesByUserID(userID)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			auction, err := uow.Auctions().GetAuctionByID(entry.AuctionID)
+			if errors.Is(err, repository.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			auctions = append(auctions, s.prices.withPrice(auction))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return auctions, nil
+}
@@ -0,0 +1,183 @@
+package service_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"auction-service/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// notifications returns the notification intents waiting in the outbox of
+// store.
+func notifications(t *testing.T, store *repository.MemoryStore) []model.Event {
+	var intents []model.Event
+	for _, event := range outboxEvents(t, store) {
+		switch event.Type {
+		case model.EventNotifyOutbid, model.EventNotifyEndingSoon, model.EventNotifyWon, model.EventNotifyLost:
+			intents = append(intents, event)
+		}
+	}
+	return intents
+}
+
+func TestWatchlist(t *testing.T) {
+	store := repository.NewMemoryStore()
+	watchlistService := service.NewWatchlistService(store)
+	auction := seedAuction(t, store, model.Auction{Item: "Watched", UserID: 1, Status: model.AuctionStatusOpen, CurrentPrice: usd(10)})
+	closed := seedAuction(t, store, model.Auction{Item: "Closed", UserID: 1, Status: model.AuctionStatusClosed})
+	deleted := seedAuction(t, store, model.Auction{Item: "Deleted", UserID: 1, Status: model.AuctionStatusOpen})
+
+	entry, err := watchlistService.WatchAuction(2, auction.ID)
+	require.NoError(t, err)
+	again, err := watchlistService.WatchAuction(2, auction.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entry.ID, again.ID)
+	_, err = watchlistService.WatchAuction(2, closed.ID)
+	assert.ErrorIs(t, err, service.ErrAuctionClosed)
+	_, err = watchlistService.WatchAuction(2, 999)
+	assert.ErrorIs(t, err, service.ErrNotFound)
+
+	_, err = watchlistService.WatchAuction(2, deleted.ID)
+	require.NoError(t, err)
+	require.NoError(t, store.Auctions().DeleteAuction(deleted.ID))
+
+	watched, err := watchlistService.GetWatchlist(2)
+	require.NoError(t, err)
+	if assert.Len(t, watched, 1) {
+		assert.Equal(t, auction.ID, watched[0].ID)
+		assert.Equal(t, usd(11), *watched[0].NextMinimumBid)
+	}
+
+	require.NoError(t, watchlistService.UnwatchAuction(2, auction.ID))
+	watched, err = watchlistService.GetWatchlist(2)
+	require.NoError(t, err)
+	assert.Empty(t, watched)
+}
+
+func TestOutbidNotifications(t *testing.T) {
+	auctionService, store := newTestService()
+	auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen, CurrentPrice: usd(10)})
+
+	_, err := auctionService.PlaceBid(auction.ID, 2, usd(20))
+	require.NoError(t, err)
+	assert.Empty(t, notifications(t, store), "nobody led before the first bid")
+
+	_, err = auctionService.PlaceBid(auction.ID, 3, usd(30))
+	require.NoError(t, err)
+	// The proxy of user 3 answers the next bid of user 2 right away, so
+	// user 3 still leads and is not told anything.
+	_, err = auctionService.PlaceProxyBid(auction.ID, 3, usd(100))
+	require.NoError(t, err)
+	_, err = auctionService.PlaceBid(auction.ID, 2, usd(40))
+	require.NoError(t, err)
+	_, err = auctionService.PlaceProxyBid(auction.ID, 4, usd(200))
+	require.NoError(t, err)
+
+	intents := notifications(t, store)
+	if assert.Len(t, intents, 2) {
+		assert.Equal(t, model.Event{Type: model.EventNotifyOutbid, AuctionID: auction.ID, UserID: 2, Amount: ptr(usd(30))}, withoutTime(intents[0]))
+		assert.Equal(t, model.Event{Type: model.EventNotifyOutbid, AuctionID: auction.ID, UserID: 3, Amount: ptr(usd(105))}, withoutTime(intents[1]))
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func withoutTime(event model.Event) model.Event {
+	event.OccurredAt = time.Time{}
+	return event
+}
+
+func TestOutcomeNotifications(t *testing.T) {
+	tests := []struct {
+		name      string
+		reserve   float64
+		wantWon   int
+		wantLost  []int
+		wantPrice float64
+	}{
+		{name: "sold", wantWon: 3, wantLost: []int{2, 4}, wantPrice: 40},
+		{name: "unsold", reserve: 100, wantLost: []int{2, 3, 4}, wantPrice: 40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auctionService, store := newTestService()
+			auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen, CurrentPrice: usd(10), ReservePrice: usd(tt.reserve)})
+			for _, bid := range []struct {
+				userID int
+				amount float64
+			}{{2, 20}, {4, 25}, {2, 30}, {3, 40}} {
+				_, err := auctionService.PlaceBid(auction.ID, bid.userID, usd(bid.amount))
+				require.NoError(t, err)
+			}
+
+			_, err := auctionService.CloseAuction(1, auction.ID)
+			require.NoError(t, err)
+
+			var won []int
+			var lost []int
+			for _, intent := range notifications(t, store) {
+				switch intent.Type {
+				case model.EventNotifyWon:
+					won = append(won, intent.UserID)
+				case model.EventNotifyLost:
+					lost = append(lost, intent.UserID)
+				default:
+					continue
+				}
+				assert.Equal(t, usd(tt.wantPrice), *intent.Amount)
+			}
+			if tt.wantWon != 0 {
+				assert.Equal(t, []int{tt.wantWon}, won)
+			} else {
+				assert.Empty(t, won)
+			}
+			assert.ElementsMatch(t, tt.wantLost, lost)
+		})
+	}
+}
+
+func TestEndingSoonNotifications(t *testing.T) {
+	clock := &fakeClock{now: start}
+	store := repository.NewMemoryStore()
+	watchlistService := service.NewWatchlistService(store)
+	closer := service.NewAuctionCloser(store.Auctions(), store, time.Second, service.WithClock(clock.Now), service.WithEndingSoonWindow(30*time.Minute))
+	auction := seedAuction(t, store, model.Auction{Item: "Test Item", UserID: 1, Status: model.AuctionStatusOpen, EndsAt: timeAt(time.Hour)})
+	unwatched := seedAuction(t, store, model.Auction{Item: "Unwatched", UserID: 1, Status: model.AuctionStatusOpen, EndsAt: timeAt(time.Hour)})
+	for _, userID := range []int{3, 2} {
+		_, err := watchlistService.WatchAuction(userID, auction.ID)
+		require.NoError(t, err)
+	}
+
+	notified, err := closer.NotifyEndingSoon()
+	require.NoError(t, err)
+	assert.Zero(t, notified)
+
+	clock.Set(start.Add(40 * time.Minute))
+	notified, err = closer.NotifyEndingSoon()
+	require.NoError(t, err)
+	assert.Equal(t, 2, notified)
+	// Each auction is announced once.
+	notified, err = closer.NotifyEndingSoon()
+	require.NoError(t, err)
+	assert.Zero(t, notified)
+
+	intents := notifications(t, store)
+	if assert.Len(t, intents, 2) {
+		for i, userID := range []int{2, 3} {
+			assert.Equal(t, model.EventNotifyEndingSoon, intents[i].Type)
+			assert.Equal(t, auction.ID, intents[i].AuctionID)
+			assert.Equal(t, userID, intents[i].UserID)
+			assert.True(t, auction.EndsAt.Equal(*intents[i].EndsAt))
+		}
+	}
+	stored, err := store.Auctions().GetAuctionByID(unwatched.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.EndingSoonNotified)
+}
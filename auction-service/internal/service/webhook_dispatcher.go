@@ -0,0 +1,180 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+)
+
+// WebhookDispatcher POSTs the queued webhook deliveries to their endpoints,
+// signed with the secret of their subscription. Failed deliveries are
+// retried with exponential backoff, and subscriptions whose endpoint keeps
+// failing are disabled.
+//
+// Deliveries are at least once and not necessarily in order: receivers
+// should drop events whose ID they have already seen and order them by
+// created_at.
+type WebhookDispatcher struct {
+	webhooks  repository.WebhookRepository
+	client    *http.Client
+	policy    WebhookPolicy
+	interval  time.Duration
+	batchSize int
+	now       func() time.Time
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewWebhookDispatcher creates a dispatcher that looks for due deliveries
+// every interval.
+func NewWebhookDispatcher(webhooks repository.WebhookRepository, interval time.Duration, opts ...Option) *WebhookDispatcher {
+	o := newOptions(opts)
+	return &WebhookDispatcher{
+		webhooks:  webhooks,
+		client:    &http.Client{Timeout: o.webhooks.Timeout},
+		policy:    o.webhooks,
+		interval:  interval,
+		batchSize: 100,
+		now:       o.now,
+		stop:      make(chan struct{}),
+	}
+}
+
+// Start delivers due webhooks in the background until Stop is called.
+func (d *WebhookDispatcher) Start() {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-d.stop:
+				return
+			case <-ticker.C:
+				if _, err := d.DeliverDue(); err != nil {
+					log.Printf("Error delivering webhooks: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop ends the polling started by Start and waits for it to finish.
+func (d *WebhookDispatcher) Stop() {
+	close(d.stop)
+	d.wg.Wait()
+}
+
+// DeliverDue tries one batch of due deliveries and returns how many
+// succeeded. Failed attempts are recorded on the delivery, not returned.
+func (d *WebhookDispatcher) DeliverDue() (int, error) {
+	deliveries, err := d.webhooks.GetDueDeliveries(d.now(), d.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, delivery := range deliveries {
+		subscription, err := d.webhooks.GetSubscriptionByID(delivery.SubscriptionID)
+		if errors.Is(err, repository.ErrSubscriptionNotFound) {
+			continue
+		}
+		if err != nil {
+			return delivered, err
+		}
+		ok, err := d.deliver(&subscription, &delivery)
+		if err != nil {
+			return delivered, err
+		}
+		if ok {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// deliver makes one attempt at delivery and records its outcome on the
+// delivery and the subscription.
+func (d *WebhookDispatcher) deliver(subscription *model.WebhookSubscription, delivery *model.WebhookDelivery) (bool, error) {
+	now := d.now().UTC()
+	if !subscription.Active {
+		delivery.Status = model.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = "subscription is disabled"
+		return false, d.webhooks.UpdateDelivery(*delivery)
+	}
+
+	delivery.Attempts++
+	delivery.LastStatusCode, delivery.LastError = d.post(*subscription, *delivery, now)
+	succeeded := delivery.LastError == ""
+	switch {
+	case succeeded:
+		delivery.Status = model.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+	case delivery.Attempts >= d.policy.MaxAttempts:
+		delivery.Status = model.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+	default:
+		next := now.Add(d.policy.Backoff << (delivery.Attempts - 1))
+		delivery.NextAttemptAt = &next
+	}
+	if err := d.webhooks.UpdateDelivery(*delivery); err != nil {
+		return false, err
+	}
+
+	failures := subscription.ConsecutiveFailures
+	if succeeded {
+		subscription.ConsecutiveFailures = 0
+	} else {
+		subscription.ConsecutiveFailures++
+		if d.policy.DisableAfter > 0 && subscription.ConsecutiveFailures >= d.policy.DisableAfter {
+			subscription.Active = false
+			subscription.DisabledAt = &now
+			subscription.DisabledReason = fmt.Sprintf("%d failed attempts in a row, the last one: %s", subscription.ConsecutiveFailures, delivery.LastError)
+			log.Printf("Disabled webhook subscription %d: %s", subscription.ID, subscription.DisabledReason)
+		}
+	}
+	if subscription.ConsecutiveFailures == failures {
+		return succeeded, nil
+	}
+	return succeeded, d.webhooks.UpdateSubscription(*subscription)
+}
+
+// post sends a delivery and returns the status code of the response and,
+// when the attempt failed, why.
+func (d *WebhookDispatcher) post(subscription model.WebhookSubscription, delivery model.WebhookDelivery, now time.Time) (int, string) {
+	req, err := http.NewRequest(http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err.Error()
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "auction-service-webhooks")
+	req.Header.Set(WebhookIDHeader, delivery.EventID)
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(subscription.Secret, now, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err.Error()
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, "endpoint answered " + resp.Status
+	}
+	return resp.StatusCode, ""
+}
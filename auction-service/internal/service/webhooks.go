@@ -0,0 +1,326 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+)
+
+var (
+	// ErrSubscriptionNotFound is returned when the webhook subscription
+	// does not exist.
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	// ErrInvalidSignature is returned by VerifyWebhookSignature when a
+	// payload was not signed with the secret, or too long ago.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+)
+
+// Headers sent with every webhook request.
+const (
+	WebhookIDHeader        = "X-Webhook-ID"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	// WebhookSignatureHeader carries "t=<unix time>,v1=<signature>", where
+	// the signature is the hex HMAC-SHA256, keyed with the subscription
+	// secret, of the timestamp, a dot and the request body.
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// WebhookPolicy decides how hard the service tries to reach webhook
+// endpoints.
+type WebhookPolicy struct {
+	// MaxAttempts is how many times a delivery is tried before it fails.
+	MaxAttempts int
+	// Backoff is the wait before the first retry, doubled on every attempt
+	// after it.
+	Backoff time.Duration
+	// DisableAfter is how many attempts in a row can fail, across all the
+	// deliveries of a subscription, before the subscription is disabled.
+	DisableAfter int
+	// Timeout limits each request.
+	Timeout time.Duration
+}
+
+// DefaultWebhookPolicy tries each delivery 6 times over about 15 minutes and
+// disables endpoints after 20 failed attempts in a row.
+var DefaultWebhookPolicy = WebhookPolicy{MaxAttempts: 6, Backoff: 30 * time.Second, DisableAfter: 20, Timeout: 10 * time.Second}
+
+// deliveryLogSize is how many deliveries GetDeliveries returns.
+const deliveryLogSize = 100
+
+// WebhookService manages the webhook subscriptions of partners and queues
+// the events they subscribed to for the WebhookDispatcher.
+type WebhookService interface {
+	// CreateSubscription stores a new subscription. The returned
+	// subscription is the only one that includes the secret, generated
+	// when none is given.
+	CreateSubscription(subscription model.WebhookSubscription) (model.WebhookSubscription, error)
+	GetSubscriptions() ([]model.WebhookSubscription, error)
+	GetSubscription(id int) (model.WebhookSubscription, error)
+	// UpdateSubscription changes the URL, event types and description of
+	// a subscription.
+	UpdateSubscription(subscription model.WebhookSubscription) (model.WebhookSubscription, error)
+	DeleteSubscription(id int) error
+	// EnableSubscription turns a disabled subscription back on.
+	EnableSubscription(id int) (model.WebhookSubscription, error)
+	// GetDeliveries returns the latest deliveries of a subscription, newest
+	// first.
+	GetDeliveries(subscriptionID int) ([]model.WebhookDelivery, error)
+	// QueueEvent queues an auction event, as published by the service, for
+	// every active subscription that wants it. Queueing the same event
+	// twice has no effect.
+	QueueEvent(event []byte) error
+}
+
+type webhookService struct {
+	webhooks repository.WebhookRepository
+}
+
+// Ensure webhookService implements WebhookService
+var _ WebhookService = (*webhookService)(nil)
+
+// NewWebhookService creates a WebhookService.
+func NewWebhookService(webhooks repository.WebhookRepository) WebhookService {
+	return &webhookService{webhooks: webhooks}
+}
+
+func (s *webhookService) CreateSubscription(subscription model.WebhookSubscription) (model.WebhookSubscription, error) {
+	if err := validateSubscription(&subscription); err != nil {
+		return model.WebhookSubscription{}, err
+	}
+	if subscription.Secret == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return model.WebhookSubscription{}, err
+		}
+		subscription.Secret = secret
+	} else if len(subscription.Secret) < 16 || len(subscription.Secret) > 64 {
+		return model.WebhookSubscription{}, fmt.Errorf("%w: secret must be 16 to 64 characters long", ErrInvalidInput)
+	}
+	subscription.Active = true
+	subscription.ConsecutiveFailures = 0
+	subscription.DisabledAt = nil
+	subscription.DisabledReason = ""
+	return s.webhooks.CreateSubscription(subscription)
+}
+
+func (s *webhookService) GetSubscriptions() ([]model.WebhookSubscription, error) {
+	subscriptions, err := s.webhooks.GetSubscriptions()
+	if err != nil {
+		return nil, err
+	}
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+	if subscriptions == nil {
+		subscriptions = []model.WebhookSubscription{}
+	}
+	return subscriptions, nil
+}
+
+func (s *webhookService) GetSubscription(id int) (model.WebhookSubscription, error) {
+	subscription, err := s.subscription(id)
+	subscription.Secret = ""
+	return subscription, err
+}
+
+func (s *webhookService) UpdateSubscription(changes model.WebhookSubscription) (model.WebhookSubscription, error) {
+	if err := validateSubscription(&changes); err != nil {
+		return model.WebhookSubscription{}, err
+	}
+	subscription, err := s.subscription(changes.ID)
+	if err != nil {
+		return model.WebhookSubscription{}, err
+	}
+	subscription.URL = changes.URL
+	subscription.EventTypes = changes.EventTypes
+	subscription.Description = changes.Description
+	if err := s.webhooks.UpdateSubscription(subscription); err != nil {
+		return model.WebhookSubscription{}, err
+	}
+	subscription.Secret = ""
+	return subscription, nil
+}
+
+func (s *webhookService) DeleteSubscription(id int) error {
+	err := s.webhooks.DeleteSubscription(id)
+	if errors.Is(err, repository.ErrSubscriptionNotFound) {
+		return ErrSubscriptionNotFound
+	}
+	return err
+}
+
+func (s *webhookService) EnableSubscription(id int) (model.WebhookSubscription, error) {
+	subscription, err := s.subscription(id)
+	if err != nil {
+		return model.WebhookSubscription{}, err
+	}
+	subscription.Active = true
+	subscription.ConsecutiveFailures = 0
+	subscription.DisabledAt = nil
+	subscription.DisabledReason = ""
+	if err := s.webhooks.UpdateSubscription(subscription); err != nil {
+		return model.WebhookSubscription{}, err
+	}
+	subscription.Secret = ""
+	return subscription, nil
+}
+
+func (s *webhookService) GetDeliveries(subscriptionID int) ([]model.WebhookDelivery, error) {
+	if _, err := s.subscription(subscriptionID); err != nil {
+		return nil, err
+	}
+	deliveries, err := s.webhooks.GetDeliveriesBySubscriptionID(subscriptionID, deliveryLogSize)
+	if deliveries == nil {
+		deliveries = []model.WebhookDelivery{}
+	}
+	return deliveries, err
+}
+
+// webhookPayload is the body POSTed to webhook endpoints. Data is the event
+// as published on the auction events queue.
+type webhookPayload struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+func (s *webhookService) QueueEvent(body []byte) error {
+	var event model.Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	if !slices.Contains(model.WebhookEventTypes, event.Type) {
+		return nil
+	}
+
+	subscriptions, err := s.webhooks.GetSubscriptions()
+	if err != nil {
+		return err
+	}
+	// The event is identified by its content, so the same outbox message
+	// published twice gets the same ID and is only delivered once.
+	sum := sha256.Sum256(body)
+	eventID := "evt_" + hex.EncodeToString(sum[:16])
+	payload, err := json.Marshal(webhookPayload{
+		ID:        eventID,
+		Type:      event.Type,
+		CreatedAt: event.OccurredAt,
+		Data:      body,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, subscription := range subscriptions {
+		if !subscription.Active || !subscription.Wants(event.Type) {
+			continue
+		}
+		_, err := s.webhooks.AddDelivery(model.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        eventID,
+			EventType:      event.Type,
+			Payload:        payload,
+			Status:         model.WebhookDeliveryPending,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *webhookService) subscription(id int) (model.WebhookSubscription, error) {
+	subscription, err := s.webhooks.GetSubscriptionByID(id)
+	if errors.Is(err, repository.ErrSubscriptionNotFound) {
+		return model.WebhookSubscription{}, ErrSubscriptionNotFound
+	}
+	return subscription, err
+}
+
+// validateSubscription checks the URL and event types of a subscription and
+// removes repeated event types.
+func validateSubscription(subscription *model.WebhookSubscription) error {
+	subscription.URL = strings.TrimSpace(subscription.URL)
+	parsed, err := url.Parse(subscription.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: webhook URL must be an http or https URL", ErrInvalidInput)
+	}
+	if len(subscription.EventTypes) == 0 {
+		return fmt.Errorf("%w: at least one event type is required", ErrInvalidInput)
+	}
+	var eventTypes []string
+	for _, eventType := range subscription.EventTypes {
+		if !slices.Contains(model.WebhookEventTypes, eventType) {
+			return fmt.Errorf("%w: unknown event type %q", ErrInvalidInput, eventType)
+		}
+		if !slices.Contains(eventTypes, eventType) {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	subscription.EventTypes = eventTypes
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(secret), nil
+}
+
+// SignWebhook returns the WebhookSignatureHeader value for body sent at
+// timestamp.
+func SignWebhook(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + unix + ",v1=" + webhookSignature(secret, unix, body)
+}
+
+// VerifyWebhookSignature checks a WebhookSignatureHeader value the way
+// receivers should: the signature must match body and secret, and the
+// timestamp must be within tolerance of now to stop replays.
+func VerifyWebhookSignature(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var unix, signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			unix = value
+		case "v1":
+			signature = value
+		}
+	}
+	seconds, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil || signature == "" {
+		return fmt.Errorf("%w: malformed header", ErrInvalidSignature)
+	}
+	if !hmac.Equal([]byte(signature), []byte(webhookSignature(secret, unix, body))) {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: timestamp outside the tolerance", ErrInvalidSignature)
+	}
+	return nil
+}
+
+func webhookSignature(secret, unix string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,250 @@
+package service_test
+
+import (
+	"auction-service/internal/model"
+	"auction-service/internal/repository"
+	"auction-service/internal/service"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookReceiver is a partner endpoint that records what it receives and
+// answers with status.
+type webhookReceiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	status   int
+	requests []receivedWebhook
+}
+
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+func newWebhookReceiver(t *testing.T) *webhookReceiver {
+	receiver := &webhookReceiver{status: http.StatusOK}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receiver.mu.Lock()
+		defer receiver.mu.Unlock()
+		receiver.requests = append(receiver.requests, receivedWebhook{header: r.Header.Clone(), body: body})
+		w.WriteHeader(receiver.status)
+	}))
+	t.Cleanup(receiver.Close)
+	return receiver
+}
+
+func (r *webhookReceiver) answer(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+func (r *webhookReceiver) received() []receivedWebhook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedWebhook(nil), r.requests...)
+}
+
+func bidPlacedEvent(t *testing.T) []byte {
+	body, err := json.Marshal(model.Event{Type: model.EventBidPlaced, AuctionID: 1, UserID: 2, Amount: ptr(usd(30)), OccurredAt: start})
+	require.NoError(t, err)
+	return body
+}
+
+func TestWebhookSubscriptionValidation(t *testing.T) {
+	webhookService := service.NewWebhookService(repository.NewMemoryStore().Webhooks())
+
+	created, err := webhookService.CreateSubscription(model.WebhookSubscription{
+		URL: "https://partner.example/hook", EventTypes: []string{model.EventBidPlaced, model.EventBidPlaced, model.EventAuctionClosed},
+	})
+	require.NoError(t, err)
+	assert.Regexp(t, `^whsec_[0-9a-f]{48}$`, created.Secret)
+	assert.True(t, created.Active)
+	assert.Equal(t, []string{model.EventBidPlaced, model.EventAuctionClosed}, created.EventTypes)
+
+	stored, err := webhookService.GetSubscription(created.ID)
+	require.NoError(t, err)
+	assert.Empty(t, stored.Secret, "the secret is only shown once")
+
+	invalid := []model.WebhookSubscription{
+		{URL: "ftp://partner.example", EventTypes: []string{model.EventBidPlaced}},
+		{URL: "https://partner.example/hook"},
+		{URL: "https://partner.example/hook", EventTypes: []string{model.EventNotifyWon}},
+		{URL: "https://partner.example/hook", EventTypes: []string{model.EventBidPlaced}, Secret: "short"},
+	}
+	for _, subscription := range invalid {
+		_, err := webhookService.CreateSubscription(subscription)
+		assert.ErrorIs(t, err, service.ErrInvalidInput, subscription)
+	}
+
+	_, err = webhookService.UpdateSubscription(model.WebhookSubscription{ID: 99, URL: "https://partner.example/hook", EventTypes: []string{model.EventBidPlaced}})
+	assert.ErrorIs(t, err, service.ErrSubscriptionNotFound)
+	assert.ErrorIs(t, webhookService.DeleteSubscription(99), service.ErrSubscriptionNotFound)
+}
+
+func TestWebhookDeliverySignsPayloads(t *testing.T) {
+	receiver := newWebhookReceiver(t)
+	store := repository.NewMemoryStore()
+	webhookService := service.NewWebhookService(store.Webhooks())
+	dispatcher := service.NewWebhookDispatcher(store.Webhooks(), time.Second, service.WithClock(func() time.Time { return start }))
+
+	subscription, err := webhookService.CreateSubscription(model.WebhookSubscription{URL: receiver.URL, EventTypes: []string{model.EventBidPlaced}})
+	require.NoError(t, err)
+	other, err := webhookService.CreateSubscription(model.WebhookSubscription{URL: receiver.URL, EventTypes: []string{model.EventAuctionClosed}})
+	require.NoError(t, err)
+
+	event := bidPlacedEvent(t)
+	require.NoError(t, webhookService.QueueEvent(event))
+	// The relay publishes at least once: the same event is only delivered once.
+	require.NoError(t, webhookService.QueueEvent(event))
+
+	delivered, err := dispatcher.DeliverDue()
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+
+	requests := receiver.received()
+	require.Len(t, requests, 1)
+	header, body := requests[0].header, requests[0].body
+	assert.Equal(t, model.EventBidPlaced, header.Get(service.WebhookEventHeader))
+	assert.Equal(t, "1714564800", header.Get(service.WebhookTimestampHeader))
+	assert.NoError(t, service.VerifyWebhookSignature(subscription.Secret, header.Get(service.WebhookSignatureHeader), body, start, 5*time.Minute))
+	assert.ErrorIs(t, service.VerifyWebhookSignature(other.Secret, header.Get(service.WebhookSignatureHeader), body, start, 5*time.Minute), service.ErrInvalidSignature)
+	assert.ErrorIs(t, service.VerifyWebhookSignature(subscription.Secret, header.Get(service.WebhookSignatureHeader), body, start.Add(time.Hour), 5*time.Minute), service.ErrInvalidSignature)
+
+	var payload struct {
+		ID        string          `json:"id"`
+		Type      string          `json:"type"`
+		CreatedAt time.Time       `json:"created_at"`
+		Data      json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, header.Get(service.WebhookIDHeader), payload.ID)
+	assert.Equal(t, model.EventBidPlaced, payload.Type)
+	assert.Equal(t, start, payload.CreatedAt)
+	assert.JSONEq(t, string(event), string(payload.Data))
+
+	deliveries, err := webhookService.GetDeliveries(subscription.ID)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, model.WebhookDeliverySucceeded, deliveries[0].Status)
+	assert.Equal(t, http.StatusOK, deliveries[0].LastStatusCode)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+
+	deliveries, err = webhookService.GetDeliveries(other.ID)
+	require.NoError(t, err)
+	assert.Empty(t, deliveries)
+}
+
+func TestWebhookDeliveryRetriesWithBackoff(t *testing.T) {
+	receiver := newWebhookReceiver(t)
+	receiver.answer(http.StatusServiceUnavailable)
+	store := repository.NewMemoryStore()
+	webhookService := service.NewWebhookService(store.Webhooks())
+	now := start
+	policy := service.WebhookPolicy{MaxAttempts: 3, Backoff: time.Minute, DisableAfter: 10, Timeout: time.Second}
+	dispatcher := service.NewWebhookDispatcher(store.Webhooks(), time.Second,
+		service.WithClock(func() time.Time { return now }), service.WithWebhookPolicy(policy))
+
+	subscription, err := webhookService.CreateSubscription(model.WebhookSubscription{URL: receiver.URL, EventTypes: []string{model.EventBidPlaced}})
+	require.NoError(t, err)
+	require.NoError(t, webhookService.QueueEvent(bidPlacedEvent(t)))
+
+	latest := func() model.WebhookDelivery {
+		deliveries, err := webhookService.GetDeliveries(subscription.ID)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		return deliveries[0]
+	}
+
+	delivered, err := dispatcher.DeliverDue()
+	require.NoError(t, err)
+	assert.Zero(t, delivered)
+	assert.Equal(t, model.WebhookDeliveryPending, latest().Status)
+	assert.Equal(t, http.StatusServiceUnavailable, latest().LastStatusCode)
+	assert.Equal(t, "endpoint answered 503 Service Unavailable", latest().LastError)
+	assert.Equal(t, start.Add(time.Minute), *latest().NextAttemptAt)
+
+	// Not due yet.
+	now = start.Add(30 * time.Second)
+	_, err = dispatcher.DeliverDue()
+	require.NoError(t, err)
+	assert.Len(t, receiver.received(), 1)
+
+	now = start.Add(time.Minute)
+	_, err = dispatcher.DeliverDue()
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(2*time.Minute), *latest().NextAttemptAt)
+
+	receiver.answer(http.StatusNoContent)
+	now = now.Add(2 * time.Minute)
+	delivered, err = dispatcher.DeliverDue()
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.Equal(t, model.WebhookDeliverySucceeded, latest().Status)
+	assert.Equal(t, 3, latest().Attempts)
+	assert.Equal(t, &now, latest().DeliveredAt)
+
+	stored, err := webhookService.GetSubscription(subscription.ID)
+	require.NoError(t, err)
+	assert.Zero(t, stored.ConsecutiveFailures, "a success resets the failures")
+}
+
+func TestWebhookSubscriptionDisabledAfterPersistentFailures(t *testing.T) {
+	receiver := newWebhookReceiver(t)
+	receiver.answer(http.StatusInternalServerError)
+	store := repository.NewMemoryStore()
+	webhookService := service.NewWebhookService(store.Webhooks())
+	now := start
+	policy := service.WebhookPolicy{MaxAttempts: 2, Backoff: time.Minute, DisableAfter: 3, Timeout: time.Second}
+	dispatcher := service.NewWebhookDispatcher(store.Webhooks(), time.Second,
+		service.WithClock(func() time.Time { return now }), service.WithWebhookPolicy(policy))
+
+	subscription, err := webhookService.CreateSubscription(model.WebhookSubscription{URL: receiver.URL, EventTypes: []string{model.EventBidPlaced, model.EventAuctionClosed}})
+	require.NoError(t, err)
+	require.NoError(t, webhookService.QueueEvent(bidPlacedEvent(t)))
+	closed, err := json.Marshal(model.Event{Type: model.EventAuctionClosed, AuctionID: 1, OccurredAt: start})
+	require.NoError(t, err)
+	require.NoError(t, webhookService.QueueEvent(closed))
+
+	for i := 0; i < 3; i++ {
+		_, err := dispatcher.DeliverDue()
+		require.NoError(t, err)
+		now = now.Add(time.Hour)
+	}
+
+	stored, err := webhookService.GetSubscription(subscription.ID)
+	require.NoError(t, err)
+	assert.False(t, stored.Active)
+	assert.Equal(t, 3, stored.ConsecutiveFailures)
+	assert.Contains(t, stored.DisabledReason, "3 failed attempts in a row")
+	assert.Len(t, receiver.received(), 3)
+
+	deliveries, err := webhookService.GetDeliveries(subscription.ID)
+	require.NoError(t, err)
+	for _, delivery := range deliveries {
+		assert.Equal(t, model.WebhookDeliveryFailed, delivery.Status)
+	}
+
+	// Disabled subscriptions get nothing new until they are enabled again.
+	later, err := json.Marshal(model.Event{Type: model.EventBidPlaced, AuctionID: 2, OccurredAt: now})
+	require.NoError(t, err)
+	require.NoError(t, webhookService.QueueEvent(later))
+	enabled, err := webhookService.EnableSubscription(subscription.ID)
+	require.NoError(t, err)
+	assert.True(t, enabled.Active)
+	assert.Zero(t, enabled.ConsecutiveFailures)
+	assert.Nil(t, enabled.DisabledAt)
+	deliveries, err = webhookService.GetDeliveries(subscription.ID)
+	require.NoError(t, err)
+	assert.Len(t, deliveries, 2)
+}
@@ -0,0 +1,54 @@
+package rabbitmq
+
+import (
+	"github.com/streadway/amqp"
+)
+
+// Publish sends body to the queue of topic, declaring it on first use.
+func (b *Bus) Publish(topic string, body []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.declared[topic] {
+		if _, err := declareQueue(b.channel, topic); err != nil {
+			return err
+		}
+		b.declared[topic] = true
+	}
+
+	return b.channel.Publish(
+		"",
+		topic,
+		false,
+		false,
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+}
+
+// Broadcast sends body to the fanout exchange of topic, declaring it on
+// first use.
+func (b *Bus) Broadcast(topic string, body []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.exchanges[topic] {
+		if err := declareExchange(b.channel, topic); err != nil {
+			return err
+		}
+		b.exchanges[topic] = true
+	}
+
+	return b.channel.Publish(
+		topic,
+		"",
+		false,
+		false,
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+}
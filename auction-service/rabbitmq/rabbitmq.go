@@ -1,22 +1,30 @@
 package rabbitmq
 
 import (
-	"encoding/json"
 	"log"
+	"sync"
 
-	"auction-service/internal/model"
-	"auction-service/internal/repository"
+	"auction-service/internal/messaging"
 
 	"github.com/streadway/amqp"
 )
 
-type Consumer struct {
-	Channel *amqp.Channel
-	Queue   amqp.Queue
-	Repo    repository.AuctionRepository
+// Bus is the AMQP driver of messaging.Bus. Every topic maps to a queue of
+// the same name on the default exchange, and every broadcast topic to a
+// fanout exchange of the same name.
+type Bus struct {
+	conn *amqp.Connection
+
+	// mu guards the publishing channel and the queues and exchanges
+	// declared on it.
+	mu        sync.Mutex
+	channel   *amqp.Channel
+	declared  map[string]bool
+	exchanges map[string]bool
 }
 
-func NewConsumer(url, queueName string, repo repository.AuctionRepository) (*Consumer, error) {
+// NewBus connects to the broker at url.
+func NewBus(url string) (*Bus, error) {
 	conn, err := amqp.Dial(url)
 	if err != nil {
 		return nil, err
@@ -24,58 +32,135 @@ func NewConsumer(url, queueName string, repo repository.AuctionRepository) (*Con
 
 	ch, err := conn.Channel()
 	if err != nil {
+		conn.Close()
 		return nil, err
 	}
 
+	return &Bus{
+		conn:      conn,
+		channel:   ch,
+		declared:  map[string]bool{},
+		exchanges: map[string]bool{},
+	}, nil
+}
+
+// Ensure Bus implements messaging.Bus
+var _ messaging.Bus = (*Bus)(nil)
+
+// Subscribe consumes the queue of topic on a channel of its own. Only one
+// message is in flight at a time so handlers see them in order.
+func (b *Bus) Subscribe(topic string, handler messaging.Handler) error {
+	ch, err := b.conn.Channel()
+	if err != nil {
+		return err
+	}
+
+	if _, err := declareQueue(ch, topic); err != nil {
+		ch.Close()
+		return err
+	}
+	if err := ch.Qos(1, 0, false); err != nil {
+		ch.Close()
+		return err
+	}
+
+	return consume(ch, topic, topic, handler)
+}
+
+// SubscribeBroadcast binds a queue of its own to the fanout exchange of
+// topic. The queue is exclusive to the connection, so the broker deletes it
+// when the subscriber goes away.
+func (b *Bus) SubscribeBroadcast(topic string, handler messaging.Handler) error {
+	ch, err := b.conn.Channel()
+	if err != nil {
+		return err
+	}
+
+	if err := declareExchange(ch, topic); err != nil {
+		ch.Close()
+		return err
+	}
 	q, err := ch.QueueDeclare(
-		queueName,
-		false,
-		false,
+		"",
 		false,
+		true,
+		true,
 		false,
 		nil,
 	)
 	if err != nil {
-		return nil, err
+		ch.Close()
+		return err
+	}
+	if err := ch.QueueBind(q.Name, "", topic, false, nil); err != nil {
+		ch.Close()
+		return err
+	}
+	if err := ch.Qos(1, 0, false); err != nil {
+		ch.Close()
+		return err
 	}
 
-	return &Consumer{
-		Channel: ch,
-		Queue:   q,
-		Repo:    repo,
-	}, nil
+	return consume(ch, q.Name, topic, handler)
+}
+
+// Close closes the connection and with it every channel and consumer.
+func (b *Bus) Close() error {
+	return b.conn.Close()
 }
 
-func (c *Consumer) StartConsuming() {
-	msgs, err := c.Channel.Consume(
-		c.Queue.Name,
+// consume hands the messages of queue to handler in the background,
+// acknowledging the ones it accepts and requeueing a failed one once.
+func consume(ch *amqp.Channel, queue, topic string, handler messaging.Handler) error {
+	msgs, err := ch.Consume(
+		queue,
 		"",
-		true,
+		false,
 		false,
 		false,
 		false,
 		nil,
 	)
 	if err != nil {
-		log.Fatalf("Failed to register a consumer: %v", err)
+		ch.Close()
+		return err
 	}
 
 	go func() {
 		for d := range msgs {
-			var user model.User
-			if err := json.Unmarshal(d.Body, &user); err != nil {
-				log.Printf("Error decoding message: %v", err)
+			err := handler(messaging.Delivery{Topic: topic, Body: d.Body, Redelivered: d.Redelivered})
+			if err == nil {
+				d.Ack(false)
 				continue
 			}
-
-			// Process the message (e.g., create a welcome auction for the new user)
-			auction := model.Auction{
-				Item:   "Welcome Item for " + user.Name,
-				UserID: user.ID,
-			}
-			if _, err := c.Repo.CreateAuction(auction); err != nil {
-				log.Printf("Error creating auction: %v", err)
+			if d.Redelivered {
+				log.Printf("Dropping message on %s after redelivery: %v", topic, err)
 			}
+			d.Nack(false, !d.Redelivered)
 		}
 	}()
+	return nil
+}
+
+func declareExchange(ch *amqp.Channel, name string) error {
+	return ch.ExchangeDeclare(
+		name,
+		"fanout",
+		false,
+		false,
+		false,
+		false,
+		nil,
+	)
+}
+
+func declareQueue(ch *amqp.Channel, name string) (amqp.Queue, error) {
+	return ch.QueueDeclare(
+		name,
+		false,
+		false,
+		false,
+		false,
+		nil,
+	)
 }
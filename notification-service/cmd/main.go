@@ -0,0 +1,97 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"notification-service/internal/channel"
+	"notification-service/internal/config"
+	"notification-service/internal/consumer"
+	"notification-service/internal/db"
+	"notification-service/internal/handler"
+	"notification-service/internal/messaging"
+	"notification-service/internal/model"
+	"notification-service/internal/repository"
+	"notification-service/internal/service"
+	"notification-service/internal/templates"
+	"notification-service/rabbitmq"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	cfg := config.LoadConfig()
+
+	// Connect to the database, Postgres or SQLite depending on the URL scheme
+	conn, err := db.Open(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	err = conn.AutoMigrate(&model.Notification{}, &model.Recipient{})
+	if err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+
+	log.Println("Migration successful")
+
+	bus, err := openBus(cfg.RabbitMQURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to the message bus: %v", err)
+	}
+
+	notifications := repository.NewNotificationRepositoryImpl(conn)
+	recipients := repository.NewRecipientRepositoryImpl(conn)
+
+	preferences := service.NewUserServiceClient(cfg.UserServiceURL, 5*time.Second)
+	notifier := service.NewNotifier(notifications, recipients, preferences, templates.Builtin())
+	if err := bus.Subscribe(cfg.QUEUE_USER_NOTIFICATIONS, consumer.NewUserNotifications(notifier).Handle); err != nil {
+		log.Fatalf("Failed to subscribe to %s: %v", cfg.QUEUE_USER_NOTIFICATIONS, err)
+	}
+	if err := bus.Subscribe(cfg.QUEUE_AUCTION_EVENTS, consumer.NewAuctionEvents(notifier).Handle); err != nil {
+		log.Fatalf("Failed to subscribe to %s: %v", cfg.QUEUE_AUCTION_EVENTS, err)
+	}
+
+	channels := map[string]channel.Channel{
+		model.ChannelWebhook: channel.NewWebhook(10 * time.Second),
+	}
+	if cfg.SMTPAddr != "" {
+		from, err := mail.ParseAddress(cfg.SMTPFrom)
+		if err != nil {
+			log.Fatalf("Invalid SMTP_FROM %q: %v", cfg.SMTPFrom, err)
+		}
+		var auth smtp.Auth
+		if cfg.SMTPUsername != "" {
+			host, _, _ := strings.Cut(cfg.SMTPAddr, ":")
+			auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, host)
+		}
+		channels[model.ChannelEmail] = channel.NewEmail(cfg.SMTPAddr, *from, auth)
+	} else {
+		log.Println("SMTP_ADDR is not set, email notifications will fail")
+	}
+	deliverer := service.NewDeliverer(notifications, recipients, channels, time.Second)
+	deliverer.Start()
+
+	notificationHandler := handler.NewNotificationHandler(service.NewNotificationService(notifications, recipients))
+	// Register HTTP endpoints with handler methods
+	http.HandleFunc("/inbox", notificationHandler.GetInbox)
+	http.HandleFunc("/inbox/read/{id}", notificationHandler.MarkRead)
+	http.HandleFunc("/notifications", notificationHandler.GetNotifications)
+	http.HandleFunc("/settings", notificationHandler.GetSettings)
+	http.HandleFunc("/settings/update", notificationHandler.UpdateSettings)
+
+	log.Printf("Notification Service running on port %s", cfg.ServerPort)
+	log.Fatal(http.ListenAndServe(":"+cfg.ServerPort, nil))
+}
+
+// openBus connects to RabbitMQ, or starts an in-process bus when url is
+// memory:// so the service can run without a broker.
+func openBus(url string) (messaging.Bus, error) {
+	if url == "memory://" {
+		return messaging.NewMemoryBus(), nil
+	}
+	return rabbitmq.NewBus(url)
+}
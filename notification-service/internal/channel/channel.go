@@ -0,0 +1,12 @@
+// Package channel delivers rendered notifications to users: by email over
+// SMTP and by webhook. Inbox notifications need no delivery, they are read
+// through the API.
+package channel
+
+import "notification-service/internal/model"
+
+// Channel sends a notification to its recipient. An error means the
+// notification was not delivered and may be retried.
+type Channel interface {
+	Send(recipient model.Recipient, notification model.Notification) error
+}
@@ -0,0 +1,56 @@
+package channel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net/mail"
+	"net/smtp"
+	"notification-service/internal/model"
+	"strings"
+	"time"
+)
+
+// Email sends notifications as plain text email through an SMTP server.
+type Email struct {
+	addr string
+	from mail.Address
+	auth smtp.Auth
+}
+
+// NewEmail creates an Email channel that relays through the SMTP server at
+// addr (host:port) with from as sender. auth may be nil for servers that do
+// not need it, like a local sink.
+func NewEmail(addr string, from mail.Address, auth smtp.Auth) *Email {
+	return &Email{addr: addr, from: from, auth: auth}
+}
+
+// Ensure Email implements Channel
+var _ Channel = (*Email)(nil)
+
+func (e *Email) Send(recipient model.Recipient, notification model.Notification) error {
+	if recipient.Email == "" {
+		return errors.New("recipient has no email address")
+	}
+	to := mail.Address{Name: recipient.Name, Address: recipient.Email}
+	return smtp.SendMail(e.addr, e.auth, e.from.Address, []string{to.Address}, e.message(to, notification))
+}
+
+// message builds the email, with the subject encoded for non-ASCII text and
+// CRLF line endings as SMTP expects.
+func (e *Email) message(to mail.Address, notification model.Notification) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", e.from.String())
+	fmt.Fprintf(&msg, "To: %s\r\n", to.String())
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", notification.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: text/plain; charset=utf-8\r\n")
+	fmt.Fprintf(&msg, "Content-Transfer-Encoding: 8bit\r\n")
+	fmt.Fprintf(&msg, "\r\n")
+	body := strings.ReplaceAll(notification.Body, "\r\n", "\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	msg.WriteString("\r\n")
+	return msg.Bytes()
+}
@@ -0,0 +1,144 @@
+package channel_test
+
+import (
+	"bufio"
+	"mime"
+	"net"
+	"net/mail"
+	"notification-service/internal/channel"
+	"notification-service/internal/model"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sentMail is a message received by smtpSink.
+type sentMail struct {
+	from string
+	to   []string
+	data string
+}
+
+// smtpSink is a minimal SMTP server that accepts every message and keeps it.
+type smtpSink struct {
+	listener net.Listener
+	mu       sync.Mutex
+	messages []sentMail
+}
+
+func newSMTPSink(t *testing.T) *smtpSink {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	sink := &smtpSink{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+	go sink.serve()
+	return sink
+}
+
+func (s *smtpSink) addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *smtpSink) received() []sentMail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]sentMail(nil), s.messages...)
+}
+
+func (s *smtpSink) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *smtpSink) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 sink ready")
+	var current sentMail
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(command, " ", 2)[0])
+		switch {
+		case verb == "EHLO" || verb == "HELO":
+			reply("250 sink")
+		case strings.HasPrefix(strings.ToUpper(command), "MAIL FROM:"):
+			current = sentMail{from: strings.Trim(command[len("MAIL FROM:"):], "<> ")}
+			reply("250 OK")
+		case strings.HasPrefix(strings.ToUpper(command), "RCPT TO:"):
+			current.to = append(current.to, strings.Trim(command[len("RCPT TO:"):], "<> "))
+			reply("250 OK")
+		case verb == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			current.data = data.String()
+			s.mu.Lock()
+			s.messages = append(s.messages, current)
+			s.mu.Unlock()
+			reply("250 OK")
+		case verb == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestEmailSend(t *testing.T) {
+	sink := newSMTPSink(t)
+	email := channel.NewEmail(sink.addr(), mail.Address{Name: "Auctions", Address: "noreply@auctions.test"}, nil)
+
+	recipient := model.Recipient{UserID: 2, Name: "Ada", Email: "ada@example.com"}
+	notification := model.Notification{ID: 1, UserID: 2, Subject: "Ganaste la subasta #7", Body: "Hola Ada:\n\nEnhorabuena."}
+	require.NoError(t, email.Send(recipient, notification))
+
+	received := sink.received()
+	if assert.Len(t, received, 1) {
+		assert.Equal(t, "noreply@auctions.test", received[0].from)
+		assert.Equal(t, []string{"ada@example.com"}, received[0].to)
+
+		msg, err := mail.ReadMessage(strings.NewReader(received[0].data))
+		require.NoError(t, err)
+		subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+		require.NoError(t, err)
+		assert.Equal(t, "Ganaste la subasta #7", subject)
+		assert.Equal(t, `"Ada" <ada@example.com>`, msg.Header.Get("To"))
+		assert.Contains(t, received[0].data, "Hola Ada:\r\n\r\nEnhorabuena.")
+	}
+}
+
+func TestEmailSendFailsWithoutServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	email := channel.NewEmail(addr, mail.Address{Address: "noreply@auctions.test"}, nil)
+	err = email.Send(model.Recipient{Email: "ada@example.com"}, model.Notification{Subject: "Hi", Body: "Hi"})
+	assert.Error(t, err)
+	assert.Error(t, email.Send(model.Recipient{}, model.Notification{}))
+}
@@ -0,0 +1,64 @@
+package channel
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"notification-service/internal/model"
+	"time"
+)
+
+// Webhook posts notifications as JSON to the webhook URL of the recipient.
+// Any 2xx answer counts as delivered.
+type Webhook struct {
+	client *http.Client
+}
+
+// NewWebhook creates a Webhook channel whose requests give up after timeout.
+func NewWebhook(timeout time.Duration) *Webhook {
+	return &Webhook{client: &http.Client{Timeout: timeout}}
+}
+
+// Ensure Webhook implements Channel
+var _ Channel = (*Webhook)(nil)
+
+// webhookPayload is the body posted to webhooks.
+type webhookPayload struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	EventType string    `json:"event_type"`
+	Locale    string    `json:"locale"`
+	Subject   string    `json:"subject"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (w *Webhook) Send(recipient model.Recipient, notification model.Notification) error {
+	if recipient.WebhookURL == "" {
+		return errors.New("recipient has no webhook")
+	}
+	payload, err := json.Marshal(webhookPayload{
+		ID:        notification.ID,
+		UserID:    notification.UserID,
+		EventType: notification.EventType,
+		Locale:    notification.Locale,
+		Subject:   notification.Subject,
+		Body:      notification.Body,
+		CreatedAt: notification.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.client.Post(recipient.WebhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package channel_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"notification-service/internal/channel"
+	"notification-service/internal/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSend(t *testing.T) {
+	var received map[string]any
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer receiver.Close()
+
+	webhook := channel.NewWebhook(time.Second)
+	notification := model.Notification{ID: 3, UserID: 2, EventType: model.EventNotifyWon, Locale: "en", Subject: "You won", Body: "Congratulations"}
+	require.NoError(t, webhook.Send(model.Recipient{UserID: 2, WebhookURL: receiver.URL}, notification))
+
+	assert.Equal(t, float64(3), received["id"])
+	assert.Equal(t, model.EventNotifyWon, received["event_type"])
+	assert.Equal(t, "You won", received["subject"])
+}
+
+func TestWebhookSendFailsOnErrorStatus(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	webhook := channel.NewWebhook(time.Second)
+	err := webhook.Send(model.Recipient{WebhookURL: receiver.URL}, model.Notification{ID: 1})
+	assert.ErrorContains(t, err, "503")
+	assert.Error(t, webhook.Send(model.Recipient{}, model.Notification{ID: 1}))
+}
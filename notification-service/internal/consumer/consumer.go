@@ -0,0 +1,65 @@
+// Package consumer holds the handlers for the events notification-service
+// subscribes to.
+package consumer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"notification-service/internal/messaging"
+	"notification-service/internal/model"
+	"notification-service/internal/service"
+)
+
+// UserNotifications records every new user as a recipient and welcomes them.
+type UserNotifications struct {
+	notifier *service.Notifier
+}
+
+func NewUserNotifications(notifier *service.Notifier) *UserNotifications {
+	return &UserNotifications{notifier: notifier}
+}
+
+// Handle processes a user.created message. Malformed messages are
+// acknowledged and logged; other errors are returned so they are redelivered.
+func (c *UserNotifications) Handle(d messaging.Delivery) error {
+	var user model.User
+	if err := json.Unmarshal(d.Body, &user); err != nil {
+		log.Printf("Error decoding message: %v", err)
+		return nil
+	}
+	if user.ID <= 0 {
+		log.Printf("Ignoring user.created message without a user ID")
+		return nil
+	}
+	return c.notifier.UserCreated(eventKey(d), user)
+}
+
+// AuctionEvents notifies users of the auction events addressed to them.
+// Events for nobody in particular, like auction.created, are ignored.
+type AuctionEvents struct {
+	notifier *service.Notifier
+}
+
+func NewAuctionEvents(notifier *service.Notifier) *AuctionEvents {
+	return &AuctionEvents{notifier: notifier}
+}
+
+// Handle processes an auction event. Malformed messages are acknowledged and
+// logged; other errors are returned so they are redelivered.
+func (c *AuctionEvents) Handle(d messaging.Delivery) error {
+	var event model.Event
+	if err := json.Unmarshal(d.Body, &event); err != nil {
+		log.Printf("Error decoding message: %v", err)
+		return nil
+	}
+	return c.notifier.Notify(eventKey(d), event)
+}
+
+// eventKey identifies a message by its topic and content, so a message the
+// publisher sends twice is only notified once.
+func eventKey(d messaging.Delivery) string {
+	sum := sha256.Sum256(append([]byte(d.Topic+"\n"), d.Body...))
+	return hex.EncodeToString(sum[:])
+}
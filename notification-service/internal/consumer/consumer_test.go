@@ -0,0 +1,50 @@
+package consumer_test
+
+import (
+	"notification-service/internal/consumer"
+	"notification-service/internal/messaging"
+	"notification-service/internal/model"
+	"notification-service/internal/repository"
+	"notification-service/internal/service"
+	"notification-service/internal/templates"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// everything is a PreferenceSource for users who want every notification.
+type everything struct{}
+
+func (everything) Preferences(userID int) (model.Preferences, error) {
+	return model.Preferences{Outbid: true, EndingSoon: true, Won: true, Lost: true}, nil
+}
+
+func TestConsumersCreateNotifications(t *testing.T) {
+	bus := messaging.NewMemoryBus()
+	defer bus.Close()
+	store := repository.NewMemoryStore()
+	notifier := service.NewNotifier(store.Notifications(), store.Recipients(), everything{}, templates.Builtin())
+	require.NoError(t, bus.Subscribe("user_notifications", consumer.NewUserNotifications(notifier).Handle))
+	require.NoError(t, bus.Subscribe("auction_events", consumer.NewAuctionEvents(notifier).Handle))
+
+	bus.Publish("user_notifications", []byte(`{"ID": 7, "Name": "Ada", "Email": "ada@example.com"}`))
+	bus.Publish("user_notifications", []byte(`not json`))
+	bus.Wait()
+	bus.Publish("auction_events", []byte(`{"type": "auction.created", "auction_id": 5, "user_id": 7}`))
+	bus.Publish("auction_events", []byte(`{"type": "notify.won", "auction_id": 5, "user_id": 7, "amount": {"amount": "20.00", "currency": "EUR"}}`))
+	// Redelivered by the broker: notified only once.
+	bus.Publish("auction_events", []byte(`{"type": "notify.won", "auction_id": 5, "user_id": 7, "amount": {"amount": "20.00", "currency": "EUR"}}`))
+	bus.Wait()
+
+	inbox, err := store.Notifications().GetNotificationsByUserID(7, model.ChannelInbox)
+	require.NoError(t, err)
+	require.Len(t, inbox, 2)
+	assert.Equal(t, model.EventNotifyWon, inbox[0].EventType)
+	assert.Contains(t, inbox[0].Body, "20.00 EUR")
+	assert.Equal(t, model.EventUserCreated, inbox[1].EventType)
+
+	email, err := store.Notifications().GetNotificationsByUserID(7, model.ChannelEmail)
+	require.NoError(t, err)
+	assert.Len(t, email, 2)
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"notification-service/internal/model"
+	"notification-service/internal/service"
+	"regexp"
+	"strconv"
+)
+
+// UserIDHeader carries the ID of the user making the request, as set by the
+// gateway in front of the services.
+const UserIDHeader = "X-User-ID"
+
+type NotificationHandler struct {
+	service service.NotificationService
+}
+
+func NewNotificationHandler(notificationService service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{service: notificationService}
+}
+
+// settingsRequest is the body of UpdateSettings.
+type settingsRequest struct {
+	Locale        string
+	WebhookURL    string
+	EmailDisabled bool
+}
+
+// GetInbox handles the request to list the inbox of the acting user.
+func (nh *NotificationHandler) GetInbox(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	notifications, err := nh.service.GetInbox(userID)
+	if err != nil {
+		writeServiceError(w, "fetching inbox", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(notifications)
+}
+
+// MarkRead handles the request to mark an inbox notification as read.
+func (nh *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	re := regexp.MustCompile(`/inbox/read/(\d+)$`)
+	matches := re.FindStringSubmatch(r.URL.Path)
+	if len(matches) != 2 {
+		http.Error(w, "Invalid notification ID", http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.Atoi(matches[1])
+	if err != nil {
+		http.Error(w, "Invalid notification ID", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	notification, err := nh.service.MarkRead(userID, id)
+	if err != nil {
+		writeServiceError(w, "marking notification read", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(notification)
+}
+
+// GetNotifications handles the request to list the notifications of the
+// acting user on every channel, with their delivery status.
+func (nh *NotificationHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	notifications, err := nh.service.GetNotifications(userID)
+	if err != nil {
+		writeServiceError(w, "fetching notifications", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(notifications)
+}
+
+// GetSettings handles the request to get the notification settings of the
+// acting user.
+func (nh *NotificationHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	settings, err := nh.service.GetSettings(userID)
+	if err != nil {
+		writeServiceError(w, "fetching settings", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// UpdateSettings handles the request to change the locale, webhook and
+// email switch of the acting user.
+func (nh *NotificationHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req settingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON request body", http.StatusBadRequest)
+		return
+	}
+
+	settings, err := nh.service.UpdateSettings(model.Recipient{
+		UserID:        userID,
+		Locale:        req.Locale,
+		WebhookURL:    req.WebhookURL,
+		EmailDisabled: req.EmailDisabled,
+	})
+	if err != nil {
+		writeServiceError(w, "updating settings", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// userIDFromRequest reads the acting user from the UserIDHeader, writing a
+// 401 response when it is missing or malformed.
+func userIDFromRequest(w http.ResponseWriter, r *http.Request) (int, bool) {
+	userID, err := strconv.Atoi(r.Header.Get(UserIDHeader))
+	if err != nil || userID <= 0 {
+		http.Error(w, "Missing or invalid "+UserIDHeader+" header", http.StatusUnauthorized)
+		return 0, false
+	}
+	return userID, true
+}
+
+// writeServiceError maps service errors to HTTP status codes.
+func writeServiceError(w http.ResponseWriter, action string, err error) {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		http.Error(w, "Notification not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidInput):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Printf("Error %s: %v", action, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
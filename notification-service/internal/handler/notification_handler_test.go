@@ -0,0 +1,169 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"notification-service/internal/handler"
+	"notification-service/internal/model"
+	"notification-service/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNotificationService struct {
+	mock.Mock
+}
+
+func (m *MockNotificationService) GetInbox(userID int) ([]model.Notification, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.Notification), args.Error(1)
+}
+
+func (m *MockNotificationService) MarkRead(userID, id int) (model.Notification, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(model.Notification), args.Error(1)
+}
+
+func (m *MockNotificationService) GetNotifications(userID int) ([]model.Notification, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.Notification), args.Error(1)
+}
+
+func (m *MockNotificationService) GetSettings(userID int) (model.Recipient, error) {
+	args := m.Called(userID)
+	return args.Get(0).(model.Recipient), args.Error(1)
+}
+
+func (m *MockNotificationService) UpdateSettings(settings model.Recipient) (model.Recipient, error) {
+	args := m.Called(settings)
+	return args.Get(0).(model.Recipient), args.Error(1)
+}
+
+func TestGetInbox(t *testing.T) {
+	mockService := new(MockNotificationService)
+	notificationHandler := handler.NewNotificationHandler(mockService)
+
+	inbox := []model.Notification{{ID: 1, UserID: 7, Channel: model.ChannelInbox, Subject: "Welcome, Ada"}}
+	mockService.On("GetInbox", 7).Return(inbox, nil)
+
+	req, _ := http.NewRequest("GET", "/inbox", nil)
+	req.Header.Set(handler.UserIDHeader, "7")
+	rr := httptest.NewRecorder()
+	notificationHandler.GetInbox(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var got []model.Notification
+	json.Unmarshal(rr.Body.Bytes(), &got)
+	assert.Equal(t, inbox, got)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetInboxRequiresUser(t *testing.T) {
+	mockService := new(MockNotificationService)
+	notificationHandler := handler.NewNotificationHandler(mockService)
+
+	req, _ := http.NewRequest("GET", "/inbox", nil)
+	rr := httptest.NewRecorder()
+	notificationHandler.GetInbox(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	mockService.AssertNotCalled(t, "GetInbox", mock.Anything)
+}
+
+func TestMarkRead(t *testing.T) {
+	mockService := new(MockNotificationService)
+	notificationHandler := handler.NewNotificationHandler(mockService)
+
+	mockService.On("MarkRead", 7, 1).Return(model.Notification{ID: 1, UserID: 7}, nil)
+	mockService.On("MarkRead", 7, 2).Return(model.Notification{}, service.ErrNotFound)
+
+	req, _ := http.NewRequest("POST", "/inbox/read/1", nil)
+	req.Header.Set(handler.UserIDHeader, "7")
+	rr := httptest.NewRecorder()
+	notificationHandler.MarkRead(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	req, _ = http.NewRequest("POST", "/inbox/read/2", nil)
+	req.Header.Set(handler.UserIDHeader, "7")
+	rr = httptest.NewRecorder()
+	notificationHandler.MarkRead(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	req, _ = http.NewRequest("POST", "/inbox/read/abc", nil)
+	req.Header.Set(handler.UserIDHeader, "7")
+	rr = httptest.NewRecorder()
+	notificationHandler.MarkRead(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetNotifications(t *testing.T) {
+	mockService := new(MockNotificationService)
+	notificationHandler := handler.NewNotificationHandler(mockService)
+
+	notifications := []model.Notification{{ID: 2, UserID: 7, Channel: model.ChannelEmail, Status: model.StatusFailed, Attempts: 5}}
+	mockService.On("GetNotifications", 7).Return(notifications, nil)
+
+	req, _ := http.NewRequest("GET", "/notifications", nil)
+	req.Header.Set(handler.UserIDHeader, "7")
+	rr := httptest.NewRecorder()
+	notificationHandler.GetNotifications(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var got []model.Notification
+	json.Unmarshal(rr.Body.Bytes(), &got)
+	assert.Equal(t, notifications, got)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetSettings(t *testing.T) {
+	mockService := new(MockNotificationService)
+	notificationHandler := handler.NewNotificationHandler(mockService)
+
+	mockService.On("GetSettings", 7).Return(model.Recipient{UserID: 7, Locale: "es"}, nil)
+
+	req, _ := http.NewRequest("GET", "/settings", nil)
+	req.Header.Set(handler.UserIDHeader, "7")
+	rr := httptest.NewRecorder()
+	notificationHandler.GetSettings(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var got model.Recipient
+	json.Unmarshal(rr.Body.Bytes(), &got)
+	assert.Equal(t, "es", got.Locale)
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateSettings(t *testing.T) {
+	mockService := new(MockNotificationService)
+	notificationHandler := handler.NewNotificationHandler(mockService)
+
+	settings := model.Recipient{UserID: 7, Locale: "es", WebhookURL: "https://hooks.example/7", EmailDisabled: true}
+	mockService.On("UpdateSettings", settings).Return(settings, nil)
+	invalid := model.Recipient{UserID: 7, WebhookURL: "nope"}
+	mockService.On("UpdateSettings", invalid).Return(model.Recipient{}, service.ErrInvalidInput)
+
+	body, _ := json.Marshal(map[string]any{"Locale": "es", "WebhookURL": "https://hooks.example/7", "EmailDisabled": true, "UserID": 99})
+	req, _ := http.NewRequest("POST", "/settings/update", bytes.NewBuffer(body))
+	req.Header.Set(handler.UserIDHeader, "7")
+	rr := httptest.NewRecorder()
+	notificationHandler.UpdateSettings(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	req, _ = http.NewRequest("POST", "/settings/update", bytes.NewBufferString(`{"WebhookURL": "nope"}`))
+	req.Header.Set(handler.UserIDHeader, "7")
+	rr = httptest.NewRecorder()
+	notificationHandler.UpdateSettings(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	req, _ = http.NewRequest("POST", "/settings/update", bytes.NewBufferString(`{`))
+	req.Header.Set(handler.UserIDHeader, "7")
+	rr = httptest.NewRecorder()
+	notificationHandler.UpdateSettings(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,42 @@
+// Package messaging defines how the services exchange events without
+// depending on a particular broker. The rabbitmq package provides the AMQP
+// driver and MemoryBus an in-process one for tests and local runs.
+package messaging
+
+import "errors"
+
+// ErrClosed is returned when publishing to or subscribing on a closed bus.
+var ErrClosed = errors.New("message bus closed")
+
+// Delivery is a message received from a topic.
+type Delivery struct {
+	Topic string
+	Body  []byte
+	// Redelivered is set when the message was negatively acknowledged before.
+	Redelivered bool
+}
+
+// Handler processes a delivery. Returning nil acknowledges it. Returning an
+// error negatively acknowledges it: the message is redelivered once, with
+// Redelivered set, and dropped if it fails again.
+type Handler func(d Delivery) error
+
+// EventPublisher sends serialized events to a topic.
+type EventPublisher interface {
+	Publish(topic string, body []byte) error
+}
+
+// EventSubscriber delivers the messages published to a topic to handler.
+// Messages of a topic are handed out one at a time, in publishing order;
+// when several handlers subscribe to the same topic they take turns, like
+// competing consumers of a queue.
+type EventSubscriber interface {
+	Subscribe(topic string, handler Handler) error
+}
+
+// Bus is a broker connection that can both publish and subscribe.
+type Bus interface {
+	EventPublisher
+	EventSubscriber
+	Close() error
+}
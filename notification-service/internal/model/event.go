@@ -0,0 +1,69 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Events the service sends notifications for. user.created comes from the
+// user service, the notify.* intents from the auction service.
+const (
+	EventUserCreated      = "user.created"
+	EventNotifyOutbid     = "notify.outbid"
+	EventNotifyEndingSoon = "notify.ending_soon"
+	EventNotifyWon        = "notify.won"
+	EventNotifyLost       = "notify.lost"
+)
+
+// Event is an auction event as published by the auction service. Only the
+// fields the templates use are decoded.
+type Event struct {
+	Type       string     `json:"type"`
+	AuctionID  int        `json:"auction_id"`
+	UserID     int        `json:"user_id"`
+	Amount     *Money     `json:"amount"`
+	EndsAt     *time.Time `json:"ends_at"`
+	OccurredAt time.Time  `json:"occurred_at"`
+}
+
+// Money is an amount as the auction service encodes it, e.g. "12.50" EUR.
+type Money struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func (m Money) String() string {
+	return fmt.Sprintf("%s %s", m.Amount, m.Currency)
+}
+
+// User is a user as published by the user service.
+type User struct {
+	ID    int
+	Name  string
+	Email string
+}
+
+// Preferences tells which notification intents a user wants, as stored in
+// the user service.
+type Preferences struct {
+	Outbid     bool
+	EndingSoon bool
+	Won        bool
+	Lost       bool
+}
+
+// Wants reports whether the user wants notifications for eventType. Events
+// that are not notification intents are always sent.
+func (p Preferences) Wants(eventType string) bool {
+	switch eventType {
+	case EventNotifyOutbid:
+		return p.Outbid
+	case EventNotifyEndingSoon:
+		return p.EndingSoon
+	case EventNotifyWon:
+		return p.Won
+	case EventNotifyLost:
+		return p.Lost
+	}
+	return true
+}
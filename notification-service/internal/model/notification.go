@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// Channels a notification can be delivered through.
+const (
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+	ChannelInbox   = "inbox"
+)
+
+// Delivery statuses of a notification.
+const (
+	StatusPending = "pending"
+	StatusSent    = "sent"
+	StatusFailed  = "failed"
+)
+
+// Notification is one message to one user through one channel. Email and
+// webhook notifications start pending and are retried until they are sent or
+// run out of attempts; inbox notifications are sent as soon as they are
+// stored.
+type Notification struct {
+	ID        int    `gorm:"primaryKey"`
+	UserID    int    `gorm:"index;not null"`
+	EventType string `gorm:"size:64;not null"`
+	// EventKey identifies the event the notification was made for, so an
+	// event delivered twice does not notify twice.
+	EventKey string `gorm:"size:64;uniqueIndex:idx_notification_event_channel;not null"`
+	Channel  string `gorm:"size:16;uniqueIndex:idx_notification_event_channel;not null"`
+	Locale   string `gorm:"size:16;not null"`
+	Subject  string `gorm:"size:255;not null"`
+	Body     string `gorm:"not null"`
+	Status   string `gorm:"size:16;index;not null"`
+	// Attempts counts the failed deliveries, LastError tells why the last
+	// one failed and NextAttemptAt when the next one is due.
+	Attempts      int `gorm:"not null;default:0"`
+	LastError     string
+	NextAttemptAt *time.Time `gorm:"index"`
+	SentAt        *time.Time
+	// ReadAt is when the user marked an inbox notification as read.
+	ReadAt    *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// IsDue reports whether a pending notification should be delivered at now.
+func (n Notification) IsDue(now time.Time) bool {
+	return n.Status == StatusPending && (n.NextAttemptAt == nil || !n.NextAttemptAt.After(now))
+}
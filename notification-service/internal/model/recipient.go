@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// DefaultLocale is the language of users who did not choose one, and of the
+// templates used when there is none in theirs.
+const DefaultLocale = "en"
+
+// Recipient is what the service knows about a user: how to reach them and in
+// which language. It is created from the user.created event; users change
+// their locale and channels through the settings endpoint.
+type Recipient struct {
+	UserID int `gorm:"primaryKey;autoIncrement:false"`
+	Name   string
+	Email  string
+	Locale string `gorm:"size:16"`
+	// WebhookURL receives every notification of the user as a JSON POST
+	// when set.
+	WebhookURL string
+	// EmailDisabled stops email notifications. The inbox always gets them.
+	EmailDisabled bool `gorm:"not null;default:false"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Channels returns the channels notifications for r go through.
+func (r Recipient) Channels() []string {
+	channels := []string{ChannelInbox}
+	if r.Email != "" && !r.EmailDisabled {
+		channels = append(channels, ChannelEmail)
+	}
+	if r.WebhookURL != "" {
+		channels = append(channels, ChannelWebhook)
+	}
+	return channels
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/glebarez/go-sqlite"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// This file keeps the differences between the supported databases, Postgres
+// and SQLite, out of the repositories themselves.
+
+// SQLite result codes, see https://www.sqlite.org/rescode.html.
+const (
+	sqliteConstraintPK     = 1555
+	sqliteConstraintUnique = 2067
+)
+
+// isUniqueViolation reports whether err comes from a unique constraint.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23505"
+	}
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		code := sqliteErr.Code()
+		return code == sqliteConstraintUnique || code == sqliteConstraintPK
+	}
+	return false
+}
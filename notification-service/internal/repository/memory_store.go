@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"notification-service/internal/model"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps notifications and recipients in memory. It mirrors the
+// behaviour of the gorm repositories and is meant for tests and local demos.
+// It is safe for concurrent use.
+type MemoryStore struct {
+	mu                 sync.RWMutex
+	notifications      []model.Notification
+	recipients         map[int]model.Recipient
+	nextNotificationID int
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{recipients: map[int]model.Recipient{}}
+}
+
+// Notifications returns a NotificationRepository backed by the store.
+func (s *MemoryStore) Notifications() NotificationRepository {
+	return &memoryNotificationRepository{store: s}
+}
+
+// Recipients returns a RecipientRepository backed by the store.
+func (s *MemoryStore) Recipients() RecipientRepository {
+	return &memoryRecipientRepository{store: s}
+}
+
+// memoryNotificationRepository is an in-memory NotificationRepository.
+type memoryNotificationRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryNotificationRepository) CreateNotification(notification model.Notification) (model.Notification, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	for _, existing := range r.store.notifications {
+		if existing.EventKey == notification.EventKey && existing.Channel == notification.Channel {
+			return notification, ErrDuplicateNotification
+		}
+	}
+	now := time.Now()
+	r.store.nextNotificationID++
+	notification.ID = r.store.nextNotificationID
+	notification.CreatedAt = now
+	notification.UpdatedAt = now
+	r.store.notifications = append(r.store.notifications, notification)
+	return notification, nil
+}
+
+func (r *memoryNotificationRepository) GetNotificationByID(id int) (model.Notification, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for _, notification := range r.store.notifications {
+		if notification.ID == id {
+			return notification, nil
+		}
+	}
+	return model.Notification{}, ErrNotFound
+}
+
+func (r *memoryNotificationRepository) GetNotificationsByUserID(userID int, channel string) ([]model.Notification, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var notifications []model.Notification
+	for _, notification := range r.store.notifications {
+		if notification.UserID == userID && (channel == "" || notification.Channel == channel) {
+			notifications = append(notifications, notification)
+		}
+	}
+	sort.Slice(notifications, func(i, j int) bool { return notifications[i].ID > notifications[j].ID })
+	return notifications, nil
+}
+
+func (r *memoryNotificationRepository) GetDueNotifications(now time.Time, limit int) ([]model.Notification, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var notifications []model.Notification
+	for _, notification := range r.store.notifications {
+		if notification.IsDue(now) && len(notifications) < limit {
+			notifications = append(notifications, notification)
+		}
+	}
+	return notifications, nil
+}
+
+func (r *memoryNotificationRepository) UpdateNotification(notification model.Notification) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	for i, existing := range r.store.notifications {
+		if existing.ID == notification.ID {
+			notification.CreatedAt = existing.CreatedAt
+			notification.UpdatedAt = time.Now()
+			r.store.notifications[i] = notification
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// memoryRecipientRepository is an in-memory RecipientRepository.
+type memoryRecipientRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryRecipientRepository) GetRecipient(userID int) (model.Recipient, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	recipient, ok := r.store.recipients[userID]
+	if !ok {
+		return model.Recipient{}, ErrNotFound
+	}
+	return recipient, nil
+}
+
+func (r *memoryRecipientRepository) SaveRecipient(recipient model.Recipient) (model.Recipient, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	now := time.Now()
+	recipient.CreatedAt = now
+	if existing, ok := r.store.recipients[recipient.UserID]; ok {
+		recipient.CreatedAt = existing.CreatedAt
+	}
+	recipient.UpdatedAt = now
+	r.store.recipients[recipient.UserID] = recipient
+	return recipient, nil
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"errors"
+	"notification-service/internal/model"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when the requested notification or recipient
+	// does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrDuplicateNotification is returned when the event already has a
+	// notification on the channel.
+	ErrDuplicateNotification = errors.New("notification already exists")
+)
+
+// NotificationRepository stores notifications and their delivery status.
+type NotificationRepository interface {
+	CreateNotification(notification model.Notification) (model.Notification, error)
+	GetNotificationByID(id int) (model.Notification, error)
+	// GetNotificationsByUserID returns the notifications of a user on
+	// channel, or on every channel when it is empty, newest first.
+	GetNotificationsByUserID(userID int, channel string) ([]model.Notification, error)
+	// GetDueNotifications returns up to limit pending notifications whose
+	// next attempt is due at now, oldest first.
+	GetDueNotifications(now time.Time, limit int) ([]model.Notification, error)
+	UpdateNotification(notification model.Notification) error
+}
+
+// RecipientRepository stores how to reach users.
+type RecipientRepository interface {
+	GetRecipient(userID int) (model.Recipient, error)
+	// SaveRecipient creates or replaces the recipient of a user.
+	SaveRecipient(recipient model.Recipient) (model.Recipient, error)
+}
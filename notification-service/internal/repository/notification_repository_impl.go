@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"errors"
+	"notification-service/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationRepositoryImpl handles database operations related to
+// notifications.
+type NotificationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepositoryImpl creates a new instance of
+// NotificationRepositoryImpl.
+func NewNotificationRepositoryImpl(db *gorm.DB) *NotificationRepositoryImpl {
+	return &NotificationRepositoryImpl{db}
+}
+
+// Ensure NotificationRepositoryImpl implements NotificationRepository
+var _ NotificationRepository = (*NotificationRepositoryImpl)(nil)
+
+// CreateNotification stores a new notification.
+func (nr *NotificationRepositoryImpl) CreateNotification(notification model.Notification) (model.Notification, error) {
+	err := nr.db.Create(&notification).Error
+	if isUniqueViolation(err) {
+		return notification, ErrDuplicateNotification
+	}
+	return notification, err
+}
+
+// GetNotificationByID returns a notification by its ID.
+func (nr *NotificationRepositoryImpl) GetNotificationByID(id int) (model.Notification, error) {
+	var notification model.Notification
+	err := nr.db.First(&notification, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return notification, ErrNotFound
+	}
+	return notification, err
+}
+
+// GetNotificationsByUserID returns the notifications of a user, newest first.
+func (nr *NotificationRepositoryImpl) GetNotificationsByUserID(userID int, channel string) ([]model.Notification, error) {
+	query := nr.db.Where("user_id = ?", userID)
+	if channel != "" {
+		query = query.Where("channel = ?", channel)
+	}
+	var notifications []model.Notification
+	err := query.Order("id DESC").Find(&notifications).Error
+	return notifications, err
+}
+
+// GetDueNotifications returns the pending notifications due at now.
+func (nr *NotificationRepositoryImpl) GetDueNotifications(now time.Time, limit int) ([]model.Notification, error) {
+	var notifications []model.Notification
+	err := nr.db.Where("status = ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?)", model.StatusPending, now.UTC()).
+		Order("id").Limit(limit).Find(&notifications).Error
+	return notifications, err
+}
+
+// UpdateNotification stores the changes of an existing notification.
+func (nr *NotificationRepositoryImpl) UpdateNotification(notification model.Notification) error {
+	result := nr.db.Model(&notification).Select("*").Omit("ID", "CreatedAt").Updates(&notification)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RecipientRepositoryImpl handles database operations related to
+// recipients.
+type RecipientRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRecipientRepositoryImpl creates a new instance of
+// RecipientRepositoryImpl.
+func NewRecipientRepositoryImpl(db *gorm.DB) *RecipientRepositoryImpl {
+	return &RecipientRepositoryImpl{db}
+}
+
+// Ensure RecipientRepositoryImpl implements RecipientRepository
+var _ RecipientRepository = (*RecipientRepositoryImpl)(nil)
+
+// GetRecipient returns the recipient of a user.
+func (rr *RecipientRepositoryImpl) GetRecipient(userID int) (model.Recipient, error) {
+	var recipient model.Recipient
+	err := rr.db.First(&recipient, "user_id = ?", userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return recipient, ErrNotFound
+	}
+	return recipient, err
+}
+
+// SaveRecipient inserts the recipient of a user or overwrites the stored
+// one, keeping its creation time.
+func (rr *RecipientRepositoryImpl) SaveRecipient(recipient model.Recipient) (model.Recipient, error) {
+	err := rr.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "email", "locale", "webhook_url", "email_disabled", "updated_at"}),
+	}).Create(&recipient).Error
+	return recipient, err
+}
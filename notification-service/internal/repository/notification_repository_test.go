@@ -0,0 +1,127 @@
+package repository_test
+
+import (
+	"log"
+	"notification-service/internal/db"
+	"notification-service/internal/model"
+	"notification-service/internal/repository"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// setupTestDB connects to DATABASE_URL, or to a fresh in-memory SQLite
+// database when it is not set, migrates the schema and empties it.
+func setupTestDB() *gorm.DB {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		dsn = "sqlite://:memory:"
+	}
+
+	conn, err := db.Open(dsn)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	if err := conn.AutoMigrate(&model.Notification{}, &model.Recipient{}); err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+
+	// Clear the tables before each test
+	conn.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&model.Notification{})
+	conn.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&model.Recipient{})
+
+	return conn
+}
+
+func TestNotificationRepository(t *testing.T) {
+	implementations := map[string]func() repository.NotificationRepository{
+		"memory": func() repository.NotificationRepository { return repository.NewMemoryStore().Notifications() },
+		"gorm": func() repository.NotificationRepository {
+			return repository.NewNotificationRepositoryImpl(setupTestDB())
+		},
+	}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(time.Minute)
+
+	for name, newRepo := range implementations {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+
+			email, err := repo.CreateNotification(model.Notification{UserID: 2, EventType: model.EventNotifyWon, EventKey: "k1", Channel: model.ChannelEmail, Locale: "en", Subject: "Won", Body: "You won", Status: model.StatusPending})
+			require.NoError(t, err)
+			assert.NotZero(t, email.ID)
+			_, err = repo.CreateNotification(model.Notification{UserID: 2, EventType: model.EventNotifyWon, EventKey: "k1", Channel: model.ChannelEmail, Locale: "en", Subject: "Won", Body: "You won", Status: model.StatusPending})
+			assert.ErrorIs(t, err, repository.ErrDuplicateNotification)
+			inbox, err := repo.CreateNotification(model.Notification{UserID: 2, EventType: model.EventNotifyWon, EventKey: "k1", Channel: model.ChannelInbox, Locale: "en", Subject: "Won", Body: "You won", Status: model.StatusSent, SentAt: &now})
+			require.NoError(t, err)
+			_, err = repo.CreateNotification(model.Notification{UserID: 3, EventType: model.EventNotifyLost, EventKey: "k2", Channel: model.ChannelWebhook, Locale: "en", Subject: "Lost", Body: "You lost", Status: model.StatusPending, NextAttemptAt: &later})
+			require.NoError(t, err)
+
+			due, err := repo.GetDueNotifications(now, 10)
+			require.NoError(t, err)
+			if assert.Len(t, due, 1) {
+				assert.Equal(t, email.ID, due[0].ID)
+			}
+			due, err = repo.GetDueNotifications(later, 10)
+			require.NoError(t, err)
+			assert.Len(t, due, 2)
+			due, err = repo.GetDueNotifications(later, 1)
+			require.NoError(t, err)
+			assert.Len(t, due, 1)
+
+			email.Status = model.StatusSent
+			email.SentAt = &now
+			require.NoError(t, repo.UpdateNotification(email))
+			stored, err := repo.GetNotificationByID(email.ID)
+			require.NoError(t, err)
+			assert.Equal(t, model.StatusSent, stored.Status)
+			assert.True(t, now.Equal(*stored.SentAt))
+			assert.ErrorIs(t, repo.UpdateNotification(model.Notification{ID: 999}), repository.ErrNotFound)
+			_, err = repo.GetNotificationByID(999)
+			assert.ErrorIs(t, err, repository.ErrNotFound)
+
+			all, err := repo.GetNotificationsByUserID(2, "")
+			require.NoError(t, err)
+			if assert.Len(t, all, 2) {
+				assert.Equal(t, inbox.ID, all[0].ID)
+			}
+			inboxOnly, err := repo.GetNotificationsByUserID(2, model.ChannelInbox)
+			require.NoError(t, err)
+			assert.Len(t, inboxOnly, 1)
+		})
+	}
+}
+
+func TestRecipientRepository(t *testing.T) {
+	implementations := map[string]func() repository.RecipientRepository{
+		"memory": func() repository.RecipientRepository { return repository.NewMemoryStore().Recipients() },
+		"gorm": func() repository.RecipientRepository {
+			return repository.NewRecipientRepositoryImpl(setupTestDB())
+		},
+	}
+
+	for name, newRepo := range implementations {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+
+			_, err := repo.GetRecipient(2)
+			assert.ErrorIs(t, err, repository.ErrNotFound)
+
+			_, err = repo.SaveRecipient(model.Recipient{UserID: 2, Name: "Ada", Email: "ada@example.com"})
+			require.NoError(t, err)
+			_, err = repo.SaveRecipient(model.Recipient{UserID: 2, Name: "Ada", Email: "ada@example.com", Locale: "es", WebhookURL: "http://hooks.test/ada", EmailDisabled: true})
+			require.NoError(t, err)
+
+			recipient, err := repo.GetRecipient(2)
+			require.NoError(t, err)
+			assert.Equal(t, "es", recipient.Locale)
+			assert.Equal(t, "http://hooks.test/ada", recipient.WebhookURL)
+			assert.True(t, recipient.EmailDisabled)
+		})
+	}
+}
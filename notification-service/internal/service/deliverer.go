@@ -0,0 +1,143 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"notification-service/internal/channel"
+	"notification-service/internal/model"
+	"notification-service/internal/repository"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxAttempts is how many times a notification is tried before
+	// it is marked failed.
+	DefaultMaxAttempts = 5
+	// DefaultRetryBackoff is the wait before the first retry of a
+	// notification, doubled on every attempt after it.
+	DefaultRetryBackoff = 30 * time.Second
+)
+
+// Deliverer sends the pending notifications through their channels and
+// records the outcome: sent, pending again with a later attempt, or failed
+// once the attempts run out. Delivery is at least once: a notification sent
+// right before the service stops may be sent again.
+type Deliverer struct {
+	notifications repository.NotificationRepository
+	recipients    repository.RecipientRepository
+	channels      map[string]channel.Channel
+	interval      time.Duration
+	batchSize     int
+	now           func() time.Time
+	maxAttempts   int
+	backoff       time.Duration
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewDeliverer creates a deliverer that looks for due notifications every
+// interval and sends them through channels, keyed by channel name.
+// Notifications on a channel missing from channels fail right away.
+func NewDeliverer(notifications repository.NotificationRepository, recipients repository.RecipientRepository, channels map[string]channel.Channel, interval time.Duration, opts ...Option) *Deliverer {
+	o := newOptions(opts)
+	return &Deliverer{
+		notifications: notifications,
+		recipients:    recipients,
+		channels:      channels,
+		interval:      interval,
+		batchSize:     100,
+		now:           o.now,
+		maxAttempts:   o.maxAttempts,
+		backoff:       o.backoff,
+		stop:          make(chan struct{}),
+	}
+}
+
+// Start delivers due notifications in the background until Stop is called.
+func (d *Deliverer) Start() {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-d.stop:
+				return
+			case <-ticker.C:
+				if _, err := d.DeliverDue(); err != nil {
+					log.Printf("Error delivering notifications: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop ends the polling started by Start and waits for it to finish.
+func (d *Deliverer) Stop() {
+	close(d.stop)
+	d.wg.Wait()
+}
+
+// DeliverDue tries one batch of due notifications and returns how many were
+// sent. Failed sends are scheduled for a retry, not returned as errors.
+func (d *Deliverer) DeliverDue() (int, error) {
+	now := d.now()
+	due, err := d.notifications.GetDueNotifications(now, d.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, notification := range due {
+		if err := d.send(notification); err != nil {
+			d.retryLater(&notification, now, err)
+		} else {
+			sentAt := now.UTC()
+			notification.Status = model.StatusSent
+			notification.SentAt = &sentAt
+			notification.NextAttemptAt = nil
+			sent++
+		}
+		if err := d.notifications.UpdateNotification(notification); err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+// errPermanent marks failures that retrying cannot fix.
+var errPermanent = errors.New("permanent failure")
+
+func (d *Deliverer) send(notification model.Notification) error {
+	ch, ok := d.channels[notification.Channel]
+	if !ok {
+		return fmt.Errorf("%w: channel %s is not configured", errPermanent, notification.Channel)
+	}
+	recipient, err := d.recipients.GetRecipient(notification.UserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: unknown recipient", errPermanent)
+	}
+	if err != nil {
+		return err
+	}
+	return ch.Send(recipient, notification)
+}
+
+// retryLater records a failed attempt and schedules the next one, or marks
+// the notification failed when no attempts are left.
+func (d *Deliverer) retryLater(notification *model.Notification, now time.Time, err error) {
+	notification.Attempts++
+	notification.LastError = err.Error()
+	if notification.Attempts >= d.maxAttempts || errors.Is(err, errPermanent) {
+		notification.Status = model.StatusFailed
+		notification.NextAttemptAt = nil
+		log.Printf("Notification %d failed on %s: %v", notification.ID, notification.Channel, err)
+		return
+	}
+	next := now.Add(d.backoff << (notification.Attempts - 1)).UTC()
+	notification.NextAttemptAt = &next
+}
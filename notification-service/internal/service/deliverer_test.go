@@ -0,0 +1,119 @@
+package service_test
+
+import (
+	"errors"
+	"notification-service/internal/channel"
+	"notification-service/internal/model"
+	"notification-service/internal/repository"
+	"notification-service/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingChannel remembers what it sends and fails while err is set.
+type recordingChannel struct {
+	sent []model.Notification
+	err  error
+}
+
+func (c *recordingChannel) Send(recipient model.Recipient, notification model.Notification) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.sent = append(c.sent, notification)
+	return nil
+}
+
+func pendingEmail(t *testing.T, store *repository.MemoryStore) model.Notification {
+	_, err := store.Recipients().SaveRecipient(model.Recipient{UserID: 2, Email: "bob@example.com"})
+	require.NoError(t, err)
+	notification, err := store.Notifications().CreateNotification(model.Notification{
+		UserID: 2, EventType: model.EventNotifyWon, EventKey: "k1", Channel: model.ChannelEmail,
+		Locale: "en", Subject: "You won", Body: "Congratulations", Status: model.StatusPending,
+	})
+	require.NoError(t, err)
+	return notification
+}
+
+func TestDelivererSendsDueNotifications(t *testing.T) {
+	store := repository.NewMemoryStore()
+	notification := pendingEmail(t, store)
+	email := &recordingChannel{}
+	deliverer := service.NewDeliverer(store.Notifications(), store.Recipients(),
+		map[string]channel.Channel{model.ChannelEmail: email}, time.Second,
+		service.WithClock(func() time.Time { return testNow }))
+
+	sent, err := deliverer.DeliverDue()
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	require.Len(t, email.sent, 1)
+	assert.Equal(t, "You won", email.sent[0].Subject)
+
+	stored, err := store.Notifications().GetNotificationByID(notification.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusSent, stored.Status)
+	assert.Equal(t, &testNow, stored.SentAt)
+
+	sent, err = deliverer.DeliverDue()
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+}
+
+func TestDelivererRetriesWithBackoff(t *testing.T) {
+	store := repository.NewMemoryStore()
+	notification := pendingEmail(t, store)
+	email := &recordingChannel{err: errors.New("mailbox unavailable")}
+	now := testNow
+	deliverer := service.NewDeliverer(store.Notifications(), store.Recipients(),
+		map[string]channel.Channel{model.ChannelEmail: email}, time.Second,
+		service.WithClock(func() time.Time { return now }), service.WithRetryPolicy(3, time.Minute))
+
+	stored := func() model.Notification {
+		n, err := store.Notifications().GetNotificationByID(notification.ID)
+		require.NoError(t, err)
+		return n
+	}
+
+	_, err := deliverer.DeliverDue()
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusPending, stored().Status)
+	assert.Equal(t, 1, stored().Attempts)
+	assert.Equal(t, "mailbox unavailable", stored().LastError)
+	assert.Equal(t, testNow.Add(time.Minute), *stored().NextAttemptAt)
+
+	// Not due yet.
+	now = testNow.Add(30 * time.Second)
+	_, err = deliverer.DeliverDue()
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored().Attempts)
+
+	now = testNow.Add(time.Minute)
+	_, err = deliverer.DeliverDue()
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored().Attempts)
+	assert.Equal(t, now.Add(2*time.Minute), *stored().NextAttemptAt)
+
+	now = now.Add(2 * time.Minute)
+	_, err = deliverer.DeliverDue()
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusFailed, stored().Status)
+	assert.Equal(t, 3, stored().Attempts)
+	assert.Nil(t, stored().NextAttemptAt)
+}
+
+func TestDelivererFailsUnconfiguredChannels(t *testing.T) {
+	store := repository.NewMemoryStore()
+	notification := pendingEmail(t, store)
+	deliverer := service.NewDeliverer(store.Notifications(), store.Recipients(), map[string]channel.Channel{}, time.Second)
+
+	_, err := deliverer.DeliverDue()
+	require.NoError(t, err)
+
+	stored, err := store.Notifications().GetNotificationByID(notification.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusFailed, stored.Status)
+	assert.Contains(t, stored.LastError, "channel email is not configured")
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"notification-service/internal/model"
+	"notification-service/internal/repository"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when the notification does not exist or
+	// belongs to another user.
+	ErrNotFound = errors.New("notification not found")
+	// ErrInvalidInput is returned when the request fails validation.
+	ErrInvalidInput = errors.New("invalid input")
+)
+
+// NotificationService gives users their inbox, the delivery status of their
+// notifications and the settings of their channels.
+type NotificationService interface {
+	// GetInbox returns the inbox notifications of a user, newest first.
+	GetInbox(userID int) ([]model.Notification, error)
+	// MarkRead marks an inbox notification of the user as read.
+	MarkRead(userID, id int) (model.Notification, error)
+	// GetNotifications returns the notifications of a user on every
+	// channel, with their delivery status, newest first.
+	GetNotifications(userID int) ([]model.Notification, error)
+	GetSettings(userID int) (model.Recipient, error)
+	// UpdateSettings changes the locale, webhook and email switch of a
+	// user. Their name and email come from the user service.
+	UpdateSettings(settings model.Recipient) (model.Recipient, error)
+}
+
+type notificationService struct {
+	notifications repository.NotificationRepository
+	recipients    repository.RecipientRepository
+	now           func() time.Time
+}
+
+// Ensure notificationService implements NotificationService
+var _ NotificationService = (*notificationService)(nil)
+
+// NewNotificationService creates a NotificationService.
+func NewNotificationService(notifications repository.NotificationRepository, recipients repository.RecipientRepository, opts ...Option) NotificationService {
+	o := newOptions(opts)
+	return &notificationService{notifications: notifications, recipients: recipients, now: o.now}
+}
+
+func (s *notificationService) GetInbox(userID int) ([]model.Notification, error) {
+	notifications, err := s.notifications.GetNotificationsByUserID(userID, model.ChannelInbox)
+	if notifications == nil {
+		notifications = []model.Notification{}
+	}
+	return notifications, err
+}
+
+func (s *notificationService) MarkRead(userID, id int) (model.Notification, error) {
+	notification, err := s.notifications.GetNotificationByID(id)
+	if errors.Is(err, repository.ErrNotFound) || err == nil && (notification.UserID != userID || notification.Channel != model.ChannelInbox) {
+		return model.Notification{}, ErrNotFound
+	}
+	if err != nil {
+		return model.Notification{}, err
+	}
+	if notification.ReadAt == nil {
+		readAt := s.now().UTC()
+		notification.ReadAt = &readAt
+		if err := s.notifications.UpdateNotification(notification); err != nil {
+			return model.Notification{}, err
+		}
+	}
+	return notification, nil
+}
+
+func (s *notificationService) GetNotifications(userID int) ([]model.Notification, error) {
+	notifications, err := s.notifications.GetNotificationsByUserID(userID, "")
+	if notifications == nil {
+		notifications = []model.Notification{}
+	}
+	return notifications, err
+}
+
+func (s *notificationService) GetSettings(userID int) (model.Recipient, error) {
+	recipient, err := s.recipients.GetRecipient(userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.Recipient{UserID: userID, Locale: model.DefaultLocale}, nil
+	}
+	if recipient.Locale == "" {
+		recipient.Locale = model.DefaultLocale
+	}
+	return recipient, err
+}
+
+func (s *notificationService) UpdateSettings(settings model.Recipient) (model.Recipient, error) {
+	settings.Locale = strings.TrimSpace(settings.Locale)
+	if len(settings.Locale) > 16 {
+		return model.Recipient{}, fmt.Errorf("%w: locale is too long", ErrInvalidInput)
+	}
+	settings.WebhookURL = strings.TrimSpace(settings.WebhookURL)
+	if settings.WebhookURL != "" {
+		parsed, err := url.Parse(settings.WebhookURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return model.Recipient{}, fmt.Errorf("%w: webhook must be an http or https URL", ErrInvalidInput)
+		}
+	}
+
+	recipient, err := s.recipients.GetRecipient(settings.UserID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return model.Recipient{}, err
+	}
+	recipient.UserID = settings.UserID
+	recipient.Locale = settings.Locale
+	recipient.WebhookURL = settings.WebhookURL
+	recipient.EmailDisabled = settings.EmailDisabled
+	return s.recipients.SaveRecipient(recipient)
+}
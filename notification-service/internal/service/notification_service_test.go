@@ -0,0 +1,70 @@
+package service_test
+
+import (
+	"notification-service/internal/model"
+	"notification-service/internal/repository"
+	"notification-service/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInboxAndMarkRead(t *testing.T) {
+	store := repository.NewMemoryStore()
+	notifier := newNotifier(store, stubPreferences{})
+	require.NoError(t, notifier.UserCreated("k1", model.User{ID: 7, Name: "Ada", Email: "ada@example.com"}))
+	notificationService := service.NewNotificationService(store.Notifications(), store.Recipients(),
+		service.WithClock(func() time.Time { return testNow }))
+
+	inbox, err := notificationService.GetInbox(7)
+	require.NoError(t, err)
+	require.Len(t, inbox, 1)
+	assert.Nil(t, inbox[0].ReadAt)
+
+	all, err := notificationService.GetNotifications(7)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	read, err := notificationService.MarkRead(7, inbox[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, &testNow, read.ReadAt)
+
+	_, err = notificationService.MarkRead(8, inbox[0].ID)
+	assert.ErrorIs(t, err, service.ErrNotFound)
+	email := channels(all)[model.ChannelEmail]
+	_, err = notificationService.MarkRead(7, email.ID)
+	assert.ErrorIs(t, err, service.ErrNotFound)
+	_, err = notificationService.MarkRead(7, 999)
+	assert.ErrorIs(t, err, service.ErrNotFound)
+
+	empty, err := notificationService.GetInbox(8)
+	require.NoError(t, err)
+	assert.NotNil(t, empty)
+}
+
+func TestSettings(t *testing.T) {
+	store := repository.NewMemoryStore()
+	_, err := store.Recipients().SaveRecipient(model.Recipient{UserID: 7, Name: "Ada", Email: "ada@example.com"})
+	require.NoError(t, err)
+	notificationService := service.NewNotificationService(store.Notifications(), store.Recipients())
+
+	settings, err := notificationService.GetSettings(7)
+	require.NoError(t, err)
+	assert.Equal(t, model.DefaultLocale, settings.Locale)
+
+	settings, err = notificationService.UpdateSettings(model.Recipient{UserID: 7, Name: "Mallory", Locale: "es", WebhookURL: "https://hooks.example/7", EmailDisabled: true})
+	require.NoError(t, err)
+	assert.Equal(t, "Ada", settings.Name, "the name comes from the user service")
+	assert.Equal(t, "es", settings.Locale)
+	assert.Equal(t, []string{model.ChannelInbox, model.ChannelWebhook}, settings.Channels())
+
+	_, err = notificationService.UpdateSettings(model.Recipient{UserID: 7, WebhookURL: "ftp://hooks.example"})
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+
+	// Users the service never heard of can save settings too.
+	settings, err = notificationService.UpdateSettings(model.Recipient{UserID: 8, Locale: "es"})
+	require.NoError(t, err)
+	assert.Equal(t, 8, settings.UserID)
+}
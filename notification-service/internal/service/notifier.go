@@ -0,0 +1,112 @@
+package service
+
+import (
+	"errors"
+	"notification-service/internal/model"
+	"notification-service/internal/repository"
+	"notification-service/internal/templates"
+	"time"
+)
+
+// Notifier turns events into notifications for the user they concern, one
+// per channel of the user. Inbox notifications are delivered right away; the
+// others are left pending for the Deliverer. Events without a template, and
+// notification intents the user turned off, are skipped.
+type Notifier struct {
+	notifications repository.NotificationRepository
+	recipients    repository.RecipientRepository
+	preferences   PreferenceSource
+	renderer      *templates.Renderer
+	now           func() time.Time
+}
+
+// NewNotifier creates a Notifier that renders notifications with renderer
+// and checks them against the preferences of each user.
+func NewNotifier(notifications repository.NotificationRepository, recipients repository.RecipientRepository, preferences PreferenceSource, renderer *templates.Renderer, opts ...Option) *Notifier {
+	o := newOptions(opts)
+	return &Notifier{
+		notifications: notifications,
+		recipients:    recipients,
+		preferences:   preferences,
+		renderer:      renderer,
+		now:           o.now,
+	}
+}
+
+// UserCreated records how to reach a new user, keeping the settings they
+// may already have, and welcomes them. key identifies the event.
+func (n *Notifier) UserCreated(key string, user model.User) error {
+	recipient, err := n.recipients.GetRecipient(user.ID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	recipient.UserID = user.ID
+	recipient.Name = user.Name
+	recipient.Email = user.Email
+	if recipient, err = n.recipients.SaveRecipient(recipient); err != nil {
+		return err
+	}
+	return n.create(key, recipient, model.Event{Type: model.EventUserCreated, UserID: user.ID})
+}
+
+// Notify creates the notifications of an auction event for the user it is
+// addressed to. key identifies the event, so it is only notified once.
+func (n *Notifier) Notify(key string, event model.Event) error {
+	if event.UserID <= 0 || !n.renderer.Has(event.Type) {
+		return nil
+	}
+
+	prefs, err := n.preferences.Preferences(event.UserID)
+	if errors.Is(err, ErrUnknownUser) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !prefs.Wants(event.Type) {
+		return nil
+	}
+
+	// Users created before this service ran have no recipient yet; they
+	// still get their notifications in the inbox.
+	recipient, err := n.recipients.GetRecipient(event.UserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		recipient, err = model.Recipient{UserID: event.UserID}, nil
+	}
+	if err != nil {
+		return err
+	}
+	return n.create(key, recipient, event)
+}
+
+// create renders event for recipient and stores a notification on each of
+// their channels. Channels that already have one for key are skipped.
+func (n *Notifier) create(key string, recipient model.Recipient, event model.Event) error {
+	message, err := n.renderer.Render(event.Type, recipient.Locale, templates.Data{Recipient: recipient, Event: event})
+	if err != nil {
+		return err
+	}
+
+	now := n.now().UTC()
+	for _, channel := range recipient.Channels() {
+		notification := model.Notification{
+			UserID:    recipient.UserID,
+			EventType: event.Type,
+			EventKey:  key,
+			Channel:   channel,
+			Locale:    message.Locale,
+			Subject:   message.Subject,
+			Body:      message.Body,
+			Status:    model.StatusPending,
+		}
+		if channel == model.ChannelInbox {
+			notification.Status = model.StatusSent
+			notification.SentAt = &now
+		}
+		_, err := n.notifications.CreateNotification(notification)
+		if err != nil && !errors.Is(err, repository.ErrDuplicateNotification) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package service_test
+
+import (
+	"errors"
+	"notification-service/internal/model"
+	"notification-service/internal/repository"
+	"notification-service/internal/service"
+	"notification-service/internal/templates"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubPreferences is a PreferenceSource with fixed answers. Users missing
+// from prefs want everything.
+type stubPreferences struct {
+	prefs map[int]model.Preferences
+	err   error
+}
+
+func (s stubPreferences) Preferences(userID int) (model.Preferences, error) {
+	if s.err != nil {
+		return model.Preferences{}, s.err
+	}
+	if prefs, ok := s.prefs[userID]; ok {
+		return prefs, nil
+	}
+	return model.Preferences{Outbid: true, EndingSoon: true, Won: true, Lost: true}, nil
+}
+
+var testNow = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func newNotifier(store *repository.MemoryStore, prefs service.PreferenceSource) *service.Notifier {
+	return service.NewNotifier(store.Notifications(), store.Recipients(), prefs, templates.Builtin(),
+		service.WithClock(func() time.Time { return testNow }))
+}
+
+func channels(notifications []model.Notification) map[string]model.Notification {
+	byChannel := map[string]model.Notification{}
+	for _, n := range notifications {
+		byChannel[n.Channel] = n
+	}
+	return byChannel
+}
+
+func TestNotifierWelcomesNewUsers(t *testing.T) {
+	store := repository.NewMemoryStore()
+	notifier := newNotifier(store, stubPreferences{})
+
+	require.NoError(t, notifier.UserCreated("k1", model.User{ID: 7, Name: "Ada", Email: "ada@example.com"}))
+
+	recipient, err := store.Recipients().GetRecipient(7)
+	require.NoError(t, err)
+	assert.Equal(t, "Ada", recipient.Name)
+	assert.Equal(t, "ada@example.com", recipient.Email)
+
+	notifications, err := store.Notifications().GetNotificationsByUserID(7, "")
+	require.NoError(t, err)
+	byChannel := channels(notifications)
+	require.Len(t, byChannel, 2)
+
+	inbox := byChannel[model.ChannelInbox]
+	assert.Equal(t, "Welcome, Ada", inbox.Subject)
+	assert.Equal(t, model.StatusSent, inbox.Status)
+	assert.Equal(t, &testNow, inbox.SentAt)
+
+	email := byChannel[model.ChannelEmail]
+	assert.Equal(t, model.StatusPending, email.Status)
+	assert.Equal(t, model.EventUserCreated, email.EventType)
+	assert.Nil(t, email.SentAt)
+}
+
+func TestNotifierKeepsSettingsOfKnownUsers(t *testing.T) {
+	store := repository.NewMemoryStore()
+	_, err := store.Recipients().SaveRecipient(model.Recipient{UserID: 7, Locale: "es", WebhookURL: "http://hooks.example/7", EmailDisabled: true})
+	require.NoError(t, err)
+	notifier := newNotifier(store, stubPreferences{})
+
+	require.NoError(t, notifier.UserCreated("k1", model.User{ID: 7, Name: "Ada", Email: "ada@example.com"}))
+
+	recipient, err := store.Recipients().GetRecipient(7)
+	require.NoError(t, err)
+	assert.Equal(t, "es", recipient.Locale)
+	assert.Equal(t, "http://hooks.example/7", recipient.WebhookURL)
+	assert.True(t, recipient.EmailDisabled)
+
+	notifications, err := store.Notifications().GetNotificationsByUserID(7, "")
+	require.NoError(t, err)
+	byChannel := channels(notifications)
+	assert.Len(t, byChannel, 2)
+	assert.Contains(t, byChannel, model.ChannelWebhook)
+	assert.Equal(t, "es", byChannel[model.ChannelInbox].Locale)
+}
+
+func TestNotifierNotifiesAuctionEvents(t *testing.T) {
+	store := repository.NewMemoryStore()
+	_, err := store.Recipients().SaveRecipient(model.Recipient{UserID: 2, Name: "Bob", Email: "bob@example.com"})
+	require.NoError(t, err)
+	notifier := newNotifier(store, stubPreferences{})
+
+	event := model.Event{Type: model.EventNotifyOutbid, AuctionID: 5, UserID: 2, Amount: &model.Money{Amount: "105.00", Currency: "USD"}}
+	require.NoError(t, notifier.Notify("k1", event))
+	// The same event delivered again is not notified twice.
+	require.NoError(t, notifier.Notify("k1", event))
+
+	notifications, err := store.Notifications().GetNotificationsByUserID(2, "")
+	require.NoError(t, err)
+	require.Len(t, notifications, 2)
+	inbox := channels(notifications)[model.ChannelInbox]
+	assert.Equal(t, "You have been outbid on auction #5", inbox.Subject)
+	assert.Contains(t, inbox.Body, "at 105.00 USD")
+}
+
+func TestNotifierSkipsUnwantedEvents(t *testing.T) {
+	store := repository.NewMemoryStore()
+	notifier := newNotifier(store, stubPreferences{prefs: map[int]model.Preferences{2: {Won: true}}})
+
+	// Not wanted, not addressed to anyone, and without a template.
+	require.NoError(t, notifier.Notify("k1", model.Event{Type: model.EventNotifyOutbid, AuctionID: 5, UserID: 2}))
+	require.NoError(t, notifier.Notify("k2", model.Event{Type: model.EventNotifyWon, AuctionID: 5}))
+	require.NoError(t, notifier.Notify("k3", model.Event{Type: "auction.created", AuctionID: 5, UserID: 2}))
+
+	notifications, err := store.Notifications().GetNotificationsByUserID(2, "")
+	require.NoError(t, err)
+	assert.Empty(t, notifications)
+
+	// Users the notifier never heard of still get an inbox notification.
+	require.NoError(t, notifier.Notify("k4", model.Event{Type: model.EventNotifyWon, AuctionID: 5, UserID: 2}))
+	notifications, err = store.Notifications().GetNotificationsByUserID(2, "")
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, model.ChannelInbox, notifications[0].Channel)
+}
+
+func TestNotifierPreferenceErrors(t *testing.T) {
+	store := repository.NewMemoryStore()
+	event := model.Event{Type: model.EventNotifyLost, AuctionID: 5, UserID: 2}
+
+	assert.NoError(t, newNotifier(store, stubPreferences{err: service.ErrUnknownUser}).Notify("k1", event))
+	unavailable := errors.New("connection refused")
+	assert.ErrorIs(t, newNotifier(store, stubPreferences{err: unavailable}).Notify("k1", event), unavailable)
+
+	notifications, err := store.Notifications().GetNotificationsByUserID(2, "")
+	require.NoError(t, err)
+	assert.Empty(t, notifications)
+}
@@ -0,0 +1,40 @@
+package service
+
+import "time"
+
+// Option customizes the services and workers of this package.
+type Option func(*options)
+
+type options struct {
+	now         func() time.Time
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// WithClock makes the service read the current time from now instead of
+// time.Now. Tests use it to control retries.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) { o.now = now }
+}
+
+// WithRetryPolicy sets how many times a notification is tried before it is
+// marked failed, and the wait before the first retry, doubled on every
+// attempt after it.
+func WithRetryPolicy(maxAttempts int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.maxAttempts = maxAttempts
+		o.backoff = backoff
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		now:         time.Now,
+		maxAttempts: DefaultMaxAttempts,
+		backoff:     DefaultRetryBackoff,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"notification-service/internal/model"
+	"strings"
+	"time"
+)
+
+// ErrUnknownUser is returned by a PreferenceSource when the user does not
+// exist, or no longer does. Nothing is sent to unknown users.
+var ErrUnknownUser = errors.New("unknown user")
+
+// PreferenceSource tells which notifications a user wants.
+type PreferenceSource interface {
+	Preferences(userID int) (model.Preferences, error)
+}
+
+// UserServiceClient reads the notification preferences users keep in the
+// user service.
+type UserServiceClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewUserServiceClient creates a client for the user service at baseURL whose
+// requests give up after timeout.
+func NewUserServiceClient(baseURL string, timeout time.Duration) *UserServiceClient {
+	return &UserServiceClient{baseURL: strings.TrimSuffix(baseURL, "/"), client: &http.Client{Timeout: timeout}}
+}
+
+// Ensure UserServiceClient implements PreferenceSource
+var _ PreferenceSource = (*UserServiceClient)(nil)
+
+func (c *UserServiceClient) Preferences(userID int) (model.Preferences, error) {
+	resp, err := c.client.Get(fmt.Sprintf("%s/users/notification-preferences/%d", c.baseURL, userID))
+	if err != nil {
+		return model.Preferences{}, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return model.Preferences{}, ErrUnknownUser
+	case resp.StatusCode != http.StatusOK:
+		return model.Preferences{}, fmt.Errorf("user service answered %s", resp.Status)
+	}
+	var prefs model.Preferences
+	if err := json.NewDecoder(resp.Body).Decode(&prefs); err != nil {
+		return model.Preferences{}, err
+	}
+	return prefs, nil
+}
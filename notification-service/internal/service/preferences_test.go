@@ -0,0 +1,38 @@
+package service_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"notification-service/internal/model"
+	"notification-service/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserServiceClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/notification-preferences/7":
+			fmt.Fprint(w, `{"UserID":7,"Outbid":false,"EndingSoon":true,"Won":true,"Lost":false}`)
+		case "/users/notification-preferences/8":
+			http.Error(w, "boom", http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	client := service.NewUserServiceClient(server.URL+"/", time.Second)
+
+	prefs, err := client.Preferences(7)
+	require.NoError(t, err)
+	assert.Equal(t, model.Preferences{EndingSoon: true, Won: true}, prefs)
+
+	_, err = client.Preferences(8)
+	assert.Error(t, err)
+	_, err = client.Preferences(9)
+	assert.ErrorIs(t, err, service.ErrUnknownUser)
+}
@@ -0,0 +1,115 @@
+// Package templates renders the subject and body of notifications from one
+// template per event type and locale.
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"notification-service/internal/model"
+	"path"
+	"strings"
+	"text/template"
+)
+
+// builtin holds the templates shipped with the service, as
+// <locale>/<event type>.tmpl files defining a "subject" and a "body"
+// template.
+//
+//go:embed */*.tmpl
+var builtin embed.FS
+
+// Data is what templates render: the event and the user it is for.
+type Data struct {
+	Recipient model.Recipient
+	Event     model.Event
+}
+
+// Message is a rendered notification.
+type Message struct {
+	Locale  string
+	Subject string
+	Body    string
+}
+
+// Renderer picks and executes the template of an event type in the locale
+// of a user.
+type Renderer struct {
+	// templates maps a locale to the templates of its event types.
+	templates map[string]map[string]*template.Template
+}
+
+// Builtin returns a Renderer for the templates shipped with the service.
+func Builtin() *Renderer {
+	renderer, err := New(builtin)
+	if err != nil {
+		panic(err)
+	}
+	return renderer
+}
+
+// New parses the <locale>/<event type>.tmpl files of fsys.
+func New(fsys fs.FS) (*Renderer, error) {
+	files, err := fs.Glob(fsys, "*/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Renderer{templates: map[string]map[string]*template.Template{}}
+	for _, file := range files {
+		locale := path.Dir(file)
+		eventType := strings.TrimSuffix(path.Base(file), ".tmpl")
+		tmpl, err := template.New(eventType).Option("missingkey=error").ParseFS(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		if tmpl.Lookup("subject") == nil || tmpl.Lookup("body") == nil {
+			return nil, fmt.Errorf("template %s must define a subject and a body", file)
+		}
+		if r.templates[locale] == nil {
+			r.templates[locale] = map[string]*template.Template{}
+		}
+		r.templates[locale][eventType] = tmpl
+	}
+	return r, nil
+}
+
+// Has reports whether eventType has a template in DefaultLocale, the one
+// every locale falls back to. Events without one are not notified.
+func (r *Renderer) Has(eventType string) bool {
+	return r.lookup(eventType, model.DefaultLocale) != nil
+}
+
+// Render executes the template of eventType in locale. It falls back to the
+// language of a regional locale (es for es-AR) and then to DefaultLocale.
+func (r *Renderer) Render(eventType, locale string, data Data) (Message, error) {
+	found := r.lookup(eventType, locale)
+	if found == nil {
+		return Message{}, fmt.Errorf("no template for %s", eventType)
+	}
+
+	var subject, body bytes.Buffer
+	if err := found.tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, err
+	}
+	if err := found.tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return Message{}, err
+	}
+	return Message{Locale: found.locale, Subject: strings.TrimSpace(subject.String()), Body: strings.TrimSpace(body.String())}, nil
+}
+
+type localized struct {
+	locale string
+	tmpl   *template.Template
+}
+
+func (r *Renderer) lookup(eventType, locale string) *localized {
+	language, _, _ := strings.Cut(locale, "-")
+	for _, candidate := range []string{locale, strings.ToLower(language), model.DefaultLocale} {
+		if tmpl, ok := r.templates[candidate][eventType]; ok {
+			return &localized{locale: candidate, tmpl: tmpl}
+		}
+	}
+	return nil
+}
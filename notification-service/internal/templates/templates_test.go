@@ -0,0 +1,63 @@
+package templates_test
+
+import (
+	"notification-service/internal/model"
+	"notification-service/internal/templates"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinTemplates(t *testing.T) {
+	renderer := templates.Builtin()
+	endsAt := time.Date(2024, 5, 1, 18, 30, 0, 0, time.UTC)
+	data := templates.Data{
+		Recipient: model.Recipient{UserID: 2, Name: "Ada"},
+		Event:     model.Event{Type: model.EventNotifyEndingSoon, AuctionID: 7, UserID: 2, Amount: &model.Money{Amount: "12.50", Currency: "EUR"}, EndsAt: &endsAt},
+	}
+
+	for _, eventType := range []string{model.EventUserCreated, model.EventNotifyOutbid, model.EventNotifyEndingSoon, model.EventNotifyWon, model.EventNotifyLost} {
+		for _, locale := range []string{"en", "es"} {
+			message, err := renderer.Render(eventType, locale, data)
+			require.NoError(t, err, "%s in %s", eventType, locale)
+			assert.Equal(t, locale, message.Locale)
+			assert.NotEmpty(t, message.Subject)
+			assert.Contains(t, message.Body, "Ada")
+		}
+	}
+
+	message, err := renderer.Render(model.EventNotifyEndingSoon, "en", data)
+	require.NoError(t, err)
+	assert.Equal(t, "Auction #7 is ending soon", message.Subject)
+	assert.Contains(t, message.Body, "2024-05-01 18:30 UTC")
+	assert.Contains(t, message.Body, "12.50 EUR")
+}
+
+func TestRenderFallsBackToLanguageAndDefault(t *testing.T) {
+	renderer := templates.Builtin()
+	data := templates.Data{Recipient: model.Recipient{Name: "Ada"}, Event: model.Event{AuctionID: 7}}
+
+	message, err := renderer.Render(model.EventNotifyWon, "es-AR", data)
+	require.NoError(t, err)
+	assert.Equal(t, "es", message.Locale)
+	assert.Equal(t, "Ganaste la subasta #7", message.Subject)
+
+	message, err = renderer.Render(model.EventNotifyWon, "fr", data)
+	require.NoError(t, err)
+	assert.Equal(t, "en", message.Locale)
+
+	assert.True(t, renderer.Has(model.EventNotifyWon))
+	assert.False(t, renderer.Has("auction.created"))
+	_, err = renderer.Render("auction.created", "en", data)
+	assert.Error(t, err)
+}
+
+func TestNewRequiresSubjectAndBody(t *testing.T) {
+	_, err := templates.New(fstest.MapFS{
+		"en/notify.won.tmpl": {Data: []byte(`{{define "subject"}}Won{{end}}`)},
+	})
+	assert.Error(t, err)
+}
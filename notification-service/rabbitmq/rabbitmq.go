@@ -0,0 +1,106 @@
+package rabbitmq
+
+import (
+	"log"
+	"notification-service/internal/messaging"
+	"sync"
+
+	"github.com/streadway/amqp"
+)
+
+// Bus is the AMQP driver of messaging.Bus. Every topic maps to a queue of
+// the same name on the default exchange.
+type Bus struct {
+	conn *amqp.Connection
+
+	// mu guards the publishing channel and the queues declared on it.
+	mu       sync.Mutex
+	channel  *amqp.Channel
+	declared map[string]bool
+}
+
+// NewBus connects to the broker at url.
+func NewBus(url string) (*Bus, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &Bus{
+		conn:     conn,
+		channel:  ch,
+		declared: map[string]bool{},
+	}, nil
+}
+
+// Ensure Bus implements messaging.Bus
+var _ messaging.Bus = (*Bus)(nil)
+
+// Subscribe consumes the queue of topic on a channel of its own. Only one
+// message is in flight at a time so handlers see them in order.
+func (b *Bus) Subscribe(topic string, handler messaging.Handler) error {
+	ch, err := b.conn.Channel()
+	if err != nil {
+		return err
+	}
+
+	if _, err := declareQueue(ch, topic); err != nil {
+		ch.Close()
+		return err
+	}
+	if err := ch.Qos(1, 0, false); err != nil {
+		ch.Close()
+		return err
+	}
+
+	msgs, err := ch.Consume(
+		topic,
+		"",
+		false,
+		false,
+		false,
+		false,
+		nil,
+	)
+	if err != nil {
+		ch.Close()
+		return err
+	}
+
+	go func() {
+		for d := range msgs {
+			err := handler(messaging.Delivery{Topic: topic, Body: d.Body, Redelivered: d.Redelivered})
+			if err == nil {
+				d.Ack(false)
+				continue
+			}
+			if d.Redelivered {
+				log.Printf("Dropping message on %s after redelivery: %v", topic, err)
+			}
+			d.Nack(false, !d.Redelivered)
+		}
+	}()
+	return nil
+}
+
+// Close closes the connection and with it every channel and consumer.
+func (b *Bus) Close() error {
+	return b.conn.Close()
+}
+
+func declareQueue(ch *amqp.Channel, name string) (amqp.Queue, error) {
+	return ch.QueueDeclare(
+		name,
+		false,
+		false,
+		false,
+		false,
+		nil,
+	)
+}
@@ -3,48 +3,68 @@ package main
 import (
 	"log"
 	"net/http"
+	"time"
 	"user-service/internal/config"
+	"user-service/internal/db"
 	"user-service/internal/handler"
+	"user-service/internal/messaging"
 	"user-service/internal/model"
 	"user-service/internal/repository"
+	"user-service/internal/service"
 	"user-service/rabbitmq"
 
 	_ "github.com/lib/pq"
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
 )
 
 func main() {
-	var publisher *rabbitmq.Publisher
-
 	cfg := config.LoadConfig() // Get DatabaseURL from config
 
 	// Database connection details (use value from config)
 	dsn := cfg.DatabaseURL // Use DatabaseURL returned by LoadConfig
 
-	// Connect to the database
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{}) // Use postgres.Open with dsn
+	// Connect to the database, Postgres or SQLite depending on the URL scheme
+	conn, err := db.Open(dsn)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
-	err = db.AutoMigrate(&model.User{}, &model.User{})
+	err = conn.AutoMigrate(&model.User{}, &model.NotificationPreferences{}, &model.OutboxMessage{})
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
 	log.Println("Migration successful")
 
-	handler.InitUserRepository(db)
+	bus, err := openBus(cfg.RabbitMQURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to the message bus: %v", err)
+	}
+
+	txManager := repository.NewTxManager(conn)
+	relay := service.NewOutboxRelay(txManager, bus, cfg.QUEUE_USER_CREATED, time.Second)
+	relay.AlsoPublishTo(cfg.QUEUE_USER_NOTIFICATIONS)
+	relay.Start()
 
-	userHandler := handler.NewUserHandler(&repository.UserRepositoryImpl{}, publisher)
+	userService := service.NewUserServiceImpl(repository.NewUserRepositoryImpl(conn), txManager)
+	userHandler := handler.NewUserHandler(userService)
 	// Register HTTP endpoints with handler methods
 	http.HandleFunc("/users", userHandler.GetAllUsers)
 	http.HandleFunc("/users/{id}", userHandler.GetUserByID)
 	http.HandleFunc("/users/create", userHandler.CreateUser)
 	http.HandleFunc("/users/update/{id}", userHandler.UpdateUser)
 	http.HandleFunc("/users/delete/{id}", userHandler.DeleteUser)
+	http.HandleFunc("/users/notification-preferences/{id}", userHandler.GetNotificationPreferences)
+	http.HandleFunc("/users/notification-preferences/update/{id}", userHandler.UpdateNotificationPreferences)
 
 	log.Printf("User Service running on port %s", cfg.ServerPort)
 	log.Fatal(http.ListenAndServe(":"+cfg.ServerPort, nil))
 }
+
+// openBus connects to RabbitMQ, or starts an in-process bus when url is
+// memory:// so the service can run without a broker.
+func openBus(url string) (messaging.Bus, error) {
+	if url == "memory://" {
+		return messaging.NewMemoryBus(), nil
+	}
+	return rabbitmq.NewBus(url)
+}
@@ -2,39 +2,26 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"regexp"
 	"strconv"
-	"user-service/internal/config"
 	"user-service/internal/model"
-	"user-service/internal/repository"
-	"user-service/rabbitmq"
-
-	"gorm.io/gorm"
+	"user-service/internal/service"
 )
 
 type UserHandler struct {
-	repo      repository.UserRepository
-	publisher *rabbitmq.Publisher
-}
-
-func NewUserHandler(repo repository.UserRepository, publisher *rabbitmq.Publisher) *UserHandler {
-	return &UserHandler{repo: repo, publisher: publisher}
-}
-
-func InitUserRepository(db *gorm.DB) repository.UserRepository {
-	return repository.NewUserRepositoryImpl(db)
+	service service.UserService
 }
 
-type Config struct {
-	RabbitMQURL string `json:"rabbitmq_url"`
-	QueueName   string `json:"queue_name"`
+func NewUserHandler(userService service.UserService) *UserHandler {
+	return &UserHandler{service: userService}
 }
 
 // GetAllUsers handles the request to get all users.
 func (uh *UserHandler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
-	users, err := uh.repo.GetAllUsers()
+	users, err := uh.service.GetAllUsers()
 	if err != nil {
 		log.Printf("Error fetching users: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -63,7 +50,7 @@ func (uh *UserHandler) GetUserByID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Obtener el usuario por su ID desde la base de datos
-	user, err := uh.repo.GetUserByID(int(userID))
+	user, err := uh.service.GetUserByID(int(userID))
 	if err != nil {
 		log.Printf("Error fetching user by ID: %v", err)
 		http.Error(w, "User not found", http.StatusNotFound)
@@ -83,29 +70,15 @@ func (uh *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	createdUser, err := uh.repo.CreateUser(newUser)
+	createdUser, err := uh.service.CreateUser(newUser)
 	if err != nil {
-		log.Printf("Error creating user: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		writeServiceError(w, "creating user", err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(createdUser)
-
-	publisher, err := rabbitmq.NewPublisher(config.LoadConfig().RabbitMQURL, config.LoadConfig().QUEUE_USER_CREATED)
-	if err != nil {
-		log.Printf("Error creating RabbitMQ publisher: %v", err)
-		return
-	}
-
-	message := []byte("New user created: " + newUser.Name)
-	err = publisher.Publish(message)
-	if err != nil {
-		log.Printf("Error publishing message to RabbitMQ: %v", err)
-		return
-	}
 }
 
 // UpdateUser handles the request to update an existing user.
@@ -131,14 +104,14 @@ func (uh *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 
 	updatedUser.ID = int(userID)
 
-	if err := uh.repo.UpdateUser(updatedUser); err != nil {
-		log.Printf("Error updating user: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	user, err := uh.service.UpdateUser(updatedUser)
+	if err != nil {
+		writeServiceError(w, "updating user", err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(updatedUser)
+	json.NewEncoder(w).Encode(user)
 }
 
 // DeleteUser handles the request to delete an existing user.
@@ -156,11 +129,85 @@ func (uh *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := uh.repo.DeleteUser(userID); err != nil {
-		log.Printf("Error deleting user: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	if err := uh.service.DeleteUser(userID); err != nil {
+		writeServiceError(w, "deleting user", err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// GetNotificationPreferences handles the request to get the notification
+// preferences of a user.
+func (uh *UserHandler) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	re := regexp.MustCompile(`/users/notification-preferences/(\d+)$`)
+	matches := re.FindStringSubmatch(r.URL.Path)
+	if len(matches) != 2 {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := strconv.Atoi(matches[1])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	prefs, err := uh.service.GetNotificationPreferences(userID)
+	if err != nil {
+		writeServiceError(w, "fetching notification preferences", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// UpdateNotificationPreferences handles the request to replace the
+// notification preferences of a user.
+func (uh *UserHandler) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	re := regexp.MustCompile(`/users/notification-preferences/update/(\d+)$`)
+	matches := re.FindStringSubmatch(r.URL.Path)
+	if len(matches) != 2 {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := strconv.Atoi(matches[1])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var prefs model.NotificationPreferences
+	if err := json.NewDecoder(r.Body).Decode(&prefs); err != nil {
+		http.Error(w, "Invalid JSON request body", http.StatusBadRequest)
+		return
+	}
+
+	prefs.UserID = userID
+
+	saved, err := uh.service.UpdateNotificationPreferences(prefs)
+	if err != nil {
+		writeServiceError(w, "updating notification preferences", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saved)
+}
+
+// writeServiceError maps service errors to HTTP status codes.
+func writeServiceError(w http.ResponseWriter, action string, err error) {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		http.Error(w, "User not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidInput):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrEmailTaken):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("Error %s: %v", action, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
@@ -7,51 +7,64 @@ import (
 	"net/http/httptest"
 	"testing"
 	"user-service/internal/handler"
+	"user-service/internal/messaging"
 	"user-service/internal/model"
+	"user-service/internal/repository"
+	"user-service/internal/service"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
-type MockUserRepository struct {
+type MockUserService struct {
 	mock.Mock
 }
 
-func (m *MockUserRepository) GetAllUsers() ([]model.User, error) {
+func (m *MockUserService) GetAllUsers() ([]model.User, error) {
 	args := m.Called()
 	return args.Get(0).([]model.User), args.Error(1)
 }
 
-func (m *MockUserRepository) GetUserByID(id int) (model.User, error) {
+func (m *MockUserService) GetUserByID(id int) (model.User, error) {
 	args := m.Called(id)
 	return args.Get(0).(model.User), args.Error(1)
 }
 
-func (m *MockUserRepository) CreateUser(user model.User) (model.User, error) {
+func (m *MockUserService) CreateUser(user model.User) (model.User, error) {
 	args := m.Called(user)
 	return args.Get(0).(model.User), args.Error(1)
 }
 
-func (m *MockUserRepository) UpdateUser(user model.User) error {
+func (m *MockUserService) UpdateUser(user model.User) (model.User, error) {
 	args := m.Called(user)
-	return args.Error(0)
+	return args.Get(0).(model.User), args.Error(1)
 }
 
-func (m *MockUserRepository) DeleteUser(id int) error {
+func (m *MockUserService) DeleteUser(id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
+func (m *MockUserService) GetNotificationPreferences(userID int) (model.NotificationPreferences, error) {
+	args := m.Called(userID)
+	return args.Get(0).(model.NotificationPreferences), args.Error(1)
+}
+
+func (m *MockUserService) UpdateNotificationPreferences(prefs model.NotificationPreferences) (model.NotificationPreferences, error) {
+	args := m.Called(prefs)
+	return args.Get(0).(model.NotificationPreferences), args.Error(1)
+}
+
 func TestGetAllUsers(t *testing.T) {
-	mockRepo := new(MockUserRepository)
-	userHandler := handler.NewUserHandler(mockRepo, nil)
+	mockService := new(MockUserService)
+	userHandler := handler.NewUserHandler(mockService)
 
 	users := []model.User{
 		{ID: 1, Name: "User 1", Email: "user1@example.com"},
 		{ID: 2, Name: "User 2", Email: "user2@example.com"},
 	}
 
-	mockRepo.On("GetAllUsers").Return(users, nil)
+	mockService.On("GetAllUsers").Return(users, nil)
 
 	req, err := http.NewRequest("GET", "/users", nil)
 	if err != nil {
@@ -70,16 +83,16 @@ func TestGetAllUsers(t *testing.T) {
 	}
 
 	assert.Equal(t, users, returnedUsers)
-	mockRepo.AssertExpectations(t)
+	mockService.AssertExpectations(t)
 }
 
 func TestGetUserByID(t *testing.T) {
-	mockRepo := new(MockUserRepository)
-	userHandler := handler.NewUserHandler(mockRepo, nil)
+	mockService := new(MockUserService)
+	userHandler := handler.NewUserHandler(mockService)
 
 	user := model.User{Name: "User 1", Email: "user1@example.com"}
 
-	mockRepo.On("GetUserByID", 1).Return(user, nil)
+	mockService.On("GetUserByID", 1).Return(user, nil)
 
 	req, err := http.NewRequest("GET", "/users/1", nil)
 	if err != nil {
@@ -99,17 +112,17 @@ func TestGetUserByID(t *testing.T) {
 	}
 
 	assert.Equal(t, user, returnedUser)
-	mockRepo.AssertExpectations(t)
+	mockService.AssertExpectations(t)
 }
 
 func TestCreateUser(t *testing.T) {
-	mockRepo := new(MockUserRepository)
-	userHandler := handler.NewUserHandler(mockRepo, nil)
+	mockService := new(MockUserService)
+	userHandler := handler.NewUserHandler(mockService)
 
 	newUser := model.User{Name: "User 1", Email: "user1@example.com"}
 	createdUser := model.User{ID: 1, Name: "User 1", Email: "user1@example.com"}
 
-	mockRepo.On("CreateUser", newUser).Return(createdUser, nil)
+	mockService.On("CreateUser", newUser).Return(createdUser, nil)
 
 	body, err := json.Marshal(newUser)
 	if err != nil {
@@ -135,16 +148,50 @@ func TestCreateUser(t *testing.T) {
 	}
 
 	assert.Equal(t, createdUser, returnedUser)
-	mockRepo.AssertExpectations(t)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateUserPublishesUserCreated(t *testing.T) {
+	bus := messaging.NewMemoryBus()
+	defer bus.Close()
+	store := repository.NewMemoryStore()
+	userHandler := handler.NewUserHandler(service.NewUserServiceImpl(store.Users(), store))
+	relay := service.NewOutboxRelay(store, bus, "user_created", 0)
+
+	var published []model.User
+	bus.Subscribe("user_created", func(d messaging.Delivery) error {
+		var user model.User
+		if err := json.Unmarshal(d.Body, &user); err != nil {
+			return err
+		}
+		published = append(published, user)
+		return nil
+	})
+
+	body := `{"name": "User 1", "email": "user1@example.com", "password": "secret"}`
+	req := httptest.NewRequest("POST", "/users/create", bytes.NewBufferString(body))
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(userHandler.CreateUser).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	_, err := relay.Flush()
+	assert.NoError(t, err)
+	bus.Wait()
+
+	if assert.Len(t, published, 1) {
+		assert.NotZero(t, published[0].ID)
+		assert.Equal(t, "user1@example.com", published[0].Email)
+		assert.Empty(t, published[0].Password)
+	}
 }
 
 func TestUpdateUser(t *testing.T) {
-	mockRepo := new(MockUserRepository)
-	userHandler := handler.NewUserHandler(mockRepo, nil)
+	mockService := new(MockUserService)
+	userHandler := handler.NewUserHandler(mockService)
 
 	updatedUser := model.User{ID: 1, Name: "User 1 Updated", Email: "user1updated@example.com"}
 
-	mockRepo.On("UpdateUser", updatedUser).Return(nil)
+	mockService.On("UpdateUser", updatedUser).Return(updatedUser, nil)
 
 	body, err := json.Marshal(updatedUser)
 	if err != nil {
@@ -170,14 +217,14 @@ func TestUpdateUser(t *testing.T) {
 	}
 
 	assert.Equal(t, updatedUser, returnedUser)
-	mockRepo.AssertExpectations(t)
+	mockService.AssertExpectations(t)
 }
 
 func TestDeleteUser(t *testing.T) {
-	mockRepo := new(MockUserRepository)
-	userHandler := handler.NewUserHandler(mockRepo, nil)
+	mockService := new(MockUserService)
+	userHandler := handler.NewUserHandler(mockService)
 
-	mockRepo.On("DeleteUser", 1).Return(nil)
+	mockService.On("DeleteUser", 1).Return(nil)
 
 	req, err := http.NewRequest("DELETE", "/users/delete/1", nil)
 	if err != nil {
@@ -189,5 +236,98 @@ func TestDeleteUser(t *testing.T) {
 	httpHandler.ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusNoContent, rr.Code)
-	mockRepo.AssertExpectations(t)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateUserInvalid(t *testing.T) {
+	mockService := new(MockUserService)
+	userHandler := handler.NewUserHandler(mockService)
+
+	newUser := model.User{Name: "User 1", Email: "not-an-email"}
+
+	mockService.On("CreateUser", newUser).Return(model.User{}, service.ErrInvalidInput)
+
+	body, err := json.Marshal(newUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := http.NewRequest("POST", "/users/create", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	httpHandler := http.HandlerFunc(userHandler.CreateUser)
+	httpHandler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestDeleteUserNotFound(t *testing.T) {
+	mockService := new(MockUserService)
+	userHandler := handler.NewUserHandler(mockService)
+
+	mockService.On("DeleteUser", 1).Return(service.ErrNotFound)
+
+	req, err := http.NewRequest("DELETE", "/users/delete/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	httpHandler := http.HandlerFunc(userHandler.DeleteUser)
+	httpHandler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetNotificationPreferences(t *testing.T) {
+	mockService := new(MockUserService)
+	userHandler := handler.NewUserHandler(mockService)
+
+	prefs := model.DefaultNotificationPreferences(1)
+	mockService.On("GetNotificationPreferences", 1).Return(prefs, nil)
+	mockService.On("GetNotificationPreferences", 2).Return(model.NotificationPreferences{}, service.ErrNotFound)
+
+	for userID, want := range map[string]int{"1": http.StatusOK, "2": http.StatusNotFound} {
+		req, err := http.NewRequest("GET", "/users/notification-preferences/"+userID, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		httpHandler := http.HandlerFunc(userHandler.GetNotificationPreferences)
+		httpHandler.ServeHTTP(rr, req)
+
+		assert.Equal(t, want, rr.Code, "user %s", userID)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateNotificationPreferences(t *testing.T) {
+	mockService := new(MockUserService)
+	userHandler := handler.NewUserHandler(mockService)
+
+	prefs := model.NotificationPreferences{UserID: 1, Outbid: true, Won: true}
+	mockService.On("UpdateNotificationPreferences", prefs).Return(prefs, nil)
+
+	req, err := http.NewRequest("PUT", "/users/notification-preferences/update/1", bytes.NewBufferString(`{"Outbid": true, "Won": true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	httpHandler := http.HandlerFunc(userHandler.UpdateNotificationPreferences)
+	httpHandler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var returned model.NotificationPreferences
+	if err := json.Unmarshal(rr.Body.Bytes(), &returned); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, prefs, returned)
+	mockService.AssertExpectations(t)
 }
@@ -0,0 +1,145 @@
+package messaging
+
+import (
+	"log"
+	"sync"
+)
+
+// MemoryBus is an in-process EventPublisher and EventSubscriber. Messages
+// published before anybody subscribes to their topic are kept until a
+// handler shows up, as a declared queue would keep them.
+type MemoryBus struct {
+	mu      sync.Mutex
+	idle    *sync.Cond
+	queues  map[string]*memoryQueue
+	dropped []Delivery
+	closed  bool
+}
+
+type memoryQueue struct {
+	pending  []Delivery
+	handlers []Handler
+	next     int
+	busy     bool
+}
+
+// NewMemoryBus creates an empty MemoryBus.
+func NewMemoryBus() *MemoryBus {
+	b := &MemoryBus{queues: map[string]*memoryQueue{}}
+	b.idle = sync.NewCond(&b.mu)
+	return b
+}
+
+// Ensure MemoryBus implements Bus
+var _ Bus = (*MemoryBus)(nil)
+
+// Publish queues a copy of body on topic.
+func (b *MemoryBus) Publish(topic string, body []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	q := b.queue(topic)
+	q.pending = append(q.pending, Delivery{Topic: topic, Body: append([]byte(nil), body...)})
+	b.dispatch(topic, q)
+	return nil
+}
+
+// Subscribe adds handler to the consumers of topic.
+func (b *MemoryBus) Subscribe(topic string, handler Handler) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	q := b.queue(topic)
+	q.handlers = append(q.handlers, handler)
+	b.dispatch(topic, q)
+	return nil
+}
+
+// Wait blocks until every message that has a handler has been processed,
+// including the messages published by the handlers themselves.
+func (b *MemoryBus) Wait() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.busy() {
+		b.idle.Wait()
+	}
+}
+
+// Dropped returns the messages that were negatively acknowledged twice.
+func (b *MemoryBus) Dropped() []Delivery {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]Delivery(nil), b.dropped...)
+}
+
+// Close rejects further messages and subscriptions and waits for the
+// running handlers to finish. Messages still queued are discarded.
+func (b *MemoryBus) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+	b.Wait()
+	return nil
+}
+
+func (b *MemoryBus) queue(topic string) *memoryQueue {
+	q, ok := b.queues[topic]
+	if !ok {
+		q = &memoryQueue{}
+		b.queues[topic] = q
+	}
+	return q
+}
+
+func (b *MemoryBus) busy() bool {
+	for _, q := range b.queues {
+		if q.busy {
+			return true
+		}
+	}
+	return false
+}
+
+// dispatch starts delivering the pending messages of q unless that is
+// already happening. It must be called with b.mu held.
+func (b *MemoryBus) dispatch(topic string, q *memoryQueue) {
+	if q.busy || len(q.pending) == 0 || len(q.handlers) == 0 {
+		return
+	}
+	q.busy = true
+	go b.deliver(topic, q)
+}
+
+// deliver hands the messages of q to its handlers one at a time. A failed
+// message goes back to the head of the queue so ordering is kept.
+func (b *MemoryBus) deliver(topic string, q *memoryQueue) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for len(q.pending) > 0 && !b.closed {
+		d := q.pending[0]
+		q.pending = q.pending[1:]
+		handler := q.handlers[q.next%len(q.handlers)]
+		q.next++
+
+		b.mu.Unlock()
+		err := handler(d)
+		b.mu.Lock()
+
+		if err == nil {
+			continue
+		}
+		if !d.Redelivered {
+			d.Redelivered = true
+			q.pending = append([]Delivery{d}, q.pending...)
+			continue
+		}
+		log.Printf("Dropping message on %s after redelivery: %v", topic, err)
+		b.dropped = append(b.dropped, d)
+	}
+	q.busy = false
+	b.idle.Broadcast()
+}
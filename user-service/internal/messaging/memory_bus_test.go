@@ -0,0 +1,113 @@
+package messaging_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"user-service/internal/messaging"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder collects the bodies delivered to it.
+type recorder struct {
+	mu     sync.Mutex
+	bodies []string
+}
+
+func (r *recorder) handle(d messaging.Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bodies = append(r.bodies, string(d.Body))
+	return nil
+}
+
+func TestMemoryBusDeliversInOrder(t *testing.T) {
+	bus := messaging.NewMemoryBus()
+	defer bus.Close()
+
+	// Messages published before the subscription are kept.
+	bus.Publish("topic", []byte("1"))
+	rec := &recorder{}
+	assert.NoError(t, bus.Subscribe("topic", rec.handle))
+	for _, body := range []string{"2", "3", "4", "5"} {
+		assert.NoError(t, bus.Publish("topic", []byte(body)))
+	}
+	bus.Publish("other", []byte("ignored"))
+	bus.Wait()
+
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, rec.bodies)
+}
+
+func TestMemoryBusRedeliversOnceAfterNack(t *testing.T) {
+	bus := messaging.NewMemoryBus()
+	defer bus.Close()
+
+	var deliveries []messaging.Delivery
+	bus.Subscribe("topic", func(d messaging.Delivery) error {
+		deliveries = append(deliveries, d)
+		switch string(d.Body) {
+		case "flaky":
+			if !d.Redelivered {
+				return errors.New("try again")
+			}
+		case "poison":
+			return errors.New("always fails")
+		}
+		return nil
+	})
+	bus.Publish("topic", []byte("flaky"))
+	bus.Publish("topic", []byte("poison"))
+	bus.Publish("topic", []byte("fine"))
+	bus.Wait()
+
+	var bodies []string
+	for _, d := range deliveries {
+		bodies = append(bodies, string(d.Body))
+	}
+	assert.Equal(t, []string{"flaky", "flaky", "poison", "poison", "fine"}, bodies)
+	assert.False(t, deliveries[0].Redelivered)
+	assert.True(t, deliveries[1].Redelivered)
+	if dropped := bus.Dropped(); assert.Len(t, dropped, 1) {
+		assert.Equal(t, "poison", string(dropped[0].Body))
+	}
+}
+
+func TestMemoryBusSharesTopicBetweenSubscribers(t *testing.T) {
+	bus := messaging.NewMemoryBus()
+	defer bus.Close()
+
+	first, second := &recorder{}, &recorder{}
+	bus.Subscribe("topic", first.handle)
+	bus.Subscribe("topic", second.handle)
+	for _, body := range []string{"1", "2", "3", "4"} {
+		bus.Publish("topic", []byte(body))
+	}
+	bus.Wait()
+
+	assert.Equal(t, []string{"1", "3"}, first.bodies)
+	assert.Equal(t, []string{"2", "4"}, second.bodies)
+}
+
+func TestMemoryBusWaitsForChainedMessages(t *testing.T) {
+	bus := messaging.NewMemoryBus()
+	defer bus.Close()
+
+	rec := &recorder{}
+	bus.Subscribe("in", func(d messaging.Delivery) error {
+		return bus.Publish("out", append([]byte("echo "), d.Body...))
+	})
+	bus.Subscribe("out", rec.handle)
+	bus.Publish("in", []byte("hello"))
+	bus.Wait()
+
+	assert.Equal(t, []string{"echo hello"}, rec.bodies)
+}
+
+func TestMemoryBusClosed(t *testing.T) {
+	bus := messaging.NewMemoryBus()
+	assert.NoError(t, bus.Close())
+
+	assert.ErrorIs(t, bus.Publish("topic", nil), messaging.ErrClosed)
+	assert.ErrorIs(t, bus.Subscribe("topic", (&recorder{}).handle), messaging.ErrClosed)
+}
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// Kinds of notifications a user can turn on or off. They match the
+// notification intents published by the auction service.
+const (
+	NotificationOutbid     = "outbid"
+	NotificationEndingSoon = "ending_soon"
+	NotificationWon        = "won"
+	NotificationLost       = "lost"
+)
+
+// NotificationPreferences tells which notifications a user wants. Users who
+// never saved theirs get DefaultNotificationPreferences.
+type NotificationPreferences struct {
+	UserID     int  `gorm:"primaryKey;autoIncrement:false"`
+	Outbid     bool `gorm:"not null"`
+	EndingSoon bool `gorm:"not null"`
+	Won        bool `gorm:"not null"`
+	Lost       bool `gorm:"not null"`
+	UpdatedAt  time.Time
+}
+
+// DefaultNotificationPreferences returns the preferences of a user who did
+// not choose any: every notification is on.
+func DefaultNotificationPreferences(userID int) NotificationPreferences {
+	return NotificationPreferences{UserID: userID, Outbid: true, EndingSoon: true, Won: true, Lost: true}
+}
+
+// Wants reports whether the user wants notifications of the given kind.
+// Unknown kinds are always sent.
+func (p NotificationPreferences) Wants(kind string) bool {
+	switch kind {
+	case NotificationOutbid:
+		return p.Outbid
+	case NotificationEndingSoon:
+		return p.EndingSoon
+	case NotificationWon:
+		return p.Won
+	case NotificationLost:
+		return p.Lost
+	}
+	return true
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// EventUserCreated is the type of the message sent when a user signs up.
+const EventUserCreated = "user.created"
+
+// OutboxMessage is an event stored in the same transaction as the change that
+// produced it. The outbox relay publishes it to the broker afterwards.
+type OutboxMessage struct {
+	ID          int    `gorm:"primaryKey"`
+	Type        string `gorm:"size:64;not null"`
+	Payload     []byte `gorm:"not null"`
+	CreatedAt   time.Time
+	PublishedAt *time.Time `gorm:"index"`
+}
@@ -0,0 +1,34 @@
+package repository_test
+
+import (
+	"testing"
+	"user-service/internal/repository"
+	"user-service/internal/repository/repositorytest"
+)
+
+func TestMemoryUserRepositoryContract(t *testing.T) {
+	repositorytest.RunUserRepositoryContract(t, func(t *testing.T) repository.UserRepository {
+		return repository.NewMemoryUserRepository()
+	})
+}
+
+func TestGormUserRepositoryContract(t *testing.T) {
+	db := setupTestDB()
+	repositorytest.RunUserRepositoryContract(t, func(t *testing.T) repository.UserRepository {
+		return repository.NewUserRepositoryImpl(db)
+	})
+}
+
+func TestMemoryTxManagerContract(t *testing.T) {
+	repositorytest.RunTxManagerContract(t, func(t *testing.T) (repository.TxManager, repository.UserRepository) {
+		store := repository.NewMemoryStore()
+		return store, store.Users()
+	})
+}
+
+func TestGormTxManagerContract(t *testing.T) {
+	db := setupTestDB()
+	repositorytest.RunTxManagerContract(t, func(t *testing.T) (repository.TxManager, repository.UserRepository) {
+		return repository.NewTxManager(db), repository.NewUserRepositoryImpl(db)
+	})
+}
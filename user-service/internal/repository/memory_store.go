@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+	"time"
+	"user-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// MemoryStore keeps users, their notification preferences and outbox
+// messages in memory. It mirrors the behaviour of the gorm repositories (soft
+// delete, unique email, ErrNotFound) and is meant for tests and local demos.
+// It is safe for concurrent use.
+//
+// Transactions are serialized and rolled back by restoring a snapshot. Writes
+// made through the store's own repositories wait for running transactions, so
+// they must not be used from inside a Transaction callback: use the
+// UnitOfWork passed to it instead.
+type MemoryStore struct {
+	txMu sync.Mutex
+	mu   sync.RWMutex
+	data memoryData
+}
+
+type memoryData struct {
+	users        map[int]model.User
+	preferences  map[int]model.NotificationPreferences
+	outbox       []model.OutboxMessage
+	nextUserID   int
+	nextOutboxID int
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: memoryData{users: map[int]model.User{}, preferences: map[int]model.NotificationPreferences{}}}
+}
+
+// Ensure MemoryStore implements TxManager
+var _ TxManager = (*MemoryStore)(nil)
+
+// Users returns a UserRepository backed by the store.
+func (s *MemoryStore) Users() UserRepository { return &MemoryUserRepository{store: s} }
+
+// NotificationPreferences returns a NotificationPreferencesRepository backed
+// by the store.
+func (s *MemoryStore) NotificationPreferences() NotificationPreferencesRepository {
+	return &memoryPreferencesRepository{store: s}
+}
+
+// Outbox returns an OutboxRepository backed by the store.
+func (s *MemoryStore) Outbox() OutboxRepository { return &memoryOutboxRepository{store: s} }
+
+// Transaction runs fn with exclusive write access to the store and restores
+// the previous state if fn returns an error.
+func (s *MemoryStore) Transaction(fn func(uow UnitOfWork) error) error {
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+	return (&memoryUnitOfWork{store: s}).Transaction(fn)
+}
+
+// write runs fn under the write lock. Outside a transaction it also waits for
+// running transactions to finish.
+func (s *MemoryStore) write(inTx bool, fn func(d *memoryData) error) error {
+	if !inTx {
+		s.txMu.Lock()
+		defer s.txMu.Unlock()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(&s.data)
+}
+
+func (s *MemoryStore) read(fn func(d *memoryData) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn(&s.data)
+}
+
+// snapshot copies the current state so a transaction can be rolled back.
+func (s *MemoryStore) snapshot() memoryData {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	copied := s.data
+	copied.users = make(map[int]model.User, len(s.data.users))
+	for id, user := range s.data.users {
+		copied.users[id] = user
+	}
+	copied.preferences = make(map[int]model.NotificationPreferences, len(s.data.preferences))
+	for id, prefs := range s.data.preferences {
+		copied.preferences[id] = prefs
+	}
+	copied.outbox = append([]model.OutboxMessage(nil), s.data.outbox...)
+	return copied
+}
+
+// restore puts back a snapshot. ID counters keep advancing, like database
+// sequences, so rolled back IDs are never reused.
+func (s *MemoryStore) restore(data memoryData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data.nextUserID = s.data.nextUserID
+	data.nextOutboxID = s.data.nextOutboxID
+	s.data = data
+}
+
+// memoryUnitOfWork hands out repositories that write inside the running
+// transaction.
+type memoryUnitOfWork struct {
+	store *MemoryStore
+}
+
+func (u *memoryUnitOfWork) Users() UserRepository {
+	return &MemoryUserRepository{store: u.store, inTx: true}
+}
+
+func (u *memoryUnitOfWork) NotificationPreferences() NotificationPreferencesRepository {
+	return &memoryPreferencesRepository{store: u.store, inTx: true}
+}
+
+func (u *memoryUnitOfWork) Outbox() OutboxRepository {
+	return &memoryOutboxRepository{store: u.store, inTx: true}
+}
+
+// Transaction works like a savepoint: only the changes made by fn are undone
+// when it fails.
+func (u *memoryUnitOfWork) Transaction(fn func(uow UnitOfWork) error) error {
+	saved := u.store.snapshot()
+	if err := fn(u); err != nil {
+		u.store.restore(saved)
+		return err
+	}
+	return nil
+}
+
+// MemoryUserRepository is an in-memory UserRepository.
+type MemoryUserRepository struct {
+	store *MemoryStore
+	inTx  bool
+}
+
+// NewMemoryUserRepository creates a UserRepository backed by a new, empty
+// MemoryStore.
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{store: NewMemoryStore()}
+}
+
+// Ensure MemoryUserRepository implements UserRepository
+var _ UserRepository = (*MemoryUserRepository)(nil)
+
+// GetAllUsers returns the users that are not deleted, ordered by ID.
+func (r *MemoryUserRepository) GetAllUsers() ([]model.User, error) {
+	var users []model.User
+	r.store.read(func(d *memoryData) error {
+		for _, user := range d.users {
+			if !user.DeletedAt.Valid {
+				users = append(users, user)
+			}
+		}
+		return nil
+	})
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users, nil
+}
+
+// GetUserByID returns a user by their ID.
+func (r *MemoryUserRepository) GetUserByID(id int) (model.User, error) {
+	var user model.User
+	err := r.store.read(func(d *memoryData) error {
+		found, ok := d.users[id]
+		if !ok || found.DeletedAt.Valid {
+			return ErrNotFound
+		}
+		user = found
+		return nil
+	})
+	return user, err
+}
+
+// CreateUser stores a new user and assigns their ID.
+func (r *MemoryUserRepository) CreateUser(user model.User) (model.User, error) {
+	err := r.store.write(r.inTx, func(d *memoryData) error {
+		if emailTaken(d, user.Email, 0) {
+			return ErrDuplicateEmail
+		}
+		now := time.Now()
+		d.nextUserID++
+		user.ID = d.nextUserID
+		user.CreatedAt = now
+		user.UpdatedAt = now
+		d.users[user.ID] = user
+		return nil
+	})
+	return user, err
+}
+
+// UpdateUser changes the name and email of an existing user.
+func (r *MemoryUserRepository) UpdateUser(updatedUser model.User) error {
+	return r.store.write(r.inTx, func(d *memoryData) error {
+		existing, ok := d.users[updatedUser.ID]
+		if !ok || existing.DeletedAt.Valid {
+			return ErrNotFound
+		}
+		if emailTaken(d, updatedUser.Email, updatedUser.ID) {
+			return ErrDuplicateEmail
+		}
+		existing.Name = updatedUser.Name
+		existing.Email = updatedUser.Email
+		existing.UpdatedAt = time.Now()
+		d.users[updatedUser.ID] = existing
+		return nil
+	})
+}
+
+// DeleteUser soft deletes a user. Deleting a missing user is not an error,
+// like with gorm.
+func (r *MemoryUserRepository) DeleteUser(id int) error {
+	return r.store.write(r.inTx, func(d *memoryData) error {
+		user, ok := d.users[id]
+		if !ok || user.DeletedAt.Valid {
+			return nil
+		}
+		user.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		d.users[id] = user
+		return nil
+	})
+}
+
+// emailTaken reports whether a user other than exceptID has email. Deleted
+// users keep their email, as the unique index in the database does.
+func emailTaken(d *memoryData, email string, exceptID int) bool {
+	for id, user := range d.users {
+		if id != exceptID && user.Email == email {
+			return true
+		}
+	}
+	return false
+}
+
+// memoryPreferencesRepository is an in-memory
+// NotificationPreferencesRepository.
+type memoryPreferencesRepository struct {
+	store *MemoryStore
+	inTx  bool
+}
+
+func (r *memoryPreferencesRepository) GetPreferences(userID int) (model.NotificationPreferences, error) {
+	var prefs model.NotificationPreferences
+	err := r.store.read(func(d *memoryData) error {
+		found, ok := d.preferences[userID]
+		if !ok {
+			return ErrNotFound
+		}
+		prefs = found
+		return nil
+	})
+	return prefs, err
+}
+
+func (r *memoryPreferencesRepository) SavePreferences(prefs model.NotificationPreferences) (model.NotificationPreferences, error) {
+	err := r.store.write(r.inTx, func(d *memoryData) error {
+		prefs.UpdatedAt = time.Now()
+		d.preferences[prefs.UserID] = prefs
+		return nil
+	})
+	return prefs, err
+}
+
+// memoryOutboxRepository is an in-memory OutboxRepository.
+type memoryOutboxRepository struct {
+	store *MemoryStore
+	inTx  bool
+}
+
+func (r *memoryOutboxRepository) AddMessage(message model.OutboxMessage) error {
+	return r.store.write(r.inTx, func(d *memoryData) error {
+		d.nextOutboxID++
+		message.ID = d.nextOutboxID
+		message.CreatedAt = time.Now()
+		message.PublishedAt = nil
+		d.outbox = append(d.outbox, message)
+		return nil
+	})
+}
+
+func (r *memoryOutboxRepository) GetPendingMessages(limit int) ([]model.OutboxMessage, error) {
+	var messages []model.OutboxMessage
+	r.store.read(func(d *memoryData) error {
+		for _, message := range d.outbox {
+			if message.PublishedAt == nil && len(messages) < limit {
+				messages = append(messages, message)
+			}
+		}
+		return nil
+	})
+	return messages, nil
+}
+
+func (r *memoryOutboxRepository) MarkPublished(id int) error {
+	return r.store.write(r.inTx, func(d *memoryData) error {
+		for i := range d.outbox {
+			if d.outbox[i].ID == id {
+				now := time.Now().UTC()
+				d.outbox[i].PublishedAt = &now
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "user-service/internal/model"
+
+// NotificationPreferencesRepository stores the notification preferences of
+// users.
+type NotificationPreferencesRepository interface {
+	// GetPreferences returns the saved preferences of a user, ErrNotFound
+	// when they never saved any.
+	GetPreferences(userID int) (model.NotificationPreferences, error)
+	// SavePreferences creates or replaces the preferences of a user.
+	SavePreferences(prefs model.NotificationPreferences) (model.NotificationPreferences, error)
+}
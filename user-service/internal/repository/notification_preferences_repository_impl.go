@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"errors"
+	"user-service/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationPreferencesRepositoryImpl handles database operations related
+// to notification preferences.
+type NotificationPreferencesRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewNotificationPreferencesRepositoryImpl creates a new instance of
+// NotificationPreferencesRepositoryImpl.
+func NewNotificationPreferencesRepositoryImpl(db *gorm.DB) *NotificationPreferencesRepositoryImpl {
+	return &NotificationPreferencesRepositoryImpl{db}
+}
+
+// Ensure NotificationPreferencesRepositoryImpl implements NotificationPreferencesRepository
+var _ NotificationPreferencesRepository = (*NotificationPreferencesRepositoryImpl)(nil)
+
+// GetPreferences returns the preferences saved for a user.
+func (pr *NotificationPreferencesRepositoryImpl) GetPreferences(userID int) (model.NotificationPreferences, error) {
+	var prefs model.NotificationPreferences
+	err := pr.db.First(&prefs, "user_id = ?", userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return prefs, ErrNotFound
+	}
+	return prefs, err
+}
+
+// SavePreferences inserts the preferences of a user or overwrites the ones
+// saved before.
+func (pr *NotificationPreferencesRepositoryImpl) SavePreferences(prefs model.NotificationPreferences) (model.NotificationPreferences, error) {
+	err := pr.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&prefs).Error
+	return prefs, err
+}
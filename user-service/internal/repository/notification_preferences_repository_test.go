@@ -0,0 +1,43 @@
+package repository_test
+
+import (
+	"testing"
+	"user-service/internal/model"
+	"user-service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationPreferencesRepository(t *testing.T) {
+	implementations := map[string]func() repository.NotificationPreferencesRepository{
+		"memory": func() repository.NotificationPreferencesRepository {
+			return repository.NewMemoryStore().NotificationPreferences()
+		},
+		"gorm": func() repository.NotificationPreferencesRepository {
+			return repository.NewNotificationPreferencesRepositoryImpl(setupTestDB())
+		},
+	}
+
+	for name, newRepo := range implementations {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+
+			_, err := repo.GetPreferences(1)
+			assert.ErrorIs(t, err, repository.ErrNotFound)
+
+			_, err = repo.SavePreferences(model.DefaultNotificationPreferences(1))
+			require.NoError(t, err)
+			_, err = repo.SavePreferences(model.NotificationPreferences{UserID: 1, Lost: true})
+			require.NoError(t, err)
+
+			prefs, err := repo.GetPreferences(1)
+			require.NoError(t, err)
+			assert.Equal(t, 1, prefs.UserID)
+			assert.False(t, prefs.Outbid)
+			assert.False(t, prefs.EndingSoon)
+			assert.False(t, prefs.Won)
+			assert.True(t, prefs.Lost)
+		})
+	}
+}
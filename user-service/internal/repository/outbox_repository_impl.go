@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"time"
+	"user-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// OutboxRepositoryImpl handles database operations related to the outbox.
+type OutboxRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepositoryImpl creates a new instance of OutboxRepositoryImpl.
+func NewOutboxRepositoryImpl(db *gorm.DB) *OutboxRepositoryImpl {
+	return &OutboxRepositoryImpl{db}
+}
+
+// Ensure OutboxRepositoryImpl implements OutboxRepository
+var _ OutboxRepository = (*OutboxRepositoryImpl)(nil)
+
+// AddMessage stores a new unpublished message.
+func (or *OutboxRepositoryImpl) AddMessage(message model.OutboxMessage) error {
+	return or.db.Create(&message).Error
+}
+
+// GetPendingMessages returns up to limit unpublished messages, oldest first.
+// Inside a transaction it locks them until the transaction ends, skipping the
+// messages another transaction holds, so every batch has a single claimant.
+func (or *OutboxRepositoryImpl) GetPendingMessages(limit int) ([]model.OutboxMessage, error) {
+	var messages []model.OutboxMessage
+	err := skipLocked(or.db).Where("published_at IS NULL").Order("id").Limit(limit).Find(&messages).Error
+	return messages, err
+}
+
+// MarkPublished records that the message has been sent to the broker.
+func (or *OutboxRepositoryImpl) MarkPublished(id int) error {
+	return or.db.Model(&model.OutboxMessage{}).Where("id = ?", id).Update("published_at", time.Now().UTC()).Error
+}
@@ -0,0 +1,198 @@
+// Package repositorytest holds behaviour tests shared by every repository
+// implementation, so the in-memory and gorm versions cannot drift apart.
+package repositorytest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+	"user-service/internal/model"
+	"user-service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RunUserRepositoryContract checks the behaviour every UserRepository must
+// have. newRepo is called once per subtest; the repositories it returns may
+// share data with earlier subtests, so every subtest uses fresh emails.
+func RunUserRepositoryContract(t *testing.T, newRepo func(t *testing.T) repository.UserRepository) {
+	var seq int
+	email := func() string {
+		seq++
+		return fmt.Sprintf("contract-%d-%d@example.com", time.Now().UnixNano(), seq)
+	}
+
+	t.Run("CreateAssignsID", func(t *testing.T) {
+		repo := newRepo(t)
+
+		created, err := repo.CreateUser(model.User{Name: "Contract User", Email: email()})
+
+		require.NoError(t, err)
+		assert.NotZero(t, created.ID)
+		assert.False(t, created.CreatedAt.IsZero())
+	})
+
+	t.Run("GetByID", func(t *testing.T) {
+		repo := newRepo(t)
+		created, err := repo.CreateUser(model.User{Name: "Contract User", Email: email()})
+		require.NoError(t, err)
+
+		fetched, err := repo.GetUserByID(created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, created.Name, fetched.Name)
+		assert.Equal(t, created.Email, fetched.Email)
+	})
+
+	t.Run("GetByIDNotFound", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetUserByID(999999)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("UniqueEmail", func(t *testing.T) {
+		repo := newRepo(t)
+		shared := email()
+		first, err := repo.CreateUser(model.User{Name: "First", Email: shared})
+		require.NoError(t, err)
+
+		_, err = repo.CreateUser(model.User{Name: "Second", Email: shared})
+		assert.ErrorIs(t, err, repository.ErrDuplicateEmail)
+
+		other, err := repo.CreateUser(model.User{Name: "Other", Email: email()})
+		require.NoError(t, err)
+		other.Email = shared
+		assert.ErrorIs(t, repo.UpdateUser(other), repository.ErrDuplicateEmail)
+
+		// The email stays taken after the user is soft deleted.
+		require.NoError(t, repo.DeleteUser(first.ID))
+		_, err = repo.CreateUser(model.User{Name: "Third", Email: shared})
+		assert.ErrorIs(t, err, repository.ErrDuplicateEmail)
+	})
+
+	t.Run("GetAllSkipsDeleted", func(t *testing.T) {
+		repo := newRepo(t)
+		kept, err := repo.CreateUser(model.User{Name: "Kept", Email: email()})
+		require.NoError(t, err)
+		deleted, err := repo.CreateUser(model.User{Name: "Deleted", Email: email()})
+		require.NoError(t, err)
+		require.NoError(t, repo.DeleteUser(deleted.ID))
+
+		users, err := repo.GetAllUsers()
+		require.NoError(t, err)
+
+		ids := map[int]bool{}
+		for _, user := range users {
+			ids[user.ID] = true
+		}
+		assert.True(t, ids[kept.ID])
+		assert.False(t, ids[deleted.ID])
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := newRepo(t)
+		created, err := repo.CreateUser(model.User{Name: "Before", Email: email()})
+		require.NoError(t, err)
+
+		created.Name = "After"
+		require.NoError(t, repo.UpdateUser(created))
+
+		fetched, err := repo.GetUserByID(created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "After", fetched.Name)
+		assert.WithinDuration(t, created.CreatedAt, fetched.CreatedAt, time.Millisecond)
+	})
+
+	t.Run("UpdateKeepsPassword", func(t *testing.T) {
+		repo := newRepo(t)
+		created, err := repo.CreateUser(model.User{Name: "Before", Email: email(), Password: "secret"})
+		require.NoError(t, err)
+
+		require.NoError(t, repo.UpdateUser(model.User{ID: created.ID, Name: "After", Email: created.Email}))
+
+		fetched, err := repo.GetUserByID(created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "After", fetched.Name)
+		assert.Equal(t, "secret", fetched.Password)
+		assert.WithinDuration(t, created.CreatedAt, fetched.CreatedAt, time.Millisecond)
+	})
+
+	t.Run("UpdateMissingOrDeleted", func(t *testing.T) {
+		repo := newRepo(t)
+		assert.ErrorIs(t, repo.UpdateUser(model.User{ID: 999999, Name: "Ghost", Email: email()}), repository.ErrNotFound)
+
+		created, err := repo.CreateUser(model.User{Name: "Deleted", Email: email()})
+		require.NoError(t, err)
+		require.NoError(t, repo.DeleteUser(created.ID))
+		assert.ErrorIs(t, repo.UpdateUser(created), repository.ErrNotFound)
+	})
+
+	t.Run("SoftDelete", func(t *testing.T) {
+		repo := newRepo(t)
+		created, err := repo.CreateUser(model.User{Name: "Deleted", Email: email()})
+		require.NoError(t, err)
+
+		require.NoError(t, repo.DeleteUser(created.ID))
+		_, err = repo.GetUserByID(created.ID)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+
+		// Deleting twice, or deleting an unknown ID, is not an error.
+		assert.NoError(t, repo.DeleteUser(created.ID))
+		assert.NoError(t, repo.DeleteUser(999999))
+	})
+}
+
+// RunTxManagerContract checks commit, rollback and savepoint behaviour of a
+// TxManager. newTx returns the manager and a repository reading the same data
+// outside of transactions.
+func RunTxManagerContract(t *testing.T, newTx func(t *testing.T) (repository.TxManager, repository.UserRepository)) {
+	t.Run("Rollback", func(t *testing.T) {
+		txManager, repo := newTx(t)
+
+		var user model.User
+		err := txManager.Transaction(func(uow repository.UnitOfWork) error {
+			var err error
+			user, err = uow.Users().CreateUser(model.User{Name: "Rolled Back", Email: fmt.Sprintf("rollback-%d@example.com", time.Now().UnixNano())})
+			if err != nil {
+				return err
+			}
+			if err := uow.Outbox().AddMessage(model.OutboxMessage{Type: model.EventUserCreated, Payload: []byte("{}")}); err != nil {
+				return err
+			}
+			return assert.AnError
+		})
+		assert.ErrorIs(t, err, assert.AnError)
+
+		_, err = repo.GetUserByID(user.ID)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("NestedRollback", func(t *testing.T) {
+		txManager, repo := newTx(t)
+
+		var outer, inner model.User
+		err := txManager.Transaction(func(uow repository.UnitOfWork) error {
+			var err error
+			outer, err = uow.Users().CreateUser(model.User{Name: "Outer", Email: fmt.Sprintf("outer-%d@example.com", time.Now().UnixNano())})
+			if err != nil {
+				return err
+			}
+			nestedErr := uow.Transaction(func(nested repository.UnitOfWork) error {
+				inner, err = nested.Users().CreateUser(model.User{Name: "Inner", Email: fmt.Sprintf("inner-%d@example.com", time.Now().UnixNano())})
+				if err != nil {
+					return err
+				}
+				return assert.AnError
+			})
+			assert.ErrorIs(t, nestedErr, assert.AnError)
+			return nil
+		})
+		require.NoError(t, err)
+
+		_, err = repo.GetUserByID(outer.ID)
+		assert.NoError(t, err)
+		_, err = repo.GetUserByID(inner.ID)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
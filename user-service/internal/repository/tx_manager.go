@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UnitOfWork gives access to repositories that share a single transaction.
+// Calling Transaction on a UnitOfWork opens a nested savepoint: if fn fails
+// only the work done inside it is rolled back.
+type UnitOfWork interface {
+	Users() UserRepository
+	NotificationPreferences() NotificationPreferencesRepository
+	Outbox() OutboxRepository
+	TxManager
+}
+
+// TxManager runs fn inside a transaction. The transaction is committed when fn
+// returns nil and rolled back otherwise.
+type TxManager interface {
+	Transaction(fn func(uow UnitOfWork) error) error
+}
+
+// DefaultMaxRetries is how many times GormTxManager retries a transaction that
+// failed because of a serialization conflict or a deadlock.
+const DefaultMaxRetries = 3
+
+// GormTxManager runs units of work on a gorm database.
+type GormTxManager struct {
+	db         *gorm.DB
+	MaxRetries int
+	// Backoff is the wait before the first retry, doubled on every attempt.
+	Backoff time.Duration
+}
+
+// NewTxManager creates a GormTxManager with the default retry policy.
+func NewTxManager(db *gorm.DB) *GormTxManager {
+	return &GormTxManager{db: db, MaxRetries: DefaultMaxRetries, Backoff: 10 * time.Millisecond}
+}
+
+// Ensure GormTxManager implements TxManager
+var _ TxManager = (*GormTxManager)(nil)
+
+// Transaction runs fn in a new transaction and retries the whole of it when
+// the database reports a serialization failure. fn must therefore be safe to
+// run more than once.
+func (m *GormTxManager) Transaction(fn func(uow UnitOfWork) error) error {
+	backoff := m.Backoff
+	for attempt := 0; ; attempt++ {
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			return fn(&gormUnitOfWork{db: tx})
+		})
+		if err == nil || attempt >= m.MaxRetries || !IsSerializationFailure(err) {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// gormUnitOfWork builds repositories bound to an open transaction.
+type gormUnitOfWork struct {
+	db *gorm.DB
+}
+
+func (u *gormUnitOfWork) Users() UserRepository    { return NewUserRepositoryImpl(u.db) }
+func (u *gormUnitOfWork) Outbox() OutboxRepository { return NewOutboxRepositoryImpl(u.db) }
+
+func (u *gormUnitOfWork) NotificationPreferences() NotificationPreferencesRepository {
+	return NewNotificationPreferencesRepositoryImpl(u.db)
+}
+
+// Transaction runs fn inside a savepoint of the current transaction.
+func (u *gormUnitOfWork) Transaction(fn func(uow UnitOfWork) error) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&gormUnitOfWork{db: tx})
+	})
+}
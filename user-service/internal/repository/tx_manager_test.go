@@ -0,0 +1,15 @@
+package repository_test
+
+import (
+	"errors"
+	"testing"
+	"user-service/internal/repository"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSerializationFailure(t *testing.T) {
+	assert.True(t, repository.IsSerializationFailure(&pgconn.PgError{Code: "40001"}))
+	assert.False(t, repository.IsSerializationFailure(errors.New("boom")))
+}
@@ -1,6 +1,17 @@
 package repository
 
-import "user-service/internal/model"
+import (
+	"errors"
+	"user-service/internal/model"
+)
+
+var (
+	// ErrNotFound is returned when the requested user does not exist.
+	ErrNotFound = errors.New("user not found")
+	// ErrDuplicateEmail is returned when another user, even a deleted one,
+	// already has the email.
+	ErrDuplicateEmail = errors.New("email already in use")
+)
 
 // UserRepository defines the methods that any
 // data storage provider needs to implement to get
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"errors"
 	"user-service/internal/model"
 
 	"gorm.io/gorm"
@@ -16,6 +17,9 @@ func NewUserRepositoryImpl(db *gorm.DB) *UserRepositoryImpl {
 	return &UserRepositoryImpl{db}
 }
 
+// Ensure UserRepositoryImpl implements UserRepository
+var _ UserRepository = (*UserRepositoryImpl)(nil)
+
 // GetAllUsers returns all users from the database.
 func (ur *UserRepositoryImpl) GetAllUsers() ([]model.User, error) {
 	var users []model.User
@@ -27,19 +31,36 @@ func (ur *UserRepositoryImpl) GetAllUsers() ([]model.User, error) {
 func (ur *UserRepositoryImpl) GetUserByID(id int) (model.User, error) {
 	var user model.User
 	err := ur.db.First(&user, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return user, ErrNotFound
+	}
 	return user, err
 }
 
 // CreateUser creates a new user in the database.
 func (ur *UserRepositoryImpl) CreateUser(user model.User) (model.User, error) {
 	err := ur.db.Create(&user).Error
+	if isUniqueViolation(err) {
+		return user, ErrDuplicateEmail
+	}
 	return user, err
 }
 
-// UpdateUser updates an existing user in the database.
+// UpdateUser updates the name and email of an existing user in the database.
+// Unlike Save it never inserts: updating a missing or deleted user returns
+// ErrNotFound.
 func (ur *UserRepositoryImpl) UpdateUser(updatedUser model.User) error {
-	err := ur.db.Save(&updatedUser).Error
-	return err
+	result := ur.db.Model(&updatedUser).Select("Name", "Email").Updates(&updatedUser)
+	if isUniqueViolation(result.Error) {
+		return ErrDuplicateEmail
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
 }
 
 // DeleteUser deletes an existing user from the database by their ID.
@@ -2,30 +2,39 @@ package repository_test
 
 import (
 	"log"
+	"os"
 	"testing"
-	"user-service/internal/config"
+	"user-service/internal/db"
 	"user-service/internal/model"
 	"user-service/internal/repository"
 
 	"github.com/stretchr/testify/assert"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// setupTestDB connects to DATABASE_URL, or to a fresh in-memory SQLite
+// database when it is not set, migrates the schema and empties it.
 func setupTestDB() *gorm.DB {
-	cfg := config.LoadConfig()
-
-	dsn := cfg.DatabaseURL
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		dsn = "sqlite://:memory:"
+	}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	conn, err := db.Open(dsn)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
-	// Clear the users table before each test
-	db.Exec("TRUNCATE TABLE users RESTART IDENTITY CASCADE")
+	if err := conn.AutoMigrate(&model.User{}, &model.NotificationPreferences{}, &model.OutboxMessage{}); err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+
+	// Clear the tables before each test
+	conn.Session(&gorm.Session{AllowGlobalUpdate: true}).Unscoped().Delete(&model.User{})
+	conn.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&model.NotificationPreferences{})
+	conn.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&model.OutboxMessage{})
 
-	return db
+	return conn
 }
 
 func TestCreateUserRepo(t *testing.T) {
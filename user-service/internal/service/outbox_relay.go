@@ -0,0 +1,116 @@
+package service
+
+import (
+	"log"
+	"sync"
+	"time"
+	"user-service/internal/messaging"
+	"user-service/internal/model"
+	"user-service/internal/repository"
+)
+
+// OutboxRelay publishes the messages stored in the outbox and marks them as
+// published. A message that fails to publish stops the batch and is retried
+// on the next tick, so events are delivered at least once. Each batch is
+// claimed in a transaction, so the relays of several replicas never publish
+// the same message, but they publish their batches side by side: messages
+// are not delivered in outbox order, and consumers must not rely on it.
+type OutboxRelay struct {
+	txManager repository.TxManager
+	publisher messaging.EventPublisher
+	topic     string
+	// copies are further topics that get every message too.
+	copies    []string
+	interval  time.Duration
+	batchSize int
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewOutboxRelay creates a relay that polls the outbox every interval and
+// publishes the messages to topic.
+func NewOutboxRelay(txManager repository.TxManager, publisher messaging.EventPublisher, topic string, interval time.Duration) *OutboxRelay {
+	return &OutboxRelay{
+		txManager: txManager,
+		publisher: publisher,
+		topic:     topic,
+		interval:  interval,
+		batchSize: 100,
+		stop:      make(chan struct{}),
+	}
+}
+
+// AlsoPublishTo publishes every message to topic as well, for consumers that
+// need their own copy of the stream instead of competing for the messages of
+// the main topic.
+func (r *OutboxRelay) AlsoPublishTo(topic string) {
+	r.copies = append(r.copies, topic)
+}
+
+// Start polls the outbox in the background until Stop is called.
+func (r *OutboxRelay) Start() {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				if _, err := r.Flush(); err != nil {
+					log.Printf("Error relaying outbox messages: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop ends the polling started by Start and waits for it to finish.
+func (r *OutboxRelay) Stop() {
+	close(r.stop)
+	r.wg.Wait()
+}
+
+// Flush publishes one batch of pending messages and returns how many were
+// sent. The batch is claimed and marked published in one transaction: other
+// relays skip it meanwhile, and take over what is left unmarked if this one
+// fails.
+func (r *OutboxRelay) Flush() (int, error) {
+	var sent int
+	var publishErr error
+	err := r.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		sent, publishErr = 0, nil
+		messages, err := uow.Outbox().GetPendingMessages(r.batchSize)
+		if err != nil {
+			return err
+		}
+		for _, message := range messages {
+			if publishErr = r.publish(message); publishErr != nil {
+				// Commit what was sent; the rest waits for the next tick.
+				return nil
+			}
+			if err := uow.Outbox().MarkPublished(message.ID); err != nil {
+				return err
+			}
+			sent++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return sent, publishErr
+}
+
+// publish sends message to the topic and its copies.
+func (r *OutboxRelay) publish(message model.OutboxMessage) error {
+	for _, topic := range append([]string{r.topic}, r.copies...) {
+		if err := r.publisher.Publish(topic, message.Payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
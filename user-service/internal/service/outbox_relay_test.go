@@ -0,0 +1,93 @@
+package service_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"user-service/internal/model"
+	"user-service/internal/repository"
+	"user-service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingPublisher struct {
+	sent   []string
+	topics []string
+	err    error
+}
+
+func (p *failingPublisher) Publish(topic string, message []byte) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.sent = append(p.sent, string(message))
+	p.topics = append(p.topics, topic)
+	return nil
+}
+
+func TestOutboxRelayRetriesAfterPublishFailure(t *testing.T) {
+	store := repository.NewMemoryStore()
+	outbox := store.Outbox()
+	outbox.AddMessage(model.OutboxMessage{Type: model.EventUserCreated, Payload: []byte(`{"ID":1}`)})
+
+	publisher := &failingPublisher{err: errors.New("broker down")}
+	relay := service.NewOutboxRelay(store, publisher, "user_created", 0)
+
+	sent, err := relay.Flush()
+	assert.Error(t, err)
+	assert.Equal(t, 0, sent)
+
+	publisher.err = nil
+	sent, err = relay.Flush()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []string{`{"ID":1}`}, publisher.sent)
+}
+
+func TestOutboxRelaysOfSeveralReplicasPublishOnce(t *testing.T) {
+	store := repository.NewMemoryStore()
+	for i := 0; i < 250; i++ {
+		store.Outbox().AddMessage(model.OutboxMessage{Type: model.EventUserCreated, Payload: []byte(fmt.Sprint(i))})
+	}
+
+	publisher := &failingPublisher{}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		relay := service.NewOutboxRelay(store, publisher, "user_created", 0)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				sent, err := relay.Flush()
+				if err != nil || sent == 0 {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if assert.Len(t, publisher.sent, 250) {
+		for i, message := range publisher.sent {
+			assert.Equal(t, fmt.Sprint(i), message)
+		}
+	}
+}
+
+func TestOutboxRelayPublishesCopies(t *testing.T) {
+	store := repository.NewMemoryStore()
+	outbox := store.Outbox()
+	outbox.AddMessage(model.OutboxMessage{Type: model.EventUserCreated, Payload: []byte(`{"ID":1}`)})
+
+	publisher := &failingPublisher{}
+	relay := service.NewOutboxRelay(store, publisher, "user_created", 0)
+	relay.AlsoPublishTo("user_notifications")
+
+	sent, err := relay.Flush()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []string{"user_created", "user_notifications"}, publisher.topics)
+	assert.Equal(t, []string{`{"ID":1}`, `{"ID":1}`}, publisher.sent)
+}
@@ -1,10 +1,28 @@
 package service
 
 import (
+	"errors"
+
 	"user-service/internal/model"
 )
 
+var (
+	// ErrNotFound is returned when the user does not exist.
+	ErrNotFound = errors.New("user not found")
+	// ErrInvalidInput is returned when the user fails validation.
+	ErrInvalidInput = errors.New("invalid input")
+	// ErrEmailTaken is returned when another user already has the email.
+	ErrEmailTaken = errors.New("email already in use")
+)
+
 type UserService interface {
-	CreateUser(name, email string) error
-	GetUserByID(id uint) (*model.User, error)
+	GetAllUsers() ([]model.User, error)
+	GetUserByID(id int) (model.User, error)
+	CreateUser(user model.User) (model.User, error)
+	UpdateUser(user model.User) (model.User, error)
+	DeleteUser(id int) error
+	// GetNotificationPreferences returns the notification preferences of a
+	// user, the defaults when they never saved any.
+	GetNotificationPreferences(userID int) (model.NotificationPreferences, error)
+	UpdateNotificationPreferences(prefs model.NotificationPreferences) (model.NotificationPreferences, error)
 }
@@ -1,26 +1,172 @@
 package service
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
 	"user-service/internal/model"
 	"user-service/internal/repository"
 )
 
 type UserServiceImpl struct {
 	userRepository repository.UserRepository
+	txManager      repository.TxManager
 }
 
-func NewUserServiceImpl(userRepository repository.UserRepository) *UserServiceImpl {
-	return &UserServiceImpl{userRepository: userRepository}
+// Ensure UserServiceImpl implements UserService
+var _ UserService = (*UserServiceImpl)(nil)
+
+// NewUserServiceImpl creates a new instance of UserServiceImpl. Reads go
+// through userRepository; changes run in txManager transactions.
+func NewUserServiceImpl(userRepository repository.UserRepository, txManager repository.TxManager) *UserServiceImpl {
+	return &UserServiceImpl{userRepository: userRepository, txManager: txManager}
 }
 
-func (s *UserServiceImpl) CreateUser(name, email string) (model.User, error) {
-	user := &model.User{
-		Name:  name,
-		Email: email,
-	}
-	return s.userRepository.CreateUser(*user)
+func (s *UserServiceImpl) GetAllUsers() ([]model.User, error) {
+	return s.userRepository.GetAllUsers()
 }
 
 func (s *UserServiceImpl) GetUserByID(id int) (model.User, error) {
-	return s.userRepository.GetUserByID(id)
+	user, err := s.userRepository.GetUserByID(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return user, ErrNotFound
+	}
+	return user, err
+}
+
+// CreateUser validates and stores the user. In the same transaction it adds
+// the user_created message to the outbox so other services can react.
+func (s *UserServiceImpl) CreateUser(user model.User) (model.User, error) {
+	if err := validateUser(&user); err != nil {
+		return model.User{}, err
+	}
+
+	var createdUser model.User
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		var err error
+		createdUser, err = uow.Users().CreateUser(user)
+		if errors.Is(err, repository.ErrDuplicateEmail) {
+			return ErrEmailTaken
+		}
+		if err != nil {
+			return err
+		}
+		return enqueueCreated(uow, createdUser)
+	})
+	if err != nil {
+		return model.User{}, err
+	}
+	return createdUser, nil
+}
+
+// UpdateUser validates and stores the name and email of an existing user.
+// Other fields of user are ignored; the password is kept.
+func (s *UserServiceImpl) UpdateUser(user model.User) (model.User, error) {
+	if err := validateUser(&user); err != nil {
+		return model.User{}, err
+	}
+
+	var updated model.User
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		existing, err := uow.Users().GetUserByID(user.ID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		existing.Name = user.Name
+		existing.Email = user.Email
+		err = uow.Users().UpdateUser(existing)
+		if errors.Is(err, repository.ErrDuplicateEmail) {
+			return ErrEmailTaken
+		}
+		if err != nil {
+			return err
+		}
+		updated, err = uow.Users().GetUserByID(user.ID)
+		return err
+	})
+	if err != nil {
+		return model.User{}, err
+	}
+	return updated, nil
+}
+
+func (s *UserServiceImpl) DeleteUser(id int) error {
+	return s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		if err := userExists(uow, id); err != nil {
+			return err
+		}
+		return uow.Users().DeleteUser(id)
+	})
+}
+
+func (s *UserServiceImpl) GetNotificationPreferences(userID int) (model.NotificationPreferences, error) {
+	var prefs model.NotificationPreferences
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		if err := userExists(uow, userID); err != nil {
+			return err
+		}
+		var err error
+		prefs, err = uow.NotificationPreferences().GetPreferences(userID)
+		if errors.Is(err, repository.ErrNotFound) {
+			prefs, err = model.DefaultNotificationPreferences(userID), nil
+		}
+		return err
+	})
+	if err != nil {
+		return model.NotificationPreferences{}, err
+	}
+	return prefs, nil
+}
+
+func (s *UserServiceImpl) UpdateNotificationPreferences(prefs model.NotificationPreferences) (model.NotificationPreferences, error) {
+	var saved model.NotificationPreferences
+	err := s.txManager.Transaction(func(uow repository.UnitOfWork) error {
+		if err := userExists(uow, prefs.UserID); err != nil {
+			return err
+		}
+		var err error
+		saved, err = uow.NotificationPreferences().SavePreferences(prefs)
+		return err
+	})
+	if err != nil {
+		return model.NotificationPreferences{}, err
+	}
+	return saved, nil
+}
+
+// userExists returns ErrNotFound when there is no user with the given ID.
+func userExists(uow repository.UnitOfWork, id int) error {
+	_, err := uow.Users().GetUserByID(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// validateUser normalizes the name and email of user and checks they are set.
+func validateUser(user *model.User) error {
+	user.Name = strings.TrimSpace(user.Name)
+	user.Email = strings.ToLower(strings.TrimSpace(user.Email))
+	if user.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+	if _, err := mail.ParseAddress(user.Email); err != nil {
+		return fmt.Errorf("%w: email is not valid", ErrInvalidInput)
+	}
+	return nil
+}
+
+// enqueueCreated stores the created user, without its password, in the outbox.
+func enqueueCreated(uow repository.UnitOfWork, user model.User) error {
+	user.Password = ""
+	payload, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+	return uow.Outbox().AddMessage(model.OutboxMessage{Type: model.EventUserCreated, Payload: payload})
 }
@@ -0,0 +1,124 @@
+package service_test
+
+import (
+	"encoding/json"
+	"testing"
+	"user-service/internal/model"
+	"user-service/internal/repository"
+	"user-service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestService() (*service.UserServiceImpl, *repository.MemoryStore) {
+	store := repository.NewMemoryStore()
+	return service.NewUserServiceImpl(store.Users(), store), store
+}
+
+func pendingMessages(t *testing.T, store *repository.MemoryStore) []model.OutboxMessage {
+	messages, err := store.Outbox().GetPendingMessages(100)
+	require.NoError(t, err)
+	return messages
+}
+
+func TestCreateUserEnqueuesWithoutPassword(t *testing.T) {
+	userService, store := newTestService()
+
+	createdUser, err := userService.CreateUser(model.User{Name: " User 1 ", Email: "User1@Example.com", Password: "secret"})
+
+	assert.NoError(t, err)
+	assert.NotZero(t, createdUser.ID)
+	assert.Equal(t, "User 1", createdUser.Name)
+	assert.Equal(t, "user1@example.com", createdUser.Email)
+
+	messages := pendingMessages(t, store)
+	if assert.Len(t, messages, 1) {
+		assert.Equal(t, model.EventUserCreated, messages[0].Type)
+		var published model.User
+		assert.NoError(t, json.Unmarshal(messages[0].Payload, &published))
+		assert.Equal(t, createdUser.ID, published.ID)
+		assert.Empty(t, published.Password)
+	}
+}
+
+func TestCreateUserDuplicateEmail(t *testing.T) {
+	userService, store := newTestService()
+
+	_, err := userService.CreateUser(model.User{Name: "User 1", Email: "user1@example.com"})
+	require.NoError(t, err)
+
+	_, err = userService.CreateUser(model.User{Name: "User 2", Email: "USER1@example.com"})
+
+	assert.ErrorIs(t, err, service.ErrEmailTaken)
+	assert.Len(t, pendingMessages(t, store), 1)
+}
+
+func TestCreateUserValidation(t *testing.T) {
+	userService, store := newTestService()
+
+	_, err := userService.CreateUser(model.User{Email: "user1@example.com"})
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+
+	_, err = userService.CreateUser(model.User{Name: "User 1", Email: "not-an-email"})
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+
+	users, _ := store.Users().GetAllUsers()
+	assert.Empty(t, users)
+}
+
+func TestUpdateUserNotFound(t *testing.T) {
+	userService, _ := newTestService()
+
+	_, err := userService.UpdateUser(model.User{ID: 1, Name: "User 1", Email: "user1@example.com"})
+
+	assert.ErrorIs(t, err, service.ErrNotFound)
+}
+
+func TestUpdateUser(t *testing.T) {
+	userService, _ := newTestService()
+	createdUser, err := userService.CreateUser(model.User{Name: "User 1", Email: "user1@example.com", Password: "secret"})
+	require.NoError(t, err)
+
+	updatedUser, err := userService.UpdateUser(model.User{ID: createdUser.ID, Name: "User 1 Updated", Email: "user1updated@example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, createdUser.CreatedAt, updatedUser.CreatedAt)
+
+	fetched, err := userService.GetUserByID(createdUser.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, updatedUser.Name, fetched.Name)
+	assert.Equal(t, updatedUser.Email, fetched.Email)
+	assert.Equal(t, "secret", fetched.Password, "updates without a password keep it")
+}
+
+func TestDeleteUser(t *testing.T) {
+	userService, _ := newTestService()
+	createdUser, err := userService.CreateUser(model.User{Name: "User 1", Email: "user1@example.com"})
+	require.NoError(t, err)
+
+	assert.NoError(t, userService.DeleteUser(createdUser.ID))
+	assert.ErrorIs(t, userService.DeleteUser(createdUser.ID), service.ErrNotFound)
+}
+
+func TestNotificationPreferences(t *testing.T) {
+	userService, _ := newTestService()
+	createdUser, err := userService.CreateUser(model.User{Name: "User 1", Email: "user1@example.com"})
+	require.NoError(t, err)
+
+	prefs, err := userService.GetNotificationPreferences(createdUser.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.DefaultNotificationPreferences(createdUser.ID), prefs)
+
+	_, err = userService.UpdateNotificationPreferences(model.NotificationPreferences{UserID: createdUser.ID, Won: true})
+	require.NoError(t, err)
+	prefs, err = userService.GetNotificationPreferences(createdUser.ID)
+	assert.NoError(t, err)
+	assert.True(t, prefs.Wants(model.NotificationWon))
+	assert.False(t, prefs.Wants(model.NotificationOutbid))
+	assert.False(t, prefs.Wants(model.NotificationEndingSoon))
+
+	_, err = userService.GetNotificationPreferences(999)
+	assert.ErrorIs(t, err, service.ErrNotFound)
+	_, err = userService.UpdateNotificationPreferences(model.NotificationPreferences{UserID: 999})
+	assert.ErrorIs(t, err, service.ErrNotFound)
+}